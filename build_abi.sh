@@ -32,6 +32,9 @@ mv $contracts_path/artifacts ./contracts_tmp
 # l1 messenger
 l1_messenger=("IL1ScrollMessenger")
 
+# l1 rollup
+l1_rollup=("IScrollChain" "IL1MessageQueue")
+
 # l1 gateway
 l1_gateway=("IL1ETHGateway" "IL1ERC20Gateway" "IL1ERC721Gateway" "IL1ERC1155Gateway")
 
@@ -50,7 +53,7 @@ fi
 mkdir internal/logic/contracts/abi/
 
 mkdir -p tmp
-contracts=("${l1_messenger[@]}" "${l1_gateway[@]}" "${l2_messenger[@]}" "${l2_gateway[@]}" "${token_list[@]}")
+contracts=("${l1_messenger[@]}" "${l1_rollup[@]}" "${l1_gateway[@]}" "${l2_messenger[@]}" "${l2_gateway[@]}" "${token_list[@]}")
 for abi_name in "${contracts[@]}"; do
   echo "Generating code for: $abi_name"
 
@@ -18,8 +18,11 @@ import (
 
 	"github.com/scroll-tech/chain-monitor/internal/config"
 	"github.com/scroll-tech/chain-monitor/internal/controller"
+	"github.com/scroll-tech/chain-monitor/internal/logic/reorg"
+	"github.com/scroll-tech/chain-monitor/internal/orm"
 	"github.com/scroll-tech/chain-monitor/internal/orm/migrate"
 	"github.com/scroll-tech/chain-monitor/internal/route"
+	"github.com/scroll-tech/chain-monitor/internal/types"
 	"github.com/scroll-tech/chain-monitor/internal/utils"
 	"github.com/scroll-tech/chain-monitor/internal/utils/database"
 	"github.com/scroll-tech/chain-monitor/internal/utils/observability"
@@ -35,7 +38,7 @@ func init() {
 	app.Usage = "The Scroll chain monitor"
 	app.Version = utils.Version
 	app.Flags = append(app.Flags, utils.CommonFlags...)
-	app.Commands = []*cli.Command{}
+	app.Commands = []*cli.Command{rescanCommand}
 	app.Before = func(ctx *cli.Context) error {
 		return utils.LogSetup(ctx)
 	}
@@ -71,6 +74,11 @@ func action(ctx *cli.Context) error {
 		}
 	}
 
+	// sync cursor operation.
+	if ctx.IsSet(utils.SyncCursorBlockFlag.Name) {
+		return setSyncCursor(subCtx, db, types.LayerType(ctx.Int(utils.SyncCursorLayerFlag.Name)), ctx.Uint64(utils.SyncCursorBlockFlag.Name), ctx.Bool(utils.SyncCursorRollbackFlag.Name))
+	}
+
 	l1Client, err := rpc.Dial(cfg.L1Config.L1URL)
 	if err != nil {
 		log.Crit("failed to connect to l1 geth", "l1 geth url", cfg.L1Config.L1URL, "err", err)
@@ -83,8 +91,8 @@ func action(ctx *cli.Context) error {
 
 	observability.Server(ctx, db)
 
-	slackAlert := controller.NewSlackAlertController(subCtx, cfg.AlertConfig)
-	slackAlert.Start()
+	alertCtl := controller.NewAlertController(subCtx, cfg, db)
+	alertCtl.Start()
 
 	contractCtl := controller.NewContractController(cfg, db, l1Client, l2Client)
 	contractCtl.Watch(subCtx)
@@ -92,6 +100,12 @@ func action(ctx *cli.Context) error {
 	crossChainCtl := controller.NewCrossChainController(cfg, db, ethclient.NewClient(l1Client), ethclient.NewClient(l2Client))
 	crossChainCtl.Watch(subCtx)
 
+	batchCtl := controller.NewBatchController(cfg, db)
+	batchCtl.Watch(subCtx)
+
+	tokenSupplyCtl := controller.NewTokenSupplyController(cfg, db, ethclient.NewClient(l1Client), ethclient.NewClient(l2Client))
+	tokenSupplyCtl.Watch(subCtx)
+
 	apiSrv := apiServer(ctx, cfg, db)
 
 	log.Info("Start chain-monitor successfully.")
@@ -99,7 +113,9 @@ func action(ctx *cli.Context) error {
 	defer func() {
 		contractCtl.Stop()
 		crossChainCtl.Stop()
-		slackAlert.Stop()
+		batchCtl.Stop()
+		tokenSupplyCtl.Stop()
+		alertCtl.Stop()
 		if err = database.CloseDB(db); err != nil {
 			log.Error("failed to close database", "err", err)
 		}
@@ -123,6 +139,27 @@ func action(ctx *cli.Context) error {
 	return nil
 }
 
+// setSyncCursor only moves the sync cursor of the layer, the ingested events are kept. With rollback, the ingested
+// events after the block number are rolled back as well, so the watcher re-ingests them.
+func setSyncCursor(ctx context.Context, db *gorm.DB, layer types.LayerType, blockNumber uint64, rollback bool) error {
+	if layer != types.Layer1 && layer != types.Layer2 {
+		return fmt.Errorf("invalid sync cursor layer: %d", layer)
+	}
+
+	if rollback {
+		if err := reorg.NewLogicReorg(db).Rollback(ctx, layer, blockNumber+1); err != nil {
+			return fmt.Errorf("set sync cursor failed, layer: %s, block number: %d, err: %w", layer.String(), blockNumber, err)
+		}
+	} else {
+		if err := orm.NewSyncCursor(db).InsertOrUpdateSyncCursor(ctx, layer, types.CheckerTypeContract, blockNumber, ""); err != nil {
+			return fmt.Errorf("set sync cursor failed, layer: %s, block number: %d, err: %w", layer.String(), blockNumber, err)
+		}
+	}
+
+	log.Info("set sync cursor successfully", "layer", layer.String(), "block number", blockNumber, "rollback", rollback)
+	return nil
+}
+
 func apiServer(ctx *cli.Context, cfg *config.Config, db *gorm.DB) *http.Server {
 	log.Info("api controller start successful")
 
@@ -0,0 +1,69 @@
+package app
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rpc"
+	"github.com/urfave/cli/v2"
+
+	"github.com/scroll-tech/chain-monitor/internal/config"
+	"github.com/scroll-tech/chain-monitor/internal/controller"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+	"github.com/scroll-tech/chain-monitor/internal/utils"
+	"github.com/scroll-tech/chain-monitor/internal/utils/database"
+)
+
+var rescanCommand = &cli.Command{
+	Name:   "rescan",
+	Usage:  "Re-run the gateway, transfer and messenger checks over a historical block range",
+	Flags:  utils.RescanFlags,
+	Action: rescanAction,
+}
+
+func rescanAction(ctx *cli.Context) error {
+	layer := types.LayerType(ctx.Int(utils.RescanLayerFlag.Name))
+	from := ctx.Uint64(utils.RescanFromFlag.Name)
+	to := ctx.Uint64(utils.RescanToFlag.Name)
+	dryRun := ctx.Bool(utils.RescanDryRunFlag.Name)
+
+	// Load config file.
+	cfgFile := ctx.String(utils.ConfigFileFlag.Name)
+	cfg, err := config.NewConfig(cfgFile)
+	if err != nil {
+		log.Crit("failed to load config file", "config file", cfgFile, "error", err)
+	}
+
+	// Create db instance.
+	db, err := database.InitDB(cfg.DBConfig)
+	if err != nil {
+		log.Crit("failed to connect to db", "err", err)
+	}
+	defer func() {
+		if err = database.CloseDB(db); err != nil {
+			log.Error("failed to close database", "err", err)
+		}
+	}()
+
+	l1Client, err := rpc.Dial(cfg.L1Config.L1URL)
+	if err != nil {
+		log.Crit("failed to connect to l1 geth", "l1 geth url", cfg.L1Config.L1URL, "err", err)
+	}
+
+	l2Client, err := rpc.Dial(cfg.L2Config.L2URL)
+	if err != nil {
+		log.Crit("failed to connect to l2 geth", "l2 geth url", cfg.L2Config.L2URL, "err", err)
+	}
+
+	log.Info("start rescan", "layer", layer.String(), "from", from, "to", to, "dry run", dryRun)
+
+	contractCtl := controller.NewContractController(cfg, db, l1Client, l2Client)
+	mismatchCount, err := contractCtl.Rescan(ctx.Context, layer, from, to, dryRun, os.Stdout)
+	if err != nil {
+		return fmt.Errorf("rescan failed, layer: %s, from: %d, to: %d, err: %w", layer.String(), from, to, err)
+	}
+
+	log.Info("rescan finished", "layer", layer.String(), "from", from, "to", to, "dry run", dryRun, "mismatches", mismatchCount)
+	return nil
+}
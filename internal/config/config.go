@@ -11,8 +11,11 @@ import (
 	"github.com/scroll-tech/chain-monitor/internal/utils/database"
 )
 
-// Gateway address list.
+// Gateway address list, the legacy gateway config which is kept as the aliases of the gateway registry.
 type Gateway struct {
+	// eth
+	ETHGateway common.Address `json:"eth_gateway"`
+
 	// erc20
 	WETHGateway          common.Address `json:"weth_gateway"`
 	StandardERC20Gateway common.Address `json:"standard_erc20_gateway"`
@@ -32,6 +35,8 @@ type Gateway struct {
 type L1Contracts struct {
 	Gateway         `json:"l1_gateways"`
 	ScrollMessenger common.Address `json:"scroll_messenger"`
+	MessageQueue    common.Address `json:"message_queue"`
+	ScrollChain     common.Address `json:"scroll_chain"`
 }
 
 // L1Config l1 chain config.
@@ -55,6 +60,7 @@ type L2Config struct {
 	L2URL       string `json:"l2_url"`
 	Confirm     rpc.BlockNumber
 	L2Contracts *L2Contracts `json:"l2_contracts"`
+	StartNumber uint64       `json:"start_number"`
 }
 
 // SlackWebhookConfig slack webhook config.
@@ -64,12 +70,110 @@ type SlackWebhookConfig struct {
 	WorkerBufferSize int    `json:"worker_buffer_size"`
 }
 
+// AlertSinkConfig the config of an alert sink.
+type AlertSinkConfig struct {
+	// the name is referenced by the alert routes.
+	Name string `json:"name"`
+	// slack, webhook, stdout or file.
+	Type             string `json:"type"`
+	URL              string `json:"url,omitempty"`
+	FilePath         string `json:"file_path,omitempty"`
+	WorkerCount      int    `json:"worker_count,omitempty"`
+	WorkerBufferSize int    `json:"worker_buffer_size,omitempty"`
+}
+
+// AlertRouteConfig routes the alerts of the severities and categories to the sinks, empty severities or categories match all.
+type AlertRouteConfig struct {
+	Severities []string `json:"severities"`
+	Categories []string `json:"categories"`
+	Sinks      []string `json:"sinks"`
+}
+
+// AlertRoutingConfig the alert sinks and the routes of the alerts.
+// The slack webhook config is registered as the sink named slack, and all the alerts are sent to every sink if there is no route.
+type AlertRoutingConfig struct {
+	Sinks  []*AlertSinkConfig  `json:"sinks"`
+	Routes []*AlertRouteConfig `json:"routes"`
+}
+
+// AlertDedupConfig the deduplication of the identical alerts.
+type AlertDedupConfig struct {
+	// the identical alerts are suppressed within throttle_window seconds after notified, then notified again as still firing reminders.
+	ThrottleWindow uint64 `json:"throttle_window"`
+	// the firing alerts which are not raised again are reminded every reminder_interval seconds until they're resolved,
+	// it defaults to the throttle window.
+	ReminderInterval uint64 `json:"reminder_interval"`
+}
+
+// AlertOutboxConfig the outbox which persists the alerts of the slack and webhook sinks until they're delivered.
+type AlertOutboxConfig struct {
+	// an alert is moved to the dead letter after max_attempts failed deliveries.
+	MaxAttempts int `json:"max_attempts"`
+	// the interval in seconds to poll the outbox for the alerts to retry.
+	PollInterval uint64 `json:"poll_interval"`
+	// the backoff in seconds before the first retry, it's doubled for each retry up to max_retry_backoff.
+	RetryBackoff    uint64 `json:"retry_backoff"`
+	MaxRetryBackoff uint64 `json:"max_retry_backoff"`
+}
+
+// FailedRelayedMessageConfig the alert thresholds of the messages which failed to be relayed.
+type FailedRelayedMessageConfig struct {
+	// alert once a message failed to be relayed for max_failed_count times.
+	MaxFailedCount uint64 `json:"max_failed_count"`
+	// alert once a message failed to be relayed and hasn't been relayed successfully in relay_time_window seconds.
+	RelayTimeWindow uint64 `json:"relay_time_window"`
+}
+
+// MessageSLAConfig the thresholds of the messages which are sent but not relayed on the other layer yet.
+type MessageSLAConfig struct {
+	// alert once a l1 sent message isn't relayed on l2 in l1_to_l2_threshold seconds.
+	L1ToL2Threshold uint64 `json:"l1_to_l2_threshold"`
+	// alert once a l2 sent message isn't relayed on l1 in l2_to_l1_threshold seconds,
+	// it should cover the batch finalization delay since the message can't be relayed before it.
+	L2ToL1Threshold uint64 `json:"l2_to_l1_threshold"`
+}
+
+// TokenPair the l1 token address and its counterpart l2 token address.
+type TokenPair struct {
+	L1Token common.Address `json:"l1_token"`
+	L2Token common.Address `json:"l2_token"`
+	// the l1 address which holds the bridged tokens, it's resolved from the gateway of the l2 token if not set.
+	L1Custody common.Address `json:"l1_custody,omitempty"`
+}
+
+// TokenSupplyConfig the invariant check of the tokens held on l1 against the supplies of the bridged tokens on l2.
+type TokenSupplyConfig struct {
+	// the interval in seconds between the checks.
+	CheckInterval uint64 `json:"check_interval"`
+	// alert once the difference between the l1 custody balance and the l2 supply with the in flight amounts
+	// exceeds threshold_ratio of the l1 custody balance.
+	ThresholdRatio float64 `json:"threshold_ratio"`
+	// the cap of the threshold in tokens, which is scaled by the decimals of the l1 token.
+	MaxThreshold float64 `json:"max_threshold"`
+}
+
+// BridgedTokenConfig the check of the l2 bridged tokens minted or burned without a gateway event.
+type BridgedTokenConfig struct {
+	Enabled bool `json:"enabled"`
+	// the l2 bridged tokens to check besides the ones of the token pairs and the ones learned from the finalized deposits.
+	L2Tokens []common.Address `json:"l2_tokens"`
+}
+
 // Config chain-monitor main config.
 type Config struct {
-	L1Config    *L1Config           `json:"l1_config"`
-	L2Config    *L2Config           `json:"l2_config"`
-	AlertConfig *SlackWebhookConfig `json:"slack_webhook_config"`
-	DBConfig    *database.Config    `json:"db_config"`
+	L1Config                   *L1Config                   `json:"l1_config"`
+	L2Config                   *L2Config                   `json:"l2_config"`
+	AlertConfig                *SlackWebhookConfig         `json:"slack_webhook_config"`
+	AlertRoutingConfig         *AlertRoutingConfig         `json:"alert_routing_config"`
+	AlertDedupConfig           *AlertDedupConfig           `json:"alert_dedup_config"`
+	AlertOutboxConfig          *AlertOutboxConfig          `json:"alert_outbox_config"`
+	DBConfig                   *database.Config            `json:"db_config"`
+	FailedRelayedMessageConfig *FailedRelayedMessageConfig `json:"failed_relayed_message_config"`
+	MessageSLAConfig           *MessageSLAConfig           `json:"message_sla_config"`
+	Gateways                   []*GatewayConfig            `json:"gateways"`
+	TokenPairs                 []*TokenPair                `json:"token_pairs"`
+	TokenSupplyConfig          *TokenSupplyConfig          `json:"token_supply_config"`
+	BridgedTokenConfig         *BridgedTokenConfig         `json:"bridged_token_config"`
 }
 
 // NewConfig return a unmarshalled config instance.
@@ -83,5 +187,8 @@ func NewConfig(file string) (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err = cfg.validateGatewayRegistry(); err != nil {
+		return nil, err
+	}
 	return &cfg, nil
 }
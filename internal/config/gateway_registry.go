@@ -0,0 +1,168 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/scroll-tech/go-ethereum/common"
+)
+
+// The layers of the gateway registry.
+const (
+	GatewayLayerL1 = "l1"
+	GatewayLayerL2 = "l2"
+)
+
+// The token standards and the abi kinds of the gateway registry.
+const (
+	GatewayKindETH     = "eth"
+	GatewayKindERC20   = "erc20"
+	GatewayKindERC721  = "erc721"
+	GatewayKindERC1155 = "erc1155"
+)
+
+// GatewayConfig a gateway of the gateway registry.
+type GatewayConfig struct {
+	// the name pairs the gateway with its counterpart gateway, which has the same name on the other layer.
+	Name string `json:"name"`
+	// the layer of the gateway, l1 or l2.
+	Layer   string         `json:"layer"`
+	Address common.Address `json:"address"`
+	// the standard of the bridged tokens, one of eth, erc20, erc721 and erc1155.
+	TokenStandard string `json:"token_standard"`
+	// the abi of the gateway events, which selects the filter and the unmarshaler of the events. It's one of the token
+	// standards, and it's the token standard if not set.
+	ABIKind string `json:"abi_kind,omitempty"`
+	// the block number from which the gateway events are watched.
+	ActivationBlock uint64 `json:"activation_block,omitempty"`
+	// the l1 gateway doesn't hold the bridged tokens, e.g. the weth gateway unwraps them to eth held by the messenger.
+	NonCustodial bool `json:"non_custodial,omitempty"`
+}
+
+// GatewayPair the gateways of both layers with the same name.
+type GatewayPair struct {
+	L1 *GatewayConfig
+	L2 *GatewayConfig
+}
+
+// aliases returns the gateways configured by the legacy fields, which are named after the fields.
+func (g *Gateway) aliases(layer string) []*GatewayConfig {
+	aliases := []*GatewayConfig{
+		{Name: "eth_gateway", Address: g.ETHGateway, TokenStandard: GatewayKindETH},
+		{Name: "weth_gateway", Address: g.WETHGateway, TokenStandard: GatewayKindERC20, NonCustodial: true},
+		{Name: "standard_erc20_gateway", Address: g.StandardERC20Gateway, TokenStandard: GatewayKindERC20},
+		{Name: "custom_erc20_gateway", Address: g.CustomERC20Gateway, TokenStandard: GatewayKindERC20},
+		{Name: "dai_gateway", Address: g.DAIGateway, TokenStandard: GatewayKindERC20},
+		{Name: "usdc_gateway", Address: g.USDCGateway, TokenStandard: GatewayKindERC20},
+		{Name: "lido_gateway", Address: g.LIDOGateway, TokenStandard: GatewayKindERC20},
+		{Name: "erc721_gateway", Address: g.ERC721Gateway, TokenStandard: GatewayKindERC721},
+		{Name: "erc1155_gateway", Address: g.ERC1155Gateway, TokenStandard: GatewayKindERC1155},
+	}
+
+	var gateways []*GatewayConfig
+	for _, alias := range aliases {
+		if alias.Address == (common.Address{}) {
+			continue
+		}
+		alias.Layer = layer
+		gateways = append(gateways, alias)
+	}
+	return gateways
+}
+
+// GatewayRegistry returns the gateways of the registry, and the gateways configured by the legacy l1_gateways and
+// l2_gateways fields which are kept as aliases. The registry entry takes precedence over the legacy field of the same
+// layer with the same name or address. The abi kinds which aren't set are filled with the token standards.
+func (c *Config) GatewayRegistry() []*GatewayConfig {
+	var gateways []*GatewayConfig
+	registered := make(map[string]bool)
+	register := func(gateway *GatewayConfig) {
+		nameKey, addressKey := gateway.Layer+":"+gateway.Name, gateway.Layer+":"+gateway.Address.Hex()
+		if registered[nameKey] || registered[addressKey] {
+			return
+		}
+		registered[nameKey], registered[addressKey] = true, true
+
+		entry := *gateway
+		if entry.ABIKind == "" {
+			entry.ABIKind = entry.TokenStandard
+		}
+		gateways = append(gateways, &entry)
+	}
+
+	for _, gateway := range c.Gateways {
+		register(gateway)
+	}
+	if c.L1Config != nil && c.L1Config.L1Contracts != nil {
+		for _, gateway := range c.L1Config.L1Contracts.Gateway.aliases(GatewayLayerL1) {
+			register(gateway)
+		}
+	}
+	if c.L2Config != nil && c.L2Config.L2Contracts != nil {
+		for _, gateway := range c.L2Config.L2Contracts.Gateway.aliases(GatewayLayerL2) {
+			register(gateway)
+		}
+	}
+	return gateways
+}
+
+// GatewayPairs returns the gateways of the registry paired by name, the gateways without counterparts are skipped.
+func (c *Config) GatewayPairs() []GatewayPair {
+	l2Gateways := make(map[string]*GatewayConfig)
+	for _, gateway := range c.GatewayRegistry() {
+		if gateway.Layer == GatewayLayerL2 {
+			l2Gateways[gateway.Name] = gateway
+		}
+	}
+
+	var pairs []GatewayPair
+	for _, gateway := range c.GatewayRegistry() {
+		if gateway.Layer != GatewayLayerL1 {
+			continue
+		}
+		if l2Gateway, ok := l2Gateways[gateway.Name]; ok {
+			pairs = append(pairs, GatewayPair{L1: gateway, L2: l2Gateway})
+		}
+	}
+	return pairs
+}
+
+// validateGatewayRegistry checks the entries of the gateway registry.
+func (c *Config) validateGatewayRegistry() error {
+	names := make(map[string]bool)
+	addresses := make(map[string]bool)
+	for _, gateway := range c.Gateways {
+		if gateway.Name == "" {
+			return fmt.Errorf("gateway name is empty, address: %s", gateway.Address.Hex())
+		}
+		if gateway.Layer != GatewayLayerL1 && gateway.Layer != GatewayLayerL2 {
+			return fmt.Errorf("gateway %s has invalid layer: %s", gateway.Name, gateway.Layer)
+		}
+		if gateway.Address == (common.Address{}) {
+			return fmt.Errorf("gateway %s has empty address", gateway.Name)
+		}
+		if !isGatewayKind(gateway.TokenStandard) {
+			return fmt.Errorf("gateway %s has invalid token standard: %s", gateway.Name, gateway.TokenStandard)
+		}
+		if gateway.ABIKind != "" && !isGatewayKind(gateway.ABIKind) {
+			return fmt.Errorf("gateway %s has invalid abi kind: %s", gateway.Name, gateway.ABIKind)
+		}
+
+		if names[gateway.Layer+":"+gateway.Name] {
+			return fmt.Errorf("gateway %s is duplicated on %s", gateway.Name, gateway.Layer)
+		}
+		names[gateway.Layer+":"+gateway.Name] = true
+		if addresses[gateway.Layer+":"+gateway.Address.Hex()] {
+			return fmt.Errorf("gateway address %s is duplicated on %s", gateway.Address.Hex(), gateway.Layer)
+		}
+		addresses[gateway.Layer+":"+gateway.Address.Hex()] = true
+	}
+	return nil
+}
+
+func isGatewayKind(kind string) bool {
+	switch kind {
+	case GatewayKindETH, GatewayKindERC20, GatewayKindERC721, GatewayKindERC1155:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,194 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGatewayRegistry(t *testing.T) {
+	l1ETHGateway, l2ETHGateway := common.HexToAddress("0x1001"), common.HexToAddress("0x2001")
+	l1DAIGateway, l2DAIGateway := common.HexToAddress("0x1002"), common.HexToAddress("0x2002")
+	l1USDTGateway := common.HexToAddress("0x1003")
+	legacyConfig := func() *Config {
+		return &Config{
+			L1Config: &L1Config{L1Contracts: &L1Contracts{Gateway: Gateway{ETHGateway: l1ETHGateway, DAIGateway: l1DAIGateway}}},
+			L2Config: &L2Config{L2Contracts: &L2Contracts{Gateway: Gateway{ETHGateway: l2ETHGateway, DAIGateway: l2DAIGateway}}},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		gateways     []*GatewayConfig
+		wantGateways []GatewayConfig
+	}{
+		{
+			name: "aliases",
+			wantGateways: []GatewayConfig{
+				{Name: "eth_gateway", Layer: GatewayLayerL1, Address: l1ETHGateway, TokenStandard: GatewayKindETH, ABIKind: GatewayKindETH},
+				{Name: "dai_gateway", Layer: GatewayLayerL1, Address: l1DAIGateway, TokenStandard: GatewayKindERC20, ABIKind: GatewayKindERC20},
+				{Name: "eth_gateway", Layer: GatewayLayerL2, Address: l2ETHGateway, TokenStandard: GatewayKindETH, ABIKind: GatewayKindETH},
+				{Name: "dai_gateway", Layer: GatewayLayerL2, Address: l2DAIGateway, TokenStandard: GatewayKindERC20, ABIKind: GatewayKindERC20},
+			},
+		},
+		{
+			// the registry entry moves the l1 dai gateway, the legacy field of the same name is ignored.
+			name: "precedenceByName",
+			gateways: []*GatewayConfig{
+				{Name: "dai_gateway", Layer: GatewayLayerL1, Address: common.HexToAddress("0x1004"), TokenStandard: GatewayKindERC20, ActivationBlock: 100},
+			},
+			wantGateways: []GatewayConfig{
+				{Name: "dai_gateway", Layer: GatewayLayerL1, Address: common.HexToAddress("0x1004"), TokenStandard: GatewayKindERC20, ABIKind: GatewayKindERC20, ActivationBlock: 100},
+				{Name: "eth_gateway", Layer: GatewayLayerL1, Address: l1ETHGateway, TokenStandard: GatewayKindETH, ABIKind: GatewayKindETH},
+				{Name: "eth_gateway", Layer: GatewayLayerL2, Address: l2ETHGateway, TokenStandard: GatewayKindETH, ABIKind: GatewayKindETH},
+				{Name: "dai_gateway", Layer: GatewayLayerL2, Address: l2DAIGateway, TokenStandard: GatewayKindERC20, ABIKind: GatewayKindERC20},
+			},
+		},
+		{
+			// the registry entry renames the l2 dai gateway, the legacy field of the same address is ignored.
+			name: "precedenceByAddress",
+			gateways: []*GatewayConfig{
+				{Name: "stablecoin_gateway", Layer: GatewayLayerL2, Address: l2DAIGateway, TokenStandard: GatewayKindERC20},
+			},
+			wantGateways: []GatewayConfig{
+				{Name: "stablecoin_gateway", Layer: GatewayLayerL2, Address: l2DAIGateway, TokenStandard: GatewayKindERC20, ABIKind: GatewayKindERC20},
+				{Name: "eth_gateway", Layer: GatewayLayerL1, Address: l1ETHGateway, TokenStandard: GatewayKindETH, ABIKind: GatewayKindETH},
+				{Name: "dai_gateway", Layer: GatewayLayerL1, Address: l1DAIGateway, TokenStandard: GatewayKindERC20, ABIKind: GatewayKindERC20},
+				{Name: "eth_gateway", Layer: GatewayLayerL2, Address: l2ETHGateway, TokenStandard: GatewayKindETH, ABIKind: GatewayKindETH},
+			},
+		},
+		{
+			// the abi kind of the registry entry is kept, the other abi kinds are the token standards.
+			name: "abiKind",
+			gateways: []*GatewayConfig{
+				{Name: "wrapped_eth_gateway", Layer: GatewayLayerL1, Address: common.HexToAddress("0x1005"), TokenStandard: GatewayKindERC20, ABIKind: GatewayKindETH},
+			},
+			wantGateways: []GatewayConfig{
+				{Name: "wrapped_eth_gateway", Layer: GatewayLayerL1, Address: common.HexToAddress("0x1005"), TokenStandard: GatewayKindERC20, ABIKind: GatewayKindETH},
+				{Name: "eth_gateway", Layer: GatewayLayerL1, Address: l1ETHGateway, TokenStandard: GatewayKindETH, ABIKind: GatewayKindETH},
+				{Name: "dai_gateway", Layer: GatewayLayerL1, Address: l1DAIGateway, TokenStandard: GatewayKindERC20, ABIKind: GatewayKindERC20},
+				{Name: "eth_gateway", Layer: GatewayLayerL2, Address: l2ETHGateway, TokenStandard: GatewayKindETH, ABIKind: GatewayKindETH},
+				{Name: "dai_gateway", Layer: GatewayLayerL2, Address: l2DAIGateway, TokenStandard: GatewayKindERC20, ABIKind: GatewayKindERC20},
+			},
+		},
+		{
+			// the legacy field of the other layer with the same name isn't overridden.
+			name: "otherLayer",
+			gateways: []*GatewayConfig{
+				{Name: "usdt_gateway", Layer: GatewayLayerL1, Address: l1USDTGateway, TokenStandard: GatewayKindERC20},
+				{Name: "eth_gateway", Layer: GatewayLayerL1, Address: l1ETHGateway, TokenStandard: GatewayKindETH, ActivationBlock: 10},
+			},
+			wantGateways: []GatewayConfig{
+				{Name: "usdt_gateway", Layer: GatewayLayerL1, Address: l1USDTGateway, TokenStandard: GatewayKindERC20, ABIKind: GatewayKindERC20},
+				{Name: "eth_gateway", Layer: GatewayLayerL1, Address: l1ETHGateway, TokenStandard: GatewayKindETH, ABIKind: GatewayKindETH, ActivationBlock: 10},
+				{Name: "dai_gateway", Layer: GatewayLayerL1, Address: l1DAIGateway, TokenStandard: GatewayKindERC20, ABIKind: GatewayKindERC20},
+				{Name: "eth_gateway", Layer: GatewayLayerL2, Address: l2ETHGateway, TokenStandard: GatewayKindETH, ABIKind: GatewayKindETH},
+				{Name: "dai_gateway", Layer: GatewayLayerL2, Address: l2DAIGateway, TokenStandard: GatewayKindERC20, ABIKind: GatewayKindERC20},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := legacyConfig()
+			cfg.Gateways = test.gateways
+
+			var gateways []GatewayConfig
+			for _, gateway := range cfg.GatewayRegistry() {
+				gateways = append(gateways, *gateway)
+			}
+			assert.Equal(t, test.wantGateways, gateways)
+		})
+	}
+}
+
+func TestGatewayPairs(t *testing.T) {
+	cfg := &Config{
+		L1Config: &L1Config{L1Contracts: &L1Contracts{Gateway: Gateway{ETHGateway: common.HexToAddress("0x1001"), ERC721Gateway: common.HexToAddress("0x1002")}}},
+		L2Config: &L2Config{L2Contracts: &L2Contracts{Gateway: Gateway{ETHGateway: common.HexToAddress("0x2001")}}},
+		Gateways: []*GatewayConfig{
+			{Name: "usdt_gateway", Layer: GatewayLayerL1, Address: common.HexToAddress("0x1003"), TokenStandard: GatewayKindERC20},
+			{Name: "usdt_gateway", Layer: GatewayLayerL2, Address: common.HexToAddress("0x2003"), TokenStandard: GatewayKindERC20},
+			// the gateway without the counterpart gateway is skipped.
+			{Name: "reverse_gateway", Layer: GatewayLayerL2, Address: common.HexToAddress("0x2004"), TokenStandard: GatewayKindERC20},
+		},
+	}
+
+	var pairs [][2]common.Address
+	for _, pair := range cfg.GatewayPairs() {
+		assert.Equal(t, pair.L1.Name, pair.L2.Name)
+		pairs = append(pairs, [2]common.Address{pair.L1.Address, pair.L2.Address})
+	}
+	assert.Equal(t, [][2]common.Address{
+		{common.HexToAddress("0x1003"), common.HexToAddress("0x2003")},
+		{common.HexToAddress("0x1001"), common.HexToAddress("0x2001")},
+	}, pairs)
+}
+
+func TestValidateGatewayRegistry(t *testing.T) {
+	gateway := func(name, layer string, address string, tokenStandard string) *GatewayConfig {
+		return &GatewayConfig{Name: name, Layer: layer, Address: common.HexToAddress(address), TokenStandard: tokenStandard}
+	}
+
+	tests := []struct {
+		name     string
+		gateways []*GatewayConfig
+		wantErr  string
+	}{
+		{"empty", nil, ""},
+		{
+			name: "valid",
+			gateways: []*GatewayConfig{
+				gateway("usdt_gateway", GatewayLayerL1, "0x1001", GatewayKindERC20),
+				// the same name and address are allowed on the other layer.
+				gateway("usdt_gateway", GatewayLayerL2, "0x1001", GatewayKindERC20),
+				gateway("nft_gateway", GatewayLayerL2, "0x2002", GatewayKindERC1155),
+			},
+		},
+		{"emptyName", []*GatewayConfig{gateway("", GatewayLayerL1, "0x1001", GatewayKindERC20)}, "gateway name is empty"},
+		{"invalidLayer", []*GatewayConfig{gateway("usdt_gateway", "l3", "0x1001", GatewayKindERC20)}, "invalid layer: l3"},
+		{"emptyAddress", []*GatewayConfig{gateway("usdt_gateway", GatewayLayerL1, "0x0", GatewayKindERC20)}, "has empty address"},
+		{"invalidTokenStandard", []*GatewayConfig{gateway("usdt_gateway", GatewayLayerL1, "0x1001", "erc777")}, "invalid token standard: erc777"},
+		{
+			name: "abiKind",
+			gateways: []*GatewayConfig{
+				{Name: "wrapped_eth_gateway", Layer: GatewayLayerL1, Address: common.HexToAddress("0x1001"), TokenStandard: GatewayKindERC20, ABIKind: GatewayKindETH},
+			},
+		},
+		{
+			name: "invalidABIKind",
+			gateways: []*GatewayConfig{
+				{Name: "usdt_gateway", Layer: GatewayLayerL1, Address: common.HexToAddress("0x1001"), TokenStandard: GatewayKindERC20, ABIKind: "erc777"},
+			},
+			wantErr: "invalid abi kind: erc777",
+		},
+		{
+			name: "duplicatedName",
+			gateways: []*GatewayConfig{
+				gateway("usdt_gateway", GatewayLayerL1, "0x1001", GatewayKindERC20),
+				gateway("usdt_gateway", GatewayLayerL1, "0x1002", GatewayKindERC20),
+			},
+			wantErr: "gateway usdt_gateway is duplicated on l1",
+		},
+		{
+			name: "duplicatedAddress",
+			gateways: []*GatewayConfig{
+				gateway("usdt_gateway", GatewayLayerL2, "0x2001", GatewayKindERC20),
+				gateway("usdc_gateway", GatewayLayerL2, "0x2001", GatewayKindERC20),
+			},
+			wantErr: "is duplicated on l2",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := (&Config{Gateways: test.gateways}).validateGatewayRegistry()
+			if test.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"github.com/scroll-tech/chain-monitor/internal/config"
+	"github.com/scroll-tech/chain-monitor/internal/logic/alert"
+)
+
+// AlertController the controller of the alert sinks
+type AlertController struct {
+	alertManager *alert.Manager
+}
+
+// NewAlertController create AlertController
+func NewAlertController(ctx context.Context, conf *config.Config, db *gorm.DB) *AlertController {
+	alertManager, err := alert.NewManager(ctx, conf, db)
+	if err != nil {
+		log.Crit("create alert manager failure", "error", err)
+		return nil
+	}
+	alert.Use(alertManager)
+
+	return &AlertController{
+		alertManager: alertManager,
+	}
+}
+
+// Start the alert sinks
+func (a *AlertController) Start() {
+	log.Info("alert controller start successful")
+
+	a.alertManager.Start()
+}
+
+// Stop the alert sinks
+func (a *AlertController) Stop() {
+	a.alertManager.Stop()
+}
@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"github.com/scroll-tech/chain-monitor/internal/config"
+	"github.com/scroll-tech/chain-monitor/internal/logic/batch"
+)
+
+// BatchController checks the batches committed and finalized on the l1 scroll chain.
+type BatchController struct {
+	batchLogic *batch.LogicBatch
+
+	stopBatchChan chan struct{}
+
+	batchControllerRunningTotal prometheus.Counter
+}
+
+// NewBatchController creates a new BatchController object.
+func NewBatchController(cfg *config.Config, db *gorm.DB) *BatchController {
+	return &BatchController{
+		batchLogic:    batch.NewLogicBatch(cfg, db),
+		stopBatchChan: make(chan struct{}),
+		batchControllerRunningTotal: promauto.With(prometheus.DefaultRegisterer).NewCounter(prometheus.CounterOpts{
+			Name: "batch_check_controller_running_total",
+			Help: "The total number of batch controller running.",
+		}),
+	}
+}
+
+// Watch starts checking the committed and finalized batches.
+func (c *BatchController) Watch(ctx context.Context) {
+	go c.watcherStart(ctx)
+}
+
+// Stop the batch controller
+func (c *BatchController) Stop() {
+	c.stopBatchChan <- struct{}{}
+}
+
+func (c *BatchController) watcherStart(ctx context.Context) {
+	log.Info("batch controller start successful")
+
+	for {
+		select {
+		case <-ctx.Done():
+			if ctx.Err() != nil {
+				log.Error("BatchController watch canceled with error", "error", ctx.Err())
+			}
+			return
+		case <-c.stopBatchChan:
+			log.Info("BatchController the run loop exit")
+			return
+		default:
+		}
+
+		c.batchControllerRunningTotal.Inc()
+
+		c.batchLogic.CheckFinalizedWithdrawRoots(ctx)
+		c.batchLogic.CheckBatchStatuses(ctx)
+
+		// To prevent frequent database access, obtaining empty values.
+		time.Sleep(10 * time.Second)
+	}
+}
@@ -2,7 +2,9 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/big"
 	"sync"
 	"time"
 
@@ -16,10 +18,14 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/scroll-tech/chain-monitor/internal/config"
+	"github.com/scroll-tech/chain-monitor/internal/logic/alert"
 	"github.com/scroll-tech/chain-monitor/internal/logic/assembler"
+	"github.com/scroll-tech/chain-monitor/internal/logic/batch"
 	"github.com/scroll-tech/chain-monitor/internal/logic/contracts"
 	"github.com/scroll-tech/chain-monitor/internal/logic/events"
 	messagematch "github.com/scroll-tech/chain-monitor/internal/logic/message_match"
+	messagequeue "github.com/scroll-tech/chain-monitor/internal/logic/message_queue"
+	"github.com/scroll-tech/chain-monitor/internal/logic/reorg"
 	"github.com/scroll-tech/chain-monitor/internal/orm"
 	"github.com/scroll-tech/chain-monitor/internal/types"
 	"github.com/scroll-tech/chain-monitor/internal/utils"
@@ -35,7 +41,11 @@ type ContractController struct {
 	eventGatherLogic      *events.EventGather
 	contractsLogic        *contracts.Contracts
 	messageMatchAssembler *assembler.MessageMatchAssembler
+	bridgedTokenRegistry  *assembler.BridgedTokenRegistry
 	messageMatchLogic     *messagematch.LogicMessageMatch
+	batchLogic            *batch.LogicBatch
+	messageQueueLogic     *messagequeue.LogicMessageQueue
+	reorgLogic            *reorg.LogicReorg
 
 	stopL1ContractChan  chan struct{}
 	stopL2ContractChan  chan struct{}
@@ -49,27 +59,35 @@ type ContractController struct {
 	contractControllerGatewayCheckFailureTotal               *prometheus.CounterVec
 	contractControllerUpdateOrInsertMessageMatchFailureTotal *prometheus.CounterVec
 	contractControllerCheckWithdrawRootFailureTotal          *prometheus.CounterVec
+	contractControllerDecodeCommitBatchFailureTotal          prometheus.Counter
 
 	db                       *gorm.DB
 	messengerMessageMatchOrm *orm.MessengerMessageMatch
 	gatewayMessageMatchOrm   *orm.GatewayMessageMatch
+	l1ETHRefundOrm           *orm.L1ETHRefund
 }
 
 // NewContractController creates a new ContractController object.
 func NewContractController(conf *config.Config, db *gorm.DB, l1Client, l2Client *rpc.Client) *ContractController {
+	bridgedTokenRegistry := assembler.NewBridgedTokenRegistry(conf, db)
 	c := &ContractController{
+		bridgedTokenRegistry:     bridgedTokenRegistry,
 		l1Client:                 l1Client,
 		l2Client:                 l2Client,
 		conf:                     conf,
 		eventGatherLogic:         events.NewEventGather(),
-		contractsLogic:           contracts.NewContracts(ethclient.NewClient(l1Client), ethclient.NewClient(l2Client)),
-		messageMatchAssembler:    assembler.NewMessageMatchAssembler(db),
+		contractsLogic:           contracts.NewContracts(l1Client, l2Client),
+		messageMatchAssembler:    assembler.NewMessageMatchAssembler(conf, db, bridgedTokenRegistry),
 		messageMatchLogic:        messagematch.NewMessageMatchLogic(conf, db),
+		batchLogic:               batch.NewLogicBatch(conf, db),
+		messageQueueLogic:        messagequeue.NewLogicMessageQueue(conf, db),
+		reorgLogic:               reorg.NewLogicReorg(db),
 		stopL1ContractChan:       make(chan struct{}),
 		stopL2ContractChan:       make(chan struct{}),
 		db:                       db,
 		messengerMessageMatchOrm: orm.NewMessengerMessageMatch(db),
 		gatewayMessageMatchOrm:   orm.NewGatewayMessageMatch(db),
+		l1ETHRefundOrm:           orm.NewL1ETHRefund(db),
 	}
 
 	if err := c.contractsLogic.Register(c.conf); err != nil {
@@ -77,11 +95,12 @@ func NewContractController(conf *config.Config, db *gorm.DB, l1Client, l2Client
 		return nil
 	}
 
-	// eth balance is checked by other means.
+	c.l1EventCategoryList = append(c.l1EventCategoryList, types.ETHEventCategory)
 	c.l1EventCategoryList = append(c.l1EventCategoryList, types.ERC20EventCategory)
 	c.l1EventCategoryList = append(c.l1EventCategoryList, types.ERC721EventCategory)
 	c.l1EventCategoryList = append(c.l1EventCategoryList, types.ERC1155EventCategory)
 
+	c.l2EventCategoryList = append(c.l2EventCategoryList, types.ETHEventCategory)
 	c.l2EventCategoryList = append(c.l2EventCategoryList, types.ERC20EventCategory)
 	c.l2EventCategoryList = append(c.l2EventCategoryList, types.ERC721EventCategory)
 	c.l2EventCategoryList = append(c.l2EventCategoryList, types.ERC1155EventCategory)
@@ -115,12 +134,23 @@ func NewContractController(conf *config.Config, db *gorm.DB, l1Client, l2Client
 		Name: "contract_controller_check_l2_withdraw_root_failure_total",
 		Help: "The total number of controller check l2 withdraw root failure total.",
 	}, []string{"layer"})
+	c.contractControllerDecodeCommitBatchFailureTotal = promauto.With(reg).NewCounter(prometheus.CounterOpts{
+		Name: "contract_controller_decode_commit_batch_failure_total",
+		Help: "The total number of controller decode commit batch calldata failure total.",
+	})
 
 	return c
 }
 
 // Watch is an exported function that starts watching the Layer 1 and Layer 2 events, which include gateways events, transfer events, and messenger events.
 func (c *ContractController) Watch(ctx context.Context) {
+	if c.bridgedTokenRegistry != nil {
+		if err := c.bridgedTokenRegistry.Load(ctx); err != nil {
+			log.Crit("load bridged token registry failure", "error", err)
+			return
+		}
+	}
+
 	go c.watcherStart(ctx, ethclient.NewClient(c.l1Client), types.Layer1, c.conf.L1Config.Confirm, 2)
 	go c.watcherStart(ctx, ethclient.NewClient(c.l2Client), types.Layer2, c.conf.L2Config.Confirm, 2)
 }
@@ -143,6 +173,11 @@ func (c *ContractController) watcherStart(ctx context.Context, client *ethclient
 	log.Info("Block process height in db", "layer", layer, "block number", blockNumberInDB)
 	start := blockNumberInDB + 1
 
+	rpcClient := c.l1Client
+	if layer == types.Layer2 {
+		rpcClient = c.l2Client
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -160,6 +195,17 @@ func (c *ContractController) watcherStart(ctx context.Context, client *ethclient
 
 		c.contractControllerRunningTotal.WithLabelValues(layer.String()).Inc()
 
+		// check whether the processed blocks are reorged, re-ingest from the fork point if so.
+		rollbackBlockNumber, reorged, reorgErr := c.reorgLogic.DetectAndRollback(ctx, rpcClient, layer)
+		if reorgErr != nil {
+			log.Error("ContractController.watcherStart detect reorg failed", "layer", layer.String(), "err", reorgErr)
+			time.Sleep(time.Second)
+			continue
+		}
+		if reorged {
+			start = rollbackBlockNumber
+		}
+
 		// 2. get latest chain confirmation number
 		confirmationNumber, latestConfirmedBlockErr := utils.GetLatestConfirmedBlockNumber(ctx, client, confirmation)
 		if latestConfirmedBlockErr != nil {
@@ -184,6 +230,10 @@ func (c *ContractController) watcherStart(ctx context.Context, client *ethclient
 		var mux sync.Mutex
 		var gatewayMessageMatches []orm.GatewayMessageMatch
 		var messengerMessageMatches []orm.MessengerMessageMatch
+		var failedRelayedMessages []orm.FailedRelayedMessageEvent
+		var l1ETHRefunds []orm.L1ETHRefund
+		var batchEvents []*events.BatchEventUnmarshaler
+		var messageQueueEvents []*events.MessageQueueEventUnmarshaler
 		for i := 0; i < concurrency; i++ {
 			if loopStart > confirmationNumber {
 				log.Info("Watcher loop start block number > ConfirmationNumber",
@@ -210,15 +260,31 @@ func (c *ContractController) watcherStart(ctx context.Context, client *ethclient
 			eg.Go(func() error {
 				var retGatewayMessageMatches []orm.GatewayMessageMatch
 				var retMessengerMessageMatches []orm.MessengerMessageMatch
+				var retFailedRelayedMessages []orm.FailedRelayedMessageEvent
+				var retL1ETHRefunds []orm.L1ETHRefund
+				var retBatchEvents []*events.BatchEventUnmarshaler
+				var retMessageQueueEvents []*events.MessageQueueEventUnmarshaler
 				var watchErr error
 				switch layer {
 				case types.Layer1:
-					retGatewayMessageMatches, retMessengerMessageMatches, watchErr = c.l1Watch(ctx, currentStart, currentEnd)
+					retGatewayMessageMatches, retMessengerMessageMatches, retFailedRelayedMessages, watchErr = c.l1Watch(ctx, currentStart, currentEnd)
+					if watchErr != nil {
+						return watchErr
+					}
+					retL1ETHRefunds, watchErr = c.l1ETHRefundWatch(ctx, currentStart, currentEnd)
+					if watchErr != nil {
+						return watchErr
+					}
+					retBatchEvents, watchErr = c.l1BatchWatch(ctx, currentStart, currentEnd)
+					if watchErr != nil {
+						return watchErr
+					}
+					retMessageQueueEvents, watchErr = c.l1MessageQueueWatch(ctx, currentStart, currentEnd)
 					if watchErr != nil {
 						return watchErr
 					}
 				case types.Layer2:
-					retGatewayMessageMatches, retMessengerMessageMatches, watchErr = c.l2Watch(ctx, currentStart, currentEnd)
+					retGatewayMessageMatches, retMessengerMessageMatches, retFailedRelayedMessages, watchErr = c.l2Watch(ctx, currentStart, currentEnd)
 					if watchErr != nil {
 						return watchErr
 					}
@@ -226,6 +292,10 @@ func (c *ContractController) watcherStart(ctx context.Context, client *ethclient
 				mux.Lock()
 				gatewayMessageMatches = append(gatewayMessageMatches, retGatewayMessageMatches...)
 				messengerMessageMatches = append(messengerMessageMatches, retMessengerMessageMatches...)
+				failedRelayedMessages = append(failedRelayedMessages, retFailedRelayedMessages...)
+				l1ETHRefunds = append(l1ETHRefunds, retL1ETHRefunds...)
+				batchEvents = append(batchEvents, retBatchEvents...)
+				messageQueueEvents = append(messageQueueEvents, retMessageQueueEvents...)
 				mux.Unlock()
 				return nil
 			})
@@ -237,36 +307,97 @@ func (c *ContractController) watcherStart(ctx context.Context, client *ethclient
 		}
 
 		if loopEnd >= start {
-			var lastMessage *orm.MessengerMessageMatch
+			// The check violations are stored with the blocks and alerted once they are stored, the watcher goes on with the
+			// next blocks. Only the failures to fetch the checked data are retried.
+			var alerts []alert.Alert
+			var withdrawRootResult *assembler.WithdrawRootCheckResult
 			if layer == types.Layer2 {
 				var checkErr error
-				lastMessage, checkErr = c.messageMatchAssembler.L2WithdrawRootsValidator(ctx, start, loopEnd, c.l2Client, c.conf.L2Config.L2Contracts.MessageQueue)
+				withdrawRootResult, checkErr = c.messageMatchAssembler.L2WithdrawRootsValidator(ctx, start, loopEnd, c.l2Client, c.conf.L2Config.L2Contracts.MessageQueue)
 				if checkErr != nil {
-					c.contractControllerCheckWithdrawRootFailureTotal.WithLabelValues(types.Layer2.String()).Inc()
 					log.Error("check withdraw roots failed", "layer", types.Layer2, "start", start, "end", loopEnd, "error", checkErr)
+					time.Sleep(time.Second)
+					continue
+				}
+				if len(withdrawRootResult.FailedMessages) > 0 {
+					c.contractControllerCheckWithdrawRootFailureTotal.WithLabelValues(types.Layer2.String()).Inc()
+				}
+				alerts = append(alerts, withdrawRootResult.Alerts...)
+			}
+
+			if layer == types.Layer1 {
+				nonceAlerts, nonceCheckErr := c.messageQueueLogic.CheckL1SentMessageNonces(ctx, messengerMessageMatches, messageQueueEvents)
+				if nonceCheckErr != nil {
+					log.Error("check l1 sent message nonces failed", "start", start, "end", loopEnd, "error", nonceCheckErr)
+					time.Sleep(time.Second)
+					continue
+				}
+				queueAlerts, queueCheckErr := c.messageQueueLogic.CheckDequeuedMessages(ctx, messageQueueEvents)
+				if queueCheckErr != nil {
+					log.Error("check l1 messages dequeued by the committed batches failed", "start", start, "end", loopEnd, "error", queueCheckErr)
+					time.Sleep(time.Second)
 					continue
 				}
+				alerts = append(alerts, nonceAlerts...)
+				alerts = append(alerts, queueAlerts...)
+			}
+
+			endHeader, headerErr := client.HeaderByNumber(ctx, new(big.Int).SetUint64(loopEnd))
+			if headerErr != nil {
+				log.Error("get end block header failed", "layer", layer, "end", loopEnd, "error", headerErr)
+				time.Sleep(time.Second)
+				continue
 			}
 
 			// Update last valid message's withdraw trie proof and block status after check.
 			updateErr := c.db.Transaction(func(tx *gorm.DB) error {
 				if layer == types.Layer2 {
-					if updateMsgProofErr := c.messengerMessageMatchOrm.UpdateMsgProofAndStatus(ctx, lastMessage, tx); updateMsgProofErr != nil {
-						return fmt.Errorf("insert or update msg proof and status failed, err: %w, message: %+v", updateMsgProofErr, lastMessage)
+					if updateMsgProofErr := c.messengerMessageMatchOrm.UpdateMsgProofAndStatus(ctx, withdrawRootResult.LastMessage, tx); updateMsgProofErr != nil {
+						return fmt.Errorf("insert or update msg proof and status failed, err: %w, message: %+v", updateMsgProofErr, withdrawRootResult.LastMessage)
+					}
+					for _, failedMessage := range withdrawRootResult.FailedMessages {
+						if updateMsgStatusErr := c.messengerMessageMatchOrm.UpdateMsgProofAndStatus(ctx, failedMessage, tx); updateMsgStatusErr != nil {
+							return fmt.Errorf("update failed withdraw root status failed, err: %w, message: %+v", updateMsgStatusErr, failedMessage)
+						}
 					}
 				}
 
-				if insertEventErr := c.messageMatchLogic.InsertOrUpdateMessageMatches(ctx, layer, gatewayMessageMatches, messengerMessageMatches); insertEventErr != nil {
+				if insertEventErr := c.messageMatchLogic.InsertOrUpdateMessageMatches(ctx, layer, gatewayMessageMatches, messengerMessageMatches, failedRelayedMessages, tx); insertEventErr != nil {
 					c.contractControllerUpdateOrInsertMessageMatchFailureTotal.WithLabelValues(layer.String()).Inc()
 					log.Error("insert message events failed", "layer", layer.String(), "error", insertEventErr)
 					return insertEventErr
 				}
+
+				if layer == types.Layer1 {
+					if insertRefundErr := c.l1ETHRefundOrm.InsertRefunds(ctx, l1ETHRefunds, tx); insertRefundErr != nil {
+						return fmt.Errorf("insert l1 eth refunds failed, err: %w", insertRefundErr)
+					}
+
+					if insertBatchErr := c.batchLogic.InsertOrUpdateBatches(ctx, batchEvents, tx); insertBatchErr != nil {
+						return fmt.Errorf("insert or update batches failed, err: %w", insertBatchErr)
+					}
+
+					if insertQueueErr := c.messageQueueLogic.InsertOrUpdateL1Events(ctx, messageQueueEvents, tx); insertQueueErr != nil {
+						return fmt.Errorf("insert or update message queue events failed, err: %w", insertQueueErr)
+					}
+				}
+
+				if recordErr := c.reorgLogic.RecordProcessedBlockHash(ctx, layer, loopEnd, endHeader.Hash(), tx); recordErr != nil {
+					return fmt.Errorf("record processed block hash failed, err: %w, block number: %v", recordErr, loopEnd)
+				}
+
+				if cursorErr := c.messageMatchLogic.UpdateSyncCursor(ctx, layer, loopEnd, endHeader.Hash().Hex(), tx); cursorErr != nil {
+					return fmt.Errorf("update sync cursor failed, err: %w, block number: %v", cursorErr, loopEnd)
+				}
 				return nil
 			})
 			if updateErr != nil {
 				log.Error("update db status after check failed", "layer", layer, "from", start, "end", loopEnd, "err", updateErr)
 				continue
 			}
+			for _, a := range alerts {
+				alert.Notify(a)
+			}
 		}
 
 		// Update start after all handlings are successful.
@@ -274,7 +405,7 @@ func (c *ContractController) watcherStart(ctx context.Context, client *ethclient
 	}
 }
 
-func (c *ContractController) l1Watch(ctx context.Context, start uint64, end uint64) ([]orm.GatewayMessageMatch, []orm.MessengerMessageMatch, error) {
+func (c *ContractController) l1Watch(ctx context.Context, start uint64, end uint64) ([]orm.GatewayMessageMatch, []orm.MessengerMessageMatch, []orm.FailedRelayedMessageEvent, error) {
 	log.Info("watching block number", "layer", types.Layer1, "start", start, "end", end)
 	opts := bind.FilterOpts{
 		Start:   start,
@@ -286,17 +417,38 @@ func (c *ContractController) l1Watch(ctx context.Context, start uint64, end uint
 	if err != nil {
 		c.contractControllerFilterGatewayIteratorFailureTotal.WithLabelValues(types.Layer1.String(), types.MessengerEventCategory.String()).Inc()
 		log.Error("get messenger iterator failed", "layer", types.Layer1, "eventCategory", types.MessengerEventCategory, "error", err)
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 	messengerEvents := c.eventGatherLogic.Dispatch(ctx, types.Layer1, types.MessengerEventCategory, messengerIterList)
 	messengerMessageMatches, err := c.messageMatchAssembler.MessageMatchAssembler(messengerEvents)
 	if err != nil {
 		log.Error("generate messenger message match failed", "layer", types.Layer2, "eventCategory", types.MessengerEventCategory, "error", err)
-		return nil, nil, err
+		return nil, nil, nil, err
+	}
+
+	failedRelayedMessages, err := c.messageMatchAssembler.FailedRelayedMessageAssembler(messengerEvents)
+	if err != nil {
+		log.Error("generate failed relayed message failed", "layer", types.Layer1, "eventCategory", types.MessengerEventCategory, "error", err)
+		return nil, nil, nil, err
+	}
+	blockTimes := newBlockTimeCache(c.l1Client)
+	if err = setFailedRelayedMessageBlockTimes(ctx, blockTimes, failedRelayedMessages); err != nil {
+		log.Error("get the block times of failed relayed messages failed", "layer", types.Layer1, "error", err)
+		return nil, nil, nil, err
+	}
+	if err = setSentMessageBlockTimes(ctx, blockTimes, types.Layer1, messengerMessageMatches); err != nil {
+		log.Error("get the block times of sent messages failed", "layer", types.Layer1, "error", err)
+		return nil, nil, nil, err
 	}
 
 	if len(messengerMessageMatches) == 0 {
-		return nil, nil, nil
+		return nil, nil, failedRelayedMessages, nil
+	}
+
+	messengerEventIndex, err := assembler.NewMessengerEventIndex(messengerEvents)
+	if err != nil {
+		log.Error("index messenger events failed", "layer", types.Layer1, "error", err)
+		return nil, nil, nil, err
 	}
 
 	var l1GatewayMessageMatches []orm.GatewayMessageMatch
@@ -305,14 +457,14 @@ func (c *ContractController) l1Watch(ctx context.Context, start uint64, end uint
 		if err != nil {
 			c.contractControllerFilterGatewayIteratorFailureTotal.WithLabelValues(types.Layer1.String(), eventCategory.String()).Inc()
 			log.Error("get contract iterator failed", "layer", types.Layer1, "eventCategory", eventCategory, "error", err)
-			return nil, nil, err
+			return nil, nil, nil, err
 		}
 
 		transferEvents, err := c.contractsLogic.GetGatewayTransfer(ctx, start, end, types.Layer1, eventCategory)
 		if err != nil {
 			c.contractControllerFilterTransferIteratorFailureTotal.WithLabelValues(types.Layer1.String(), "transfer").Inc()
 			log.Error("get gateway related transfer events failed", "layer", types.Layer1, "eventCategory", eventCategory, "error", err)
-			return nil, nil, err
+			return nil, nil, nil, err
 		}
 
 		// parse the gateway and messenger event data
@@ -323,19 +475,186 @@ func (c *ContractController) l1Watch(ctx context.Context, start uint64, end uint
 		}
 
 		// match transfer event
-		retL1MessageMatches, checkErr := c.messageMatchAssembler.GatewayMessageAssembler(eventCategory, gatewayEvents, messengerEvents, transferEvents)
-		l1GatewayMessageMatches = append(l1GatewayMessageMatches, retL1MessageMatches...)
+		retL1MessageMatches, checkErr := c.messageMatchAssembler.GatewayMessageAssembler(eventCategory, gatewayEvents, messengerEventIndex, transferEvents)
 		if checkErr != nil {
 			c.contractControllerGatewayCheckFailureTotal.WithLabelValues(types.Layer1.String()).Inc()
 			log.Error("event matcher deal failed", "layer", types.Layer1, "eventCategory", eventCategory, "error", checkErr)
-			return nil, nil, err
+			return nil, nil, nil, checkErr
 		}
+		// The mismatched transfers are marked failed in the message matches, so the watcher goes on with the blocks.
+		if failed := countFailedGatewayMessageMatches(types.Layer1, retL1MessageMatches); failed > 0 {
+			c.contractControllerGatewayCheckFailureTotal.WithLabelValues(types.Layer1.String()).Add(float64(failed))
+		}
+		l1GatewayMessageMatches = append(l1GatewayMessageMatches, retL1MessageMatches...)
 	}
+	c.messageMatchAssembler.MarkGatewayMessagePairingFailures(types.Layer1, messengerEventIndex, l1GatewayMessageMatches)
+	c.messageMatchAssembler.MarkFailedMessengerMessageMatches(types.Layer1, l1GatewayMessageMatches, messengerMessageMatches)
 
-	return l1GatewayMessageMatches, messengerMessageMatches, nil
+	return l1GatewayMessageMatches, messengerMessageMatches, failedRelayedMessages, nil
 }
 
-func (c *ContractController) l2Watch(ctx context.Context, start uint64, end uint64) ([]orm.GatewayMessageMatch, []orm.MessengerMessageMatch, error) {
+// l1ETHRefundWatch returns the eth refunds of the dropped messages, which are emitted by the eth gateway and the erc20
+// gateways unwrapping the tokens to eth. The messages are dropped without any messenger event, so the refunds are
+// watched apart from the gateway events paired with the messenger events.
+func (c *ContractController) l1ETHRefundWatch(ctx context.Context, start uint64, end uint64) ([]orm.L1ETHRefund, error) {
+	opts := bind.FilterOpts{
+		Start:   start,
+		End:     &end,
+		Context: ctx,
+	}
+
+	var refunds []orm.L1ETHRefund
+	for _, eventCategory := range []types.EventCategory{types.ETHEventCategory, types.ERC20EventCategory} {
+		wrapIterList, err := c.contractsLogic.Iterator(ctx, &opts, types.Layer1, eventCategory)
+		if err != nil {
+			c.contractControllerFilterGatewayIteratorFailureTotal.WithLabelValues(types.Layer1.String(), eventCategory.String()).Inc()
+			log.Error("get contract iterator failed", "layer", types.Layer1, "eventCategory", eventCategory, "error", err)
+			return nil, err
+		}
+		gatewayEvents := c.eventGatherLogic.Dispatch(ctx, types.Layer1, eventCategory, wrapIterList)
+		refunds = append(refunds, c.messageMatchAssembler.L1ETHRefundAssembler(gatewayEvents)...)
+	}
+	return refunds, nil
+}
+
+// l1BatchWatch returns the batch events of the l1 scroll chain, the l2 block ranges of the committed batches are decoded from the commit calldata.
+func (c *ContractController) l1BatchWatch(ctx context.Context, start uint64, end uint64) ([]*events.BatchEventUnmarshaler, error) {
+	opts := bind.FilterOpts{
+		Start:   start,
+		End:     &end,
+		Context: ctx,
+	}
+
+	batchIterList, err := c.contractsLogic.Iterator(ctx, &opts, types.Layer1, types.BatchEventCategory)
+	if err != nil {
+		c.contractControllerFilterGatewayIteratorFailureTotal.WithLabelValues(types.Layer1.String(), types.BatchEventCategory.String()).Inc()
+		log.Error("get batch iterator failed", "layer", types.Layer1, "eventCategory", types.BatchEventCategory, "error", err)
+		return nil, err
+	}
+
+	var batchEvents []*events.BatchEventUnmarshaler
+	blockTimes := newBlockTimeCache(c.l1Client)
+	dispatchedEvents := c.eventGatherLogic.Dispatch(ctx, types.Layer1, types.BatchEventCategory, batchIterList)
+	for _, event := range dispatchedEvents {
+		batchEvent, ok := event.(*events.BatchEventUnmarshaler)
+		if !ok {
+			continue
+		}
+
+		if batchEvent.Type == types.L1CommitBatch {
+			startBlockNumber, endBlockNumber, rangeErr := c.contractsLogic.GetCommitBatchBlockRange(ctx, batchEvent.TxHash, batchEvent.BatchIndex)
+			if rangeErr != nil && !errors.Is(rangeErr, contracts.ErrUnknownCommitBatchCalldata) {
+				log.Error("get commit batch block range failed", "batch index", batchEvent.BatchIndex, "tx hash", batchEvent.TxHash, "error", rangeErr)
+				return nil, rangeErr
+			}
+			if rangeErr != nil {
+				// The batch is stored without the l2 block range, it's alerted by the batch status check once it's stored.
+				c.contractControllerDecodeCommitBatchFailureTotal.Inc()
+				log.Error("decode commit batch calldata failed", "batch index", batchEvent.BatchIndex, "tx hash", batchEvent.TxHash, "error", rangeErr)
+			}
+			batchEvent.StartBlockNumber = startBlockNumber
+			batchEvent.EndBlockNumber = endBlockNumber
+		}
+
+		if batchEvent.Type == types.L1FinalizeBatch {
+			blockTime, timeErr := blockTimes.get(ctx, batchEvent.Number)
+			if timeErr != nil {
+				log.Error("get finalize batch block time failed", "batch index", batchEvent.BatchIndex, "block number", batchEvent.Number, "error", timeErr)
+				return nil, timeErr
+			}
+			batchEvent.BlockTime = blockTime
+		}
+		batchEvents = append(batchEvents, batchEvent)
+	}
+	return batchEvents, nil
+}
+
+// l1MessageQueueWatch returns the enqueue, dequeue and drop events of the l1 message queue.
+func (c *ContractController) l1MessageQueueWatch(ctx context.Context, start uint64, end uint64) ([]*events.MessageQueueEventUnmarshaler, error) {
+	opts := bind.FilterOpts{
+		Start:   start,
+		End:     &end,
+		Context: ctx,
+	}
+
+	queueIterList, err := c.contractsLogic.Iterator(ctx, &opts, types.Layer1, types.MessageQueueEventCategory)
+	if err != nil {
+		c.contractControllerFilterGatewayIteratorFailureTotal.WithLabelValues(types.Layer1.String(), types.MessageQueueEventCategory.String()).Inc()
+		log.Error("get message queue iterator failed", "layer", types.Layer1, "eventCategory", types.MessageQueueEventCategory, "error", err)
+		return nil, err
+	}
+
+	var queueEvents []*events.MessageQueueEventUnmarshaler
+	for _, event := range c.eventGatherLogic.Dispatch(ctx, types.Layer1, types.MessageQueueEventCategory, queueIterList) {
+		if queueEvent, ok := event.(*events.MessageQueueEventUnmarshaler); ok {
+			queueEvents = append(queueEvents, queueEvent)
+		}
+	}
+	return queueEvents, nil
+}
+
+// blockTimeCache fetches the block times of the watched blocks, the header of each block is fetched once.
+type blockTimeCache struct {
+	client *ethclient.Client
+	times  map[uint64]time.Time
+}
+
+func newBlockTimeCache(client *rpc.Client) *blockTimeCache {
+	return &blockTimeCache{
+		client: ethclient.NewClient(client),
+		times:  make(map[uint64]time.Time),
+	}
+}
+
+func (b *blockTimeCache) get(ctx context.Context, blockNumber uint64) (time.Time, error) {
+	if blockTime, ok := b.times[blockNumber]; ok {
+		return blockTime, nil
+	}
+	header, err := b.client.HeaderByNumber(ctx, new(big.Int).SetUint64(blockNumber))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("get block header failed, block number: %v, err: %w", blockNumber, err)
+	}
+	blockTime := time.Unix(int64(header.Time), 0).UTC()
+	b.times[blockNumber] = blockTime
+	return blockTime, nil
+}
+
+// setFailedRelayedMessageBlockTimes sets the block times of the FailedRelayedMessage events, so the relay time window
+// of the failures is measured in block time.
+func setFailedRelayedMessageBlockTimes(ctx context.Context, blockTimes *blockTimeCache, failedRelayedMessages []orm.FailedRelayedMessageEvent) error {
+	for i := range failedRelayedMessages {
+		blockTime, err := blockTimes.get(ctx, failedRelayedMessages[i].BlockNumber)
+		if err != nil {
+			return err
+		}
+		failedRelayedMessages[i].BlockTime = blockTime
+	}
+	return nil
+}
+
+// setSentMessageBlockTimes sets the block times of the sent messages, the stuck messages are aged from them.
+func setSentMessageBlockTimes(ctx context.Context, blockTimes *blockTimeCache, layer types.LayerType, messengerMessageMatches []orm.MessengerMessageMatch) error {
+	for i := range messengerMessageMatches {
+		message := &messengerMessageMatches[i]
+		var blockNumber uint64
+		switch {
+		case layer == types.Layer1 && message.L1EventType == int(types.L1SentMessage):
+			blockNumber = message.L1BlockNumber
+		case layer == types.Layer2 && message.L2EventType == int(types.L2SentMessage):
+			blockNumber = message.L2BlockNumber
+		default:
+			continue
+		}
+		blockTime, err := blockTimes.get(ctx, blockNumber)
+		if err != nil {
+			return err
+		}
+		message.SentBlockTime = &blockTime
+	}
+	return nil
+}
+
+func (c *ContractController) l2Watch(ctx context.Context, start uint64, end uint64) ([]orm.GatewayMessageMatch, []orm.MessengerMessageMatch, []orm.FailedRelayedMessageEvent, error) {
 	log.Info("watching block number", "layer", types.Layer2, "start", start, "end", end)
 	opts := bind.FilterOpts{
 		Start:   start,
@@ -347,17 +666,51 @@ func (c *ContractController) l2Watch(ctx context.Context, start uint64, end uint
 	if err != nil {
 		c.contractControllerFilterGatewayIteratorFailureTotal.WithLabelValues(types.Layer2.String(), types.MessengerEventCategory.String()).Inc()
 		log.Error("get messenger iterator failed", "layer", types.Layer2, "eventCategory", types.MessengerEventCategory, "error", err)
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 	messengerEvents := c.eventGatherLogic.Dispatch(ctx, types.Layer2, types.MessengerEventCategory, messengerIterList)
 	messengerMessageMatches, err := c.messageMatchAssembler.MessageMatchAssembler(messengerEvents)
 	if err != nil {
 		log.Error("generate messenger message match failed", "layer", types.Layer2, "eventCategory", types.MessengerEventCategory, "error", err)
-		return nil, nil, err
+		return nil, nil, nil, err
+	}
+
+	failedRelayedMessages, err := c.messageMatchAssembler.FailedRelayedMessageAssembler(messengerEvents)
+	if err != nil {
+		log.Error("generate failed relayed message failed", "layer", types.Layer2, "eventCategory", types.MessengerEventCategory, "error", err)
+		return nil, nil, nil, err
+	}
+	blockTimes := newBlockTimeCache(c.l2Client)
+	if err = setFailedRelayedMessageBlockTimes(ctx, blockTimes, failedRelayedMessages); err != nil {
+		log.Error("get the block times of failed relayed messages failed", "layer", types.Layer2, "error", err)
+		return nil, nil, nil, err
+	}
+	if err = setSentMessageBlockTimes(ctx, blockTimes, types.Layer2, messengerMessageMatches); err != nil {
+		log.Error("get the block times of sent messages failed", "layer", types.Layer2, "error", err)
+		return nil, nil, nil, err
 	}
 
 	if len(messengerMessageMatches) == 0 {
-		return nil, nil, nil
+		// The bridged tokens minted or burned without any gateway event are still checked.
+		if c.bridgedTokenRegistry != nil {
+			transferEvents, transferErr := c.contractsLogic.GetGatewayTransfer(ctx, start, end, types.Layer2, types.ERC20EventCategory)
+			if transferErr != nil {
+				c.contractControllerFilterTransferIteratorFailureTotal.WithLabelValues(types.Layer2.String(), "transfer").Inc()
+				log.Error("get gateway related transfer events failed", "layer", types.Layer2, "eventCategory", types.ERC20EventCategory, "error", transferErr)
+				return nil, nil, nil, transferErr
+			}
+			if checkErr := c.messageMatchAssembler.BridgedTokenTransferValidator(transferEvents); checkErr != nil {
+				log.Error("check bridged token transfers failed", "layer", types.Layer2, "error", checkErr)
+				return nil, nil, nil, checkErr
+			}
+		}
+		return nil, nil, failedRelayedMessages, nil
+	}
+
+	messengerEventIndex, err := assembler.NewMessengerEventIndex(messengerEvents)
+	if err != nil {
+		log.Error("index messenger events failed", "layer", types.Layer2, "error", err)
+		return nil, nil, nil, err
 	}
 
 	var l2GatewayMessageMatches []orm.GatewayMessageMatch
@@ -367,7 +720,7 @@ func (c *ContractController) l2Watch(ctx context.Context, start uint64, end uint
 		if err != nil {
 			c.contractControllerFilterGatewayIteratorFailureTotal.WithLabelValues(types.Layer2.String(), eventCategory.String()).Inc()
 			log.Error("get contract iterator failed", "layer", types.Layer2, "eventCategory", eventCategory, "error", err)
-			return nil, nil, err
+			return nil, nil, nil, err
 		}
 
 		var transferEvents []events.EventUnmarshaler
@@ -375,24 +728,48 @@ func (c *ContractController) l2Watch(ctx context.Context, start uint64, end uint
 		if err != nil {
 			c.contractControllerFilterTransferIteratorFailureTotal.WithLabelValues(types.Layer2.String(), "transfer").Inc()
 			log.Error("get gateway related transfer events failed", "layer", types.Layer2, "eventCategory", eventCategory, "error", err)
-			return nil, nil, err
+			return nil, nil, nil, err
 		}
 
 		// parse the event data
 		gatewayEvents := c.eventGatherLogic.Dispatch(ctx, types.Layer2, eventCategory, wrapIterList)
 		if gatewayEvents == nil {
+			if eventCategory == types.ERC20EventCategory && c.bridgedTokenRegistry != nil {
+				if checkErr := c.messageMatchAssembler.BridgedTokenTransferValidator(transferEvents); checkErr != nil {
+					log.Error("check bridged token transfers failed", "layer", types.Layer2, "error", checkErr)
+					return nil, nil, nil, checkErr
+				}
+			}
 			log.Debug("dispatch gateway events returns empty data", "layer", types.Layer2, "eventCategory", eventCategory)
 			continue
 		}
 
 		// match transfer event
-		retL2MessageMatches, checkErr := c.messageMatchAssembler.GatewayMessageAssembler(eventCategory, gatewayEvents, messengerEvents, transferEvents)
-		l2GatewayMessageMatches = append(l2GatewayMessageMatches, retL2MessageMatches...)
+		retL2MessageMatches, checkErr := c.messageMatchAssembler.GatewayMessageAssembler(eventCategory, gatewayEvents, messengerEventIndex, transferEvents)
 		if checkErr != nil {
 			c.contractControllerGatewayCheckFailureTotal.WithLabelValues(types.Layer2.String()).Inc()
 			log.Error("event matcher deal failed", "layer", types.Layer2, "eventCategory", eventCategory, "error", checkErr)
-			return nil, nil, err
+			return nil, nil, nil, checkErr
+		}
+		// The mismatched transfers are marked failed in the message matches, so the watcher goes on with the blocks.
+		if failed := countFailedGatewayMessageMatches(types.Layer2, retL2MessageMatches); failed > 0 {
+			c.contractControllerGatewayCheckFailureTotal.WithLabelValues(types.Layer2.String()).Add(float64(failed))
+		}
+		l2GatewayMessageMatches = append(l2GatewayMessageMatches, retL2MessageMatches...)
+	}
+	c.messageMatchAssembler.MarkGatewayMessagePairingFailures(types.Layer2, messengerEventIndex, l2GatewayMessageMatches)
+	c.messageMatchAssembler.MarkFailedMessengerMessageMatches(types.Layer2, l2GatewayMessageMatches, messengerMessageMatches)
+	return l2GatewayMessageMatches, messengerMessageMatches, failedRelayedMessages, nil
+}
+
+// countFailedGatewayMessageMatches counts the gateway message matches of the layer whose transfer checks are failed.
+func countFailedGatewayMessageMatches(layer types.LayerType, gatewayMessageMatches []orm.GatewayMessageMatch) int {
+	var failed int
+	for _, gatewayMessageMatch := range gatewayMessageMatches {
+		if (layer == types.Layer1 && gatewayMessageMatch.L1BlockStatus == int(types.BlockStatusTypeFailed)) ||
+			(layer == types.Layer2 && gatewayMessageMatch.L2BlockStatus == int(types.BlockStatusTypeFailed)) {
+			failed++
 		}
 	}
-	return l2GatewayMessageMatches, messengerMessageMatches, nil
+	return failed
 }
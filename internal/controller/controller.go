@@ -8,7 +8,15 @@ import (
 // FinalizeBatchCtl the Finalize batch handler
 var FinalizeBatchCtl *FinalizeBatchCheckController
 
+// MessageMatchCtl the message match query handler
+var MessageMatchCtl *MessageMatchController
+
+// WithdrawProofCtl the withdraw proof handler
+var WithdrawProofCtl *WithdrawProofController
+
 // InitAPI init the api controller
 func InitAPI(conf *config.Config, db *gorm.DB) {
 	FinalizeBatchCtl = NewFinalizeBatchCheckController(conf, db)
+	MessageMatchCtl = NewMessageMatchController(conf, db)
+	WithdrawProofCtl = NewWithdrawProofController(conf, db)
 }
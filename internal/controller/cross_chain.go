@@ -19,6 +19,8 @@ import (
 type CrossChainController struct {
 	gatewayCrossChainLogic   *crosschain.LogicGatewayCrossChain
 	messengerCrossChainLogic *crosschain.LogicMessengerCrossChain
+	failedRelayedLogic       *crosschain.LogicFailedRelayedMessage
+	stuckMessageLogic        *crosschain.LogicStuckMessage
 
 	stopL1CrossChainChan chan struct{}
 	stopL2CrossChainChan chan struct{}
@@ -33,8 +35,10 @@ func NewCrossChainController(cfg *config.Config, db *gorm.DB, l1Client, l2Client
 	return &CrossChainController{
 		stopL1CrossChainChan:     make(chan struct{}),
 		stopL2CrossChainChan:     make(chan struct{}),
-		gatewayCrossChainLogic:   crosschain.NewLogicGatewayCrossChain(db),
+		gatewayCrossChainLogic:   crosschain.NewLogicGatewayCrossChain(cfg, db, l2Client),
 		messengerCrossChainLogic: crosschain.NewLogicMessengerCrossChain(db, l1Client, l2Client, l1MessengerAddr, l2MessengerAddr, cfg.L1Config.StartMessengerBalance),
+		failedRelayedLogic:       crosschain.NewLogicFailedRelayedMessage(cfg.FailedRelayedMessageConfig, db),
+		stuckMessageLogic:        crosschain.NewLogicStuckMessage(cfg.MessageSLAConfig, db),
 		crossChainControllerRunningTotal: promauto.With(prometheus.DefaultRegisterer).NewCounterVec(prometheus.CounterOpts{
 			Name: "cross_chain_check_controller_running_total",
 			Help: "The total number of cross chain controller running.",
@@ -45,6 +49,11 @@ func NewCrossChainController(cfg *config.Config, db *gorm.DB, l1Client, l2Client
 // Watch is a method that triggers the proposer methods for Layer 1 and Layer 2, as well as the
 // eth balance checker methods for both layers.
 func (c *CrossChainController) Watch(ctx context.Context) {
+	if err := c.gatewayCrossChainLogic.LoadTokenPairs(ctx); err != nil {
+		log.Crit("load configured token pairs failure", "error", err)
+		return
+	}
+
 	go c.watcherStart(ctx, types.Layer1)
 	go c.watcherStart(ctx, types.Layer2)
 }
@@ -76,7 +85,10 @@ func (c *CrossChainController) watcherStart(ctx context.Context, layer types.Lay
 		c.crossChainControllerRunningTotal.WithLabelValues(layer.String()).Inc()
 
 		c.gatewayCrossChainLogic.CheckCrossChainGatewayMessage(ctx, layer)
+		c.messengerCrossChainLogic.CheckCrossChainMessengerMessage(ctx, layer)
 		c.messengerCrossChainLogic.CheckETHBalance(ctx, layer)
+		c.failedRelayedLogic.CheckFailedRelayedMessage(ctx, layer)
+		c.stuckMessageLogic.CheckStuckMessage(ctx, layer)
 
 		// To prevent frequent database access, obtaining empty values.
 		time.Sleep(10 * time.Second)
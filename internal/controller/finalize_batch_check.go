@@ -1,13 +1,17 @@
 package controller
 
 import (
+	"errors"
+
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"gorm.io/gorm"
 
 	"github.com/scroll-tech/chain-monitor/internal/config"
+	"github.com/scroll-tech/chain-monitor/internal/logic/batch"
 	messagematch "github.com/scroll-tech/chain-monitor/internal/logic/message_match"
+	"github.com/scroll-tech/chain-monitor/internal/orm"
 	"github.com/scroll-tech/chain-monitor/internal/types"
 )
 
@@ -16,6 +20,7 @@ type FinalizeBatchCheckController struct {
 	db *gorm.DB
 
 	messageMatchLogic *messagematch.LogicMessageMatch
+	batchLogic        *batch.LogicBatch
 
 	gatewayBatchFinalizeCheckFailed   prometheus.Counter
 	messengerBatchFinalizeCheckFailed prometheus.Counter
@@ -26,6 +31,7 @@ func NewFinalizeBatchCheckController(conf *config.Config, db *gorm.DB) *Finalize
 	return &FinalizeBatchCheckController{
 		db:                db,
 		messageMatchLogic: messagematch.NewMessageMatchLogic(conf, db),
+		batchLogic:        batch.NewLogicBatch(conf, db),
 
 		gatewayBatchFinalizeCheckFailed: promauto.With(prometheus.DefaultRegisterer).NewCounter(prometheus.CounterOpts{
 			Name: "gateway_batch_finalized_failed_total",
@@ -38,7 +44,8 @@ func NewFinalizeBatchCheckController(conf *config.Config, db *gorm.DB) *Finalize
 	}
 }
 
-// BatchStatus get the upcoming finalized batch status
+// BatchStatus get the upcoming finalized batch status, which is valid only if the l2 blocks of the batch pass all the
+// checks, with the violations which make the batch pending or invalid.
 func (f *FinalizeBatchCheckController) BatchStatus(ctx *gin.Context) {
 	var finalizeBatchParam types.FinalizeBatchCheckParam
 	err := ctx.ShouldBind(&finalizeBatchParam)
@@ -47,14 +54,50 @@ func (f *FinalizeBatchCheckController) BatchStatus(ctx *gin.Context) {
 		return
 	}
 
-	gatewayCheck, messengerCheck := f.messageMatchLogic.GetBlocksStatus(ctx, finalizeBatchParam.StartBlockNumber, finalizeBatchParam.EndBlockNumber)
-	if !gatewayCheck {
-		f.gatewayBatchFinalizeCheckFailed.Inc()
+	blocksStatus, err := f.messageMatchLogic.GetBlocksStatus(ctx, finalizeBatchParam.StartBlockNumber, finalizeBatchParam.EndBlockNumber)
+	if err != nil {
+		types.RenderFailure(ctx, types.InternalServerError, err)
+		return
 	}
 
-	if !messengerCheck {
+	var gatewayCheckFailed, messengerCheckFailed bool
+	for _, violation := range blocksStatus.Violations {
+		if violation.Status != messagematch.ViolationStatusFailed {
+			continue
+		}
+		switch violation.Table {
+		case new(orm.GatewayMessageMatch).TableName():
+			gatewayCheckFailed = true
+		case new(orm.MessengerMessageMatch).TableName():
+			messengerCheckFailed = true
+		}
+	}
+	if gatewayCheckFailed {
+		f.gatewayBatchFinalizeCheckFailed.Inc()
+	}
+	if messengerCheckFailed {
 		f.messengerBatchFinalizeCheckFailed.Inc()
 	}
 
-	types.RenderJSON(ctx, types.Success, nil, gatewayCheck && messengerCheck)
+	types.RenderSuccess(ctx, blocksStatus)
+}
+
+// Batch get the batch committed on l1 and the status computed by the monitor
+func (f *FinalizeBatchCheckController) Batch(ctx *gin.Context) {
+	var param types.BatchParam
+	if err := ctx.ShouldBindUri(&param); err != nil {
+		types.RenderJSON(ctx, types.ErrParameterInvalidNo, err, nil)
+		return
+	}
+
+	batchView, err := f.batchLogic.GetBatch(ctx, param.BatchIndex)
+	if errors.Is(err, batch.ErrBatchNotFound) {
+		types.RenderFailure(ctx, types.ErrBatchNotFoundNo, err)
+		return
+	}
+	if err != nil {
+		types.RenderFailure(ctx, types.InternalServerError, err)
+		return
+	}
+	types.RenderSuccess(ctx, batchView)
 }
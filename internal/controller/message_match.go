@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/scroll-tech/chain-monitor/internal/config"
+	messagematch "github.com/scroll-tech/chain-monitor/internal/logic/message_match"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+)
+
+// MessageMatchController queries the message matches for the troubleshooting of the cross chain messages
+type MessageMatchController struct {
+	messageMatchLogic *messagematch.LogicMessageMatch
+}
+
+// NewMessageMatchController create message match controller instance
+func NewMessageMatchController(conf *config.Config, db *gorm.DB) *MessageMatchController {
+	return &MessageMatchController{
+		messageMatchLogic: messagematch.NewMessageMatchLogic(conf, db),
+	}
+}
+
+// MessageMatch get the messenger and gateway message matches of the message hash
+func (m *MessageMatchController) MessageMatch(ctx *gin.Context) {
+	var param types.MessageMatchParam
+	if err := ctx.ShouldBind(&param); err != nil {
+		types.RenderJSON(ctx, types.ErrParameterInvalidNo, err, nil)
+		return
+	}
+
+	matches, err := m.messageMatchLogic.GetMessageMatches(ctx, param.MessageHash)
+	if err != nil {
+		types.RenderFailure(ctx, types.InternalServerError, err)
+		return
+	}
+	types.RenderSuccess(ctx, matches)
+}
+
+// MessageMatchesByTxHash get the messenger and gateway message matches of the l1 or l2 tx hash
+func (m *MessageMatchController) MessageMatchesByTxHash(ctx *gin.Context) {
+	var param types.MessageMatchTxHashParam
+	if err := ctx.ShouldBind(&param); err != nil {
+		types.RenderJSON(ctx, types.ErrParameterInvalidNo, err, nil)
+		return
+	}
+
+	matches, err := m.messageMatchLogic.GetMessageMatchesByTxHash(ctx, param.TxHash)
+	if err != nil {
+		types.RenderFailure(ctx, types.InternalServerError, err)
+		return
+	}
+	types.RenderSuccess(ctx, matches)
+}
+
+// MessageMatches list the messenger or gateway message matches by the status, block range and token filters
+func (m *MessageMatchController) MessageMatches(ctx *gin.Context) {
+	var param types.MessageMatchListParam
+	if err := ctx.ShouldBind(&param); err != nil {
+		types.RenderJSON(ctx, types.ErrParameterInvalidNo, err, nil)
+		return
+	}
+
+	page, err := m.messageMatchLogic.ListMessageMatches(ctx, &param)
+	if err != nil {
+		types.RenderFailure(ctx, types.InternalServerError, err)
+		return
+	}
+	types.RenderSuccess(ctx, page)
+}
@@ -0,0 +1,269 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"github.com/scroll-tech/chain-monitor/internal/logic/alert"
+	crosschain "github.com/scroll-tech/chain-monitor/internal/logic/cross_chain"
+	"github.com/scroll-tech/chain-monitor/internal/orm"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+)
+
+const (
+	// rescanMismatchTypeAlert the alert raised by the gateway/transfer/messenger checks during rescan.
+	rescanMismatchTypeAlert = "alert"
+	// rescanMismatchTypeWatchFailed the events of the block range can't be fetched or assembled.
+	rescanMismatchTypeWatchFailed = "watch_failed"
+	// rescanMismatchTypeMessengerEvent the rescanned messenger event differs from the ingested one.
+	rescanMismatchTypeMessengerEvent = "messenger_event"
+	// rescanMismatchTypeGatewayEvent the rescanned gateway event differs from the ingested one.
+	rescanMismatchTypeGatewayEvent = "gateway_event"
+	// rescanMismatchTypeGatewayCrossChain the rescanned gateway event doesn't match the event of the other layer.
+	rescanMismatchTypeGatewayCrossChain = "gateway_cross_chain"
+)
+
+// RescanMismatch is a mismatch found by rescan, which is reported as a json line.
+type RescanMismatch struct {
+	Layer            string       `json:"layer"`
+	StartBlockNumber uint64       `json:"start_block_number"`
+	EndBlockNumber   uint64       `json:"end_block_number"`
+	Type             string       `json:"type"`
+	MessageHash      string       `json:"message_hash,omitempty"`
+	Detail           string       `json:"detail"`
+	Alert            *alert.Alert `json:"alert,omitempty"`
+}
+
+// Rescan re-runs the gateway, transfer and messenger checks over the block range [start, end] of the layer and
+// writes every mismatch to w as a json line. The results are upserted into the database unless dryRun is set,
+// the sync cursor of the watcher is left untouched. It returns the number of reported mismatches.
+func (c *ContractController) Rescan(ctx context.Context, layer types.LayerType, start, end uint64, dryRun bool, w io.Writer) (int, error) {
+	if layer != types.Layer1 && layer != types.Layer2 {
+		return 0, fmt.Errorf("invalid rescan layer: %d", layer)
+	}
+	if start > end {
+		return 0, fmt.Errorf("invalid rescan block range, from: %d, to: %d", start, end)
+	}
+
+	if c.bridgedTokenRegistry != nil {
+		if err := c.bridgedTokenRegistry.Load(ctx); err != nil {
+			return 0, err
+		}
+	}
+
+	// The blocks after the sync cursor haven't been ingested, so the events missing in db are only reported before it.
+	processedBlockNumber, err := c.messageMatchLogic.GetLatestBlockNumber(ctx, layer)
+	if err != nil {
+		return 0, fmt.Errorf("get latest processed block number failed, layer: %s, err: %w", layer.String(), err)
+	}
+
+	reporter := newRescanReporter(w, layer)
+	defer reporter.captureAlerts()()
+
+	for from, to := start, start; from <= end; from = to + 1 {
+		select {
+		case <-ctx.Done():
+			return reporter.mismatchCount, ctx.Err()
+		default:
+		}
+
+		to = from + maxBlockFetchSize
+		if to > end {
+			to = end
+		}
+		reporter.startBlockNumber, reporter.endBlockNumber = from, to
+
+		var gatewayMessageMatches []orm.GatewayMessageMatch
+		var messengerMessageMatches []orm.MessengerMessageMatch
+		var failedRelayedMessages []orm.FailedRelayedMessageEvent
+		var watchErr error
+		switch layer {
+		case types.Layer1:
+			gatewayMessageMatches, messengerMessageMatches, failedRelayedMessages, watchErr = c.l1Watch(ctx, from, to)
+		case types.Layer2:
+			gatewayMessageMatches, messengerMessageMatches, failedRelayedMessages, watchErr = c.l2Watch(ctx, from, to)
+		}
+		if watchErr != nil {
+			reporter.report(rescanMismatchTypeWatchFailed, "", watchErr.Error())
+			continue
+		}
+
+		if checkErr := c.rescanCheckMessengerMessageMatches(ctx, layer, processedBlockNumber, messengerMessageMatches, reporter.report); checkErr != nil {
+			return reporter.mismatchCount, checkErr
+		}
+
+		if checkErr := c.rescanCheckGatewayMessageMatches(ctx, layer, processedBlockNumber, gatewayMessageMatches, reporter.report); checkErr != nil {
+			return reporter.mismatchCount, checkErr
+		}
+
+		if dryRun {
+			continue
+		}
+
+		if upsertErr := c.messageMatchLogic.UpsertMessageMatches(ctx, layer, gatewayMessageMatches, messengerMessageMatches, failedRelayedMessages); upsertErr != nil {
+			return reporter.mismatchCount, fmt.Errorf("upsert rescan results failed, layer: %s, from: %d, to: %d, err: %w", layer.String(), from, to, upsertErr)
+		}
+	}
+
+	return reporter.mismatchCount, nil
+}
+
+// rescanReporter writes the mismatches found in the block range being rescanned to the writer as json lines.
+type rescanReporter struct {
+	encoder          *json.Encoder
+	layer            types.LayerType
+	startBlockNumber uint64
+	endBlockNumber   uint64
+	mismatchCount    int
+}
+
+func newRescanReporter(w io.Writer, layer types.LayerType) *rescanReporter {
+	return &rescanReporter{encoder: json.NewEncoder(w), layer: layer}
+}
+
+func (r *rescanReporter) write(mismatch RescanMismatch) {
+	r.mismatchCount++
+	mismatch.Layer = r.layer.String()
+	mismatch.StartBlockNumber = r.startBlockNumber
+	mismatch.EndBlockNumber = r.endBlockNumber
+	if err := r.encoder.Encode(mismatch); err != nil {
+		log.Error("write rescan mismatch failed", "mismatch", mismatch, "error", err)
+	}
+}
+
+func (r *rescanReporter) report(mismatchType, messageHash, detail string) {
+	r.write(RescanMismatch{Type: mismatchType, MessageHash: messageHash, Detail: detail})
+}
+
+// captureAlerts reports the alerts raised by the checks instead of sending them to the configured sinks, the returned
+// function restores the previous alert manager.
+func (r *rescanReporter) captureAlerts() func() {
+	previousManager := alert.Use(alert.NewManagerWithSinks(alert.NewFuncSink("rescan", func(a alert.Alert) {
+		r.write(RescanMismatch{Type: rescanMismatchTypeAlert, MessageHash: a.MessageHash, Detail: a.Title, Alert: &a})
+	})))
+	return func() {
+		alert.Use(previousManager)
+	}
+}
+
+// rescanCheckMessengerMessageMatches compares the rescanned messenger events with the ingested ones.
+func (c *ContractController) rescanCheckMessengerMessageMatches(ctx context.Context, layer types.LayerType, processedBlockNumber uint64, messages []orm.MessengerMessageMatch, report func(mismatchType, messageHash, detail string)) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	var messageHashes []string
+	for _, message := range messages {
+		messageHashes = append(messageHashes, message.MessageHash)
+	}
+
+	dbMessages, err := c.messengerMessageMatchOrm.GetMessageMatchesByMessageHashes(ctx, messageHashes)
+	if err != nil {
+		return fmt.Errorf("get messenger message matches failed, err: %w", err)
+	}
+
+	dbMessageMap := make(map[string]orm.MessengerMessageMatch)
+	for _, dbMessage := range dbMessages {
+		dbMessageMap[dbMessage.MessageHash] = dbMessage
+	}
+
+	for _, message := range messages {
+		dbMessage := dbMessageMap[message.MessageHash]
+		var blockNumber, dbBlockNumber uint64
+		var eventType, dbEventType int
+		var txHash, dbTxHash string
+		if layer == types.Layer1 {
+			blockNumber, eventType, txHash = message.L1BlockNumber, message.L1EventType, message.L1TxHash
+			dbBlockNumber, dbEventType, dbTxHash = dbMessage.L1BlockNumber, dbMessage.L1EventType, dbMessage.L1TxHash
+		} else {
+			blockNumber, eventType, txHash = message.L2BlockNumber, message.L2EventType, message.L2TxHash
+			dbBlockNumber, dbEventType, dbTxHash = dbMessage.L2BlockNumber, dbMessage.L2EventType, dbMessage.L2TxHash
+		}
+
+		if dbBlockNumber == 0 {
+			if blockNumber <= processedBlockNumber {
+				report(rescanMismatchTypeMessengerEvent, message.MessageHash, fmt.Sprintf("messenger event %s in block %d tx %s is not ingested", types.EventType(eventType).String(), blockNumber, txHash))
+			}
+			continue
+		}
+
+		if blockNumber != dbBlockNumber || eventType != dbEventType || txHash != dbTxHash {
+			report(rescanMismatchTypeMessengerEvent, message.MessageHash, fmt.Sprintf("messenger event mismatch, rescanned: %s in block %d tx %s, ingested: %s in block %d tx %s",
+				types.EventType(eventType).String(), blockNumber, txHash, types.EventType(dbEventType).String(), dbBlockNumber, dbTxHash))
+		}
+	}
+	return nil
+}
+
+// rescanCheckGatewayMessageMatches compares the rescanned gateway events with the ingested ones, and checks them
+// against the events of the other layer.
+func (c *ContractController) rescanCheckGatewayMessageMatches(ctx context.Context, layer types.LayerType, processedBlockNumber uint64, messages []orm.GatewayMessageMatch, report func(mismatchType, messageHash, detail string)) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	var messageHashes []string
+	for _, message := range messages {
+		messageHashes = append(messageHashes, message.MessageHash)
+	}
+
+	dbMessages, err := c.gatewayMessageMatchOrm.GetGatewayMessageMatchesByMessageHashes(ctx, messageHashes)
+	if err != nil {
+		return fmt.Errorf("get gateway message matches failed, err: %w", err)
+	}
+
+	dbMessageMap := make(map[string]orm.GatewayMessageMatch)
+	for _, dbMessage := range dbMessages {
+		dbMessageMap[dbMessage.MessageHash] = dbMessage
+	}
+
+	checker := crosschain.NewGatewayCrossEventMatcher()
+	for _, message := range messages {
+		dbMessage, exists := dbMessageMap[message.MessageHash]
+		merged := dbMessage
+		merged.MessageHash = message.MessageHash
+		merged.TokenType = message.TokenType
+
+		var blockNumber, dbBlockNumber uint64
+		var eventType int
+		var rescanned, ingested string
+		if layer == types.Layer1 {
+			blockNumber, dbBlockNumber, eventType = message.L1BlockNumber, dbMessage.L1BlockNumber, message.L1EventType
+			rescanned = fmt.Sprintf("%d/%s/%s/%s/%s", message.L1EventType, message.L1TxHash, message.L1TokenAddress, message.L1TokenIds, message.L1Amounts)
+			ingested = fmt.Sprintf("%d/%s/%s/%s/%s", dbMessage.L1EventType, dbMessage.L1TxHash, dbMessage.L1TokenAddress, dbMessage.L1TokenIds, dbMessage.L1Amounts)
+			merged.L1EventType, merged.L1BlockNumber, merged.L1TxHash = message.L1EventType, message.L1BlockNumber, message.L1TxHash
+			merged.L1TokenAddress, merged.L1TokenIds, merged.L1Amounts = message.L1TokenAddress, message.L1TokenIds, message.L1Amounts
+		} else {
+			blockNumber, dbBlockNumber, eventType = message.L2BlockNumber, dbMessage.L2BlockNumber, message.L2EventType
+			rescanned = fmt.Sprintf("%d/%s/%s/%s/%s", message.L2EventType, message.L2TxHash, message.L2TokenAddress, message.L2TokenIds, message.L2Amounts)
+			ingested = fmt.Sprintf("%d/%s/%s/%s/%s", dbMessage.L2EventType, dbMessage.L2TxHash, dbMessage.L2TokenAddress, dbMessage.L2TokenIds, dbMessage.L2Amounts)
+			merged.L2EventType, merged.L2BlockNumber, merged.L2TxHash = message.L2EventType, message.L2BlockNumber, message.L2TxHash
+			merged.L2TokenAddress, merged.L2TokenIds, merged.L2Amounts = message.L2TokenAddress, message.L2TokenIds, message.L2Amounts
+		}
+
+		switch {
+		case !exists || dbBlockNumber == 0:
+			if blockNumber <= processedBlockNumber {
+				report(rescanMismatchTypeGatewayEvent, message.MessageHash, fmt.Sprintf("gateway event %s in block %d is not ingested", types.EventType(eventType).String(), blockNumber))
+			}
+		case dbBlockNumber != blockNumber || ingested != rescanned:
+			// the event info is formatted as event_type/tx_hash/token_address/token_ids/amounts.
+			report(rescanMismatchTypeGatewayEvent, message.MessageHash, fmt.Sprintf("gateway event mismatch, rescanned: %s in block %d, ingested: %s in block %d", rescanned, blockNumber, ingested, dbBlockNumber))
+		}
+
+		// Only the messages which have the events of both layers can be checked across chains.
+		if merged.L1BlockNumber == 0 || merged.L2BlockNumber == 0 {
+			continue
+		}
+		for _, checkLayer := range []types.LayerType{types.Layer1, types.Layer2} {
+			if checkResult := checker.GatewayCrossChainCheck(checkLayer, merged); checkResult != types.MismatchTypeValid {
+				report(rescanMismatchTypeGatewayCrossChain, message.MessageHash, fmt.Sprintf("%s check failed: %s", checkLayer.String(), checkResult.String()))
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package controller
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"github.com/scroll-tech/chain-monitor/internal/config"
+	"github.com/scroll-tech/chain-monitor/internal/logic/alert"
+	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/il1scrollmessenger"
+	"github.com/scroll-tech/chain-monitor/internal/orm"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+	"github.com/scroll-tech/chain-monitor/internal/utils"
+	"github.com/scroll-tech/chain-monitor/internal/utils/testcontainer"
+)
+
+// chainService serves the logs of the messenger and the block headers of a chain.
+type chainService struct {
+	messenger common.Address
+	logs      []gethTypes.Log
+}
+
+type logQuery struct {
+	Addresses []common.Address `json:"address"`
+	Topics    [][]common.Hash  `json:"topics"`
+}
+
+func (s *chainService) GetLogs(query logQuery) ([]gethTypes.Log, error) {
+	logs := []gethTypes.Log{}
+	for _, address := range query.Addresses {
+		if address != s.messenger {
+			continue
+		}
+		for _, vLog := range s.logs {
+			if len(query.Topics) > 0 && len(query.Topics[0]) > 0 && query.Topics[0][0] == vLog.Topics[0] {
+				logs = append(logs, vLog)
+			}
+		}
+	}
+	return logs, nil
+}
+
+func (s *chainService) GetBlockByNumber(number hexutil.Uint64, _ bool) (*gethTypes.Header, error) {
+	return &gethTypes.Header{Number: new(big.Int).SetUint64(uint64(number)), Difficulty: big.NewInt(0), Time: uint64(number) * 12}, nil
+}
+
+func newChainClient(t *testing.T, service *chainService) *rpc.Client {
+	server := rpc.NewServer()
+	assert.NoError(t, server.RegisterName("eth", service))
+	t.Cleanup(server.Stop)
+	return rpc.DialInProc(server)
+}
+
+// sentMessageLog packs the l1 messenger SentMessage event of the block.
+func sentMessageLog(t *testing.T, messenger, sender, target common.Address, value, nonce *big.Int, blockNumber uint64) gethTypes.Log {
+	messengerABI, err := il1scrollmessenger.Il1scrollmessengerMetaData.GetAbi()
+	assert.NoError(t, err)
+	event := messengerABI.Events["SentMessage"]
+	data, err := event.Inputs.NonIndexed().Pack(value, nonce, big.NewInt(1000000), []byte{})
+	assert.NoError(t, err)
+	return gethTypes.Log{
+		Address:     messenger,
+		Topics:      []common.Hash{event.ID, common.BytesToHash(sender.Bytes()), common.BytesToHash(target.Bytes())},
+		Data:        data,
+		BlockNumber: blockNumber,
+		TxHash:      common.BigToHash(new(big.Int).SetUint64(blockNumber)),
+	}
+}
+
+func newRescanContractController(t *testing.T, db *gorm.DB, service *chainService) *ContractController {
+	// the metrics of the controller are registered once by the registerer.
+	previousRegisterer := prometheus.DefaultRegisterer
+	prometheus.DefaultRegisterer = prometheus.NewRegistry()
+	defer func() { prometheus.DefaultRegisterer = previousRegisterer }()
+
+	cfg := &config.Config{
+		L1Config: &config.L1Config{L1Contracts: &config.L1Contracts{ScrollMessenger: service.messenger}},
+		L2Config: &config.L2Config{L2Contracts: &config.L2Contracts{}},
+	}
+	client := newChainClient(t, service)
+	return NewContractController(cfg, db, client, client)
+}
+
+func decodeRescanMismatches(t *testing.T, output *bytes.Buffer) []RescanMismatch {
+	var mismatches []RescanMismatch
+	scanner := bufio.NewScanner(output)
+	for scanner.Scan() {
+		var mismatch RescanMismatch
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &mismatch))
+		mismatches = append(mismatches, mismatch)
+	}
+	return mismatches
+}
+
+func TestRescanReporter(t *testing.T) {
+	var alerts []alert.Alert
+	previous := alert.Use(alert.NewManagerWithSinks(alert.NewFuncSink("stdout", func(a alert.Alert) { alerts = append(alerts, a) })))
+	defer alert.Use(previous)
+
+	var output bytes.Buffer
+	reporter := newRescanReporter(&output, types.Layer2)
+	reporter.startBlockNumber, reporter.endBlockNumber = 100, 149
+	reporter.report(rescanMismatchTypeWatchFailed, "", "get logs failed")
+
+	// the alerts raised while rescanning are reported instead of being sent to the sinks.
+	restore := reporter.captureAlerts()
+	reporter.startBlockNumber, reporter.endBlockNumber = 150, 199
+	alert.Notify(alert.Alert{Category: alert.CategoryGatewayTransfer, Title: "gateway transfer mismatch", MessageHash: "0x1"})
+	restore()
+	assert.Empty(t, alerts)
+
+	alert.Notify(alert.Alert{Category: alert.CategoryGatewayTransfer, Title: "gateway transfer mismatch", MessageHash: "0x2"})
+	assert.Len(t, alerts, 1)
+
+	mismatches := decodeRescanMismatches(t, &output)
+	assert.Equal(t, 2, reporter.mismatchCount)
+	assert.Len(t, mismatches, 2)
+	assert.Equal(t, RescanMismatch{Layer: types.Layer2.String(), StartBlockNumber: 100, EndBlockNumber: 149, Type: rescanMismatchTypeWatchFailed, Detail: "get logs failed"}, mismatches[0])
+	assert.Equal(t, rescanMismatchTypeAlert, mismatches[1].Type)
+	assert.Equal(t, uint64(150), mismatches[1].StartBlockNumber)
+	assert.Equal(t, "0x1", mismatches[1].MessageHash)
+	assert.Equal(t, "gateway transfer mismatch", mismatches[1].Detail)
+	assert.Equal(t, "0x1", mismatches[1].Alert.MessageHash)
+}
+
+func TestContractController_Rescan(t *testing.T) {
+	var alerts []alert.Alert
+	previous := alert.Use(alert.NewManagerWithSinks(alert.NewFuncSink("stdout", func(a alert.Alert) { alerts = append(alerts, a) })))
+	defer alert.Use(previous)
+
+	ctx := context.Background()
+	db := testcontainer.SetupDB(ctx, t)
+	messengerOrm := orm.NewMessengerMessageMatch(db)
+
+	messenger, sender, target := common.HexToAddress("0x1001"), common.HexToAddress("0x1"), common.HexToAddress("0x2")
+	service := &chainService{messenger: messenger, logs: []gethTypes.Log{
+		sentMessageLog(t, messenger, sender, target, big.NewInt(10), big.NewInt(1), 100),
+	}}
+	c := newRescanContractController(t, db, service)
+	messageHash := utils.ComputeMessageHash(sender, target, big.NewInt(10), big.NewInt(1), []byte{}).Hex()
+
+	// the blocks before 120 are ingested.
+	assert.NoError(t, orm.NewSyncCursor(db).InsertOrUpdateSyncCursor(ctx, types.Layer1, types.CheckerTypeContract, 120, ""))
+
+	tests := []struct {
+		name string
+		test func(t *testing.T)
+	}{
+		{
+			"invalidBlockRange", func(t *testing.T) {
+				_, err := c.Rescan(ctx, types.Layer1, 200, 100, true, &bytes.Buffer{})
+				assert.ErrorContains(t, err, "invalid rescan block range")
+			},
+		},
+		{
+			"dryRun", func(t *testing.T) {
+				var output bytes.Buffer
+				mismatchCount, err := c.Rescan(ctx, types.Layer1, 90, 160, true, &output)
+				assert.NoError(t, err)
+				assert.Equal(t, 1, mismatchCount)
+
+				mismatches := decodeRescanMismatches(t, &output)
+				assert.Len(t, mismatches, 1)
+				assert.Equal(t, types.Layer1.String(), mismatches[0].Layer)
+				assert.Equal(t, uint64(90), mismatches[0].StartBlockNumber)
+				assert.Equal(t, uint64(139), mismatches[0].EndBlockNumber)
+				assert.Equal(t, rescanMismatchTypeMessengerEvent, mismatches[0].Type)
+				assert.Equal(t, messageHash, mismatches[0].MessageHash)
+				assert.Contains(t, mismatches[0].Detail, "is not ingested")
+
+				// the rescanned message isn't written into the database.
+				messages, err := messengerOrm.GetMessageMatchesByMessageHashes(ctx, []string{messageHash})
+				assert.NoError(t, err)
+				assert.Empty(t, messages)
+
+				// the alert manager is restored after the rescan.
+				alert.Notify(alert.Alert{Category: alert.CategoryGatewayTransfer, Title: "gateway transfer mismatch"})
+				assert.Len(t, alerts, 1)
+			},
+		},
+		{
+			"upsert", func(t *testing.T) {
+				var output bytes.Buffer
+				mismatchCount, err := c.Rescan(ctx, types.Layer1, 90, 160, false, &output)
+				assert.NoError(t, err)
+				assert.Equal(t, 1, mismatchCount)
+
+				messages, err := messengerOrm.GetMessageMatchesByMessageHashes(ctx, []string{messageHash})
+				assert.NoError(t, err)
+				assert.Len(t, messages, 1)
+				assert.Equal(t, uint64(100), messages[0].L1BlockNumber)
+
+				// the rescanned message is ingested now.
+				output.Reset()
+				mismatchCount, err = c.Rescan(ctx, types.Layer1, 90, 160, true, &output)
+				assert.NoError(t, err)
+				assert.Equal(t, 0, mismatchCount)
+				assert.Empty(t, output.String())
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, test.test)
+	}
+}
@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/ethclient"
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"github.com/scroll-tech/chain-monitor/internal/config"
+	crosschain "github.com/scroll-tech/chain-monitor/internal/logic/cross_chain"
+)
+
+const defaultTokenSupplyCheckInterval uint64 = 60
+
+// TokenSupplyController checks the l1 custody balances of the bridged tokens against their l2 supplies periodically.
+type TokenSupplyController struct {
+	tokenSupplyLogic *crosschain.LogicTokenSupply
+	checkInterval    time.Duration
+
+	stopTokenSupplyChan chan struct{}
+
+	tokenSupplyControllerRunningTotal prometheus.Counter
+}
+
+// NewTokenSupplyController creates a new TokenSupplyController object.
+func NewTokenSupplyController(cfg *config.Config, db *gorm.DB, l1Client, l2Client *ethclient.Client) *TokenSupplyController {
+	checkInterval := defaultTokenSupplyCheckInterval
+	if cfg.TokenSupplyConfig != nil && cfg.TokenSupplyConfig.CheckInterval != 0 {
+		checkInterval = cfg.TokenSupplyConfig.CheckInterval
+	}
+
+	return &TokenSupplyController{
+		tokenSupplyLogic:    crosschain.NewLogicTokenSupply(cfg, db, l1Client, l2Client),
+		checkInterval:       time.Duration(checkInterval) * time.Second,
+		stopTokenSupplyChan: make(chan struct{}),
+		tokenSupplyControllerRunningTotal: promauto.With(prometheus.DefaultRegisterer).NewCounter(prometheus.CounterOpts{
+			Name: "token_supply_controller_running_total",
+			Help: "The total number of token supply controller running.",
+		}),
+	}
+}
+
+// Watch starts checking the token supplies.
+func (c *TokenSupplyController) Watch(ctx context.Context) {
+	go c.watcherStart(ctx)
+}
+
+// Stop the token supply controller
+func (c *TokenSupplyController) Stop() {
+	c.stopTokenSupplyChan <- struct{}{}
+}
+
+func (c *TokenSupplyController) watcherStart(ctx context.Context) {
+	log.Info("token supply controller start successful", "check interval", c.checkInterval)
+
+	for {
+		select {
+		case <-ctx.Done():
+			if ctx.Err() != nil {
+				log.Error("TokenSupplyController watch canceled with error", "error", ctx.Err())
+			}
+			return
+		case <-c.stopTokenSupplyChan:
+			log.Info("TokenSupplyController the run loop exit")
+			return
+		default:
+		}
+
+		c.tokenSupplyControllerRunningTotal.Inc()
+
+		c.tokenSupplyLogic.CheckTokenSupply(ctx)
+
+		time.Sleep(c.checkInterval)
+	}
+}
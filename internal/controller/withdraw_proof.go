@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/scroll-tech/chain-monitor/internal/config"
+	messagematch "github.com/scroll-tech/chain-monitor/internal/logic/message_match"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+)
+
+// WithdrawProofController serves the withdraw proofs of the l2 sent messages
+type WithdrawProofController struct {
+	messageMatchLogic *messagematch.LogicMessageMatch
+}
+
+// NewWithdrawProofController create withdraw proof controller instance
+func NewWithdrawProofController(conf *config.Config, db *gorm.DB) *WithdrawProofController {
+	return &WithdrawProofController{
+		messageMatchLogic: messagematch.NewMessageMatchLogic(conf, db),
+	}
+}
+
+// WithdrawProof get the merkle proof, nonce and withdraw root of the l2 sent message
+func (w *WithdrawProofController) WithdrawProof(ctx *gin.Context) {
+	var param types.WithdrawProofParam
+	if err := ctx.ShouldBind(&param); err != nil {
+		types.RenderJSON(ctx, types.ErrParameterInvalidNo, err, nil)
+		return
+	}
+
+	proof, err := w.messageMatchLogic.GetWithdrawProof(ctx, param.MessageHash)
+	if errors.Is(err, messagematch.ErrWithdrawProofNotFound) {
+		types.RenderFailure(ctx, types.ErrWithdrawProofNotFoundNo, err)
+		return
+	}
+	if err != nil {
+		types.RenderFailure(ctx, types.InternalServerError, err)
+		return
+	}
+	types.RenderSuccess(ctx, proof)
+}
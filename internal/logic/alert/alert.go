@@ -0,0 +1,636 @@
+package alert
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+
+	"github.com/scroll-tech/chain-monitor/internal/orm"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+)
+
+// Severity the severity of an alert.
+type Severity string
+
+const (
+	// SeverityInfo the alert is informational.
+	SeverityInfo Severity = "info"
+	// SeverityWarning the alert needs attention but the funds are not at risk.
+	SeverityWarning Severity = "warning"
+	// SeverityCritical the alert indicates a broken invariant of the bridge.
+	SeverityCritical Severity = "critical"
+)
+
+// Category the check which raises the alert.
+type Category string
+
+const (
+	// CategoryWithdrawRoot the l2 withdraw root check.
+	CategoryWithdrawRoot Category = "withdraw_root"
+	// CategoryGatewayTransfer the gateway event and transfer event check.
+	CategoryGatewayTransfer Category = "gateway_transfer"
+	// CategoryGatewayMessage the gateway event and messenger message check.
+	CategoryGatewayMessage Category = "gateway_message"
+	// CategoryCrossChainGateway the cross chain gateway event check.
+	CategoryCrossChainGateway Category = "cross_chain_gateway"
+	// CategoryCrossChainMessenger the cross chain messenger event check.
+	CategoryCrossChainMessenger Category = "cross_chain_messenger"
+	// CategoryCrossChainETH the cross chain eth balance check.
+	CategoryCrossChainETH Category = "cross_chain_eth"
+	// CategoryGatewayEventDuplicated the duplicated gateway event check.
+	CategoryGatewayEventDuplicated Category = "gateway_event_duplicated"
+	// CategoryMessengerEventDuplicated the duplicated messenger event check.
+	CategoryMessengerEventDuplicated Category = "messenger_event_duplicated"
+	// CategoryFailedRelayedMessage the failed relayed message check.
+	CategoryFailedRelayedMessage Category = "failed_relayed_message"
+	// CategoryChainReorg the chain reorg detection.
+	CategoryChainReorg Category = "chain_reorg"
+	// CategoryStuckMessage the sla check of the messages which are not relayed in time.
+	CategoryStuckMessage Category = "stuck_message"
+	// CategoryFinalizedWithdrawRoot the withdraw root check of the batches finalized on l1.
+	CategoryFinalizedWithdrawRoot Category = "finalized_withdraw_root"
+	// CategoryBatchFinalized the status check of the batches finalized on l1.
+	CategoryBatchFinalized Category = "batch_finalized"
+	// CategoryBatchBlockRange the l2 block range decoding of the committed batches.
+	CategoryBatchBlockRange Category = "batch_block_range"
+	// CategoryMessageQueue the queue index check of the l1 messages popped by the committed batches.
+	CategoryMessageQueue Category = "message_queue"
+	// CategoryMessengerNonce the nonce check of the l1 sent messages.
+	CategoryMessengerNonce Category = "messenger_nonce"
+	// CategoryTokenSupply the invariant check of the l1 custody balance against the l2 bridged token supply.
+	CategoryTokenSupply Category = "token_supply"
+	// CategoryBridgedTokenTransfer the l2 bridged token minted or burned without a gateway event.
+	CategoryBridgedTokenTransfer Category = "bridged_token_transfer"
+)
+
+// Field is a named value of an alert, Number is set for the numeric values such as amounts and balances.
+type Field struct {
+	Name   string   `json:"name"`
+	Value  string   `json:"value"`
+	Number *big.Int `json:"number,omitempty"`
+}
+
+// StringField creates a field of string value.
+func StringField(name, value string) Field {
+	return Field{Name: name, Value: value}
+}
+
+// NumberField creates a field of numeric value.
+func NumberField(name string, value *big.Int) Field {
+	return Field{Name: name, Value: value.String(), Number: value}
+}
+
+// Uint64Field creates a field of uint64 value.
+func Uint64Field(name string, value uint64) Field {
+	return NumberField(name, new(big.Int).SetUint64(value))
+}
+
+// Alert is an alert raised by the checkers, which is rendered by the sinks it's routed to.
+type Alert struct {
+	Severity    Severity        `json:"severity"`
+	Category    Category        `json:"category"`
+	Title       string          `json:"title"`
+	Layer       types.LayerType `json:"layer,omitempty"`
+	BlockNumber uint64          `json:"block_number,omitempty"`
+	TxHash      string          `json:"tx_hash,omitempty"`
+	MessageHash string          `json:"message_hash,omitempty"`
+	Subject     string          `json:"subject,omitempty"`
+	Fields      []Field         `json:"fields,omitempty"`
+	Resolved    bool            `json:"resolved,omitempty"`
+	Time        time.Time       `json:"time"`
+}
+
+// Fingerprint identifies the identical alerts. The subject identifies the alerts which are not about a message,
+// such as the token of a supply check.
+func (a Alert) Fingerprint() string {
+	if a.MessageHash == "" && a.Subject != "" {
+		return Fingerprint(a.Category, a.Layer, a.Subject, a.BlockNumber)
+	}
+	return Fingerprint(a.Category, a.Layer, a.MessageHash, a.BlockNumber)
+}
+
+// Fingerprint identifies the identical alerts by the category and layer, with the message hash (or the subject)
+// or the block number if there is neither.
+func Fingerprint(category Category, layer types.LayerType, messageHash string, blockNumber uint64) string {
+	if messageHash != "" {
+		return fmt.Sprintf("%s:%d:%s", category, layer, messageHash)
+	}
+	return fmt.Sprintf("%s:%d:%d", category, layer, blockNumber)
+}
+
+// GatewayTransferInfo the alert info of gateway and transfer event
+type GatewayTransferInfo struct {
+	TokenAddress    common.Address
+	TokenType       types.TokenType
+	Layer           types.LayerType
+	EventType       types.EventType
+	BlockNumber     uint64
+	TxHash          common.Hash
+	MessageHash     common.Hash
+	Error           string
+	TransferBalance *big.Int
+	GatewayBalance  *big.Int
+}
+
+// GatewayMessageInfo the alert info of the gateway event which doesn't match its messenger message
+type GatewayMessageInfo struct {
+	TokenAddress string
+	TokenType    types.TokenType
+	Layer        types.LayerType
+	EventType    types.EventType
+	BlockNumber  uint64
+	TxHash       string
+	MessageHash  string
+	Error        string
+}
+
+// WithdrawRootInfo the alert info of withdraw root
+type WithdrawRootInfo struct {
+	BlockNumber          uint64
+	LastWithdrawRoot     common.Hash
+	ExpectedWithdrawRoot common.Hash
+}
+
+// ReorgInfo the alert info of chain reorg
+type ReorgInfo struct {
+	Layer               types.LayerType
+	ReorgBlockNumber    uint64
+	ProcessedBlockHash  common.Hash
+	CanonicalBlockHash  common.Hash
+	RollbackBlockNumber uint64
+	Error               string
+}
+
+// MessageQueueInfo the alert info of the l1 messages popped by the committed batch out of the queue order
+type MessageQueueInfo struct {
+	L1BlockNumber      uint64
+	L1TxHash           string
+	StartQueueIndex    uint64
+	Count              uint64
+	ExpectedQueueIndex uint64
+}
+
+// MessengerNonceInfo the alert info of the l1 sent message whose nonce doesn't follow the last one
+type MessengerNonceInfo struct {
+	L1BlockNumber        uint64
+	L1TxHash             string
+	MessageHash          string
+	MessageNonce         uint64
+	ExpectedMessageNonce uint64
+}
+
+// TokenSupplyInfo the alert info of the l1 custody balance of a token pair which doesn't match the l2 bridged token supply
+type TokenSupplyInfo struct {
+	L1Token            common.Address
+	L2Token            common.Address
+	L1Custody          common.Address
+	L1BlockNumber      uint64
+	L2BlockNumber      uint64
+	CustodyBalance     *big.Int
+	TotalSupply        *big.Int
+	PendingDeposits    *big.Int
+	PendingWithdrawals *big.Int
+	// the custody balance minus the total supply and the in flight amounts, negative if the l2 supply isn't fully backed.
+	Difference *big.Int
+}
+
+// WithdrawRootAlert creates the alert of withdraw root mismatch
+func WithdrawRootAlert(info WithdrawRootInfo) Alert {
+	return Alert{
+		Severity:    SeverityCritical,
+		Category:    CategoryWithdrawRoot,
+		Title:       "L2 withdraw root check failed",
+		Layer:       types.Layer2,
+		BlockNumber: info.BlockNumber,
+		Fields: []Field{
+			Uint64Field("block number", info.BlockNumber),
+			StringField("got withdraw root", info.LastWithdrawRoot.Hex()),
+			StringField("excepted withdraw root", info.ExpectedWithdrawRoot.Hex()),
+		},
+	}
+}
+
+// GatewayTransferAlert creates the alert of gateway and transfer event mismatch
+func GatewayTransferAlert(info GatewayTransferInfo) Alert {
+	return Alert{
+		Severity:    SeverityCritical,
+		Category:    CategoryGatewayTransfer,
+		Title:       "Gateway event and transfer event check failed",
+		Layer:       info.Layer,
+		BlockNumber: info.BlockNumber,
+		TxHash:      info.TxHash.Hex(),
+		MessageHash: info.MessageHash.Hex(),
+		Fields: []Field{
+			StringField("token type", info.TokenType.String()),
+			StringField("token address", info.TokenAddress.Hex()),
+			StringField("layer type", info.Layer.String()),
+			StringField("event type", info.EventType.String()),
+			Uint64Field("block number", info.BlockNumber),
+			StringField("tx_hash", info.TxHash.Hex()),
+			StringField("msg_hash", info.MessageHash.Hex()),
+			NumberField("transfer balance", info.TransferBalance),
+			NumberField("gateway balance", info.GatewayBalance),
+			StringField("err info", info.Error),
+		},
+	}
+}
+
+// GatewayMessageAlert creates the alert of gateway event and messenger message mismatch
+func GatewayMessageAlert(info GatewayMessageInfo) Alert {
+	return Alert{
+		Severity:    SeverityCritical,
+		Category:    CategoryGatewayMessage,
+		Title:       "Gateway event and messenger message check failed",
+		Layer:       info.Layer,
+		BlockNumber: info.BlockNumber,
+		TxHash:      info.TxHash,
+		MessageHash: info.MessageHash,
+		Fields: []Field{
+			StringField("token type", info.TokenType.String()),
+			StringField("token address", info.TokenAddress),
+			StringField("layer type", info.Layer.String()),
+			StringField("event type", info.EventType.String()),
+			Uint64Field("block number", info.BlockNumber),
+			StringField("tx_hash", info.TxHash),
+			StringField("msg_hash", info.MessageHash),
+			StringField("err info", info.Error),
+		},
+	}
+}
+
+// BridgedTokenTransferAlert creates the alert of the l2 bridged token minted or burned without a gateway event,
+// the negative transfer balance is minted and the positive one is burned.
+func BridgedTokenTransferAlert(info GatewayTransferInfo) Alert {
+	severity, title := SeverityWarning, "L2 bridged token burned without gateway event"
+	if info.TransferBalance.Sign() < 0 {
+		severity, title = SeverityCritical, "L2 bridged token minted without gateway event"
+	}
+	return Alert{
+		Severity:    severity,
+		Category:    CategoryBridgedTokenTransfer,
+		Title:       title,
+		Layer:       info.Layer,
+		BlockNumber: info.BlockNumber,
+		TxHash:      info.TxHash.Hex(),
+		Subject:     info.TokenAddress.Hex() + ":" + info.TxHash.Hex(),
+		Fields: []Field{
+			StringField("token address", info.TokenAddress.Hex()),
+			Uint64Field("block number", info.BlockNumber),
+			StringField("tx_hash", info.TxHash.Hex()),
+			NumberField("transfer balance", info.TransferBalance),
+			StringField("err info", info.Error),
+		},
+	}
+}
+
+// GatewayCrossChainAlert creates the alert of cross chain gateway event mismatch
+func GatewayCrossChainAlert(layer types.LayerType, message orm.GatewayMessageMatch, checkResult types.MismatchType) Alert {
+	return Alert{
+		Severity:    SeverityCritical,
+		Category:    CategoryCrossChainGateway,
+		Title:       "Cross chain gateway event check failed",
+		Layer:       layer,
+		MessageHash: message.MessageHash,
+		Fields: []Field{
+			StringField("database id", strconv.FormatInt(message.ID, 10)),
+			StringField("token type", types.TokenType(message.TokenType).String()),
+			StringField("l1 event type", types.EventType(message.L1EventType).String()),
+			StringField("l2 event type", types.EventType(message.L2EventType).String()),
+			StringField("mismatch type", checkResult.String()),
+			Uint64Field("l1 block number", message.L1BlockNumber),
+			Uint64Field("l2 block number", message.L2BlockNumber),
+			StringField("l1 amount", message.L1Amounts),
+			StringField("l2 amount", message.L2Amounts),
+			StringField("l1 token address", message.L1TokenAddress),
+			StringField("l2 token address", message.L2TokenAddress),
+			StringField("l1 token", message.L1TokenIds),
+			StringField("l2 token", message.L2TokenIds),
+			StringField("l1 tx_hash", message.L1TxHash),
+			StringField("l2 tx_hash", message.L2TxHash),
+			StringField("msg_hash", message.MessageHash),
+		},
+	}
+}
+
+// MessengerCrossChainAlert creates the alert of the messenger message whose relayed event doesn't match the sent event of the other layer.
+func MessengerCrossChainAlert(layer types.LayerType, message orm.MessengerMessageMatch, checkResult types.MismatchType) Alert {
+	return Alert{
+		Severity:    SeverityCritical,
+		Category:    CategoryCrossChainMessenger,
+		Title:       "Cross chain messenger event check failed",
+		Layer:       layer,
+		MessageHash: message.MessageHash,
+		Fields: []Field{
+			StringField("database id", strconv.FormatInt(message.ID, 10)),
+			StringField("l1 event type", types.EventType(message.L1EventType).String()),
+			StringField("l2 event type", types.EventType(message.L2EventType).String()),
+			StringField("mismatch type", checkResult.String()),
+			Uint64Field("l1 block number", message.L1BlockNumber),
+			Uint64Field("l2 block number", message.L2BlockNumber),
+			StringField("l1 tx_hash", message.L1TxHash),
+			StringField("l2 tx_hash", message.L2TxHash),
+			StringField("msg_hash", message.MessageHash),
+		},
+	}
+}
+
+// CrossChainETHAlert creates the alert of cross chain eth balance mismatch
+func CrossChainETHAlert(layer types.LayerType, message *orm.MessengerMessageMatch, expectedEndBalance, actualEndBalance *big.Int) Alert {
+	blockNumber := message.L1BlockNumber
+	if layer == types.Layer2 {
+		blockNumber = message.L2BlockNumber
+	}
+	return Alert{
+		Severity:    SeverityCritical,
+		Category:    CategoryCrossChainETH,
+		Title:       "Cross chain ETH balance check failed",
+		Layer:       layer,
+		BlockNumber: blockNumber,
+		MessageHash: message.MessageHash,
+		Fields: []Field{
+			StringField("database id", strconv.FormatInt(message.ID, 10)),
+			StringField("l1 event type", types.EventType(message.L1EventType).String()),
+			StringField("l2 event type", types.EventType(message.L2EventType).String()),
+			Uint64Field("l1 block number", message.L1BlockNumber),
+			Uint64Field("l2 block number", message.L2BlockNumber),
+			StringField("l1 tx_hash", message.L1TxHash),
+			StringField("l2 tx_hash", message.L2TxHash),
+			StringField("msg_hash", message.MessageHash),
+			NumberField("expected end balance", expectedEndBalance),
+			NumberField("actual end balance", actualEndBalance),
+		},
+	}
+}
+
+// GatewayMessageMatchDuplicatedAlert creates the alert of duplicated gateway message
+func GatewayMessageMatchDuplicatedAlert(layer types.LayerType, message orm.GatewayMessageMatch) Alert {
+	eventType, blockNumber, txHash := message.L1EventType, message.L1BlockNumber, message.L1TxHash
+	if layer == types.Layer2 {
+		eventType, blockNumber, txHash = message.L2EventType, message.L2BlockNumber, message.L2TxHash
+	}
+	return duplicatedEventAlert(CategoryGatewayEventDuplicated, "Gateway event duplicated", layer, eventType, blockNumber, txHash, message.MessageHash)
+}
+
+// MessengerMessageMatchDuplicatedAlert creates the alert of duplicated messenger message
+func MessengerMessageMatchDuplicatedAlert(layer types.LayerType, message orm.MessengerMessageMatch) Alert {
+	eventType, blockNumber, txHash := message.L1EventType, message.L1BlockNumber, message.L1TxHash
+	if layer == types.Layer2 {
+		eventType, blockNumber, txHash = message.L2EventType, message.L2BlockNumber, message.L2TxHash
+	}
+	return duplicatedEventAlert(CategoryMessengerEventDuplicated, "Messenger event duplicated", layer, eventType, blockNumber, txHash, message.MessageHash)
+}
+
+func duplicatedEventAlert(category Category, title string, layer types.LayerType, eventType int, blockNumber uint64, txHash, messageHash string) Alert {
+	prefix := "l1"
+	if layer == types.Layer2 {
+		prefix = "l2"
+	}
+	return Alert{
+		Severity:    SeverityWarning,
+		Category:    category,
+		Title:       title,
+		Layer:       layer,
+		BlockNumber: blockNumber,
+		TxHash:      txHash,
+		MessageHash: messageHash,
+		Fields: []Field{
+			StringField("layer", layer.String()),
+			StringField(prefix+" event type", types.EventType(eventType).String()),
+			Uint64Field(prefix+" block number", blockNumber),
+			StringField(prefix+" tx_hash", txHash),
+			StringField("msg_hash", messageHash),
+		},
+	}
+}
+
+// FailedRelayedMessageAlert creates the alert of failed relayed message
+func FailedRelayedMessageAlert(message orm.FailedRelayedMessage, reason string) Alert {
+	return Alert{
+		Severity:    SeverityWarning,
+		Category:    CategoryFailedRelayedMessage,
+		Title:       "Message relay failed",
+		Layer:       types.LayerType(message.Layer),
+		BlockNumber: message.LastFailedBlockNumber,
+		TxHash:      message.LastFailedTxHash,
+		MessageHash: message.MessageHash,
+		Fields: []Field{
+			StringField("database id", strconv.FormatInt(message.ID, 10)),
+			StringField("layer", types.LayerType(message.Layer).String()),
+			Uint64Field("failed count", message.FailedCount),
+			StringField("first failed at", message.FirstFailedAt.String()),
+			Uint64Field("last failed block number", message.LastFailedBlockNumber),
+			StringField("last failed tx_hash", message.LastFailedTxHash),
+			StringField("msg_hash", message.MessageHash),
+			StringField("reason", reason),
+		},
+	}
+}
+
+// StuckMessageAlert the alert of a message which is sent on the layer but not relayed on the other layer within the threshold.
+func StuckMessageAlert(layer types.LayerType, message orm.MessengerMessageMatch, age, threshold time.Duration) Alert {
+	blockNumber, txHash := message.L1BlockNumber, message.L1TxHash
+	title := "Deposit message not relayed on L2"
+	if layer == types.Layer2 {
+		blockNumber, txHash = message.L2BlockNumber, message.L2TxHash
+		title = "Withdraw message not relayed on L1"
+	}
+
+	return Alert{
+		Severity:    SeverityWarning,
+		Category:    CategoryStuckMessage,
+		Title:       title,
+		Layer:       layer,
+		BlockNumber: blockNumber,
+		TxHash:      txHash,
+		MessageHash: message.MessageHash,
+		Fields: []Field{
+			StringField("database id", strconv.FormatInt(message.ID, 10)),
+			StringField("sent layer", layer.String()),
+			Uint64Field("sent block number", blockNumber),
+			StringField("sent tx_hash", txHash),
+			StringField("msg_hash", message.MessageHash),
+			StringField("age", age.Round(time.Second).String()),
+			StringField("threshold", threshold.String()),
+		},
+	}
+}
+
+// FinalizedWithdrawRootAlert creates the alert of a finalized batch whose withdraw root mismatches the one computed locally
+func FinalizedWithdrawRootAlert(batch orm.Batch, localWithdrawRoot common.Hash) Alert {
+	return Alert{
+		Severity:    SeverityCritical,
+		Category:    CategoryFinalizedWithdrawRoot,
+		Title:       "Finalized batch withdraw root mismatch",
+		Layer:       types.Layer1,
+		BlockNumber: batch.FinalizeBlockNumber,
+		TxHash:      batch.FinalizeTxHash,
+		Fields: []Field{
+			Uint64Field("batch index", batch.BatchIndex),
+			StringField("batch hash", batch.BatchHash),
+			Uint64Field("l2 start block number", batch.StartBlockNumber),
+			Uint64Field("l2 end block number", batch.EndBlockNumber),
+			Uint64Field("finalize block number", batch.FinalizeBlockNumber),
+			StringField("finalize tx_hash", batch.FinalizeTxHash),
+			StringField("finalized withdraw root", batch.WithdrawRoot),
+			StringField("local withdraw root", localWithdrawRoot.Hex()),
+		},
+	}
+}
+
+// BatchFinalizedAlert creates the alert of a batch finalized on l1 while its status is pending or invalid
+func BatchFinalizedAlert(batch orm.Batch, status types.BatchStatus) Alert {
+	severity, title := SeverityWarning, "Batch finalized before it's verified"
+	if status == types.BatchStatusTypeInvalid {
+		severity, title = SeverityCritical, "Invalid batch finalized"
+	}
+	return Alert{
+		Severity:    severity,
+		Category:    CategoryBatchFinalized,
+		Title:       title,
+		Layer:       types.Layer1,
+		BlockNumber: batch.FinalizeBlockNumber,
+		TxHash:      batch.FinalizeTxHash,
+		Fields: []Field{
+			Uint64Field("batch index", batch.BatchIndex),
+			StringField("batch hash", batch.BatchHash),
+			StringField("status", status.String()),
+			Uint64Field("l2 start block number", batch.StartBlockNumber),
+			Uint64Field("l2 end block number", batch.EndBlockNumber),
+			Uint64Field("finalize block number", batch.FinalizeBlockNumber),
+			StringField("finalize tx_hash", batch.FinalizeTxHash),
+		},
+	}
+}
+
+// BatchBlockRangeUnknownAlert the l2 block range of the committed batch can't be decoded, its l2 blocks and withdraw root aren't checked.
+func BatchBlockRangeUnknownAlert(batch orm.Batch) Alert {
+	return Alert{
+		Severity:    SeverityCritical,
+		Category:    CategoryBatchBlockRange,
+		Title:       "Unknown l2 block range of the committed batch",
+		Layer:       types.Layer1,
+		BlockNumber: batch.CommitBlockNumber,
+		TxHash:      batch.CommitTxHash,
+		Fields: []Field{
+			Uint64Field("batch index", batch.BatchIndex),
+			StringField("batch hash", batch.BatchHash),
+			Uint64Field("commit block number", batch.CommitBlockNumber),
+			StringField("commit tx_hash", batch.CommitTxHash),
+		},
+	}
+}
+
+// MessageQueueSkippedAlert creates the alert of the l1 messages missed by the committed batches, which are neither
+// included on l2 nor skipped in the bitmap
+func MessageQueueSkippedAlert(info MessageQueueInfo) Alert {
+	return messageQueueAlert(SeverityCritical, "L1 messages missed by the committed batches", info)
+}
+
+// MessageQueueOutOfOrderAlert creates the alert of the l1 messages popped by the committed batches again
+func MessageQueueOutOfOrderAlert(info MessageQueueInfo) Alert {
+	return messageQueueAlert(SeverityCritical, "L1 messages popped by the committed batches out of order", info)
+}
+
+func messageQueueAlert(severity Severity, title string, info MessageQueueInfo) Alert {
+	return Alert{
+		Severity:    severity,
+		Category:    CategoryMessageQueue,
+		Title:       title,
+		Layer:       types.Layer1,
+		BlockNumber: info.L1BlockNumber,
+		TxHash:      info.L1TxHash,
+		Fields: []Field{
+			Uint64Field("l1 block number", info.L1BlockNumber),
+			StringField("l1 tx_hash", info.L1TxHash),
+			Uint64Field("start queue index", info.StartQueueIndex),
+			Uint64Field("count", info.Count),
+			Uint64Field("expected queue index", info.ExpectedQueueIndex),
+		},
+	}
+}
+
+// MessengerNonceGapAlert creates the alert of the l1 sent message nonces which are missed, neither sent by the messenger
+// nor enqueued by the enforced transactions
+func MessengerNonceGapAlert(info MessengerNonceInfo) Alert {
+	return messengerNonceAlert("L1 sent message nonces missed", info)
+}
+
+// MessengerNonceDuplicatedAlert creates the alert of the l1 sent message whose nonce is sent before
+func MessengerNonceDuplicatedAlert(info MessengerNonceInfo) Alert {
+	return messengerNonceAlert("L1 sent message nonce duplicated", info)
+}
+
+func messengerNonceAlert(title string, info MessengerNonceInfo) Alert {
+	return Alert{
+		Severity:    SeverityCritical,
+		Category:    CategoryMessengerNonce,
+		Title:       title,
+		Layer:       types.Layer1,
+		BlockNumber: info.L1BlockNumber,
+		TxHash:      info.L1TxHash,
+		MessageHash: info.MessageHash,
+		Fields: []Field{
+			Uint64Field("l1 block number", info.L1BlockNumber),
+			StringField("l1 tx_hash", info.L1TxHash),
+			StringField("msg_hash", info.MessageHash),
+			Uint64Field("message nonce", info.MessageNonce),
+			Uint64Field("expected message nonce", info.ExpectedMessageNonce),
+		},
+	}
+}
+
+// TokenSupplyAlert creates the alert of the l1 custody balance which doesn't match the l2 bridged token supply
+func TokenSupplyAlert(info TokenSupplyInfo) Alert {
+	severity, title := SeverityWarning, "L1 custody balance exceeds L2 bridged token supply"
+	if info.Difference.Sign() < 0 {
+		severity, title = SeverityCritical, "L2 bridged token supply exceeds L1 custody balance"
+	}
+	return Alert{
+		Severity:    severity,
+		Category:    CategoryTokenSupply,
+		Title:       title,
+		Layer:       types.Layer2,
+		BlockNumber: info.L2BlockNumber,
+		Subject:     info.L2Token.Hex(),
+		Fields: []Field{
+			StringField("l1 token", info.L1Token.Hex()),
+			StringField("l2 token", info.L2Token.Hex()),
+			StringField("l1 custody", info.L1Custody.Hex()),
+			Uint64Field("l1 block number", info.L1BlockNumber),
+			Uint64Field("l2 block number", info.L2BlockNumber),
+			NumberField("l1 custody balance", info.CustodyBalance),
+			NumberField("l2 total supply", info.TotalSupply),
+			NumberField("pending deposits", info.PendingDeposits),
+			NumberField("pending withdrawals", info.PendingWithdrawals),
+			NumberField("difference", info.Difference),
+		},
+	}
+}
+
+// ReorgAlert creates the alert of chain reorg
+func ReorgAlert(info ReorgInfo) Alert {
+	a := Alert{
+		Severity:    SeverityWarning,
+		Category:    CategoryChainReorg,
+		Title:       "Chain reorg detected",
+		Layer:       info.Layer,
+		BlockNumber: info.ReorgBlockNumber,
+		Fields: []Field{
+			StringField("layer", info.Layer.String()),
+			Uint64Field("reorg block number", info.ReorgBlockNumber),
+			StringField("processed block hash", info.ProcessedBlockHash.Hex()),
+			StringField("canonical block hash", info.CanonicalBlockHash.Hex()),
+		},
+	}
+	if info.Error != "" {
+		// The fork point is unknown, the events may have been rolled back too far or not far enough.
+		a.Severity = SeverityCritical
+		a.Fields = append(a.Fields, StringField("err info", info.Error))
+	} else {
+		a.Fields = append(a.Fields, Uint64Field("rollback from block number", info.RollbackBlockNumber))
+	}
+	return a
+}
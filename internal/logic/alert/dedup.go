@@ -0,0 +1,129 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"gorm.io/gorm"
+
+	"github.com/scroll-tech/chain-monitor/internal/orm"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+)
+
+var alertSuppressedTotal = promauto.With(prometheus.DefaultRegisterer).NewCounterVec(prometheus.CounterOpts{
+	Name: "alert_suppressed_total",
+	Help: "The total number of identical alerts suppressed within the throttle window.",
+}, []string{"category"})
+
+// reminderClaimLimit the max number of the still firing alerts reminded by a sweep.
+const reminderClaimLimit = 100
+
+// deduplicator records the alerts in db, the identical alerts are suppressed within the throttle window
+// and notified again as still firing reminders after it.
+type deduplicator struct {
+	alertOrm         *orm.Alert
+	throttleWindow   time.Duration
+	reminderInterval time.Duration
+}
+
+func newDeduplicator(db *gorm.DB, throttleWindow, reminderInterval time.Duration) *deduplicator {
+	return &deduplicator{
+		alertOrm:         orm.NewAlert(db),
+		throttleWindow:   throttleWindow,
+		reminderInterval: reminderInterval,
+	}
+}
+
+// check records the occurrence of the alert, and returns the alert to notify and whether it should be notified.
+func (d *deduplicator) check(ctx context.Context, alert Alert) (Alert, bool, error) {
+	fingerprint := alert.Fingerprint()
+	newRecord := orm.Alert{
+		Fingerprint: fingerprint,
+		Category:    string(alert.Category),
+		Severity:    string(alert.Severity),
+		Title:       alert.Title,
+		Layer:       int(alert.Layer),
+		MessageHash: alert.MessageHash,
+		BlockNumber: alert.BlockNumber,
+	}
+	record, notify, err := d.alertOrm.InsertOrUpdateOccurrence(ctx, newRecord, d.throttleWindow)
+	if err != nil {
+		return alert, true, fmt.Errorf("record alert occurrence failed, fingerprint: %s, err: %w", fingerprint, err)
+	}
+
+	if !notify {
+		alertSuppressedTotal.WithLabelValues(string(alert.Category)).Inc()
+		return alert, false, nil
+	}
+
+	// the first occurrence, or the refired occurrence of a resolved alert.
+	if record.OccurrenceCount == 1 {
+		return alert, true, nil
+	}
+
+	alert.Title = "Still firing: " + alert.Title
+	alert.Fields = append(alert.Fields,
+		Uint64Field("occurrences", record.OccurrenceCount),
+		StringField("first seen at", record.FirstSeenAt.String()),
+	)
+	return alert, true, nil
+}
+
+// remind claims the firing alerts which are not notified within the reminder interval, and returns the still firing
+// reminders of them. The alerts which are not raised again by the checkers are reminded by it until they're resolved.
+func (d *deduplicator) remind(ctx context.Context) ([]Alert, error) {
+	records, err := d.alertOrm.ClaimReminderAlerts(ctx, d.reminderInterval, reminderClaimLimit)
+	if err != nil {
+		return nil, fmt.Errorf("claim reminder alerts failed, err: %w", err)
+	}
+
+	var alerts []Alert
+	for _, record := range records {
+		alerts = append(alerts, Alert{
+			Severity:    Severity(record.Severity),
+			Category:    Category(record.Category),
+			Title:       "Still firing: " + record.Title,
+			Layer:       types.LayerType(record.Layer),
+			BlockNumber: record.BlockNumber,
+			MessageHash: record.MessageHash,
+			Fields: []Field{
+				StringField("fingerprint", record.Fingerprint),
+				Uint64Field("occurrences", record.OccurrenceCount),
+				StringField("first seen at", record.FirstSeenAt.String()),
+				StringField("last seen at", record.LastSeenAt.String()),
+			},
+		})
+	}
+	return alerts, nil
+}
+
+// resolve marks the firing alerts of the fingerprints as resolved, and returns the resolved notifications of them.
+func (d *deduplicator) resolve(ctx context.Context, fingerprints []string) ([]Alert, error) {
+	records, err := d.alertOrm.ResolveAlerts(ctx, fingerprints)
+	if err != nil {
+		return nil, fmt.Errorf("resolve alerts failed, err: %w", err)
+	}
+
+	var alerts []Alert
+	for _, record := range records {
+		alerts = append(alerts, Alert{
+			Severity:    Severity(record.Severity),
+			Category:    Category(record.Category),
+			Title:       "Resolved: " + record.Title,
+			Layer:       types.LayerType(record.Layer),
+			BlockNumber: record.BlockNumber,
+			MessageHash: record.MessageHash,
+			Resolved:    true,
+			Fields: []Field{
+				StringField("fingerprint", record.Fingerprint),
+				Uint64Field("occurrences", record.OccurrenceCount),
+				StringField("first seen at", record.FirstSeenAt.String()),
+				StringField("last seen at", record.LastSeenAt.String()),
+			},
+		})
+	}
+	return alerts, nil
+}
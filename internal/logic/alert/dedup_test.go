@@ -0,0 +1,52 @@
+package alert
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scroll-tech/chain-monitor/internal/types"
+	"github.com/scroll-tech/chain-monitor/internal/utils/testcontainer"
+)
+
+func TestDeduplicator(t *testing.T) {
+	ctx := context.Background()
+	db := testcontainer.SetupDB(ctx, t)
+
+	var alerts []Alert
+	m := NewManagerWithSinks(NewFuncSink("stdout", func(alert Alert) { alerts = append(alerts, alert) }))
+	m.dedup = newDeduplicator(db, time.Hour, time.Hour)
+
+	alert := Alert{Severity: SeverityCritical, Category: CategoryWithdrawRoot, Title: "withdraw root mismatch", Layer: types.Layer2, BlockNumber: 100}
+	m.deduplicate(ctx, alert)
+	m.deduplicate(ctx, alert)
+	assert.Len(t, alerts, 1)
+	assert.Equal(t, "withdraw root mismatch", alerts[0].Title)
+
+	// the firing alert is reminded once the reminder interval passes.
+	m.remind(ctx)
+	assert.Len(t, alerts, 1)
+	m.dedup.reminderInterval = 0
+	m.remind(ctx)
+	assert.Len(t, alerts, 2)
+	assert.Equal(t, "Still firing: withdraw root mismatch", alerts[1].Title)
+
+	// the identical alert is notified as still firing once the throttle window passes.
+	m.dedup.throttleWindow = 0
+	m.deduplicate(ctx, alert)
+	assert.Len(t, alerts, 3)
+	assert.Equal(t, "Still firing: withdraw root mismatch", alerts[2].Title)
+
+	// the resolved alert is notified as a new alert if it's raised again.
+	m.dedup.throttleWindow = time.Hour
+	resolved, err := m.dedup.resolve(ctx, []string{alert.Fingerprint()})
+	assert.NoError(t, err)
+	assert.Len(t, resolved, 1)
+	m.remind(ctx)
+	assert.Len(t, alerts, 3)
+	m.deduplicate(ctx, alert)
+	assert.Len(t, alerts, 4)
+	assert.Equal(t, "withdraw root mismatch", alerts[3].Title)
+}
@@ -0,0 +1,340 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"github.com/scroll-tech/chain-monitor/internal/config"
+	"github.com/scroll-tech/chain-monitor/internal/orm"
+	"github.com/scroll-tech/chain-monitor/internal/utils"
+	"github.com/scroll-tech/chain-monitor/internal/utils/fanout"
+)
+
+const (
+	defaultWorkerCount      = 1
+	defaultWorkerBufferSize = 1000
+
+	defaultOutboxMaxAttempts     = 10
+	defaultOutboxPollInterval    = 5 * time.Second
+	defaultOutboxRetryBackoff    = 5 * time.Second
+	defaultOutboxMaxRetryBackoff = 10 * time.Minute
+
+	// outboxQueryTimeout bounds the time the outbox worker and the run loop wait for the db.
+	outboxQueryTimeout = 3 * time.Second
+	// outboxDeliverTimeout bounds a delivery, the claimed alerts are not claimed again within the lease.
+	outboxDeliverTimeout = time.Minute
+	outboxClaimLease     = 2 * outboxDeliverTimeout
+)
+
+var (
+	alertSinkRunningTotal = promauto.With(prometheus.DefaultRegisterer).NewCounterVec(prometheus.CounterOpts{
+		Name: "alert_sink_running_total",
+		Help: "The total number of alert sink running.",
+	}, []string{"sink"})
+
+	alertSinkQueueDepth = promauto.With(prometheus.DefaultRegisterer).NewGaugeVec(prometheus.GaugeOpts{
+		Name: "alert_sink_queue_depth",
+		Help: "The number of alerts waiting to be delivered by the sink, including the pending alerts in the outbox.",
+	}, []string{"sink"})
+
+	alertSinkSendDuration = promauto.With(prometheus.DefaultRegisterer).NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "alert_sink_send_duration_seconds",
+		Help:    "The latency of delivering an alert to the sink.",
+		Buckets: prometheus.ExponentialBuckets(0.05, 2, 10),
+	}, []string{"sink"})
+
+	alertOutboxDeadLetterTotal = promauto.With(prometheus.DefaultRegisterer).NewCounterVec(prometheus.CounterOpts{
+		Name: "alert_outbox_dead_letter_total",
+		Help: "The total number of alerts moved to the dead letter after the max delivery attempts.",
+	}, []string{"sink"})
+)
+
+// durableSink is implemented by the sinks which can persist the alerts in the outbox before delivering them.
+type durableSink interface {
+	enableOutbox(db *gorm.DB, cfg *config.AlertOutboxConfig)
+}
+
+// queuedSink queues the alerts and delivers them by the fanout workers, so the checkers are not blocked by the network.
+// If the outbox is enabled, the alerts are persisted in db first, and retried with backoff until they're delivered
+// or moved to the dead letter, so they survive the restarts and the sink outages.
+type queuedSink struct {
+	name    string
+	deliver func(ctx context.Context, alert Alert) error
+
+	ctx             context.Context
+	senderQueue     chan Alert
+	sendWorker      *fanout.Fanout
+	stopTimeoutChan chan struct{}
+
+	outboxOrm       *orm.AlertOutbox
+	outboxQueue     chan Alert
+	outboxStop      chan struct{}
+	claimLimit      int
+	maxAttempts     int
+	pollInterval    time.Duration
+	retryBackoff    time.Duration
+	maxRetryBackoff time.Duration
+}
+
+func newQueuedSink(ctx context.Context, name string, workerCount, workerBufferSize int, deliver func(ctx context.Context, alert Alert) error) *queuedSink {
+	if workerCount <= 0 {
+		workerCount = defaultWorkerCount
+	}
+	if workerBufferSize <= 0 {
+		workerBufferSize = defaultWorkerBufferSize
+	}
+
+	return &queuedSink{
+		name:            name,
+		deliver:         deliver,
+		ctx:             ctx,
+		senderQueue:     make(chan Alert, workerBufferSize),
+		stopTimeoutChan: make(chan struct{}),
+		sendWorker: fanout.New(name,
+			fanout.WithWorker(workerCount),
+			fanout.WithBuffer(workerBufferSize),
+		),
+		outboxQueue: make(chan Alert, workerBufferSize),
+		outboxStop:  make(chan struct{}),
+		claimLimit:  workerBufferSize,
+	}
+}
+
+func (s *queuedSink) enableOutbox(db *gorm.DB, cfg *config.AlertOutboxConfig) {
+	s.outboxOrm = orm.NewAlertOutbox(db)
+	s.maxAttempts = defaultOutboxMaxAttempts
+	s.pollInterval = defaultOutboxPollInterval
+	s.retryBackoff = defaultOutboxRetryBackoff
+	s.maxRetryBackoff = defaultOutboxMaxRetryBackoff
+	if cfg.MaxAttempts > 0 {
+		s.maxAttempts = cfg.MaxAttempts
+	}
+	if cfg.PollInterval > 0 {
+		s.pollInterval = time.Duration(cfg.PollInterval) * time.Second
+	}
+	if cfg.RetryBackoff > 0 {
+		s.retryBackoff = time.Duration(cfg.RetryBackoff) * time.Second
+	}
+	if cfg.MaxRetryBackoff > 0 {
+		s.maxRetryBackoff = time.Duration(cfg.MaxRetryBackoff) * time.Second
+	}
+}
+
+// Name returns the name of the sink
+func (s *queuedSink) Name() string {
+	return s.name
+}
+
+// Send queues the alert to be persisted in the outbox by the outbox worker, or queues it in memory if the outbox is
+// disabled or its queue is full. The alert is dropped if the memory queue is full, Send never waits for the db or the delivery.
+func (s *queuedSink) Send(alert Alert) error {
+	if s.outboxOrm != nil {
+		select {
+		case s.outboxQueue <- alert:
+			return nil
+		default:
+			log.Warn("alert outbox queue is full, fallback to the memory queue", "sink", s.name, "category", alert.Category)
+		}
+	}
+	return s.enqueue(alert)
+}
+
+// enqueue queues the alert in memory to be delivered by the fanout workers.
+func (s *queuedSink) enqueue(alert Alert) error {
+	select {
+	case s.senderQueue <- alert:
+		alertSinkQueueDepth.WithLabelValues(s.name).Inc()
+		return nil
+	default:
+		return fmt.Errorf("alert queue of sink %s is full", s.name)
+	}
+}
+
+func (s *queuedSink) insertOutbox(alert Alert) error {
+	payload, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("marshal alert failed, err: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, outboxQueryTimeout)
+	defer cancel()
+	outbox := orm.AlertOutbox{
+		Sink:        s.name,
+		Category:    string(alert.Category),
+		Payload:     string(payload),
+		Layer:       int(alert.Layer),
+		BlockNumber: alert.BlockNumber,
+	}
+	return s.outboxOrm.InsertAlertOutbox(ctx, outbox)
+}
+
+// Start the sink
+func (s *queuedSink) Start() {
+	if s.outboxOrm != nil {
+		go s.runOutbox()
+	}
+	go s.run()
+}
+
+// Stop the sink, the undelivered alerts in the outbox are delivered after restart.
+func (s *queuedSink) Stop() {
+	close(s.outboxStop)
+	s.stopTimeoutChan <- struct{}{}
+}
+
+// runOutbox persists the queued alerts in the outbox and drains the outbox, the alerts failed to be persisted fall back
+// to the memory queue. It's separated from the run loop, so the memory queue is delivered even if the db hangs.
+func (s *queuedSink) runOutbox() {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+	// deliver the alerts left in the outbox by the last run.
+	s.drainOutbox()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-s.outboxStop:
+			return
+		case <-ticker.C:
+			s.drainOutbox()
+		case alert := <-s.outboxQueue:
+			if err := s.insertOutbox(alert); err != nil {
+				log.Error("persist alert in outbox failed, fallback to the memory queue", "sink", s.name, "category", alert.Category, "error", err)
+				if enqueueErr := s.enqueue(alert); enqueueErr != nil {
+					alertSinkSendFailureTotal.WithLabelValues(s.name).Inc()
+					log.Error("queue alert failed", "sink", s.name, "category", alert.Category, "error", enqueueErr)
+				}
+				continue
+			}
+			s.drainOutbox()
+		}
+	}
+}
+
+func (s *queuedSink) send(alert Alert) {
+	doSend := func(ctx context.Context) {
+		alertSinkQueueDepth.WithLabelValues(s.name).Dec()
+		if err := s.timedDeliver(ctx, alert); err != nil {
+			log.Error("appear error when deliver alert", "sink", s.name, "category", alert.Category, "err", err)
+		}
+	}
+
+	if err := s.sendWorker.Do(context.Background(), doSend); err != nil {
+		log.Error("do send alert failed", "sink", s.name, "error", err, "category", alert.Category)
+	}
+}
+
+func (s *queuedSink) timedDeliver(ctx context.Context, alert Alert) error {
+	start := time.Now()
+	err := s.deliver(ctx, alert)
+	alertSinkSendDuration.WithLabelValues(s.name).Observe(time.Since(start).Seconds())
+	if err != nil {
+		alertSinkSendFailureTotal.WithLabelValues(s.name).Inc()
+	}
+	return err
+}
+
+// drainOutbox claims the due alerts in the outbox and delivers them by the fanout workers.
+func (s *queuedSink) drainOutbox() {
+	if s.outboxOrm == nil {
+		return
+	}
+
+	countCtx, countCancel := context.WithTimeout(s.ctx, outboxQueryTimeout)
+	defer countCancel()
+	if pending, err := s.outboxOrm.CountPendingAlertOutboxes(countCtx, s.name); err == nil {
+		alertSinkQueueDepth.WithLabelValues(s.name).Set(float64(pending) + float64(len(s.senderQueue)))
+	}
+
+	claimCtx, claimCancel := context.WithTimeout(s.ctx, outboxQueryTimeout)
+	defer claimCancel()
+	outboxes, err := s.outboxOrm.ClaimPendingAlertOutboxes(claimCtx, s.name, s.claimLimit, outboxClaimLease)
+	if err != nil {
+		log.Error("claim alerts in outbox failed", "sink", s.name, "error", err)
+		return
+	}
+
+	for _, outbox := range outboxes {
+		outbox := outbox
+		doSend := func(ctx context.Context) {
+			s.deliverOutbox(outbox)
+		}
+		if err := s.sendWorker.Do(s.ctx, doSend); err != nil {
+			// The claimed alerts are delivered after the lease expires.
+			log.Error("do send outbox alert failed", "sink", s.name, "error", err, "category", outbox.Category)
+			return
+		}
+	}
+}
+
+func (s *queuedSink) deliverOutbox(outbox orm.AlertOutbox) {
+	var alert Alert
+	if err := json.Unmarshal([]byte(outbox.Payload), &alert); err != nil {
+		log.Error("unmarshal outbox alert failed", "sink", s.name, "id", outbox.ID, "error", err)
+		s.failOutbox(outbox, fmt.Errorf("unmarshal alert failed, err: %w", err), true)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, outboxDeliverTimeout)
+	defer cancel()
+	if err := s.timedDeliver(ctx, alert); err != nil {
+		log.Warn("deliver outbox alert failed", "sink", s.name, "id", outbox.ID, "attempts", outbox.Attempts+1, "category", alert.Category, "error", err)
+		s.failOutbox(outbox, err, outbox.Attempts+1 >= s.maxAttempts)
+		return
+	}
+
+	if err := s.outboxOrm.UpdateSent(s.ctx, outbox.ID); err != nil {
+		log.Error("update outbox alert sent failed", "sink", s.name, "id", outbox.ID, "error", err)
+	}
+}
+
+func (s *queuedSink) failOutbox(outbox orm.AlertOutbox, deliverErr error, deadLetter bool) {
+	nextAttemptAt := utils.NowUTC().Add(s.backoff(outbox.Attempts + 1))
+	if err := s.outboxOrm.UpdateFailed(s.ctx, outbox.ID, deliverErr.Error(), nextAttemptAt, deadLetter); err != nil {
+		log.Error("update outbox alert failed failed", "sink", s.name, "id", outbox.ID, "error", err)
+		return
+	}
+	if deadLetter {
+		alertOutboxDeadLetterTotal.WithLabelValues(s.name).Inc()
+		log.Error("alert moved to the dead letter", "sink", s.name, "id", outbox.ID, "category", outbox.Category, "error", deliverErr)
+	}
+}
+
+// backoff returns the exponential backoff before the next attempt.
+func (s *queuedSink) backoff(attempts int) time.Duration {
+	backoff := s.retryBackoff
+	for i := 1; i < attempts && backoff < s.maxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > s.maxRetryBackoff {
+		backoff = s.maxRetryBackoff
+	}
+	return backoff
+}
+
+func (s *queuedSink) run() {
+	for {
+		alertSinkRunningTotal.WithLabelValues(s.name).Inc()
+
+		select {
+		case alert := <-s.senderQueue:
+			s.send(alert)
+		case <-s.ctx.Done():
+			if s.ctx.Err() != nil {
+				log.Error("alert sink canceled with error", "sink", s.name, "error", s.ctx.Err())
+			}
+			return
+		case <-s.stopTimeoutChan:
+			log.Info("alert sink the run loop exit", "sink", s.name)
+			return
+		}
+	}
+}
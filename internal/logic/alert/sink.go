@@ -0,0 +1,365 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"github.com/scroll-tech/chain-monitor/internal/config"
+	"github.com/scroll-tech/chain-monitor/internal/utils"
+)
+
+const (
+	sinkTypeSlack   = "slack"
+	sinkTypeWebhook = "webhook"
+	sinkTypeStdout  = "stdout"
+	sinkTypeFile    = "file"
+
+	// slackSinkName the name of the sink created by the slack webhook config.
+	slackSinkName = "slack"
+
+	// reminderSweepInterval the interval to sweep the firing alerts which are due to be reminded.
+	reminderSweepInterval = time.Minute
+	// dedupQueueSize bounds the alerts waiting to be deduplicated, the alerts overflowing it are notified without dedup.
+	dedupQueueSize = 1000
+	// dedupCheckTimeout bounds the time the dedup worker waits for the db.
+	dedupCheckTimeout = 3 * time.Second
+)
+
+var (
+	manager   *Manager
+	managerMu sync.RWMutex
+
+	alertNotifyTotal = promauto.With(prometheus.DefaultRegisterer).NewCounterVec(prometheus.CounterOpts{
+		Name: "alert_notify_total",
+		Help: "The total number of alerts notified.",
+	}, []string{"category", "severity"})
+
+	alertSinkSendFailureTotal = promauto.With(prometheus.DefaultRegisterer).NewCounterVec(prometheus.CounterOpts{
+		Name: "alert_sink_send_failure_total",
+		Help: "The total number of alerts failed to be sent to the sink.",
+	}, []string{"sink"})
+
+	alertDedupOverflowTotal = promauto.With(prometheus.DefaultRegisterer).NewCounterVec(prometheus.CounterOpts{
+		Name: "alert_dedup_overflow_total",
+		Help: "The total number of alerts notified without dedup since the dedup queue is full.",
+	}, []string{"category"})
+)
+
+// AlertSink delivers the alerts to a destination, Send must not block the checkers.
+type AlertSink interface {
+	Name() string
+	Send(alert Alert) error
+	Start()
+	Stop()
+}
+
+// Manager routes the alerts to the sinks by severity and category.
+type Manager struct {
+	ctx         context.Context
+	sinks       []AlertSink
+	sinkIndex   map[string]AlertSink
+	routes      []*config.AlertRouteConfig
+	dedup       *deduplicator
+	dedupQueue  chan Alert
+	stopWorkers context.CancelFunc
+}
+
+// NewManager creates the alert sinks and routes from the config, the alerts are deduplicated
+// and persisted in the outbox in db if they're configured.
+func NewManager(ctx context.Context, cfg *config.Config, db *gorm.DB) (*Manager, error) {
+	var sinks []AlertSink
+	if cfg.AlertConfig != nil && cfg.AlertConfig.WebhookURL != "" {
+		sinks = append(sinks, NewSlackSink(ctx, slackSinkName, cfg.AlertConfig.WebhookURL, cfg.AlertConfig.WorkerCount, cfg.AlertConfig.WorkerBufferSize))
+	}
+
+	var routes []*config.AlertRouteConfig
+	if cfg.AlertRoutingConfig != nil {
+		for _, sinkCfg := range cfg.AlertRoutingConfig.Sinks {
+			sink, err := newSink(ctx, sinkCfg)
+			if err != nil {
+				return nil, err
+			}
+			sinks = append(sinks, sink)
+		}
+		routes = cfg.AlertRoutingConfig.Routes
+	}
+
+	if cfg.AlertOutboxConfig != nil && db != nil {
+		for _, sink := range sinks {
+			if durable, ok := sink.(durableSink); ok {
+				durable.enableOutbox(db, cfg.AlertOutboxConfig)
+			}
+		}
+	}
+
+	m := NewManagerWithSinks(sinks...)
+	for _, route := range routes {
+		for _, sinkName := range route.Sinks {
+			if _, ok := m.sinkIndex[sinkName]; !ok {
+				log.Warn("alert route refers to an unknown sink", "sink", sinkName)
+			}
+		}
+	}
+	m.ctx = ctx
+	m.routes = routes
+	if cfg.AlertDedupConfig != nil && cfg.AlertDedupConfig.ThrottleWindow > 0 {
+		throttleWindow := time.Duration(cfg.AlertDedupConfig.ThrottleWindow) * time.Second
+		reminderInterval := throttleWindow
+		if cfg.AlertDedupConfig.ReminderInterval > 0 {
+			reminderInterval = time.Duration(cfg.AlertDedupConfig.ReminderInterval) * time.Second
+		}
+		m.dedup = newDeduplicator(db, throttleWindow, reminderInterval)
+		m.dedupQueue = make(chan Alert, dedupQueueSize)
+	}
+	return m, nil
+}
+
+// NewManagerWithSinks creates a manager which sends all the alerts to every sink.
+func NewManagerWithSinks(sinks ...AlertSink) *Manager {
+	m := &Manager{
+		ctx:       context.Background(),
+		sinks:     sinks,
+		sinkIndex: make(map[string]AlertSink),
+	}
+	for _, sink := range sinks {
+		m.sinkIndex[sink.Name()] = sink
+	}
+	return m
+}
+
+func newSink(ctx context.Context, cfg *config.AlertSinkConfig) (AlertSink, error) {
+	switch cfg.Type {
+	case sinkTypeSlack:
+		return NewSlackSink(ctx, cfg.Name, cfg.URL, cfg.WorkerCount, cfg.WorkerBufferSize), nil
+	case sinkTypeWebhook:
+		return NewWebhookSink(ctx, cfg.Name, cfg.URL, cfg.WorkerCount, cfg.WorkerBufferSize), nil
+	case sinkTypeStdout:
+		return NewWriterSink(cfg.Name, os.Stdout), nil
+	case sinkTypeFile:
+		return NewFileSink(cfg.Name, cfg.FilePath)
+	}
+	return nil, fmt.Errorf("unknown alert sink type: %s, sink: %s", cfg.Type, cfg.Name)
+}
+
+// Start all the sinks, and the dedup worker and the reminder sweep of the firing alerts if the alerts are deduplicated.
+func (m *Manager) Start() {
+	for _, sink := range m.sinks {
+		sink.Start()
+	}
+
+	if m.dedup != nil {
+		ctx, cancel := context.WithCancel(m.ctx)
+		m.stopWorkers = cancel
+		go m.runDedup(ctx)
+		go m.sweepReminders(ctx)
+	}
+}
+
+// Stop all the sinks
+func (m *Manager) Stop() {
+	if m.stopWorkers != nil {
+		m.stopWorkers()
+	}
+	for _, sink := range m.sinks {
+		sink.Stop()
+	}
+}
+
+// sweepReminders periodically reminds the firing alerts which are not notified within the reminder interval.
+func (m *Manager) sweepReminders(ctx context.Context) {
+	ticker := time.NewTicker(reminderSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.remind(ctx)
+		}
+	}
+}
+
+func (m *Manager) remind(ctx context.Context) {
+	alerts, err := m.dedup.remind(ctx)
+	if err != nil {
+		log.Error("remind firing alerts failed", "error", err)
+		return
+	}
+	for _, alert := range alerts {
+		alert.Time = utils.NowUTC()
+		m.send(alert)
+	}
+}
+
+// route returns the sinks which the alert is routed to.
+func (m *Manager) route(alert Alert) []AlertSink {
+	if len(m.routes) == 0 {
+		return m.sinks
+	}
+
+	var sinks []AlertSink
+	routed := make(map[string]bool)
+	for _, route := range m.routes {
+		if !matchRoute(route.Severities, string(alert.Severity)) || !matchRoute(route.Categories, string(alert.Category)) {
+			continue
+		}
+		for _, sinkName := range route.Sinks {
+			sink, ok := m.sinkIndex[sinkName]
+			if !ok || routed[sinkName] {
+				continue
+			}
+			routed[sinkName] = true
+			sinks = append(sinks, sink)
+		}
+	}
+	return sinks
+}
+
+func matchRoute(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// notify never blocks the checkers, the alerts are deduplicated by the dedup worker since it waits for the db.
+func (m *Manager) notify(alert Alert) {
+	if m.dedup == nil {
+		m.send(alert)
+		return
+	}
+
+	select {
+	case m.dedupQueue <- alert:
+	default:
+		// Notify the alert without dedup, a duplicated alert is better than a missed one.
+		alertDedupOverflowTotal.WithLabelValues(string(alert.Category)).Inc()
+		log.Warn("alert dedup queue is full, notify the alert without dedup", "category", alert.Category, "title", alert.Title)
+		m.send(alert)
+	}
+}
+
+// runDedup deduplicates the queued alerts and sends the ones to notify.
+func (m *Manager) runDedup(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case alert := <-m.dedupQueue:
+			m.deduplicate(ctx, alert)
+		}
+	}
+}
+
+func (m *Manager) deduplicate(ctx context.Context, alert Alert) {
+	ctx, cancel := context.WithTimeout(ctx, dedupCheckTimeout)
+	defer cancel()
+
+	alert, notify, err := m.dedup.check(ctx, alert)
+	if err != nil {
+		// Notify the alert anyway, a duplicated alert is better than a missed one.
+		log.Error("deduplicate alert failed", "category", alert.Category, "error", err)
+	}
+	if !notify {
+		return
+	}
+	m.send(alert)
+}
+
+func (m *Manager) send(alert Alert) {
+	for _, sink := range m.route(alert) {
+		if err := sink.Send(alert); err != nil {
+			alertSinkSendFailureTotal.WithLabelValues(sink.Name()).Inc()
+			log.Error("send alert to sink failed", "sink", sink.Name(), "category", alert.Category, "error", err)
+		}
+	}
+}
+
+// Use sets the manager which the alerts are notified to, and returns the previous one.
+func Use(m *Manager) *Manager {
+	managerMu.Lock()
+	defer managerMu.Unlock()
+	previous := manager
+	manager = m
+	return previous
+}
+
+// Notify routes the alert to the sinks of the manager in use.
+func Notify(alert Alert) {
+	if alert.Time.IsZero() {
+		alert.Time = utils.NowUTC()
+	}
+	alertNotifyTotal.WithLabelValues(string(alert.Category), string(alert.Severity)).Inc()
+
+	managerMu.RLock()
+	m := manager
+	managerMu.RUnlock()
+	if m == nil {
+		log.Debug("alert manager is not initialized, drop the alert", "category", alert.Category, "title", alert.Title)
+		return
+	}
+	m.notify(alert)
+}
+
+// Resolve marks the firing alerts of the fingerprints as resolved and notifies the resolved alerts.
+// It's a no-op if the alerts are not deduplicated, since the firing alerts are not recorded.
+func Resolve(ctx context.Context, fingerprints ...string) {
+	managerMu.RLock()
+	m := manager
+	managerMu.RUnlock()
+	if m == nil || m.dedup == nil || len(fingerprints) == 0 {
+		return
+	}
+
+	alerts, err := m.dedup.resolve(ctx, fingerprints)
+	if err != nil {
+		log.Error("resolve alerts failed", "error", err)
+		return
+	}
+	for _, alert := range alerts {
+		alert.Time = utils.NowUTC()
+		m.send(alert)
+	}
+}
+
+// FuncSink passes the alerts to a function, it's used by the commands which report the alerts by themselves.
+type FuncSink struct {
+	name string
+	fn   func(alert Alert)
+}
+
+// NewFuncSink creates the sink which calls fn for every alert
+func NewFuncSink(name string, fn func(alert Alert)) *FuncSink {
+	return &FuncSink{name: name, fn: fn}
+}
+
+// Name returns the name of the sink
+func (s *FuncSink) Name() string {
+	return s.name
+}
+
+// Send passes the alert to the function
+func (s *FuncSink) Send(alert Alert) error {
+	s.fn(alert)
+	return nil
+}
+
+// Start the sink
+func (s *FuncSink) Start() {}
+
+// Stop the sink
+func (s *FuncSink) Stop() {}
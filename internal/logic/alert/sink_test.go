@@ -0,0 +1,95 @@
+package alert
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/scroll-tech/chain-monitor/internal/config"
+)
+
+func TestManager_Route(t *testing.T) {
+	var slackAlerts, pagerAlerts []Alert
+	slackSink := NewFuncSink("slack", func(alert Alert) { slackAlerts = append(slackAlerts, alert) })
+	pagerSink := NewFuncSink("pager", func(alert Alert) { pagerAlerts = append(pagerAlerts, alert) })
+
+	m := NewManagerWithSinks(slackSink, pagerSink)
+	m.routes = []*config.AlertRouteConfig{
+		{Sinks: []string{"slack"}},
+		{Severities: []string{string(SeverityCritical)}, Categories: []string{string(CategoryWithdrawRoot)}, Sinks: []string{"pager", "slack"}},
+	}
+
+	m.notify(Alert{Severity: SeverityWarning, Category: CategoryChainReorg})
+	m.notify(Alert{Severity: SeverityCritical, Category: CategoryWithdrawRoot})
+	m.notify(Alert{Severity: SeverityCritical, Category: CategoryCrossChainETH})
+
+	assert.Len(t, slackAlerts, 3)
+	assert.Len(t, pagerAlerts, 1)
+	assert.Equal(t, CategoryWithdrawRoot, pagerAlerts[0].Category)
+}
+
+func TestManager_RouteWithoutRoutes(t *testing.T) {
+	var alerts []Alert
+	m := NewManagerWithSinks(NewFuncSink("stdout", func(alert Alert) { alerts = append(alerts, alert) }))
+	m.notify(Alert{Severity: SeverityInfo, Category: CategoryChainReorg})
+	assert.Len(t, alerts, 1)
+}
+
+func TestQueuedSink_Backoff(t *testing.T) {
+	s := &queuedSink{retryBackoff: 5 * time.Second, maxRetryBackoff: time.Minute}
+	assert.Equal(t, 5*time.Second, s.backoff(1))
+	assert.Equal(t, 10*time.Second, s.backoff(2))
+	assert.Equal(t, 40*time.Second, s.backoff(4))
+	assert.Equal(t, time.Minute, s.backoff(5))
+	assert.Equal(t, time.Minute, s.backoff(100))
+}
+
+func TestManager_NotifyWithHangingDB(t *testing.T) {
+	// the db accepts the connections but never responds.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, acceptErr := listener.Accept()
+			if acceptErr != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	db, err := gorm.Open(postgres.Open("postgres://postgres:postgres@"+listener.Addr().String()+"/test?sslmode=disable"), &gorm.Config{DisableAutomaticPing: true})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var delivered atomic.Int64
+	sink := newQueuedSink(ctx, "webhook", 1, 1, func(ctx context.Context, alert Alert) error {
+		delivered.Add(1)
+		return nil
+	})
+	sink.enableOutbox(db, &config.AlertOutboxConfig{})
+
+	m := NewManagerWithSinks(sink)
+	m.ctx = ctx
+	m.dedup = newDeduplicator(db, time.Hour, time.Hour)
+	m.dedupQueue = make(chan Alert, 1)
+	m.Start()
+
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		m.notify(Alert{Severity: SeverityCritical, Category: CategoryWithdrawRoot, BlockNumber: uint64(i)})
+	}
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+
+	// the alerts overflowing the dedup queue and the outbox queue are still delivered by the memory queue.
+	assert.Eventually(t, func() bool { return delivered.Load() > 0 }, 2*outboxQueryTimeout, 10*time.Millisecond)
+}
@@ -0,0 +1,74 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// SlackSink sends the alerts to the slack webhook as mrkdwn messages.
+type SlackSink struct {
+	*queuedSink
+	webhookURL string
+	notifyCli  *resty.Client
+}
+
+// NewSlackSink creates the slack sink
+func NewSlackSink(ctx context.Context, name, webhookURL string, workerCount, workerBufferSize int) *SlackSink {
+	s := &SlackSink{webhookURL: webhookURL}
+
+	cli := resty.New()
+	cli.SetRetryCount(5)
+	cli.SetTimeout(time.Second * 3)
+	s.notifyCli = cli
+
+	s.queuedSink = newQueuedSink(ctx, name, workerCount, workerBufferSize, s.deliver)
+	return s
+}
+
+func (s *SlackSink) deliver(ctx context.Context, alert Alert) error {
+	hookContent := map[string]string{
+		"types": "mrkdwn",
+		"text":  MrkDwn(alert),
+	}
+
+	data, err := json.Marshal(hookContent)
+	if err != nil {
+		return fmt.Errorf("failed to marshal hook content, err: %w", err)
+	}
+
+	request := s.notifyCli.R().SetContext(ctx).SetHeader("Content-Type", "application/json")
+	request = request.SetFormData(map[string]string{"payload": string(data)})
+	resp, err := request.Post(s.webhookURL)
+	if err != nil {
+		return fmt.Errorf("send slack message failed, err: %w", err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("send slack message failed, status: %s", resp.Status())
+	}
+	return nil
+}
+
+// MrkDwn make the markdown message of the alert
+func MrkDwn(alert Alert) string {
+	var buffer bytes.Buffer
+	switch {
+	case alert.Resolved:
+		buffer.WriteString("\n:white_check_mark: ")
+	case alert.Severity == SeverityCritical:
+		buffer.WriteString("\n:bangbang: ")
+	case alert.Severity == SeverityWarning:
+		buffer.WriteString("\n:warning: ")
+	default:
+		buffer.WriteString("\n:information_source: ")
+	}
+	buffer.WriteString(fmt.Sprintf("*%s*\n", alert.Title))
+	for _, field := range alert.Fields {
+		buffer.WriteString(fmt.Sprintf("• %s: %s\n", field.Name, field.Value))
+	}
+	return buffer.String()
+}
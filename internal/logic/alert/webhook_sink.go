@@ -0,0 +1,40 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// WebhookSink posts the alerts to a generic webhook as json.
+type WebhookSink struct {
+	*queuedSink
+	url       string
+	notifyCli *resty.Client
+}
+
+// NewWebhookSink creates the webhook sink
+func NewWebhookSink(ctx context.Context, name, url string, workerCount, workerBufferSize int) *WebhookSink {
+	s := &WebhookSink{url: url}
+
+	cli := resty.New()
+	cli.SetRetryCount(5)
+	cli.SetTimeout(time.Second * 3)
+	s.notifyCli = cli
+
+	s.queuedSink = newQueuedSink(ctx, name, workerCount, workerBufferSize, s.deliver)
+	return s
+}
+
+func (s *WebhookSink) deliver(ctx context.Context, alert Alert) error {
+	resp, err := s.notifyCli.R().SetContext(ctx).SetHeader("Content-Type", "application/json").SetBody(alert).Post(s.url)
+	if err != nil {
+		return fmt.Errorf("post alert to webhook failed, err: %w", err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("post alert to webhook failed, status: %s", resp.Status())
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package alert
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// WriterSink writes the alerts to the writer as json lines, it's used for stdout and files.
+type WriterSink struct {
+	name    string
+	mu      sync.Mutex
+	encoder *json.Encoder
+	closer  io.Closer
+}
+
+// NewWriterSink creates the sink which writes the alerts to w
+func NewWriterSink(name string, w io.Writer) *WriterSink {
+	return &WriterSink{
+		name:    name,
+		encoder: json.NewEncoder(w),
+	}
+}
+
+// NewFileSink creates the sink which appends the alerts to the file
+func NewFileSink(name, filePath string) (*WriterSink, error) {
+	fp, err := os.OpenFile(filepath.Clean(filePath), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open alert file failed, sink: %s, file: %s, err: %w", name, filePath, err)
+	}
+	s := NewWriterSink(name, fp)
+	s.closer = fp
+	return s, nil
+}
+
+// Name returns the name of the sink
+func (s *WriterSink) Name() string {
+	return s.name
+}
+
+// Send writes the alert
+func (s *WriterSink) Send(alert Alert) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.encoder.Encode(alert)
+}
+
+// Start the sink
+func (s *WriterSink) Start() {}
+
+// Stop the sink, the file is closed
+func (s *WriterSink) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closer != nil {
+		_ = s.closer.Close()
+		s.closer = nil
+	}
+}
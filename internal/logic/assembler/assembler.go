@@ -2,53 +2,132 @@ package assembler
 
 import (
 	"context"
-	"math"
+	"fmt"
 
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/rpc"
 	"gorm.io/gorm"
 
+	"github.com/scroll-tech/chain-monitor/internal/config"
 	"github.com/scroll-tech/chain-monitor/internal/logic/events"
 	"github.com/scroll-tech/chain-monitor/internal/orm"
 	"github.com/scroll-tech/chain-monitor/internal/types"
 )
 
-type messageEventKey struct {
-	TxHash   common.Hash
-	LogIndex uint
-}
-
 // MessageMatchAssembler is a structure that helps in verifying the data integrity
 // in the blockchain by checking the message matches and the events.
 type MessageMatchAssembler struct {
 	messengerMessageMatchOrm *orm.MessengerMessageMatch
 
-	transferMatcher *TransferEventMatcher
+	bridgedTokenRegistry *BridgedTokenRegistry
+	transferMatcher      *TransferEventMatcher
+
+	// the counterpart gateways of the other layer by the gateways of each layer.
+	counterpartGateways map[types.LayerType]map[common.Address]common.Address
+	// the l1 gateways refunding the dropped messages in eth held by the messenger, e.g. the weth gateway.
+	nonCustodialGateways map[common.Address]bool
 }
 
-// NewMessageMatchAssembler returns a new message match instance.
-func NewMessageMatchAssembler(db *gorm.DB) *MessageMatchAssembler {
+// NewMessageMatchAssembler returns a new message match instance, the bridged token registry is nil if the bridged token check is disabled.
+func NewMessageMatchAssembler(cfg *config.Config, db *gorm.DB, bridgedTokenRegistry *BridgedTokenRegistry) *MessageMatchAssembler {
 	return &MessageMatchAssembler{
 		messengerMessageMatchOrm: orm.NewMessengerMessageMatch(db),
-		transferMatcher:          NewTransferEventMatcher(),
+		bridgedTokenRegistry:     bridgedTokenRegistry,
+		transferMatcher:          NewTransferEventMatcher(bridgedTokenRegistry),
+		counterpartGateways:      newCounterpartGateways(cfg),
+		nonCustodialGateways:     newNonCustodialGateways(cfg),
+	}
+}
+
+// newNonCustodialGateways returns the registered l1 gateways which don't hold the bridged tokens.
+func newNonCustodialGateways(cfg *config.Config) map[common.Address]bool {
+	nonCustodialGateways := make(map[common.Address]bool)
+	for _, gateway := range cfg.GatewayRegistry() {
+		if gateway.Layer == config.GatewayLayerL1 && gateway.NonCustodial {
+			nonCustodialGateways[gateway.Address] = true
+		}
 	}
+	return nonCustodialGateways
 }
 
-// GatewayMessageAssembler assemble the gateway events.
-func (c *MessageMatchAssembler) GatewayMessageAssembler(eventCategory types.EventCategory, gatewayEvents, messengerEvents, transferEvents []events.EventUnmarshaler) ([]orm.GatewayMessageMatch, error) {
+// newCounterpartGateways maps the registered gateways of each layer to their counterpart gateways of the other layer.
+func newCounterpartGateways(cfg *config.Config) map[types.LayerType]map[common.Address]common.Address {
+	counterpartGateways := map[types.LayerType]map[common.Address]common.Address{
+		types.Layer1: make(map[common.Address]common.Address),
+		types.Layer2: make(map[common.Address]common.Address),
+	}
+	for _, pair := range cfg.GatewayPairs() {
+		counterpartGateways[types.Layer1][pair.L1.Address] = pair.L2.Address
+		counterpartGateways[types.Layer2][pair.L2.Address] = pair.L1.Address
+	}
+	return counterpartGateways
+}
+
+// GatewayMessageAssembler assemble the gateway events, which are paired with the messenger events of the index. The
+// index is shared by the event categories of the block range, so a messenger event is paired with one gateway event at most.
+func (c *MessageMatchAssembler) GatewayMessageAssembler(eventCategory types.EventCategory, gatewayEvents []events.EventUnmarshaler, messengerEventIndex *MessengerEventIndex, transferEvents []events.EventUnmarshaler) ([]orm.GatewayMessageMatch, error) {
 	switch eventCategory {
+	case types.ETHEventCategory:
+		return c.ethEventMessageMatchAssembler(gatewayEvents, messengerEventIndex)
 	case types.ERC20EventCategory:
-		return c.erc20EventMessageMatchAssembler(gatewayEvents, messengerEvents, transferEvents)
+		return c.erc20EventMessageMatchAssembler(gatewayEvents, messengerEventIndex, transferEvents)
 	case types.ERC721EventCategory:
-		return c.erc721EventMessageMatchAssembler(gatewayEvents, messengerEvents, transferEvents)
+		return c.erc721EventMessageMatchAssembler(gatewayEvents, messengerEventIndex, transferEvents)
 	case types.ERC1155EventCategory:
-		return c.erc1155EventMessageMatchAssembler(gatewayEvents, messengerEvents, transferEvents)
+		return c.erc1155EventMessageMatchAssembler(gatewayEvents, messengerEventIndex, transferEvents)
 	}
 	return nil, nil
 }
 
-// L2WithdrawRootsValidator the L2 withdraw roots validator.
-func (c *MessageMatchAssembler) L2WithdrawRootsValidator(ctx context.Context, startBlockNumber, endBlockNumber uint64, client *rpc.Client, messageQueueAddr common.Address) (*orm.MessengerMessageMatch, error) {
+// MarkGatewayMessagePairingFailures marks the gateway message matches of the messages which are claimed by several
+// gateway events, or aren't sent to the counterpart gateways, as failed with the reasons.
+func (c *MessageMatchAssembler) MarkGatewayMessagePairingFailures(layer types.LayerType, messengerEventIndex *MessengerEventIndex, gatewayMessageMatches []orm.GatewayMessageMatch) {
+	messengerEventIndex.markPairingFailures(layer, gatewayMessageMatches)
+}
+
+// BridgedTokenTransferValidator checks the l2 erc20 transfer events of the blocks without gateway events,
+// the bridged tokens minted or burned in them are flagged.
+func (c *MessageMatchAssembler) BridgedTokenTransferValidator(transferEvents []events.EventUnmarshaler) error {
+	var erc20TransferEvents []events.ERC20GatewayEventUnmarshaler
+	for _, eventData := range transferEvents {
+		transferEventUnmarshaler, ok := eventData.(*events.ERC20GatewayEventUnmarshaler)
+		if !ok {
+			return fmt.Errorf("eventData is not of type *events.ERC20GatewayEventUnmarshaler")
+		}
+		erc20TransferEvents = append(erc20TransferEvents, *transferEventUnmarshaler)
+	}
+	_, err := c.transferMatcher.erc20Matcher(erc20TransferEvents, nil)
+	return err
+}
+
+// MarkFailedMessengerMessageMatches marks the messenger message matches of the failed gateway message matches as failed
+// with the same reasons, since the messages carry the mismatched gateway events.
+func (c *MessageMatchAssembler) MarkFailedMessengerMessageMatches(layer types.LayerType, gatewayMessageMatches []orm.GatewayMessageMatch, messengerMessageMatches []orm.MessengerMessageMatch) {
+	failureReasons := make(map[string]string)
+	for _, gatewayMessageMatch := range gatewayMessageMatches {
+		if (layer == types.Layer1 && gatewayMessageMatch.L1BlockStatus == int(types.BlockStatusTypeFailed)) ||
+			(layer == types.Layer2 && gatewayMessageMatch.L2BlockStatus == int(types.BlockStatusTypeFailed)) {
+			failureReasons[gatewayMessageMatch.MessageHash] = gatewayMessageMatch.FailureReason
+		}
+	}
+
+	for i := range messengerMessageMatches {
+		reason, ok := failureReasons[messengerMessageMatches[i].MessageHash]
+		if !ok {
+			continue
+		}
+		if layer == types.Layer1 {
+			messengerMessageMatches[i].L1BlockStatus = int(types.BlockStatusTypeFailed)
+		} else {
+			messengerMessageMatches[i].L2BlockStatus = int(types.BlockStatusTypeFailed)
+		}
+		messengerMessageMatches[i].FailureReason = reason
+	}
+}
+
+// L2WithdrawRootsValidator the L2 withdraw roots validator, the mismatches are returned in the result and the error
+// is only returned if the messages or the withdraw roots can't be fetched.
+func (c *MessageMatchAssembler) L2WithdrawRootsValidator(ctx context.Context, startBlockNumber, endBlockNumber uint64, client *rpc.Client, messageQueueAddr common.Address) (*WithdrawRootCheckResult, error) {
 	return c.checkL2WithdrawRoots(ctx, startBlockNumber, endBlockNumber, client, messageQueueAddr)
 }
 
@@ -57,34 +136,12 @@ func (c *MessageMatchAssembler) MessageMatchAssembler(messengerEvents []events.E
 	return c.messengerMessageMatchAssembler(messengerEvents)
 }
 
-func (c *MessageMatchAssembler) findNextMessageEvent(txHash common.Hash, logIndex uint, messageHashes map[messageEventKey]common.Hash) (common.Hash, bool) {
-	var nextMessageHash common.Hash
-	var found bool
-	var smallestDiff uint = math.MaxUint
-	for key, msgHash := range messageHashes {
-		if key.TxHash == txHash && key.LogIndex > logIndex {
-			if diff := key.LogIndex - logIndex; diff < smallestDiff {
-				smallestDiff = diff
-				nextMessageHash = msgHash
-				found = true
-			}
-		}
-	}
-	return nextMessageHash, found
+// L1ETHRefundAssembler assemble the eth refunds of the dropped messages in the l1 gateway events.
+func (c *MessageMatchAssembler) L1ETHRefundAssembler(gatewayEvents []events.EventUnmarshaler) []orm.L1ETHRefund {
+	return c.l1ETHRefundAssembler(gatewayEvents)
 }
 
-func (c *MessageMatchAssembler) findPrevMessageEvent(txHash common.Hash, logIndex uint, messageHashes map[messageEventKey]common.Hash) (common.Hash, bool) {
-	var prevMessageHash common.Hash
-	var found bool
-	var smallestDiff uint = math.MaxUint
-	for key, msgHash := range messageHashes {
-		if key.TxHash == txHash && key.LogIndex < logIndex {
-			if diff := logIndex - key.LogIndex; diff < smallestDiff {
-				smallestDiff = diff
-				prevMessageHash = msgHash
-				found = true
-			}
-		}
-	}
-	return prevMessageHash, found
+// FailedRelayedMessageAssembler assemble the FailedRelayedMessage events of the messenger events, the block times are left to the caller.
+func (c *MessageMatchAssembler) FailedRelayedMessageAssembler(messengerEvents []events.EventUnmarshaler) ([]orm.FailedRelayedMessageEvent, error) {
+	return c.failedRelayedMessageAssembler(messengerEvents)
 }
@@ -0,0 +1,76 @@
+package assembler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"gorm.io/gorm"
+
+	"github.com/scroll-tech/chain-monitor/internal/config"
+	"github.com/scroll-tech/chain-monitor/internal/orm"
+)
+
+// BridgedTokenRegistry keeps the l2 tokens which are minted and burned by the gateways only. The tokens are configured,
+// or learned from the finalized erc20 deposits, so the l2 tokens which aren't bridged are still ignored.
+type BridgedTokenRegistry struct {
+	gatewayMessageMatchOrm *orm.GatewayMessageMatch
+
+	mu     sync.RWMutex
+	tokens map[common.Address]struct{}
+}
+
+// NewBridgedTokenRegistry creates a new BridgedTokenRegistry with the configured l2 bridged tokens,
+// returns nil if the bridged token check is disabled.
+func NewBridgedTokenRegistry(cfg *config.Config, db *gorm.DB) *BridgedTokenRegistry {
+	if cfg.BridgedTokenConfig == nil || !cfg.BridgedTokenConfig.Enabled {
+		return nil
+	}
+
+	r := &BridgedTokenRegistry{
+		gatewayMessageMatchOrm: orm.NewGatewayMessageMatch(db),
+		tokens:                 make(map[common.Address]struct{}),
+	}
+	for _, tokenAddress := range cfg.BridgedTokenConfig.L2Tokens {
+		r.tokens[tokenAddress] = struct{}{}
+	}
+	for _, tokenPair := range cfg.TokenPairs {
+		r.tokens[tokenPair.L2Token] = struct{}{}
+	}
+	return r
+}
+
+// Load learns the l2 bridged tokens of the finalized deposits ingested before.
+func (r *BridgedTokenRegistry) Load(ctx context.Context) error {
+	tokenAddresses, err := r.gatewayMessageMatchOrm.GetFinalizedDepositL2TokenAddresses(ctx)
+	if err != nil {
+		return fmt.Errorf("get finalized deposit l2 token addresses failed, err: %w", err)
+	}
+
+	for _, tokenAddress := range tokenAddresses {
+		r.Learn(common.HexToAddress(tokenAddress))
+	}
+	return nil
+}
+
+// Learn adds the l2 token of a finalized deposit, it's a no-op if the registry is disabled.
+func (r *BridgedTokenRegistry) Learn(tokenAddress common.Address) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens[tokenAddress] = struct{}{}
+}
+
+// IsBridged returns whether the l2 token is a bridged token, always false if the registry is disabled.
+func (r *BridgedTokenRegistry) IsBridged(tokenAddress common.Address) bool {
+	if r == nil {
+		return false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.tokens[tokenAddress]
+	return ok
+}
@@ -0,0 +1,69 @@
+package assembler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scroll-tech/chain-monitor/internal/config"
+	"github.com/scroll-tech/chain-monitor/internal/orm"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+	"github.com/scroll-tech/chain-monitor/internal/utils/testcontainer"
+)
+
+func TestBridgedTokenRegistry(t *testing.T) {
+	configuredToken, pairedToken := common.HexToAddress("0x21"), common.HexToAddress("0x22")
+	learnedToken, unknownToken := common.HexToAddress("0x23"), common.HexToAddress("0x24")
+	newConfig := func(bridgedTokenConfig *config.BridgedTokenConfig) *config.Config {
+		return &config.Config{
+			TokenPairs:         []*config.TokenPair{{L1Token: common.HexToAddress("0x12"), L2Token: pairedToken}},
+			BridgedTokenConfig: bridgedTokenConfig,
+		}
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		for _, cfg := range []*config.Config{newConfig(nil), newConfig(&config.BridgedTokenConfig{L2Tokens: []common.Address{configuredToken}})} {
+			r := NewBridgedTokenRegistry(cfg, nil)
+			assert.Nil(t, r)
+			// the disabled registry learns nothing and knows no token.
+			r.Learn(learnedToken)
+			assert.False(t, r.IsBridged(configuredToken))
+			assert.False(t, r.IsBridged(learnedToken))
+		}
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		r := NewBridgedTokenRegistry(newConfig(&config.BridgedTokenConfig{Enabled: true, L2Tokens: []common.Address{configuredToken}}), nil)
+		assert.NotNil(t, r)
+		assert.True(t, r.IsBridged(configuredToken))
+		assert.True(t, r.IsBridged(pairedToken))
+		assert.False(t, r.IsBridged(learnedToken))
+
+		r.Learn(learnedToken)
+		assert.True(t, r.IsBridged(learnedToken))
+		assert.False(t, r.IsBridged(unknownToken))
+	})
+}
+
+func TestBridgedTokenRegistry_Load(t *testing.T) {
+	ctx := context.Background()
+	db := testcontainer.SetupDB(ctx, t)
+
+	depositedToken, withdrawnToken := common.HexToAddress("0x21"), common.HexToAddress("0x22")
+	for _, message := range []orm.GatewayMessageMatch{
+		{MessageHash: "0x1", TokenType: int(types.TokenTypeERC20), L2EventType: int(types.L2FinalizeDepositERC20), L2BlockNumber: 10, L2TokenAddress: depositedToken.Hex()},
+		{MessageHash: "0x2", TokenType: int(types.TokenTypeERC20), L2EventType: int(types.L2FinalizeDepositERC20), L2BlockNumber: 11, L2TokenAddress: depositedToken.Hex()},
+		// only the tokens of the finalized deposits are learned.
+		{MessageHash: "0x3", TokenType: int(types.TokenTypeERC20), L2EventType: int(types.L2WithdrawERC20), L2BlockNumber: 12, L2TokenAddress: withdrawnToken.Hex()},
+	} {
+		_, err := orm.NewGatewayMessageMatch(db).InsertOrUpdateEventInfo(ctx, types.Layer2, message)
+		assert.NoError(t, err)
+	}
+
+	r := NewBridgedTokenRegistry(&config.Config{BridgedTokenConfig: &config.BridgedTokenConfig{Enabled: true}}, db)
+	assert.NoError(t, r.Load(ctx))
+	assert.True(t, r.IsBridged(depositedToken))
+	assert.False(t, r.IsBridged(withdrawnToken))
+}
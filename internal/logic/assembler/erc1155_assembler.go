@@ -4,24 +4,12 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/scroll-tech/go-ethereum/common"
-
 	"github.com/scroll-tech/chain-monitor/internal/logic/events"
 	"github.com/scroll-tech/chain-monitor/internal/orm"
 	"github.com/scroll-tech/chain-monitor/internal/types"
 )
 
-func (c *MessageMatchAssembler) erc1155EventMessageMatchAssembler(gatewayEventsData, messengerEventsData, transferEventsData []events.EventUnmarshaler) ([]orm.GatewayMessageMatch, error) {
-	messageHashes := make(map[messageEventKey]common.Hash)
-	for _, eventData := range messengerEventsData {
-		messengerEventUnmarshaler, ok := eventData.(*events.MessengerEventUnmarshaler)
-		if !ok {
-			return nil, fmt.Errorf("eventData is not of type *events.MessengerEventUnmarshaler")
-		}
-		key := messageEventKey{TxHash: messengerEventUnmarshaler.TxHash, LogIndex: messengerEventUnmarshaler.Index}
-		messageHashes[key] = messengerEventUnmarshaler.MessageHash
-	}
-
+func (c *MessageMatchAssembler) erc1155EventMessageMatchAssembler(gatewayEventsData []events.EventUnmarshaler, messengerEventIndex *MessengerEventIndex, transferEventsData []events.EventUnmarshaler) ([]orm.GatewayMessageMatch, error) {
 	var messageMatches []orm.GatewayMessageMatch
 	var gatewayEvents []events.ERC1155GatewayEventUnmarshaler
 	for _, eventData := range gatewayEventsData {
@@ -31,10 +19,8 @@ func (c *MessageMatchAssembler) erc1155EventMessageMatchAssembler(gatewayEventsD
 		var tmpMessageMatch orm.GatewayMessageMatch
 		switch erc1155EventUnmarshaler.Type {
 		case types.L1DepositERC1155:
-			messageHash, exists := c.findPrevMessageEvent(erc1155EventUnmarshaler.TxHash, erc1155EventUnmarshaler.Index, messageHashes)
-			if !exists {
-				return nil, fmt.Errorf("message hash does not exist for erc1155 event %v", erc1155EventUnmarshaler)
-			}
+			messengerEvent, paired := c.pairSentMessage(messengerEventIndex, types.Layer1, erc1155EventUnmarshaler.Type, erc1155EventUnmarshaler.GatewayAddress, erc1155EventUnmarshaler.TxHash, erc1155EventUnmarshaler.Index)
+			messageHash := messengerEvent.MessageHash
 			var tokenIdsStrList []string
 			for _, tokenID := range erc1155EventUnmarshaler.TokenIds {
 				tokenIdsStrList = append(tokenIdsStrList, tokenID.String())
@@ -44,21 +30,31 @@ func (c *MessageMatchAssembler) erc1155EventMessageMatchAssembler(gatewayEventsD
 				amountStrList = append(amountStrList, amount.String())
 			}
 			tmpMessageMatch = orm.GatewayMessageMatch{
-				MessageHash:   messageHash.Hex(),
-				TokenType:     int(types.TokenTypeERC1155),
-				L1EventType:   int(erc1155EventUnmarshaler.Type),
-				L1BlockNumber: erc1155EventUnmarshaler.Number,
-				L1TxHash:      erc1155EventUnmarshaler.TxHash.Hex(),
-				L1TokenIds:    strings.Join(tokenIdsStrList, ","),
-				L1Amounts:     strings.Join(amountStrList, ","),
+				MessageHash:    messageHash.Hex(),
+				TokenType:      int(types.TokenTypeERC1155),
+				L1EventType:    int(erc1155EventUnmarshaler.Type),
+				L1BlockNumber:  erc1155EventUnmarshaler.Number,
+				L1TxHash:       erc1155EventUnmarshaler.TxHash.Hex(),
+				L1TokenAddress: erc1155EventUnmarshaler.TokenAddress.Hex(),
+				L1TokenIds:     strings.Join(tokenIdsStrList, ","),
+				L1Amounts:      strings.Join(amountStrList, ","),
+			}
+			expected := gatewayMessageFields{
+				L1Token:  erc1155EventUnmarshaler.TokenAddress,
+				L2Token:  erc1155EventUnmarshaler.CounterpartTokenAddress,
+				From:     erc1155EventUnmarshaler.From,
+				To:       erc1155EventUnmarshaler.To,
+				TokenIds: erc1155EventUnmarshaler.TokenIds,
+				Amounts:  erc1155EventUnmarshaler.Amounts,
+			}
+			if paired {
+				checkGatewayMessage(&tmpMessageMatch, types.Layer1, erc1155EventUnmarshaler.Type, expected, messengerEvent.Message)
 			}
 			messageMatches = append(messageMatches, tmpMessageMatch)
 			erc1155EventUnmarshaler.MessageHash = messageHash
 		case types.L1FinalizeWithdrawERC1155:
-			messageHash, exists := c.findNextMessageEvent(erc1155EventUnmarshaler.TxHash, erc1155EventUnmarshaler.Index, messageHashes)
-			if !exists {
-				return nil, fmt.Errorf("message hash does not exist for erc1155 event %v", erc1155EventUnmarshaler)
-			}
+			messengerEvent := c.pairRelayedMessage(messengerEventIndex, types.Layer1, erc1155EventUnmarshaler.Type, erc1155EventUnmarshaler.TxHash, erc1155EventUnmarshaler.Index)
+			messageHash := messengerEvent.MessageHash
 			var tokenIdsStrList []string
 			for _, tokenID := range erc1155EventUnmarshaler.TokenIds {
 				tokenIdsStrList = append(tokenIdsStrList, tokenID.String())
@@ -68,21 +64,20 @@ func (c *MessageMatchAssembler) erc1155EventMessageMatchAssembler(gatewayEventsD
 				amountStrList = append(amountStrList, amount.String())
 			}
 			tmpMessageMatch = orm.GatewayMessageMatch{
-				MessageHash:   messageHash.Hex(),
-				TokenType:     int(types.TokenTypeERC1155),
-				L1EventType:   int(erc1155EventUnmarshaler.Type),
-				L1BlockNumber: erc1155EventUnmarshaler.Number,
-				L1TxHash:      erc1155EventUnmarshaler.TxHash.Hex(),
-				L1TokenIds:    strings.Join(tokenIdsStrList, ","),
-				L1Amounts:     strings.Join(amountStrList, ","),
+				MessageHash:    messageHash.Hex(),
+				TokenType:      int(types.TokenTypeERC1155),
+				L1EventType:    int(erc1155EventUnmarshaler.Type),
+				L1BlockNumber:  erc1155EventUnmarshaler.Number,
+				L1TxHash:       erc1155EventUnmarshaler.TxHash.Hex(),
+				L1TokenAddress: erc1155EventUnmarshaler.TokenAddress.Hex(),
+				L1TokenIds:     strings.Join(tokenIdsStrList, ","),
+				L1Amounts:      strings.Join(amountStrList, ","),
 			}
 			messageMatches = append(messageMatches, tmpMessageMatch)
 			erc1155EventUnmarshaler.MessageHash = messageHash
 		case types.L2WithdrawERC1155:
-			messageHash, exists := c.findPrevMessageEvent(erc1155EventUnmarshaler.TxHash, erc1155EventUnmarshaler.Index, messageHashes)
-			if !exists {
-				return nil, fmt.Errorf("message hash does not exist for erc1155 event %v", erc1155EventUnmarshaler)
-			}
+			messengerEvent, paired := c.pairSentMessage(messengerEventIndex, types.Layer2, erc1155EventUnmarshaler.Type, erc1155EventUnmarshaler.GatewayAddress, erc1155EventUnmarshaler.TxHash, erc1155EventUnmarshaler.Index)
+			messageHash := messengerEvent.MessageHash
 			var tokenIdsStrList []string
 			for _, tokenID := range erc1155EventUnmarshaler.TokenIds {
 				tokenIdsStrList = append(tokenIdsStrList, tokenID.String())
@@ -92,21 +87,31 @@ func (c *MessageMatchAssembler) erc1155EventMessageMatchAssembler(gatewayEventsD
 				amountStrList = append(amountStrList, amount.String())
 			}
 			tmpMessageMatch = orm.GatewayMessageMatch{
-				MessageHash:   messageHash.Hex(),
-				TokenType:     int(types.TokenTypeERC1155),
-				L2EventType:   int(erc1155EventUnmarshaler.Type),
-				L2BlockNumber: erc1155EventUnmarshaler.Number,
-				L2TxHash:      erc1155EventUnmarshaler.TxHash.Hex(),
-				L2TokenIds:    strings.Join(tokenIdsStrList, ","),
-				L2Amounts:     strings.Join(amountStrList, ","),
+				MessageHash:    messageHash.Hex(),
+				TokenType:      int(types.TokenTypeERC1155),
+				L2EventType:    int(erc1155EventUnmarshaler.Type),
+				L2BlockNumber:  erc1155EventUnmarshaler.Number,
+				L2TxHash:       erc1155EventUnmarshaler.TxHash.Hex(),
+				L2TokenAddress: erc1155EventUnmarshaler.TokenAddress.Hex(),
+				L2TokenIds:     strings.Join(tokenIdsStrList, ","),
+				L2Amounts:      strings.Join(amountStrList, ","),
+			}
+			expected := gatewayMessageFields{
+				L1Token:  erc1155EventUnmarshaler.CounterpartTokenAddress,
+				L2Token:  erc1155EventUnmarshaler.TokenAddress,
+				From:     erc1155EventUnmarshaler.From,
+				To:       erc1155EventUnmarshaler.To,
+				TokenIds: erc1155EventUnmarshaler.TokenIds,
+				Amounts:  erc1155EventUnmarshaler.Amounts,
+			}
+			if paired {
+				checkGatewayMessage(&tmpMessageMatch, types.Layer2, erc1155EventUnmarshaler.Type, expected, messengerEvent.Message)
 			}
 			messageMatches = append(messageMatches, tmpMessageMatch)
 			erc1155EventUnmarshaler.MessageHash = messageHash
 		case types.L2FinalizeDepositERC1155:
-			messageHash, exists := c.findNextMessageEvent(erc1155EventUnmarshaler.TxHash, erc1155EventUnmarshaler.Index, messageHashes)
-			if !exists {
-				return nil, fmt.Errorf("message hash does not exist for erc1155 event %v", erc1155EventUnmarshaler)
-			}
+			messengerEvent := c.pairRelayedMessage(messengerEventIndex, types.Layer2, erc1155EventUnmarshaler.Type, erc1155EventUnmarshaler.TxHash, erc1155EventUnmarshaler.Index)
+			messageHash := messengerEvent.MessageHash
 			var tokenIdsStrList []string
 			for _, tokenID := range erc1155EventUnmarshaler.TokenIds {
 				tokenIdsStrList = append(tokenIdsStrList, tokenID.String())
@@ -116,13 +121,14 @@ func (c *MessageMatchAssembler) erc1155EventMessageMatchAssembler(gatewayEventsD
 				amountStrList = append(amountStrList, amount.String())
 			}
 			tmpMessageMatch = orm.GatewayMessageMatch{
-				MessageHash:   messageHash.Hex(),
-				TokenType:     int(types.TokenTypeERC1155),
-				L2EventType:   int(erc1155EventUnmarshaler.Type),
-				L2BlockNumber: erc1155EventUnmarshaler.Number,
-				L2TxHash:      erc1155EventUnmarshaler.TxHash.Hex(),
-				L2TokenIds:    strings.Join(tokenIdsStrList, ","),
-				L2Amounts:     strings.Join(amountStrList, ","),
+				MessageHash:    messageHash.Hex(),
+				TokenType:      int(types.TokenTypeERC1155),
+				L2EventType:    int(erc1155EventUnmarshaler.Type),
+				L2BlockNumber:  erc1155EventUnmarshaler.Number,
+				L2TxHash:       erc1155EventUnmarshaler.TxHash.Hex(),
+				L2TokenAddress: erc1155EventUnmarshaler.TokenAddress.Hex(),
+				L2TokenIds:     strings.Join(tokenIdsStrList, ","),
+				L2Amounts:      strings.Join(amountStrList, ","),
 			}
 			messageMatches = append(messageMatches, tmpMessageMatch)
 			erc1155EventUnmarshaler.MessageHash = messageHash
@@ -138,5 +144,10 @@ func (c *MessageMatchAssembler) erc1155EventMessageMatchAssembler(gatewayEventsD
 		transferEvents = append(transferEvents, *transferEventUnmarshaler)
 	}
 
-	return messageMatches, c.transferMatcher.erc1155Matcher(transferEvents, gatewayEvents)
+	mismatches, err := c.transferMatcher.erc1155Matcher(transferEvents, gatewayEvents)
+	if err != nil {
+		return messageMatches, err
+	}
+	markTransferMismatches(messageMatches, mismatches)
+	return messageMatches, nil
 }
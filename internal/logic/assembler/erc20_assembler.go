@@ -2,8 +2,8 @@ package assembler
 
 import (
 	"fmt"
+	"math/big"
 
-	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/shopspring/decimal"
 
 	"github.com/scroll-tech/chain-monitor/internal/logic/events"
@@ -11,17 +11,7 @@ import (
 	"github.com/scroll-tech/chain-monitor/internal/types"
 )
 
-func (c *MessageMatchAssembler) erc20EventMessageMatchAssembler(gatewayEventsData, messengerEventsData, transferEventsData []events.EventUnmarshaler) ([]orm.GatewayMessageMatch, error) {
-	messageHashes := make(map[messageEventKey]common.Hash)
-	for _, eventData := range messengerEventsData {
-		messengerEventUnmarshaler, ok := eventData.(*events.MessengerEventUnmarshaler)
-		if !ok {
-			return nil, fmt.Errorf("erc20 eventData is not of type *events.MessengerEventUnmarshaler")
-		}
-		key := messageEventKey{TxHash: messengerEventUnmarshaler.TxHash, LogIndex: messengerEventUnmarshaler.Index}
-		messageHashes[key] = messengerEventUnmarshaler.MessageHash
-	}
-
+func (c *MessageMatchAssembler) erc20EventMessageMatchAssembler(gatewayEventsData []events.EventUnmarshaler, messengerEventIndex *MessengerEventIndex, transferEventsData []events.EventUnmarshaler) ([]orm.GatewayMessageMatch, error) {
 	var messageMatches []orm.GatewayMessageMatch
 	var gatewayEvents []events.ERC20GatewayEventUnmarshaler
 	for _, eventData := range gatewayEventsData {
@@ -31,65 +21,84 @@ func (c *MessageMatchAssembler) erc20EventMessageMatchAssembler(gatewayEventsDat
 		var tmpMessageMatch orm.GatewayMessageMatch
 		switch erc20EventUnmarshaler.Type {
 		case types.L1DepositERC20:
-			messageHash, exists := c.findPrevMessageEvent(erc20EventUnmarshaler.TxHash, erc20EventUnmarshaler.Index, messageHashes)
-			if !exists {
-				return nil, fmt.Errorf("message hash does not exist for erc20 event %v", erc20EventUnmarshaler)
-			}
+			messengerEvent, paired := c.pairSentMessage(messengerEventIndex, types.Layer1, erc20EventUnmarshaler.Type, erc20EventUnmarshaler.GatewayAddress, erc20EventUnmarshaler.TxHash, erc20EventUnmarshaler.Index)
+			messageHash := messengerEvent.MessageHash
 			tmpMessageMatch = orm.GatewayMessageMatch{
-				MessageHash:   messageHash.Hex(),
-				TokenType:     int(types.TokenTypeERC20),
-				L1EventType:   int(erc20EventUnmarshaler.Type),
-				L1BlockNumber: erc20EventUnmarshaler.Number,
-				L1TxHash:      erc20EventUnmarshaler.TxHash.Hex(),
-				L1Amounts:     decimal.NewFromBigInt(erc20EventUnmarshaler.Amount, 0).String(),
+				MessageHash:    messageHash.Hex(),
+				TokenType:      int(types.TokenTypeERC20),
+				L1EventType:    int(erc20EventUnmarshaler.Type),
+				L1BlockNumber:  erc20EventUnmarshaler.Number,
+				L1TxHash:       erc20EventUnmarshaler.TxHash.Hex(),
+				L1TokenAddress: erc20EventUnmarshaler.TokenAddress.Hex(),
+				L1Amounts:      decimal.NewFromBigInt(erc20EventUnmarshaler.Amount, 0).String(),
+			}
+			expected := gatewayMessageFields{
+				L1Token: erc20EventUnmarshaler.TokenAddress,
+				L2Token: erc20EventUnmarshaler.CounterpartTokenAddress,
+				From:    erc20EventUnmarshaler.From,
+				To:      erc20EventUnmarshaler.To,
+				Amounts: []*big.Int{erc20EventUnmarshaler.Amount},
+				Data:    erc20EventUnmarshaler.Data,
+			}
+			if paired {
+				checkGatewayMessage(&tmpMessageMatch, types.Layer1, erc20EventUnmarshaler.Type, expected, messengerEvent.Message)
 			}
 			messageMatches = append(messageMatches, tmpMessageMatch)
 			erc20EventUnmarshaler.MessageHash = messageHash
 		case types.L1FinalizeWithdrawERC20:
-			messageHash, exists := c.findNextMessageEvent(erc20EventUnmarshaler.TxHash, erc20EventUnmarshaler.Index, messageHashes)
-			if !exists {
-				return nil, fmt.Errorf("message hash does not exist for erc20 event %v", erc20EventUnmarshaler)
-			}
+			messengerEvent := c.pairRelayedMessage(messengerEventIndex, types.Layer1, erc20EventUnmarshaler.Type, erc20EventUnmarshaler.TxHash, erc20EventUnmarshaler.Index)
+			messageHash := messengerEvent.MessageHash
 			tmpMessageMatch = orm.GatewayMessageMatch{
-				MessageHash:   messageHash.Hex(),
-				TokenType:     int(types.TokenTypeERC20),
-				L1EventType:   int(erc20EventUnmarshaler.Type),
-				L1BlockNumber: erc20EventUnmarshaler.Number,
-				L1TxHash:      erc20EventUnmarshaler.TxHash.Hex(),
-				L1Amounts:     decimal.NewFromBigInt(erc20EventUnmarshaler.Amount, 0).String(),
+				MessageHash:    messageHash.Hex(),
+				TokenType:      int(types.TokenTypeERC20),
+				L1EventType:    int(erc20EventUnmarshaler.Type),
+				L1BlockNumber:  erc20EventUnmarshaler.Number,
+				L1TxHash:       erc20EventUnmarshaler.TxHash.Hex(),
+				L1TokenAddress: erc20EventUnmarshaler.TokenAddress.Hex(),
+				L1Amounts:      decimal.NewFromBigInt(erc20EventUnmarshaler.Amount, 0).String(),
 			}
 			messageMatches = append(messageMatches, tmpMessageMatch)
 			erc20EventUnmarshaler.MessageHash = messageHash
 		case types.L2WithdrawERC20:
-			messageHash, exists := c.findPrevMessageEvent(erc20EventUnmarshaler.TxHash, erc20EventUnmarshaler.Index, messageHashes)
-			if !exists {
-				return nil, fmt.Errorf("message hash does not exist for erc20 event %v", erc20EventUnmarshaler)
-			}
+			messengerEvent, paired := c.pairSentMessage(messengerEventIndex, types.Layer2, erc20EventUnmarshaler.Type, erc20EventUnmarshaler.GatewayAddress, erc20EventUnmarshaler.TxHash, erc20EventUnmarshaler.Index)
+			messageHash := messengerEvent.MessageHash
 			tmpMessageMatch = orm.GatewayMessageMatch{
-				MessageHash:   messageHash.Hex(),
-				TokenType:     int(types.TokenTypeERC20),
-				L2EventType:   int(erc20EventUnmarshaler.Type),
-				L2BlockNumber: erc20EventUnmarshaler.Number,
-				L2TxHash:      erc20EventUnmarshaler.TxHash.Hex(),
-				L2Amounts:     decimal.NewFromBigInt(erc20EventUnmarshaler.Amount, 0).String(),
+				MessageHash:    messageHash.Hex(),
+				TokenType:      int(types.TokenTypeERC20),
+				L2EventType:    int(erc20EventUnmarshaler.Type),
+				L2BlockNumber:  erc20EventUnmarshaler.Number,
+				L2TxHash:       erc20EventUnmarshaler.TxHash.Hex(),
+				L2TokenAddress: erc20EventUnmarshaler.TokenAddress.Hex(),
+				L2Amounts:      decimal.NewFromBigInt(erc20EventUnmarshaler.Amount, 0).String(),
+			}
+			expected := gatewayMessageFields{
+				L1Token: erc20EventUnmarshaler.CounterpartTokenAddress,
+				L2Token: erc20EventUnmarshaler.TokenAddress,
+				From:    erc20EventUnmarshaler.From,
+				To:      erc20EventUnmarshaler.To,
+				Amounts: []*big.Int{erc20EventUnmarshaler.Amount},
+				Data:    erc20EventUnmarshaler.Data,
+			}
+			if paired {
+				checkGatewayMessage(&tmpMessageMatch, types.Layer2, erc20EventUnmarshaler.Type, expected, messengerEvent.Message)
 			}
 			messageMatches = append(messageMatches, tmpMessageMatch)
 			erc20EventUnmarshaler.MessageHash = messageHash
 		case types.L2FinalizeDepositERC20:
-			messageHash, exists := c.findNextMessageEvent(erc20EventUnmarshaler.TxHash, erc20EventUnmarshaler.Index, messageHashes)
-			if !exists {
-				return nil, fmt.Errorf("message hash does not exist for erc20 event %v", erc20EventUnmarshaler)
-			}
+			messengerEvent := c.pairRelayedMessage(messengerEventIndex, types.Layer2, erc20EventUnmarshaler.Type, erc20EventUnmarshaler.TxHash, erc20EventUnmarshaler.Index)
+			messageHash := messengerEvent.MessageHash
 			tmpMessageMatch = orm.GatewayMessageMatch{
-				MessageHash:   messageHash.Hex(),
-				TokenType:     int(types.TokenTypeERC20),
-				L2EventType:   int(erc20EventUnmarshaler.Type),
-				L2BlockNumber: erc20EventUnmarshaler.Number,
-				L2TxHash:      erc20EventUnmarshaler.TxHash.Hex(),
-				L2Amounts:     decimal.NewFromBigInt(erc20EventUnmarshaler.Amount, 0).String(),
+				MessageHash:    messageHash.Hex(),
+				TokenType:      int(types.TokenTypeERC20),
+				L2EventType:    int(erc20EventUnmarshaler.Type),
+				L2BlockNumber:  erc20EventUnmarshaler.Number,
+				L2TxHash:       erc20EventUnmarshaler.TxHash.Hex(),
+				L2TokenAddress: erc20EventUnmarshaler.TokenAddress.Hex(),
+				L2Amounts:      decimal.NewFromBigInt(erc20EventUnmarshaler.Amount, 0).String(),
 			}
 			messageMatches = append(messageMatches, tmpMessageMatch)
 			erc20EventUnmarshaler.MessageHash = messageHash
+			c.bridgedTokenRegistry.Learn(erc20EventUnmarshaler.TokenAddress)
 		}
 	}
 
@@ -102,6 +111,10 @@ func (c *MessageMatchAssembler) erc20EventMessageMatchAssembler(gatewayEventsDat
 		transferEvents = append(transferEvents, *transferEventUnmarshaler)
 	}
 
-	err := c.transferMatcher.erc20Matcher(transferEvents, gatewayEvents)
-	return messageMatches, err
+	mismatches, err := c.transferMatcher.erc20Matcher(transferEvents, gatewayEvents)
+	if err != nil {
+		return messageMatches, err
+	}
+	markTransferMismatches(messageMatches, mismatches)
+	return messageMatches, nil
 }
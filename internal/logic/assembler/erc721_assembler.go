@@ -4,24 +4,12 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/scroll-tech/go-ethereum/common"
-
 	"github.com/scroll-tech/chain-monitor/internal/logic/events"
 	"github.com/scroll-tech/chain-monitor/internal/orm"
 	"github.com/scroll-tech/chain-monitor/internal/types"
 )
 
-func (c *MessageMatchAssembler) erc721EventMessageMatchAssembler(gatewayEventsData, messengerEventsData, transferEventsData []events.EventUnmarshaler) ([]orm.GatewayMessageMatch, error) {
-	messageHashes := make(map[messageEventKey]common.Hash)
-	for _, eventData := range messengerEventsData {
-		messengerEventUnmarshaler, ok := eventData.(*events.MessengerEventUnmarshaler)
-		if !ok {
-			return nil, fmt.Errorf("eventData is not of type *events.MessengerEventUnmarshaler")
-		}
-		key := messageEventKey{TxHash: messengerEventUnmarshaler.TxHash, LogIndex: messengerEventUnmarshaler.Index}
-		messageHashes[key] = messengerEventUnmarshaler.MessageHash
-	}
-
+func (c *MessageMatchAssembler) erc721EventMessageMatchAssembler(gatewayEventsData []events.EventUnmarshaler, messengerEventIndex *MessengerEventIndex, transferEventsData []events.EventUnmarshaler) ([]orm.GatewayMessageMatch, error) {
 	var messageMatches []orm.GatewayMessageMatch
 	var gatewayEvents []events.ERC721GatewayEventUnmarshaler
 	for _, eventData := range gatewayEventsData {
@@ -31,78 +19,94 @@ func (c *MessageMatchAssembler) erc721EventMessageMatchAssembler(gatewayEventsDa
 		var tmpMessageMatch orm.GatewayMessageMatch
 		switch erc721EventUnmarshaler.Type {
 		case types.L1DepositERC721:
-			messageHash, exists := c.findPrevMessageEvent(erc721EventUnmarshaler.TxHash, erc721EventUnmarshaler.Index, messageHashes)
-			if !exists {
-				return nil, fmt.Errorf("message hash does not exist for erc721 event %v", erc721EventUnmarshaler)
-			}
+			messengerEvent, paired := c.pairSentMessage(messengerEventIndex, types.Layer1, erc721EventUnmarshaler.Type, erc721EventUnmarshaler.GatewayAddress, erc721EventUnmarshaler.TxHash, erc721EventUnmarshaler.Index)
+			messageHash := messengerEvent.MessageHash
 			var tokenIdsStrList []string
 			for _, tokenID := range erc721EventUnmarshaler.TokenIds {
 				tokenIdsStrList = append(tokenIdsStrList, tokenID.String())
 			}
 			tmpMessageMatch = orm.GatewayMessageMatch{
-				MessageHash:   messageHash.Hex(),
-				TokenType:     int(types.TokenTypeERC721),
-				L1EventType:   int(erc721EventUnmarshaler.Type),
-				L1BlockNumber: erc721EventUnmarshaler.Number,
-				L1TxHash:      erc721EventUnmarshaler.TxHash.Hex(),
-				L1TokenIds:    strings.Join(tokenIdsStrList, ","),
+				MessageHash:    messageHash.Hex(),
+				TokenType:      int(types.TokenTypeERC721),
+				L1EventType:    int(erc721EventUnmarshaler.Type),
+				L1BlockNumber:  erc721EventUnmarshaler.Number,
+				L1TxHash:       erc721EventUnmarshaler.TxHash.Hex(),
+				L1TokenAddress: erc721EventUnmarshaler.TokenAddress.Hex(),
+				L1TokenIds:     strings.Join(tokenIdsStrList, ","),
+			}
+			expected := gatewayMessageFields{
+				L1Token:  erc721EventUnmarshaler.TokenAddress,
+				L2Token:  erc721EventUnmarshaler.CounterpartTokenAddress,
+				From:     erc721EventUnmarshaler.From,
+				To:       erc721EventUnmarshaler.To,
+				TokenIds: erc721EventUnmarshaler.TokenIds,
+			}
+			if paired {
+				checkGatewayMessage(&tmpMessageMatch, types.Layer1, erc721EventUnmarshaler.Type, expected, messengerEvent.Message)
 			}
 			messageMatches = append(messageMatches, tmpMessageMatch)
 			erc721EventUnmarshaler.MessageHash = messageHash
 		case types.L1FinalizeWithdrawERC721:
-			messageHash, exists := c.findNextMessageEvent(erc721EventUnmarshaler.TxHash, erc721EventUnmarshaler.Index, messageHashes)
-			if !exists {
-				return nil, fmt.Errorf("message hash does not exist for erc721 event %v", erc721EventUnmarshaler)
-			}
+			messengerEvent := c.pairRelayedMessage(messengerEventIndex, types.Layer1, erc721EventUnmarshaler.Type, erc721EventUnmarshaler.TxHash, erc721EventUnmarshaler.Index)
+			messageHash := messengerEvent.MessageHash
 			var tokenIdsStrList []string
 			for _, tokenID := range erc721EventUnmarshaler.TokenIds {
 				tokenIdsStrList = append(tokenIdsStrList, tokenID.String())
 			}
 			tmpMessageMatch = orm.GatewayMessageMatch{
-				MessageHash:   messageHash.Hex(),
-				TokenType:     int(types.TokenTypeERC721),
-				L1EventType:   int(erc721EventUnmarshaler.Type),
-				L1BlockNumber: erc721EventUnmarshaler.Number,
-				L1TxHash:      erc721EventUnmarshaler.TxHash.Hex(),
-				L1TokenIds:    strings.Join(tokenIdsStrList, ","),
+				MessageHash:    messageHash.Hex(),
+				TokenType:      int(types.TokenTypeERC721),
+				L1EventType:    int(erc721EventUnmarshaler.Type),
+				L1BlockNumber:  erc721EventUnmarshaler.Number,
+				L1TxHash:       erc721EventUnmarshaler.TxHash.Hex(),
+				L1TokenAddress: erc721EventUnmarshaler.TokenAddress.Hex(),
+				L1TokenIds:     strings.Join(tokenIdsStrList, ","),
 			}
 			messageMatches = append(messageMatches, tmpMessageMatch)
 			erc721EventUnmarshaler.MessageHash = messageHash
 		case types.L2WithdrawERC721:
-			messageHash, exists := c.findPrevMessageEvent(erc721EventUnmarshaler.TxHash, erc721EventUnmarshaler.Index, messageHashes)
-			if !exists {
-				return nil, fmt.Errorf("message hash does not exist for erc721 event %v", erc721EventUnmarshaler)
-			}
+			messengerEvent, paired := c.pairSentMessage(messengerEventIndex, types.Layer2, erc721EventUnmarshaler.Type, erc721EventUnmarshaler.GatewayAddress, erc721EventUnmarshaler.TxHash, erc721EventUnmarshaler.Index)
+			messageHash := messengerEvent.MessageHash
 			var tokenIdsStrList []string
 			for _, tokenID := range erc721EventUnmarshaler.TokenIds {
 				tokenIdsStrList = append(tokenIdsStrList, tokenID.String())
 			}
 			tmpMessageMatch = orm.GatewayMessageMatch{
-				MessageHash:   messageHash.Hex(),
-				TokenType:     int(types.TokenTypeERC721),
-				L2EventType:   int(erc721EventUnmarshaler.Type),
-				L2BlockNumber: erc721EventUnmarshaler.Number,
-				L2TxHash:      erc721EventUnmarshaler.TxHash.Hex(),
-				L2TokenIds:    strings.Join(tokenIdsStrList, ","),
+				MessageHash:    messageHash.Hex(),
+				TokenType:      int(types.TokenTypeERC721),
+				L2EventType:    int(erc721EventUnmarshaler.Type),
+				L2BlockNumber:  erc721EventUnmarshaler.Number,
+				L2TxHash:       erc721EventUnmarshaler.TxHash.Hex(),
+				L2TokenAddress: erc721EventUnmarshaler.TokenAddress.Hex(),
+				L2TokenIds:     strings.Join(tokenIdsStrList, ","),
+			}
+			expected := gatewayMessageFields{
+				L1Token:  erc721EventUnmarshaler.CounterpartTokenAddress,
+				L2Token:  erc721EventUnmarshaler.TokenAddress,
+				From:     erc721EventUnmarshaler.From,
+				To:       erc721EventUnmarshaler.To,
+				TokenIds: erc721EventUnmarshaler.TokenIds,
+			}
+			if paired {
+				checkGatewayMessage(&tmpMessageMatch, types.Layer2, erc721EventUnmarshaler.Type, expected, messengerEvent.Message)
 			}
 			messageMatches = append(messageMatches, tmpMessageMatch)
 			erc721EventUnmarshaler.MessageHash = messageHash
 		case types.L2FinalizeDepositERC721:
-			messageHash, exists := c.findNextMessageEvent(erc721EventUnmarshaler.TxHash, erc721EventUnmarshaler.Index, messageHashes)
-			if !exists {
-				return nil, fmt.Errorf("message hash does not exist for erc721 event %v", erc721EventUnmarshaler)
-			}
+			messengerEvent := c.pairRelayedMessage(messengerEventIndex, types.Layer2, erc721EventUnmarshaler.Type, erc721EventUnmarshaler.TxHash, erc721EventUnmarshaler.Index)
+			messageHash := messengerEvent.MessageHash
 			var tokenIdsStrList []string
 			for _, tokenID := range erc721EventUnmarshaler.TokenIds {
 				tokenIdsStrList = append(tokenIdsStrList, tokenID.String())
 			}
 			tmpMessageMatch = orm.GatewayMessageMatch{
-				MessageHash:   messageHash.Hex(),
-				TokenType:     int(types.TokenTypeERC721),
-				L2EventType:   int(erc721EventUnmarshaler.Type),
-				L2BlockNumber: erc721EventUnmarshaler.Number,
-				L2TxHash:      erc721EventUnmarshaler.TxHash.Hex(),
-				L2TokenIds:    strings.Join(tokenIdsStrList, ","),
+				MessageHash:    messageHash.Hex(),
+				TokenType:      int(types.TokenTypeERC721),
+				L2EventType:    int(erc721EventUnmarshaler.Type),
+				L2BlockNumber:  erc721EventUnmarshaler.Number,
+				L2TxHash:       erc721EventUnmarshaler.TxHash.Hex(),
+				L2TokenAddress: erc721EventUnmarshaler.TokenAddress.Hex(),
+				L2TokenIds:     strings.Join(tokenIdsStrList, ","),
 			}
 			messageMatches = append(messageMatches, tmpMessageMatch)
 			erc721EventUnmarshaler.MessageHash = messageHash
@@ -118,5 +122,10 @@ func (c *MessageMatchAssembler) erc721EventMessageMatchAssembler(gatewayEventsDa
 		transferEvents = append(transferEvents, *transferEventUnmarshaler)
 	}
 
-	return messageMatches, c.transferMatcher.erc721Matcher(transferEvents, gatewayEvents)
+	mismatches, err := c.transferMatcher.erc721Matcher(transferEvents, gatewayEvents)
+	if err != nil {
+		return messageMatches, err
+	}
+	markTransferMismatches(messageMatches, mismatches)
+	return messageMatches, nil
 }
@@ -0,0 +1,137 @@
+package assembler
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/shopspring/decimal"
+
+	"github.com/scroll-tech/chain-monitor/internal/logic/events"
+	"github.com/scroll-tech/chain-monitor/internal/orm"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+)
+
+func (c *MessageMatchAssembler) ethEventMessageMatchAssembler(gatewayEventsData []events.EventUnmarshaler, messengerEventIndex *MessengerEventIndex) ([]orm.GatewayMessageMatch, error) {
+	var messageMatches []orm.GatewayMessageMatch
+	for _, eventData := range gatewayEventsData {
+		ethEventUnmarshaler, ok := eventData.(*events.ETHGatewayEventUnmarshaler)
+		if !ok {
+			return nil, fmt.Errorf("eventData is not of type *events.ETHGatewayEventUnmarshaler")
+		}
+
+		var tmpMessageMatch orm.GatewayMessageMatch
+		switch ethEventUnmarshaler.Type {
+		case types.L1DepositETH:
+			messengerEvent, paired := c.pairSentMessage(messengerEventIndex, types.Layer1, ethEventUnmarshaler.Type, ethEventUnmarshaler.GatewayAddress, ethEventUnmarshaler.TxHash, ethEventUnmarshaler.Index)
+			messageHash := messengerEvent.MessageHash
+			tmpMessageMatch = orm.GatewayMessageMatch{
+				MessageHash:   messageHash.Hex(),
+				TokenType:     int(types.TokenTypeETH),
+				L1EventType:   int(ethEventUnmarshaler.Type),
+				L1BlockNumber: ethEventUnmarshaler.Number,
+				L1TxHash:      ethEventUnmarshaler.TxHash.Hex(),
+				L1Amounts:     decimal.NewFromBigInt(ethEventUnmarshaler.Amount, 0).String(),
+			}
+			expected := gatewayMessageFields{
+				From:    ethEventUnmarshaler.From,
+				To:      ethEventUnmarshaler.To,
+				Amounts: []*big.Int{ethEventUnmarshaler.Amount},
+				Data:    ethEventUnmarshaler.Data,
+			}
+			if paired {
+				checkGatewayMessage(&tmpMessageMatch, types.Layer1, ethEventUnmarshaler.Type, expected, messengerEvent.Message)
+			}
+			messageMatches = append(messageMatches, tmpMessageMatch)
+			ethEventUnmarshaler.MessageHash = messageHash
+		case types.L1FinalizeWithdrawETH:
+			messengerEvent := c.pairRelayedMessage(messengerEventIndex, types.Layer1, ethEventUnmarshaler.Type, ethEventUnmarshaler.TxHash, ethEventUnmarshaler.Index)
+			messageHash := messengerEvent.MessageHash
+			tmpMessageMatch = orm.GatewayMessageMatch{
+				MessageHash:   messageHash.Hex(),
+				TokenType:     int(types.TokenTypeETH),
+				L1EventType:   int(ethEventUnmarshaler.Type),
+				L1BlockNumber: ethEventUnmarshaler.Number,
+				L1TxHash:      ethEventUnmarshaler.TxHash.Hex(),
+				L1Amounts:     decimal.NewFromBigInt(ethEventUnmarshaler.Amount, 0).String(),
+			}
+			messageMatches = append(messageMatches, tmpMessageMatch)
+			ethEventUnmarshaler.MessageHash = messageHash
+		case types.L1RefundETH:
+			// The refund has no message hash to pair, it's assembled by l1ETHRefundAssembler instead.
+			continue
+		case types.L2WithdrawETH:
+			messengerEvent, paired := c.pairSentMessage(messengerEventIndex, types.Layer2, ethEventUnmarshaler.Type, ethEventUnmarshaler.GatewayAddress, ethEventUnmarshaler.TxHash, ethEventUnmarshaler.Index)
+			messageHash := messengerEvent.MessageHash
+			tmpMessageMatch = orm.GatewayMessageMatch{
+				MessageHash:   messageHash.Hex(),
+				TokenType:     int(types.TokenTypeETH),
+				L2EventType:   int(ethEventUnmarshaler.Type),
+				L2BlockNumber: ethEventUnmarshaler.Number,
+				L2TxHash:      ethEventUnmarshaler.TxHash.Hex(),
+				L2Amounts:     decimal.NewFromBigInt(ethEventUnmarshaler.Amount, 0).String(),
+			}
+			expected := gatewayMessageFields{
+				From:    ethEventUnmarshaler.From,
+				To:      ethEventUnmarshaler.To,
+				Amounts: []*big.Int{ethEventUnmarshaler.Amount},
+				Data:    ethEventUnmarshaler.Data,
+			}
+			if paired {
+				checkGatewayMessage(&tmpMessageMatch, types.Layer2, ethEventUnmarshaler.Type, expected, messengerEvent.Message)
+			}
+			messageMatches = append(messageMatches, tmpMessageMatch)
+			ethEventUnmarshaler.MessageHash = messageHash
+		case types.L2FinalizeDepositETH:
+			messengerEvent := c.pairRelayedMessage(messengerEventIndex, types.Layer2, ethEventUnmarshaler.Type, ethEventUnmarshaler.TxHash, ethEventUnmarshaler.Index)
+			messageHash := messengerEvent.MessageHash
+			tmpMessageMatch = orm.GatewayMessageMatch{
+				MessageHash:   messageHash.Hex(),
+				TokenType:     int(types.TokenTypeETH),
+				L2EventType:   int(ethEventUnmarshaler.Type),
+				L2BlockNumber: ethEventUnmarshaler.Number,
+				L2TxHash:      ethEventUnmarshaler.TxHash.Hex(),
+				L2Amounts:     decimal.NewFromBigInt(ethEventUnmarshaler.Amount, 0).String(),
+			}
+			messageMatches = append(messageMatches, tmpMessageMatch)
+			ethEventUnmarshaler.MessageHash = messageHash
+		}
+	}
+
+	// The gateway doesn't emit transfer events for ETH, the eth amount is checked by the messenger balance checker.
+	return messageMatches, nil
+}
+
+// l1ETHRefundAssembler assembles the eth refunds of the dropped messages. The l1 messenger sends the value of a dropped
+// message back through the gateway which sent it without any messenger event, so the l1 messenger eth balance check
+// takes the refunds from the RefundETH events, and the RefundERC20 events of the non custodial gateways which unwrap the
+// tokens to eth, e.g. the weth gateway.
+func (c *MessageMatchAssembler) l1ETHRefundAssembler(gatewayEvents []events.EventUnmarshaler) []orm.L1ETHRefund {
+	var refunds []orm.L1ETHRefund
+	for _, eventData := range gatewayEvents {
+		switch event := eventData.(type) {
+		case *events.ETHGatewayEventUnmarshaler:
+			if event.Type != types.L1RefundETH {
+				continue
+			}
+			refunds = append(refunds, newL1ETHRefund(event.Type, event.Number, event.TxHash, event.Index, event.GatewayAddress, event.Amount))
+		case *events.ERC20GatewayEventUnmarshaler:
+			if event.Type != types.L1RefundERC20 || !c.nonCustodialGateways[event.GatewayAddress] {
+				continue
+			}
+			refunds = append(refunds, newL1ETHRefund(event.Type, event.Number, event.TxHash, event.Index, event.GatewayAddress, event.Amount))
+		}
+	}
+	return refunds
+}
+
+func newL1ETHRefund(eventType types.EventType, blockNumber uint64, txHash common.Hash, logIndex uint, gatewayAddress common.Address, amount *big.Int) orm.L1ETHRefund {
+	return orm.L1ETHRefund{
+		EventType:      int(eventType),
+		BlockNumber:    blockNumber,
+		TxHash:         txHash.Hex(),
+		LogIndex:       logIndex,
+		GatewayAddress: gatewayAddress.Hex(),
+		Amount:         decimal.NewFromBigInt(amount, 0).String(),
+	}
+}
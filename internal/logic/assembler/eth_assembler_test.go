@@ -0,0 +1,53 @@
+package assembler
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scroll-tech/chain-monitor/internal/logic/events"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+)
+
+func TestL1ETHRefundAssembler(t *testing.T) {
+	ethGateway := common.HexToAddress("0x1000")
+	wethGateway := common.HexToAddress("0x1001")
+	standardGateway := common.HexToAddress("0x1002")
+	c := &MessageMatchAssembler{nonCustodialGateways: map[common.Address]bool{wethGateway: true}}
+
+	refundTxHash := common.HexToHash("0x01")
+	gatewayEvents := []events.EventUnmarshaler{
+		&events.ETHGatewayEventUnmarshaler{Type: types.L1DepositETH, Number: 10, TxHash: common.HexToHash("0x02"), Amount: big.NewInt(5), Index: 1, GatewayAddress: ethGateway},
+		&events.ETHGatewayEventUnmarshaler{Type: types.L1RefundETH, Number: 11, TxHash: refundTxHash, Amount: big.NewInt(7), Index: 3, GatewayAddress: ethGateway},
+		&events.ERC20GatewayEventUnmarshaler{Type: types.L1RefundERC20, Number: 12, TxHash: common.HexToHash("0x03"), Amount: big.NewInt(9), Index: 0, GatewayAddress: wethGateway},
+		// the standard gateway holds the refunded tokens, the messenger eth balance isn't changed.
+		&events.ERC20GatewayEventUnmarshaler{Type: types.L1RefundERC20, Number: 12, TxHash: common.HexToHash("0x04"), Amount: big.NewInt(100), Index: 0, GatewayAddress: standardGateway},
+	}
+
+	refunds := c.l1ETHRefundAssembler(gatewayEvents)
+	assert.Len(t, refunds, 2)
+
+	assert.Equal(t, int(types.L1RefundETH), refunds[0].EventType)
+	assert.Equal(t, uint64(11), refunds[0].BlockNumber)
+	assert.Equal(t, refundTxHash.Hex(), refunds[0].TxHash)
+	assert.Equal(t, uint(3), refunds[0].LogIndex)
+	assert.Equal(t, ethGateway.Hex(), refunds[0].GatewayAddress)
+	assert.Equal(t, "7", refunds[0].Amount)
+
+	assert.Equal(t, int(types.L1RefundERC20), refunds[1].EventType)
+	assert.Equal(t, wethGateway.Hex(), refunds[1].GatewayAddress)
+	assert.Equal(t, "9", refunds[1].Amount)
+}
+
+func TestETHEventMessageMatchAssemblerSkipsRefund(t *testing.T) {
+	c := &MessageMatchAssembler{}
+
+	gatewayEvents := []events.EventUnmarshaler{
+		&events.ETHGatewayEventUnmarshaler{Type: types.L1RefundETH, Number: 11, TxHash: common.HexToHash("0x01"), Amount: big.NewInt(7), Index: 3},
+	}
+	messageMatches, err := c.ethEventMessageMatchAssembler(gatewayEvents, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, messageMatches)
+}
@@ -0,0 +1,228 @@
+package assembler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/accounts/abi"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"github.com/scroll-tech/chain-monitor/internal/logic/alert"
+	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/il1erc1155gateway"
+	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/il1erc20gateway"
+	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/il1erc721gateway"
+	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/il1ethgateway"
+	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/il2erc1155gateway"
+	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/il2erc20gateway"
+	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/il2erc721gateway"
+	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/il2ethgateway"
+	"github.com/scroll-tech/chain-monitor/internal/orm"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+)
+
+var gatewayMessageMismatchTotal = promauto.With(prometheus.DefaultRegisterer).NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_message_payload_mismatch_total",
+	Help: "The total number of the gateway events which don't match the finalize calls carried by their sent messages.",
+}, []string{"layer", "event_type"})
+
+// gatewayMessageMethods the finalize call of the gateway on the other layer, which the sent message of the gateway event carries.
+var gatewayMessageMethods = map[types.EventType]string{
+	types.L1DepositETH:           "finalizeDepositETH",
+	types.L1DepositERC20:         "finalizeDepositERC20",
+	types.L1DepositERC721:        "finalizeDepositERC721",
+	types.L1BatchDepositERC721:   "finalizeBatchDepositERC721",
+	types.L1DepositERC1155:       "finalizeDepositERC1155",
+	types.L1BatchDepositERC1155:  "finalizeBatchDepositERC1155",
+	types.L2WithdrawETH:          "finalizeWithdrawETH",
+	types.L2WithdrawERC20:        "finalizeWithdrawERC20",
+	types.L2WithdrawERC721:       "finalizeWithdrawERC721",
+	types.L2BatchWithdrawERC721:  "finalizeBatchWithdrawERC721",
+	types.L2WithdrawERC1155:      "finalizeWithdrawERC1155",
+	types.L2BatchWithdrawERC1155: "finalizeBatchWithdrawERC1155",
+}
+
+// gatewayMessageFields the fields of a gateway finalize call, decoded from the sent message or taken from the gateway
+// event. The token addresses are not set for eth, the amounts are not set for erc721, and the data passed to the
+// recipient is only set for eth and erc20.
+type gatewayMessageFields struct {
+	L1Token  common.Address
+	L2Token  common.Address
+	From     common.Address
+	To       common.Address
+	TokenIds []*big.Int
+	Amounts  []*big.Int
+	Data     []byte
+}
+
+// gatewayMessageABIs returns the abis of the gateways which are called by the messages sent on the layer.
+func gatewayMessageABIs(layer types.LayerType) ([]*abi.ABI, error) {
+	metaData := []func() (*abi.ABI, error){
+		il2ethgateway.Il2ethgatewayMetaData.GetAbi,
+		il2erc20gateway.Il2erc20gatewayMetaData.GetAbi,
+		il2erc721gateway.Il2erc721gatewayMetaData.GetAbi,
+		il2erc1155gateway.Il2erc1155gatewayMetaData.GetAbi,
+	}
+	if layer == types.Layer2 {
+		metaData = []func() (*abi.ABI, error){
+			il1ethgateway.Il1ethgatewayMetaData.GetAbi,
+			il1erc20gateway.Il1erc20gatewayMetaData.GetAbi,
+			il1erc721gateway.Il1erc721gatewayMetaData.GetAbi,
+			il1erc1155gateway.Il1erc1155gatewayMetaData.GetAbi,
+		}
+	}
+
+	var abis []*abi.ABI
+	for _, getABI := range metaData {
+		parsed, err := getABI()
+		if err != nil {
+			return nil, err
+		}
+		abis = append(abis, parsed)
+	}
+	return abis, nil
+}
+
+// decodeGatewayMessage decodes the finalize call carried by the message sent on the layer with the gateway abis of
+// the other layer, and returns the name of the called method with its fields.
+func decodeGatewayMessage(layer types.LayerType, message []byte) (string, *gatewayMessageFields, error) {
+	if len(message) < 4 {
+		return "", nil, errors.New("the message is not a gateway call")
+	}
+
+	abis, err := gatewayMessageABIs(layer)
+	if err != nil {
+		return "", nil, err
+	}
+
+	for _, parsed := range abis {
+		method, methodErr := parsed.MethodById(message[:4])
+		if methodErr != nil {
+			continue
+		}
+
+		args, unpackErr := method.Inputs.Unpack(message[4:])
+		if unpackErr != nil {
+			return method.Name, nil, fmt.Errorf("unpack %s failed, err: %w", method.Name, unpackErr)
+		}
+
+		fields := &gatewayMessageFields{}
+		for i, input := range method.Inputs {
+			switch strings.TrimPrefix(input.Name, "_") {
+			case "l1Token":
+				fields.L1Token, _ = args[i].(common.Address)
+			case "l2Token":
+				fields.L2Token, _ = args[i].(common.Address)
+			case "from":
+				fields.From, _ = args[i].(common.Address)
+			case "to":
+				fields.To, _ = args[i].(common.Address)
+			case "tokenId":
+				fields.TokenIds = []*big.Int{args[i].(*big.Int)}
+			case "tokenIds":
+				fields.TokenIds, _ = args[i].([]*big.Int)
+			case "amount":
+				fields.Amounts = []*big.Int{args[i].(*big.Int)}
+			case "amounts":
+				fields.Amounts, _ = args[i].([]*big.Int)
+			case "data":
+				fields.Data, _ = args[i].([]byte)
+			}
+		}
+		return method.Name, fields, nil
+	}
+	return "", nil, errors.New("the message is not a gateway call")
+}
+
+// compareGatewayMessage returns the first field of the decoded finalize call which differs from the gateway event.
+func compareGatewayMessage(expected, actual *gatewayMessageFields) error {
+	switch {
+	case expected.L1Token != actual.L1Token:
+		return fmt.Errorf("l1 token mismatch, event: %s, message: %s", expected.L1Token.Hex(), actual.L1Token.Hex())
+	case expected.L2Token != actual.L2Token:
+		return fmt.Errorf("l2 token mismatch, event: %s, message: %s", expected.L2Token.Hex(), actual.L2Token.Hex())
+	case expected.From != actual.From:
+		return fmt.Errorf("from mismatch, event: %s, message: %s", expected.From.Hex(), actual.From.Hex())
+	case expected.To != actual.To:
+		return fmt.Errorf("to mismatch, event: %s, message: %s", expected.To.Hex(), actual.To.Hex())
+	case !equalBigInts(expected.TokenIds, actual.TokenIds):
+		return fmt.Errorf("token ids mismatch, event: %v, message: %v", expected.TokenIds, actual.TokenIds)
+	case !equalBigInts(expected.Amounts, actual.Amounts):
+		return fmt.Errorf("amounts mismatch, event: %v, message: %v", expected.Amounts, actual.Amounts)
+	case !bytes.Equal(expected.Data, actual.Data):
+		return fmt.Errorf("data mismatch, event: %s, message: %s", hexutil.Encode(expected.Data), hexutil.Encode(actual.Data))
+	}
+	return nil
+}
+
+func equalBigInts(a, b []*big.Int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Cmp(b[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// checkGatewayMessage checks the finalize call carried by the sent message of the gateway deposit or withdraw event
+// against the event in the same tx. A mismatch reveals a gateway which forges the payload of the message, the message
+// match is marked failed with the reason and alerted.
+func checkGatewayMessage(messageMatch *orm.GatewayMessageMatch, layer types.LayerType, eventType types.EventType, expected gatewayMessageFields, message []byte) {
+	method, ok := gatewayMessageMethods[eventType]
+	if !ok {
+		return
+	}
+
+	checkErr := func() error {
+		actualMethod, actual, err := decodeGatewayMessage(layer, message)
+		if err != nil {
+			return err
+		}
+		if actualMethod != method {
+			return fmt.Errorf("method mismatch, event: %s, message: %s", method, actualMethod)
+		}
+		return compareGatewayMessage(&expected, actual)
+	}()
+	if checkErr == nil {
+		return
+	}
+
+	gatewayMessageMismatchTotal.WithLabelValues(layer.String(), eventType.String()).Inc()
+	markGatewayMessageFailed(messageMatch, layer, eventType, fmt.Errorf("gateway message payload mismatch: %w", checkErr))
+}
+
+// markGatewayMessageFailed marks the block status of the layer failed with the reason, and alerts the gateway event
+// which doesn't match its messenger message.
+func markGatewayMessageFailed(messageMatch *orm.GatewayMessageMatch, layer types.LayerType, eventType types.EventType, checkErr error) {
+	info := alert.GatewayMessageInfo{
+		TokenType:   types.TokenType(messageMatch.TokenType),
+		Layer:       layer,
+		EventType:   eventType,
+		MessageHash: messageMatch.MessageHash,
+		Error:       checkErr.Error(),
+	}
+	tokenAddress := messageMatch.L1TokenAddress
+	if layer == types.Layer1 {
+		info.BlockNumber, info.TxHash = messageMatch.L1BlockNumber, messageMatch.L1TxHash
+		messageMatch.L1BlockStatus = int(types.BlockStatusTypeFailed)
+	} else {
+		info.BlockNumber, info.TxHash = messageMatch.L2BlockNumber, messageMatch.L2TxHash
+		tokenAddress = messageMatch.L2TokenAddress
+		messageMatch.L2BlockStatus = int(types.BlockStatusTypeFailed)
+	}
+	info.TokenAddress = tokenAddress
+	messageMatch.FailureReason = fmt.Sprintf("%s: %s, token address: %s", layer.String(), checkErr.Error(), tokenAddress)
+
+	alert.Notify(alert.GatewayMessageAlert(info))
+	log.Error("gateway event doesn't match the messenger message", "layer", layer.String(), "event type", eventType.String(),
+		"tx hash", info.TxHash, "message hash", info.MessageHash, "error", checkErr)
+}
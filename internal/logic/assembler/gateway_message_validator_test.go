@@ -0,0 +1,169 @@
+package assembler
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/accounts/abi"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/il1ethgateway"
+	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/il2erc1155gateway"
+	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/il2erc20gateway"
+	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/il2erc721gateway"
+	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/il2ethgateway"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+)
+
+func packGatewayMessage(t *testing.T, getABI func() (*abi.ABI, error), method string, args ...interface{}) []byte {
+	parsed, err := getABI()
+	assert.NoError(t, err)
+	message, err := parsed.Pack(method, args...)
+	assert.NoError(t, err)
+	return message
+}
+
+func TestGatewayMessageValidator(t *testing.T) {
+	l1Token, l2Token := common.HexToAddress("0x11"), common.HexToAddress("0x21")
+	from, to := common.HexToAddress("0x1"), common.HexToAddress("0x2")
+	tokenIds, amounts := []*big.Int{big.NewInt(1), big.NewInt(2)}, []*big.Int{big.NewInt(10), big.NewInt(20)}
+	data := []byte{0xca, 0xfe}
+
+	tests := []struct {
+		name       string
+		layer      types.LayerType
+		message    []byte
+		expected   gatewayMessageFields
+		wantMethod string
+		wantErr    string
+	}{
+		{
+			name:       "eth",
+			layer:      types.Layer1,
+			message:    packGatewayMessage(t, il2ethgateway.Il2ethgatewayMetaData.GetAbi, "finalizeDepositETH", from, to, amounts[0], data),
+			expected:   gatewayMessageFields{From: from, To: to, Amounts: amounts[:1], Data: data},
+			wantMethod: "finalizeDepositETH",
+		},
+		{
+			name:       "ethDataMismatch",
+			layer:      types.Layer1,
+			message:    packGatewayMessage(t, il2ethgateway.Il2ethgatewayMetaData.GetAbi, "finalizeDepositETH", from, to, amounts[0], []byte{0xbe, 0xef}),
+			expected:   gatewayMessageFields{From: from, To: to, Amounts: amounts[:1], Data: data},
+			wantMethod: "finalizeDepositETH",
+			wantErr:    "data mismatch, event: 0xcafe, message: 0xbeef",
+		},
+		{
+			name:       "ethWithdraw",
+			layer:      types.Layer2,
+			message:    packGatewayMessage(t, il1ethgateway.Il1ethgatewayMetaData.GetAbi, "finalizeWithdrawETH", from, to, amounts[0], []byte{}),
+			expected:   gatewayMessageFields{From: from, To: to, Amounts: amounts[:1]},
+			wantMethod: "finalizeWithdrawETH",
+		},
+		{
+			name:       "erc20",
+			layer:      types.Layer1,
+			message:    packGatewayMessage(t, il2erc20gateway.Il2erc20gatewayMetaData.GetAbi, "finalizeDepositERC20", l1Token, l2Token, from, to, amounts[0], data),
+			expected:   gatewayMessageFields{L1Token: l1Token, L2Token: l2Token, From: from, To: to, Amounts: amounts[:1], Data: data},
+			wantMethod: "finalizeDepositERC20",
+		},
+		{
+			name:       "erc20AmountMismatch",
+			layer:      types.Layer1,
+			message:    packGatewayMessage(t, il2erc20gateway.Il2erc20gatewayMetaData.GetAbi, "finalizeDepositERC20", l1Token, l2Token, from, to, amounts[1], data),
+			expected:   gatewayMessageFields{L1Token: l1Token, L2Token: l2Token, From: from, To: to, Amounts: amounts[:1], Data: data},
+			wantMethod: "finalizeDepositERC20",
+			wantErr:    "amounts mismatch, event: [10], message: [20]",
+		},
+		{
+			name:       "erc20L2TokenMismatch",
+			layer:      types.Layer1,
+			message:    packGatewayMessage(t, il2erc20gateway.Il2erc20gatewayMetaData.GetAbi, "finalizeDepositERC20", l1Token, l1Token, from, to, amounts[0], data),
+			expected:   gatewayMessageFields{L1Token: l1Token, L2Token: l2Token, From: from, To: to, Amounts: amounts[:1], Data: data},
+			wantMethod: "finalizeDepositERC20",
+			wantErr:    "l2 token mismatch",
+		},
+		{
+			name:       "erc721",
+			layer:      types.Layer1,
+			message:    packGatewayMessage(t, il2erc721gateway.Il2erc721gatewayMetaData.GetAbi, "finalizeDepositERC721", l1Token, l2Token, from, to, tokenIds[0]),
+			expected:   gatewayMessageFields{L1Token: l1Token, L2Token: l2Token, From: from, To: to, TokenIds: tokenIds[:1]},
+			wantMethod: "finalizeDepositERC721",
+		},
+		{
+			name:       "erc721RecipientMismatch",
+			layer:      types.Layer1,
+			message:    packGatewayMessage(t, il2erc721gateway.Il2erc721gatewayMetaData.GetAbi, "finalizeDepositERC721", l1Token, l2Token, from, from, tokenIds[0]),
+			expected:   gatewayMessageFields{L1Token: l1Token, L2Token: l2Token, From: from, To: to, TokenIds: tokenIds[:1]},
+			wantMethod: "finalizeDepositERC721",
+			wantErr:    "to mismatch",
+		},
+		{
+			name:       "batchERC721",
+			layer:      types.Layer1,
+			message:    packGatewayMessage(t, il2erc721gateway.Il2erc721gatewayMetaData.GetAbi, "finalizeBatchDepositERC721", l1Token, l2Token, from, to, tokenIds),
+			expected:   gatewayMessageFields{L1Token: l1Token, L2Token: l2Token, From: from, To: to, TokenIds: tokenIds},
+			wantMethod: "finalizeBatchDepositERC721",
+		},
+		{
+			name:       "batchERC721TokenIdsMismatch",
+			layer:      types.Layer1,
+			message:    packGatewayMessage(t, il2erc721gateway.Il2erc721gatewayMetaData.GetAbi, "finalizeBatchDepositERC721", l1Token, l2Token, from, to, tokenIds[:1]),
+			expected:   gatewayMessageFields{L1Token: l1Token, L2Token: l2Token, From: from, To: to, TokenIds: tokenIds},
+			wantMethod: "finalizeBatchDepositERC721",
+			wantErr:    "token ids mismatch, event: [1 2], message: [1]",
+		},
+		{
+			name:       "erc1155",
+			layer:      types.Layer1,
+			message:    packGatewayMessage(t, il2erc1155gateway.Il2erc1155gatewayMetaData.GetAbi, "finalizeDepositERC1155", l1Token, l2Token, from, to, tokenIds[0], amounts[0]),
+			expected:   gatewayMessageFields{L1Token: l1Token, L2Token: l2Token, From: from, To: to, TokenIds: tokenIds[:1], Amounts: amounts[:1]},
+			wantMethod: "finalizeDepositERC1155",
+		},
+		{
+			name:       "batchERC1155",
+			layer:      types.Layer1,
+			message:    packGatewayMessage(t, il2erc1155gateway.Il2erc1155gatewayMetaData.GetAbi, "finalizeBatchDepositERC1155", l1Token, l2Token, from, to, tokenIds, amounts),
+			expected:   gatewayMessageFields{L1Token: l1Token, L2Token: l2Token, From: from, To: to, TokenIds: tokenIds, Amounts: amounts},
+			wantMethod: "finalizeBatchDepositERC1155",
+		},
+		{
+			name:       "batchERC1155AmountsMismatch",
+			layer:      types.Layer1,
+			message:    packGatewayMessage(t, il2erc1155gateway.Il2erc1155gatewayMetaData.GetAbi, "finalizeBatchDepositERC1155", l1Token, l2Token, from, to, tokenIds, []*big.Int{amounts[1], amounts[0]}),
+			expected:   gatewayMessageFields{L1Token: l1Token, L2Token: l2Token, From: from, To: to, TokenIds: tokenIds, Amounts: amounts},
+			wantMethod: "finalizeBatchDepositERC1155",
+			wantErr:    "amounts mismatch, event: [10 20], message: [20 10]",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			method, actual, err := decodeGatewayMessage(test.layer, test.message)
+			assert.NoError(t, err)
+			assert.Equal(t, test.wantMethod, method)
+
+			err = compareGatewayMessage(&test.expected, actual)
+			if test.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestDecodeGatewayMessageNotGatewayCall(t *testing.T) {
+	// the finalize call of the l2 gateway isn't decoded by the abis of the l1 gateways.
+	message := packGatewayMessage(t, il2erc20gateway.Il2erc20gatewayMetaData.GetAbi, "finalizeDepositERC20", common.Address{}, common.Address{}, common.Address{}, common.Address{}, big.NewInt(1), []byte{})
+	_, _, err := decodeGatewayMessage(types.Layer2, message)
+	assert.Error(t, err)
+
+	_, _, err = decodeGatewayMessage(types.Layer1, []byte{0x01})
+	assert.Error(t, err)
+
+	// the truncated finalize call can't be unpacked.
+	method, _, err := decodeGatewayMessage(types.Layer1, message[:36])
+	assert.Error(t, err)
+	assert.Equal(t, "finalizeDepositERC20", method)
+}
@@ -31,6 +31,10 @@ func (c *MessageMatchAssembler) messengerMessageMatchAssembler(messengerEvents [
 				L1TxHash:        messengerEventUnmarshaler.TxHash.Hex(),
 				ETHAmount:       decimal.NewFromBigInt(messengerEventUnmarshaler.Value, 0).String(),
 				ETHAmountStatus: int(types.ETHAmountStatusTypeSet),
+				Sender:          messengerEventUnmarshaler.Sender.Hex(),
+				Target:          messengerEventUnmarshaler.Target.Hex(),
+				MessageNonce:    messengerEventUnmarshaler.MessageNonce.String(),
+				Calldata:        messengerEventUnmarshaler.Message,
 			}
 			messageMatches = append(messageMatches, tmpMessageMatch)
 		case types.L1RelayedMessage:
@@ -50,6 +54,10 @@ func (c *MessageMatchAssembler) messengerMessageMatchAssembler(messengerEvents [
 				ETHAmount:        decimal.NewFromBigInt(messengerEventUnmarshaler.Value, 0).String(),
 				ETHAmountStatus:  int(types.ETHAmountStatusTypeSet),
 				NextMessageNonce: messengerEventUnmarshaler.MessageNonce.Uint64() + 1,
+				Sender:           messengerEventUnmarshaler.Sender.Hex(),
+				Target:           messengerEventUnmarshaler.Target.Hex(),
+				MessageNonce:     messengerEventUnmarshaler.MessageNonce.String(),
+				Calldata:         messengerEventUnmarshaler.Message,
 			}
 			messageMatches = append(messageMatches, tmpMessageMatch)
 		case types.L2RelayedMessage:
@@ -64,3 +72,26 @@ func (c *MessageMatchAssembler) messengerMessageMatchAssembler(messengerEvents [
 	}
 	return messageMatches, nil
 }
+
+func (c *MessageMatchAssembler) failedRelayedMessageAssembler(messengerEvents []events.EventUnmarshaler) ([]orm.FailedRelayedMessageEvent, error) {
+	var failedRelayedMessageEvents []orm.FailedRelayedMessageEvent
+	for _, eventData := range messengerEvents {
+		messengerEventUnmarshaler, ok := eventData.(*events.MessengerEventUnmarshaler)
+		if !ok {
+			return nil, fmt.Errorf("eventData is not of type *events.MessengerEventUnmarshaler")
+		}
+
+		if messengerEventUnmarshaler.Type != types.L1FailedRelayedMessage && messengerEventUnmarshaler.Type != types.L2FailedRelayedMessage {
+			continue
+		}
+
+		failedRelayedMessageEvents = append(failedRelayedMessageEvents, orm.FailedRelayedMessageEvent{
+			MessageHash: messengerEventUnmarshaler.MessageHash.Hex(),
+			Layer:       int(messengerEventUnmarshaler.Layer),
+			BlockNumber: messengerEventUnmarshaler.Number,
+			TxHash:      messengerEventUnmarshaler.TxHash.Hex(),
+			LogIndex:    messengerEventUnmarshaler.Index,
+		})
+	}
+	return failedRelayedMessageEvents, nil
+}
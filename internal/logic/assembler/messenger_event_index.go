@@ -0,0 +1,189 @@
+package assembler
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"github.com/scroll-tech/chain-monitor/internal/logic/events"
+	"github.com/scroll-tech/chain-monitor/internal/orm"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+)
+
+var gatewayMessagePairingFailureTotal = promauto.With(prometheus.DefaultRegisterer).NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_message_pairing_failure_total",
+	Help: "The total number of the gateway events which are paired with no message, a message claimed by another gateway event or not sent to the counterpart gateway.",
+}, []string{"layer", "type"})
+
+const (
+	pairingFailureAmbiguous       = "ambiguous"
+	pairingFailureTargetNotMatch  = "target_not_match"
+	pairingFailureSenderNotMatch  = "sender_not_match"
+	pairingFailureMessageNotExist = "message_not_exist"
+)
+
+// messengerLog a messenger event of the tx, which is paired with one gateway event at most.
+type messengerLog struct {
+	event *events.MessengerEventUnmarshaler
+	// the log index of the gateway event which the message is paired with, nil if the message isn't paired yet.
+	pairedLogIndex *uint
+}
+
+// pairingFailure the gateway event which is paired with the message but fails the pairing checks.
+type pairingFailure struct {
+	eventType types.EventType
+	reason    string
+}
+
+// MessengerEventIndex indexes the sent and relayed messenger events of a block range by tx, sorted by log index,
+// so the gateway events are paired with the messenger events of the same tx without scanning all the events.
+type MessengerEventIndex struct {
+	sentMessages    map[common.Hash][]*messengerLog
+	relayedMessages map[common.Hash][]*messengerLog
+
+	// the failed pairings by message hash, which are marked failed after all the gateway events are paired.
+	pairingFailures map[common.Hash]pairingFailure
+}
+
+// NewMessengerEventIndex creates the messenger event index of the messenger events of a block range.
+func NewMessengerEventIndex(messengerEvents []events.EventUnmarshaler) (*MessengerEventIndex, error) {
+	index := &MessengerEventIndex{
+		sentMessages:    make(map[common.Hash][]*messengerLog),
+		relayedMessages: make(map[common.Hash][]*messengerLog),
+		pairingFailures: make(map[common.Hash]pairingFailure),
+	}
+	for _, eventData := range messengerEvents {
+		messengerEventUnmarshaler, ok := eventData.(*events.MessengerEventUnmarshaler)
+		if !ok {
+			return nil, fmt.Errorf("eventData is not of type *events.MessengerEventUnmarshaler")
+		}
+
+		logs := index.sentMessages
+		switch messengerEventUnmarshaler.Type {
+		case types.L1SentMessage, types.L2SentMessage:
+		case types.L1RelayedMessage, types.L2RelayedMessage:
+			logs = index.relayedMessages
+		default:
+			continue
+		}
+		logs[messengerEventUnmarshaler.TxHash] = append(logs[messengerEventUnmarshaler.TxHash], &messengerLog{event: messengerEventUnmarshaler})
+	}
+
+	for _, logs := range []map[common.Hash][]*messengerLog{index.sentMessages, index.relayedMessages} {
+		for _, txLogs := range logs {
+			sort.Slice(txLogs, func(i, j int) bool { return txLogs[i].event.Index < txLogs[j].event.Index })
+		}
+	}
+	return index, nil
+}
+
+// pairSentMessage pairs the gateway deposit or withdraw event with its SentMessage event. The gateway sends the message
+// right before it emits the event, so the message is the nearest SentMessage event before the gateway event in the tx,
+// and it's sent by the gateway to the counterpart gateway if the counterpart gateway is configured. If the tx has no
+// SentMessage event before the gateway event, or the message is claimed by another gateway event, the gateway event is
+// flagged and paired with a placeholder message instead, reported by the false return value.
+func (c *MessageMatchAssembler) pairSentMessage(index *MessengerEventIndex, layer types.LayerType, eventType types.EventType, gatewayAddress common.Address, txHash common.Hash, logIndex uint) (*events.MessengerEventUnmarshaler, bool) {
+	txLogs := index.sentMessages[txHash]
+	i := sort.Search(len(txLogs), func(i int) bool { return txLogs[i].event.Index >= logIndex })
+	if i == 0 {
+		return index.unpaired(layer, eventType, txHash, logIndex, pairingFailureMessageNotExist,
+			fmt.Sprintf("no sent message before the gateway event at log index %d", logIndex)), false
+	}
+
+	messengerEvent, ok := index.pair(txLogs[i-1], layer, eventType, logIndex)
+	if !ok {
+		return messengerEvent, false
+	}
+
+	counterpartGateway, exists := c.counterpartGateways[layer][gatewayAddress]
+	if !exists {
+		log.Debug("the gateway isn't configured with a counterpart gateway", "layer", layer.String(), "gateway", gatewayAddress.Hex())
+		return messengerEvent, true
+	}
+	switch {
+	case messengerEvent.Sender != gatewayAddress:
+		index.fail(messengerEvent, layer, eventType, pairingFailureSenderNotMatch,
+			fmt.Sprintf("the message is sent by %s instead of the gateway %s emitting the event at log index %d", messengerEvent.Sender.Hex(), gatewayAddress.Hex(), logIndex))
+	case messengerEvent.Target != counterpartGateway:
+		index.fail(messengerEvent, layer, eventType, pairingFailureTargetNotMatch,
+			fmt.Sprintf("the message targets %s instead of the counterpart gateway %s", messengerEvent.Target.Hex(), counterpartGateway.Hex()))
+	}
+	return messengerEvent, true
+}
+
+// pairRelayedMessage pairs the gateway finalize event with its RelayedMessage event. The messenger emits the event after
+// the call of the gateway returns, so the message is the nearest RelayedMessage event after the gateway event in the tx,
+// the messages relayed by the callbacks of the gateway are emitted before the gateway event. If the tx has no
+// RelayedMessage event after the gateway event, or the message is claimed by another gateway event, the gateway event
+// is flagged and paired with a placeholder message instead.
+func (c *MessageMatchAssembler) pairRelayedMessage(index *MessengerEventIndex, layer types.LayerType, eventType types.EventType, txHash common.Hash, logIndex uint) *events.MessengerEventUnmarshaler {
+	txLogs := index.relayedMessages[txHash]
+	i := sort.Search(len(txLogs), func(i int) bool { return txLogs[i].event.Index > logIndex })
+	if i == len(txLogs) {
+		return index.unpaired(layer, eventType, txHash, logIndex, pairingFailureMessageNotExist,
+			fmt.Sprintf("no relayed message after the gateway event at log index %d", logIndex))
+	}
+
+	messengerEvent, _ := index.pair(txLogs[i], layer, eventType, logIndex)
+	return messengerEvent
+}
+
+// pair consumes the messenger log for the gateway event. The message claimed by two gateway events is ambiguous, both
+// claimants are flagged, and the later one is paired with a placeholder message.
+func (index *MessengerEventIndex) pair(messengerLog *messengerLog, layer types.LayerType, eventType types.EventType, logIndex uint) (*events.MessengerEventUnmarshaler, bool) {
+	if messengerLog.pairedLogIndex != nil {
+		first, second := *messengerLog.pairedLogIndex, logIndex
+		if first > second {
+			first, second = second, first
+		}
+		reason := fmt.Sprintf("the message %s is claimed by the gateway events at log index %d and %d", messengerLog.event.MessageHash.Hex(), first, second)
+		index.fail(messengerLog.event, layer, eventType, pairingFailureAmbiguous, reason)
+		return index.unpaired(layer, eventType, messengerLog.event.TxHash, logIndex, pairingFailureAmbiguous, reason), false
+	}
+	messengerLog.pairedLogIndex = &logIndex
+	return messengerLog.event, true
+}
+
+// unpaired flags the gateway event which isn't paired with a message, and returns its placeholder message keyed by
+// the tx hash and the log index of the gateway event, so the gateway event is stored and marked failed.
+func (index *MessengerEventIndex) unpaired(layer types.LayerType, eventType types.EventType, txHash common.Hash, logIndex uint, failureType, reason string) *events.MessengerEventUnmarshaler {
+	messengerEvent := &events.MessengerEventUnmarshaler{
+		Layer:       layer,
+		TxHash:      txHash,
+		MessageHash: unpairedMessageHash(txHash, logIndex),
+	}
+	index.fail(messengerEvent, layer, eventType, failureType, reason)
+	return messengerEvent
+}
+
+// unpairedMessageHash the key of the placeholder message, which is the hash of the tx hash and the log index of the gateway event.
+func unpairedMessageHash(txHash common.Hash, logIndex uint) common.Hash {
+	return crypto.Keccak256Hash(txHash.Bytes(), binary.BigEndian.AppendUint64(nil, uint64(logIndex)))
+}
+
+func (index *MessengerEventIndex) fail(messengerEvent *events.MessengerEventUnmarshaler, layer types.LayerType, eventType types.EventType, failureType, reason string) {
+	gatewayMessagePairingFailureTotal.WithLabelValues(layer.String(), failureType).Inc()
+	log.Error("gateway event pairing failed", "layer", layer.String(), "event type", eventType.String(), "tx hash", messengerEvent.TxHash.Hex(),
+		"message hash", messengerEvent.MessageHash.Hex(), "reason", reason)
+	if _, exists := index.pairingFailures[messengerEvent.MessageHash]; exists {
+		return
+	}
+	index.pairingFailures[messengerEvent.MessageHash] = pairingFailure{eventType: eventType, reason: reason}
+}
+
+// markPairingFailures marks the gateway message matches of the failed pairings as failed with the reasons, and alerts them.
+func (index *MessengerEventIndex) markPairingFailures(layer types.LayerType, gatewayMessageMatches []orm.GatewayMessageMatch) {
+	for i := range gatewayMessageMatches {
+		failure, exists := index.pairingFailures[common.HexToHash(gatewayMessageMatches[i].MessageHash)]
+		if !exists {
+			continue
+		}
+		markGatewayMessageFailed(&gatewayMessageMatches[i], layer, failure.eventType, fmt.Errorf("gateway event pairing failed: %s", failure.reason))
+	}
+}
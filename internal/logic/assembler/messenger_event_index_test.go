@@ -0,0 +1,122 @@
+package assembler
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scroll-tech/chain-monitor/internal/logic/alert"
+	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/il2erc20gateway"
+	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/il2ethgateway"
+	"github.com/scroll-tech/chain-monitor/internal/logic/events"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+)
+
+func TestMessengerEventIndex(t *testing.T) {
+	var alerts []alert.Alert
+	previous := alert.Use(alert.NewManagerWithSinks(alert.NewFuncSink("stdout", func(a alert.Alert) { alerts = append(alerts, a) })))
+	defer alert.Use(previous)
+
+	l1ETHGateway, l2ETHGateway := common.HexToAddress("0x1001"), common.HexToAddress("0x2001")
+	l1ERC20Gateway, l2ERC20Gateway := common.HexToAddress("0x1002"), common.HexToAddress("0x2002")
+	l1Token, l2Token := common.HexToAddress("0x11"), common.HexToAddress("0x21")
+	from, to := common.HexToAddress("0x1"), common.HexToAddress("0x2")
+	c := &MessageMatchAssembler{counterpartGateways: map[types.LayerType]map[common.Address]common.Address{
+		types.Layer1: {l1ETHGateway: l2ETHGateway, l1ERC20Gateway: l2ERC20Gateway},
+	}}
+
+	sentMessage := func(txHash common.Hash, logIndex uint, messageHash common.Hash, sender, target common.Address, message []byte) *events.MessengerEventUnmarshaler {
+		return &events.MessengerEventUnmarshaler{Layer: types.Layer1, Type: types.L1SentMessage, TxHash: txHash, Index: logIndex, Sender: sender, Target: target, MessageHash: messageHash, Message: message}
+	}
+	depositETH := func(txHash common.Hash, logIndex uint, amount int64) *events.ETHGatewayEventUnmarshaler {
+		return &events.ETHGatewayEventUnmarshaler{Type: types.L1DepositETH, TxHash: txHash, Index: logIndex, Amount: big.NewInt(amount), GatewayAddress: l1ETHGateway, From: from, To: to}
+	}
+	depositETHMessage := func(amount int64) []byte {
+		return packGatewayMessage(t, il2ethgateway.Il2ethgatewayMetaData.GetAbi, "finalizeDepositETH", from, to, big.NewInt(amount), []byte{})
+	}
+
+	multiDepositTx, routerBatchTx := common.HexToHash("0xa1"), common.HexToHash("0xa2")
+	forgedTx, ambiguousTx := common.HexToHash("0xa3"), common.HexToHash("0xa4")
+	messengerEvents := []events.EventUnmarshaler{
+		// the messenger events are indexed by log index, regardless of the order they are fetched.
+		sentMessage(multiDepositTx, 2, common.HexToHash("0x2"), l1ETHGateway, l2ETHGateway, depositETHMessage(20)),
+		sentMessage(multiDepositTx, 0, common.HexToHash("0x1"), l1ETHGateway, l2ETHGateway, depositETHMessage(10)),
+		// the router deposits through the erc20 gateway and the eth gateway in one tx.
+		sentMessage(routerBatchTx, 0, common.HexToHash("0x3"), l1ERC20Gateway, l2ERC20Gateway,
+			packGatewayMessage(t, il2erc20gateway.Il2erc20gatewayMetaData.GetAbi, "finalizeDepositERC20", l1Token, l2Token, from, to, big.NewInt(30), []byte{})),
+		sentMessage(routerBatchTx, 3, common.HexToHash("0x4"), l1ETHGateway, l2ETHGateway, depositETHMessage(40)),
+		// the deposit is emitted by the eth gateway, but the message is sent by another contract.
+		sentMessage(forgedTx, 5, common.HexToHash("0x5"), from, l2ETHGateway, depositETHMessage(50)),
+		sentMessage(ambiguousTx, 0, common.HexToHash("0x6"), l1ETHGateway, l2ETHGateway, depositETHMessage(60)),
+	}
+	index, err := NewMessengerEventIndex(messengerEvents)
+	assert.NoError(t, err)
+
+	ethEvents := []events.EventUnmarshaler{
+		depositETH(multiDepositTx, 1, 10),
+		depositETH(multiDepositTx, 3, 20),
+		depositETH(routerBatchTx, 4, 40),
+		// no message is sent before the forged deposit, and no message is relayed after the forged finalize event.
+		depositETH(forgedTx, 0, 1),
+		&events.ETHGatewayEventUnmarshaler{Type: types.L1FinalizeWithdrawETH, TxHash: forgedTx, Index: 1, Amount: big.NewInt(1)},
+		depositETH(forgedTx, 6, 50),
+		// both deposits claim the message.
+		depositETH(ambiguousTx, 1, 60),
+		depositETH(ambiguousTx, 2, 60),
+	}
+	erc20Events := []events.EventUnmarshaler{
+		&events.ERC20GatewayEventUnmarshaler{Type: types.L1DepositERC20, TxHash: routerBatchTx, Index: 1, Amount: big.NewInt(30),
+			TokenAddress: l1Token, CounterpartTokenAddress: l2Token, GatewayAddress: l1ERC20Gateway, From: from, To: to},
+	}
+
+	messageMatches, err := c.ethEventMessageMatchAssembler(ethEvents, index)
+	assert.NoError(t, err)
+	// the tokens deposited are transferred to the erc20 gateway.
+	transferEvents := []events.EventUnmarshaler{
+		&events.ERC20GatewayEventUnmarshaler{Layer: types.Layer1, TxHash: routerBatchTx, TokenAddress: l1Token, Amount: big.NewInt(30)},
+	}
+	erc20MessageMatches, err := c.erc20EventMessageMatchAssembler(erc20Events, index, transferEvents)
+	assert.NoError(t, err)
+	messageMatches = append(messageMatches, erc20MessageMatches...)
+	c.MarkGatewayMessagePairingFailures(types.Layer1, index, messageMatches)
+
+	tests := []struct {
+		name        string
+		messageHash common.Hash
+		wantReason  string
+	}{
+		{"multiDepositFirst", common.HexToHash("0x1"), ""},
+		{"multiDepositSecond", common.HexToHash("0x2"), ""},
+		{"routerBatchETH", common.HexToHash("0x4"), ""},
+		{"routerBatchERC20", common.HexToHash("0x3"), ""},
+		{"forgedWithoutSentMessage", unpairedMessageHash(forgedTx, 0), "no sent message before the gateway event at log index 0"},
+		{"forgedWithoutRelayedMessage", unpairedMessageHash(forgedTx, 1), "no relayed message after the gateway event at log index 1"},
+		{"forgedSender", common.HexToHash("0x5"), "the message is sent by " + from.Hex()},
+		{"ambiguousFirst", common.HexToHash("0x6"), "is claimed by the gateway events at log index 1 and 2"},
+		{"ambiguousSecond", unpairedMessageHash(ambiguousTx, 2), "is claimed by the gateway events at log index 1 and 2"},
+	}
+
+	assert.Len(t, messageMatches, len(tests))
+	assert.Len(t, alerts, 5)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var found bool
+			for _, messageMatch := range messageMatches {
+				if messageMatch.MessageHash != test.messageHash.Hex() {
+					continue
+				}
+				found = true
+				if test.wantReason == "" {
+					assert.Equal(t, int(types.BlockStatusTypeUnchecked), messageMatch.L1BlockStatus)
+					assert.Empty(t, messageMatch.FailureReason)
+				} else {
+					assert.Equal(t, int(types.BlockStatusTypeFailed), messageMatch.L1BlockStatus)
+					assert.Contains(t, messageMatch.FailureReason, test.wantReason)
+				}
+			}
+			assert.True(t, found)
+		})
+	}
+}
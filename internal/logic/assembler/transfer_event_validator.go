@@ -4,10 +4,14 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/log"
 
+	"github.com/scroll-tech/chain-monitor/internal/logic/alert"
 	"github.com/scroll-tech/chain-monitor/internal/logic/events"
-	"github.com/scroll-tech/chain-monitor/internal/logic/slack"
+	"github.com/scroll-tech/chain-monitor/internal/orm"
 	"github.com/scroll-tech/chain-monitor/internal/types"
 )
 
@@ -17,6 +21,8 @@ const (
 
 	gatewayEventDontHaveTransferEvent        = "the transfer event associated with the gateway event does not exist"
 	gatewayEventBalanceMismatchTransferEvent = "the gateway event's balance don't match the balance of transfer event"
+
+	bridgedTokenTransferDontHaveGatewayEvent = "the bridged token is minted or burned without a gateway event"
 )
 
 type erc20MatcherKey struct {
@@ -45,15 +51,24 @@ type matcherValue struct {
 	messageHash common.Hash
 }
 
+var bridgedTokenTransferFlaggedTotal = promauto.With(prometheus.DefaultRegisterer).NewCounterVec(prometheus.CounterOpts{
+	Name: "l2_bridged_token_transfer_without_gateway_event_total",
+	Help: "The total number of the l2 bridged token mints and burns without a gateway event.",
+}, []string{"type"})
+
 // TransferEventMatcher checks the existence of an event and consistency of the transferred amount.
-type TransferEventMatcher struct{}
+type TransferEventMatcher struct {
+	bridgedTokenRegistry *BridgedTokenRegistry
+}
 
-// NewTransferEventMatcher creates a new instance of TransferEventMatcher.
-func NewTransferEventMatcher() *TransferEventMatcher {
-	return &TransferEventMatcher{}
+// NewTransferEventMatcher creates a new instance of TransferEventMatcher, the l2 transfers without a gateway event
+// are flagged if their tokens are in the bridged token registry.
+func NewTransferEventMatcher(bridgedTokenRegistry *BridgedTokenRegistry) *TransferEventMatcher {
+	return &TransferEventMatcher{bridgedTokenRegistry: bridgedTokenRegistry}
 }
 
-func (t *TransferEventMatcher) erc20Matcher(transferEvents, gatewayEvents []events.ERC20GatewayEventUnmarshaler) error {
+func (t *TransferEventMatcher) erc20Matcher(transferEvents, gatewayEvents []events.ERC20GatewayEventUnmarshaler) ([]alert.GatewayTransferInfo, error) {
+	var mismatches []alert.GatewayTransferInfo
 	transferBalances := make(map[erc20MatcherKey]matcherValue)
 	gatewayBalances := make(map[erc20MatcherKey]matcherValue)
 
@@ -84,9 +99,9 @@ func (t *TransferEventMatcher) erc20Matcher(transferEvents, gatewayEvents []even
 			tokenAddress: event.TokenAddress,
 			txHash:       event.TxHash,
 		}
-		// filter airdrop Transfers.
+		// filter airdrop Transfers, the bridged tokens burned without a gateway event are kept to be flagged.
 		_, exists := gatewayBalances[key]
-		if !exists && event.Amount.Sign() >= 0 {
+		if !exists && event.Amount.Sign() >= 0 && !t.isBridgedTokenTransfer(event.Layer, event.TokenAddress) {
 			continue
 		}
 		if _, exists := transferBalances[key]; !exists {
@@ -107,7 +122,7 @@ func (t *TransferEventMatcher) erc20Matcher(transferEvents, gatewayEvents []even
 		// If the corresponding Transfer does not exist,
 		// or if the tokens of Transfer events < the difference of tokens in gateway events (more tokens out).
 		if !exists || transferMatcherValue.balance.Cmp(gatewayMatcherValue.balance) < 0 {
-			info := slack.GatewayTransferInfo{
+			info := alert.GatewayTransferInfo{
 				TokenAddress:    transferMatcherKey.tokenAddress,
 				TokenType:       transferMatcherValue.tokenType,
 				Layer:           transferMatcherValue.layer,
@@ -118,8 +133,11 @@ func (t *TransferEventMatcher) erc20Matcher(transferEvents, gatewayEvents []even
 				TransferBalance: transferMatcherValue.balance,
 			}
 			if !exists {
-				// Ignore additional Transfer events in Layer2.
+				// Ignore additional Transfer events in Layer2, except the bridged tokens which are minted and burned by the gateways only.
 				if info.Layer == types.Layer2 {
+					if t.isBridgedTokenTransfer(info.Layer, info.TokenAddress) {
+						t.flagBridgedTokenTransfer(info)
+					}
 					continue
 				}
 				info.Error = transferEventDontHaveGatewayEvent
@@ -127,9 +145,7 @@ func (t *TransferEventMatcher) erc20Matcher(transferEvents, gatewayEvents []even
 				info.Error = transferEventBalanceMismatchGatewayEvent
 				info.GatewayBalance = gatewayMatcherValue.balance
 			}
-			slack.Notify(slack.MrkDwnGatewayTransferMessage(info))
-			return fmt.Errorf("balance mismatch for token %s: transfer balance = %s, gateway balance = %s, info = %v",
-				info.TokenAddress.Hex(), info.TransferBalance.String(), info.GatewayBalance.String(), info)
+			mismatches = append(mismatches, t.reportTransferMismatch(info))
 		}
 	}
 
@@ -138,7 +154,7 @@ func (t *TransferEventMatcher) erc20Matcher(transferEvents, gatewayEvents []even
 		// If the corresponding Transfer does not exist,
 		// or if the tokens of Transfer events < the difference of tokens in gateway events (more tokens out).
 		if !exists || gatewayMatcherValue.balance.Cmp(transferMatcherValue.balance) > 0 {
-			info := slack.GatewayTransferInfo{
+			info := alert.GatewayTransferInfo{
 				TokenAddress:   gatewayMatcherKey.tokenAddress,
 				TokenType:      gatewayMatcherValue.tokenType,
 				Layer:          gatewayMatcherValue.layer,
@@ -154,21 +170,71 @@ func (t *TransferEventMatcher) erc20Matcher(transferEvents, gatewayEvents []even
 				info.Error = gatewayEventBalanceMismatchTransferEvent
 				info.TransferBalance = transferMatcherValue.balance
 			}
-			slack.Notify(slack.MrkDwnGatewayTransferMessage(info))
-			return fmt.Errorf("balance mismatch for token %s: gateway balance = %s, transfer balance = %s, info = %v",
-				info.TokenAddress.Hex(), info.GatewayBalance.String(), info.TransferBalance.String(), info)
+			mismatches = append(mismatches, t.reportTransferMismatch(info))
+		}
+	}
+	return mismatches, nil
+}
+
+// reportTransferMismatch alerts the mismatch of the gateway events and the transfer events, the checks of the other
+// transfers go on so all the mismatches of the blocks are reported.
+func (t *TransferEventMatcher) reportTransferMismatch(info alert.GatewayTransferInfo) alert.GatewayTransferInfo {
+	alert.Notify(alert.GatewayTransferAlert(info))
+	log.Error("gateway event and transfer event mismatch", "token type", info.TokenType, "token address", info.TokenAddress.Hex(), "layer", info.Layer,
+		"block number", info.BlockNumber, "tx hash", info.TxHash.Hex(), "transfer balance", info.TransferBalance, "gateway balance", info.GatewayBalance, "error", info.Error)
+	return info
+}
+
+// markTransferMismatches marks the gateway message matches of the mismatched transfers as failed with the reasons. The mismatched
+// transfers without a gateway event have no message match, they are alerted only.
+func markTransferMismatches(messageMatches []orm.GatewayMessageMatch, mismatches []alert.GatewayTransferInfo) {
+	for _, mismatch := range mismatches {
+		txHash, tokenAddress := mismatch.TxHash.Hex(), mismatch.TokenAddress.Hex()
+		reason := fmt.Sprintf("%s: %s, token address: %s", mismatch.Layer.String(), mismatch.Error, tokenAddress)
+		for i := range messageMatches {
+			messageMatch := &messageMatches[i]
+			switch mismatch.Layer {
+			case types.Layer1:
+				if messageMatch.L1TxHash == txHash && messageMatch.L1TokenAddress == tokenAddress {
+					messageMatch.L1BlockStatus = int(types.BlockStatusTypeFailed)
+					messageMatch.FailureReason = reason
+				}
+			case types.Layer2:
+				if messageMatch.L2TxHash == txHash && messageMatch.L2TokenAddress == tokenAddress {
+					messageMatch.L2BlockStatus = int(types.BlockStatusTypeFailed)
+					messageMatch.FailureReason = reason
+				}
+			}
 		}
 	}
-	return nil
 }
 
-func (t *TransferEventMatcher) erc721Matcher(transferEvents, gatewayEvents []events.ERC721GatewayEventUnmarshaler) error {
+func (t *TransferEventMatcher) isBridgedTokenTransfer(layer types.LayerType, tokenAddress common.Address) bool {
+	return layer == types.Layer2 && t.bridgedTokenRegistry.IsBridged(tokenAddress)
+}
+
+// flagBridgedTokenTransfer alerts the bridged token minted or burned without a gateway event, the checks of the other
+// transfers go on since it's not a mismatch of the gateway events.
+func (t *TransferEventMatcher) flagBridgedTokenTransfer(info alert.GatewayTransferInfo) {
+	transferType := "burn"
+	if info.TransferBalance.Sign() < 0 {
+		transferType = "mint"
+	}
+	info.Error = bridgedTokenTransferDontHaveGatewayEvent
+	bridgedTokenTransferFlaggedTotal.WithLabelValues(transferType).Inc()
+	alert.Notify(alert.BridgedTokenTransferAlert(info))
+	log.Error("l2 bridged token minted or burned without gateway event", "type", transferType, "token address", info.TokenAddress.Hex(),
+		"block number", info.BlockNumber, "tx hash", info.TxHash.Hex(), "transfer balance", info.TransferBalance)
+}
+
+func (t *TransferEventMatcher) erc721Matcher(transferEvents, gatewayEvents []events.ERC721GatewayEventUnmarshaler) ([]alert.GatewayTransferInfo, error) {
+	var mismatches []alert.GatewayTransferInfo
 	transferTokenIds := make(map[erc721MatchKey]matcherValue)
 	gatewayTokenIds := make(map[erc721MatchKey]matcherValue)
 
 	for _, event := range gatewayEvents {
 		if len(event.TokenIds) != len(event.Amounts) {
-			return fmt.Errorf("erc1155 gateway event tokenIds and amounts not match, %v", event)
+			return nil, fmt.Errorf("erc1155 gateway event tokenIds and amounts not match, %v", event)
 		}
 
 		for idx, tokenID := range event.TokenIds {
@@ -201,7 +267,7 @@ func (t *TransferEventMatcher) erc721Matcher(transferEvents, gatewayEvents []eve
 
 	for _, event := range transferEvents {
 		if len(event.TokenIds) != len(event.Amounts) {
-			return fmt.Errorf("erc721 transfer event tokenIds and amounts not match, %v", event)
+			return nil, fmt.Errorf("erc721 transfer event tokenIds and amounts not match, %v", event)
 		}
 
 		for idx, tokenID := range event.TokenIds {
@@ -234,7 +300,7 @@ func (t *TransferEventMatcher) erc721Matcher(transferEvents, gatewayEvents []eve
 		// If the corresponding Transfer does not exist,
 		// or if the tokens of Transfer events < the difference of tokens in gateway events (more tokens out).
 		if !exists || transferMatcherValue.balance.Cmp(gatewayMatcherValue.balance) < 0 {
-			info := slack.GatewayTransferInfo{
+			info := alert.GatewayTransferInfo{
 				TokenAddress:    transferMatcherKey.tokenAddress,
 				TokenType:       transferMatcherValue.tokenType,
 				Layer:           transferMatcherValue.layer,
@@ -254,9 +320,7 @@ func (t *TransferEventMatcher) erc721Matcher(transferEvents, gatewayEvents []eve
 				info.Error = transferEventBalanceMismatchGatewayEvent
 				info.GatewayBalance = gatewayMatcherValue.balance
 			}
-			slack.Notify(slack.MrkDwnGatewayTransferMessage(info))
-			return fmt.Errorf("erc721 mismatch for tokenAddress %s: transfer amount = %s, gateway amount = %s",
-				info.TokenAddress.Hex(), info.TransferBalance.String(), info.GatewayBalance.String())
+			mismatches = append(mismatches, t.reportTransferMismatch(info))
 		}
 	}
 
@@ -265,7 +329,7 @@ func (t *TransferEventMatcher) erc721Matcher(transferEvents, gatewayEvents []eve
 		// If the corresponding Transfer does not exist,
 		// or if the tokens of Transfer events < the difference of tokens in gateway events (more tokens out).
 		if !exists || gatewayMatcherValue.balance.Cmp(transferMatcherValue.balance) > 0 {
-			info := slack.GatewayTransferInfo{
+			info := alert.GatewayTransferInfo{
 				TokenAddress:   gatewayMatcherKey.tokenAddress,
 				TokenType:      gatewayMatcherValue.tokenType,
 				Layer:          gatewayMatcherValue.layer,
@@ -281,22 +345,21 @@ func (t *TransferEventMatcher) erc721Matcher(transferEvents, gatewayEvents []eve
 				info.Error = gatewayEventBalanceMismatchTransferEvent
 				info.TransferBalance = transferMatcherValue.balance
 			}
-			slack.Notify(slack.MrkDwnGatewayTransferMessage(info))
-			return fmt.Errorf("erc721 mismatch for tokenAddress %s: gateway amount = %s, transfer amount = %s",
-				info.TokenAddress.Hex(), info.GatewayBalance.String(), info.TransferBalance.String())
+			mismatches = append(mismatches, t.reportTransferMismatch(info))
 		}
 	}
 
-	return nil
+	return mismatches, nil
 }
 
-func (t *TransferEventMatcher) erc1155Matcher(transferEvents, gatewayEvents []events.ERC1155GatewayEventUnmarshaler) error {
+func (t *TransferEventMatcher) erc1155Matcher(transferEvents, gatewayEvents []events.ERC1155GatewayEventUnmarshaler) ([]alert.GatewayTransferInfo, error) {
+	var mismatches []alert.GatewayTransferInfo
 	transferTokenIds := make(map[erc1155MatchKey]matcherValue)
 	gatewayTokenIds := make(map[erc1155MatchKey]matcherValue)
 
 	for _, event := range gatewayEvents {
 		if len(event.TokenIds) != len(event.Amounts) {
-			return fmt.Errorf("erc1155 gateway event tokenIds and amounts not match, %v", event)
+			return nil, fmt.Errorf("erc1155 gateway event tokenIds and amounts not match, %v", event)
 		}
 
 		for idx, tokenID := range event.TokenIds {
@@ -327,7 +390,7 @@ func (t *TransferEventMatcher) erc1155Matcher(transferEvents, gatewayEvents []ev
 
 	for _, event := range transferEvents {
 		if len(event.TokenIds) != len(event.Amounts) {
-			return fmt.Errorf("erc1155 transfer event tokenIds and amounts not match, %v", event)
+			return nil, fmt.Errorf("erc1155 transfer event tokenIds and amounts not match, %v", event)
 		}
 
 		for idx, tokenID := range event.TokenIds {
@@ -360,7 +423,7 @@ func (t *TransferEventMatcher) erc1155Matcher(transferEvents, gatewayEvents []ev
 		// If the corresponding Transfer does not exist,
 		// or if the tokens of Transfer events < the difference of tokens in gateway events (more tokens out).
 		if !exists || transferMatcherValue.balance.Cmp(gatewayMatcherValue.balance) < 0 {
-			info := slack.GatewayTransferInfo{
+			info := alert.GatewayTransferInfo{
 				TokenAddress:    transferMatcherKey.tokenAddress,
 				TokenType:       transferMatcherValue.tokenType,
 				Layer:           transferMatcherValue.layer,
@@ -380,9 +443,7 @@ func (t *TransferEventMatcher) erc1155Matcher(transferEvents, gatewayEvents []ev
 				info.Error = transferEventBalanceMismatchGatewayEvent
 				info.GatewayBalance = gatewayMatcherValue.balance
 			}
-			slack.Notify(slack.MrkDwnGatewayTransferMessage(info))
-			return fmt.Errorf("erc1155 mismatch for tokenAddress %s: transfer amount = %s, gateway amount = %s",
-				info.TokenAddress.Hex(), info.TransferBalance.String(), info.GatewayBalance.String())
+			mismatches = append(mismatches, t.reportTransferMismatch(info))
 		}
 	}
 
@@ -391,7 +452,7 @@ func (t *TransferEventMatcher) erc1155Matcher(transferEvents, gatewayEvents []ev
 		// If the corresponding Transfer does not exist,
 		// or if the tokens of Transfer events < the difference of tokens in gateway events (more tokens out).
 		if !exists || gatewayMatcherValue.balance.Cmp(transferMatcherValue.balance) > 0 {
-			info := slack.GatewayTransferInfo{
+			info := alert.GatewayTransferInfo{
 				TokenAddress:   gatewayMatcherKey.tokenAddress,
 				TokenType:      gatewayMatcherValue.tokenType,
 				Layer:          gatewayMatcherValue.layer,
@@ -407,11 +468,9 @@ func (t *TransferEventMatcher) erc1155Matcher(transferEvents, gatewayEvents []ev
 				info.Error = gatewayEventBalanceMismatchTransferEvent
 				info.TransferBalance = transferMatcherValue.balance
 			}
-			slack.Notify(slack.MrkDwnGatewayTransferMessage(info))
-			return fmt.Errorf("erc1155 mismatch for token %s: gateway amount = %s, transfer amount = %s",
-				info.TokenAddress.Hex(), info.GatewayBalance.String(), info.TransferBalance.String())
+			mismatches = append(mismatches, t.reportTransferMismatch(info))
 		}
 	}
 
-	return nil
+	return mismatches, nil
 }
@@ -0,0 +1,83 @@
+package assembler
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scroll-tech/chain-monitor/internal/config"
+	"github.com/scroll-tech/chain-monitor/internal/logic/alert"
+	"github.com/scroll-tech/chain-monitor/internal/logic/events"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+)
+
+func TestFlagBridgedTokenTransfer(t *testing.T) {
+	var alerts []alert.Alert
+	previous := alert.Use(alert.NewManagerWithSinks(alert.NewFuncSink("stdout", func(a alert.Alert) { alerts = append(alerts, a) })))
+	defer alert.Use(previous)
+
+	configuredToken, learnedToken, unknownToken := common.HexToAddress("0x21"), common.HexToAddress("0x22"), common.HexToAddress("0x23")
+	registry := NewBridgedTokenRegistry(&config.Config{BridgedTokenConfig: &config.BridgedTokenConfig{Enabled: true, L2Tokens: []common.Address{configuredToken}}}, nil)
+	registry.Learn(learnedToken)
+
+	txHash := common.HexToHash("0xa1")
+	// the minted tokens are transferred from the zero address, so the l2 mints are negative and the burns are positive.
+	mint := func(tokenAddress common.Address, amount int64) events.ERC20GatewayEventUnmarshaler {
+		return events.ERC20GatewayEventUnmarshaler{Layer: types.Layer2, Number: 10, TxHash: txHash, TokenAddress: tokenAddress, Amount: big.NewInt(-amount)}
+	}
+	burn := func(tokenAddress common.Address, amount int64) events.ERC20GatewayEventUnmarshaler {
+		return events.ERC20GatewayEventUnmarshaler{Layer: types.Layer2, Number: 10, TxHash: txHash, TokenAddress: tokenAddress, Amount: big.NewInt(amount)}
+	}
+	gatewayEvent := func(eventType types.EventType, tokenAddress common.Address, amount int64) events.ERC20GatewayEventUnmarshaler {
+		return events.ERC20GatewayEventUnmarshaler{Layer: types.Layer2, Type: eventType, Number: 10, TxHash: txHash, TokenAddress: tokenAddress, Amount: big.NewInt(amount)}
+	}
+
+	tests := []struct {
+		name           string
+		registry       *BridgedTokenRegistry
+		transferEvents []events.ERC20GatewayEventUnmarshaler
+		gatewayEvents  []events.ERC20GatewayEventUnmarshaler
+		wantSeverity   alert.Severity
+	}{
+		{"configuredTokenMint", registry, []events.ERC20GatewayEventUnmarshaler{mint(configuredToken, 10)}, nil, alert.SeverityCritical},
+		{"configuredTokenBurn", registry, []events.ERC20GatewayEventUnmarshaler{burn(configuredToken, 10)}, nil, alert.SeverityWarning},
+		{"learnedTokenMint", registry, []events.ERC20GatewayEventUnmarshaler{mint(learnedToken, 10)}, nil, alert.SeverityCritical},
+		{"learnedTokenBurn", registry, []events.ERC20GatewayEventUnmarshaler{burn(learnedToken, 10)}, nil, alert.SeverityWarning},
+		{
+			"mintWithGatewayEvent", registry,
+			[]events.ERC20GatewayEventUnmarshaler{mint(configuredToken, 10)},
+			[]events.ERC20GatewayEventUnmarshaler{gatewayEvent(types.L2FinalizeDepositERC20, configuredToken, 10)},
+			"",
+		},
+		{
+			"burnWithGatewayEvent", registry,
+			[]events.ERC20GatewayEventUnmarshaler{burn(learnedToken, 10)},
+			[]events.ERC20GatewayEventUnmarshaler{gatewayEvent(types.L2WithdrawERC20, learnedToken, 10)},
+			"",
+		},
+		// the l2 tokens which aren't bridged are minted and burned by others.
+		{"unknownTokenMint", registry, []events.ERC20GatewayEventUnmarshaler{mint(unknownToken, 10)}, nil, ""},
+		{"unknownTokenBurn", registry, []events.ERC20GatewayEventUnmarshaler{burn(unknownToken, 10)}, nil, ""},
+		{"disabled", nil, []events.ERC20GatewayEventUnmarshaler{mint(configuredToken, 10), burn(learnedToken, 10)}, nil, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			alerts = nil
+			mismatches, err := NewTransferEventMatcher(test.registry).erc20Matcher(test.transferEvents, test.gatewayEvents)
+			assert.NoError(t, err)
+			// the flagged transfers are alerted only, they aren't the mismatches of the gateway events.
+			assert.Empty(t, mismatches)
+			if test.wantSeverity == "" {
+				assert.Empty(t, alerts)
+				return
+			}
+			assert.Len(t, alerts, 1)
+			assert.Equal(t, alert.CategoryBridgedTokenTransfer, alerts[0].Category)
+			assert.Equal(t, test.wantSeverity, alerts[0].Severity)
+			assert.Equal(t, test.transferEvents[0].TokenAddress.Hex()+":"+txHash.Hex(), alerts[0].Subject)
+		})
+	}
+}
@@ -9,19 +9,51 @@ import (
 	"github.com/scroll-tech/go-ethereum/log"
 	"github.com/scroll-tech/go-ethereum/rpc"
 
-	"github.com/scroll-tech/chain-monitor/internal/logic/slack"
+	"github.com/scroll-tech/chain-monitor/internal/logic/alert"
 	"github.com/scroll-tech/chain-monitor/internal/orm"
 	"github.com/scroll-tech/chain-monitor/internal/types"
 	"github.com/scroll-tech/chain-monitor/internal/utils"
 	"github.com/scroll-tech/chain-monitor/internal/utils/msgproof"
 )
 
-func (c *MessageMatchAssembler) checkL2WithdrawRoots(ctx context.Context, startBlockNumber, endBlockNumber uint64, client *rpc.Client, messageQueueAddr common.Address) (*orm.MessengerMessageMatch, error) {
+// WithdrawRootCheckResult is the result of the l2 withdraw roots check of a block range.
+type WithdrawRootCheckResult struct {
+	// LastMessage is the last message passing the check, its proof recovers the withdraw trie of the next check.
+	LastMessage *orm.MessengerMessageMatch
+	// FailedMessages are the messages from the first block whose withdraw root mismatches, with the failure reasons.
+	FailedMessages []*orm.MessengerMessageMatch
+	// Alerts are notified once the check result is stored.
+	Alerts []alert.Alert
+}
+
+func (c *MessageMatchAssembler) checkL2WithdrawRoots(ctx context.Context, startBlockNumber, endBlockNumber uint64, client *rpc.Client, messageQueueAddr common.Address) (*WithdrawRootCheckResult, error) {
 	log.Info("checking l2 withdraw roots", "start", startBlockNumber, "end", endBlockNumber)
 
+	result := &WithdrawRootCheckResult{}
 	if startBlockNumber > endBlockNumber {
-		return nil, nil
+		return result, nil
+	}
+
+	l2SentMessages, err := c.messengerMessageMatchOrm.GetL2SentMessagesInBlockRange(ctx, startBlockNumber, endBlockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("get l2 sent messages in block range failed, err: %w", err)
+	}
+	if len(l2SentMessages) == 0 {
+		return result, nil
+	}
+
+	// The withdraw trie diverged from the l2 one at a message checked before, which is alerted already. The messages
+	// after it can't be verified until the diverged messages are re-ingested.
+	lastCheckedMsg, err := c.messengerMessageMatchOrm.GetLatestCheckedL2SentMessageMatch(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get latest checked l2 message match failed, err: %w", err)
+	}
+	if lastCheckedMsg != nil && lastCheckedMsg.WithdrawRootStatus == int(types.WithdrawRootStatusTypeFailed) {
+		reason := fmt.Sprintf("%s withdraw root check: withdraw trie diverged before, at message nonce %d", types.Layer2.String(), lastCheckedMsg.NextMessageNonce-1)
+		result.FailedMessages = withdrawRootFailedMessages(l2SentMessages, reason)
+		return result, nil
 	}
+
 	// recover latest withdraw trie.
 	withdrawTrie := msgproof.NewWithdrawTrie()
 	msg, err := c.messengerMessageMatchOrm.GetLatestValidL2SentMessageMatch(ctx)
@@ -32,14 +64,6 @@ func (c *MessageMatchAssembler) checkL2WithdrawRoots(ctx context.Context, startB
 		withdrawTrie.Initialize(msg.NextMessageNonce-1, common.HexToHash(msg.MessageHash), msg.MessageProof)
 	}
 
-	l2SentMessages, err := c.messengerMessageMatchOrm.GetL2SentMessagesInBlockRange(ctx, startBlockNumber, endBlockNumber)
-	if err != nil {
-		return nil, fmt.Errorf("get l2 sent messages in block range failed, err: %w", err)
-	}
-	if len(l2SentMessages) == 0 {
-		return nil, nil
-	}
-
 	sentMessageEventHashesMap := make(map[uint64][]common.Hash)
 	for _, message := range l2SentMessages {
 		sentMessageEventHashesMap[message.L2BlockNumber] = append(sentMessageEventHashesMap[message.L2BlockNumber], common.HexToHash(message.MessageHash))
@@ -56,25 +80,36 @@ func (c *MessageMatchAssembler) checkL2WithdrawRoots(ctx context.Context, startB
 		return nil, fmt.Errorf("get l2 withdraw roots failed, message queue addr: %v, blocks: %v, err: %w", messageQueueAddr, blockNums, err)
 	}
 
-	var lastMessage *orm.MessengerMessageMatch
+	var fingerprints []string
 	for _, blockNum := range blockNums {
 		eventHashes := sentMessageEventHashesMap[blockNum]
 		proofs := withdrawTrie.AppendMessages(eventHashes)
 		lastWithdrawRoot := withdrawTrie.MessageRoot()
 		if lastWithdrawRoot != withdrawRoots[blockNum] {
-			info := slack.WithdrawRootInfo{
+			info := alert.WithdrawRootInfo{
 				BlockNumber:          blockNum,
 				LastWithdrawRoot:     lastWithdrawRoot,
 				ExpectedWithdrawRoot: withdrawRoots[blockNum],
 			}
-			slack.Notify(slack.MrkDwnWithdrawRootMessage(info))
-			return nil, fmt.Errorf("withdraw root mismatch in %v, got: %v, expected %v", blockNum, lastWithdrawRoot, withdrawRoots[blockNum])
+			log.Error("withdraw root mismatch", "block number", blockNum, "got", lastWithdrawRoot, "expected", withdrawRoots[blockNum])
+			// The trie diverges from this block on, the messages of the later blocks are failed without comparing the roots.
+			var divergedMessages []*orm.MessengerMessageMatch
+			for _, message := range l2SentMessages {
+				if message.L2BlockNumber >= blockNum {
+					divergedMessages = append(divergedMessages, message)
+				}
+			}
+			reason := fmt.Sprintf("%s withdraw root check: mismatch at block %d, got %s, expected %s", types.Layer2.String(), blockNum, lastWithdrawRoot.Hex(), withdrawRoots[blockNum].Hex())
+			result.FailedMessages = withdrawRootFailedMessages(divergedMessages, reason)
+			result.Alerts = append(result.Alerts, alert.WithdrawRootAlert(info))
+			break
 		}
+		fingerprints = append(fingerprints, alert.Fingerprint(alert.CategoryWithdrawRoot, types.Layer2, "", blockNum))
 		// current block has SentMessage events.
 		numEvents := len(eventHashes)
 		if numEvents > 0 {
 			// only update the last message of this check.
-			lastMessage = &orm.MessengerMessageMatch{
+			result.LastMessage = &orm.MessengerMessageMatch{
 				MessageHash:           eventHashes[numEvents-1].Hex(),
 				MessageProof:          proofs[numEvents-1],
 				WithdrawRootStatus:    int(types.WithdrawRootStatusTypeValid),
@@ -84,5 +119,23 @@ func (c *MessageMatchAssembler) checkL2WithdrawRoots(ctx context.Context, startB
 			}
 		}
 	}
-	return lastMessage, nil
+
+	// The blocks which mismatched before may pass the check after the events are re-ingested.
+	alert.Resolve(ctx, fingerprints...)
+	return result, nil
+}
+
+// withdrawRootFailedMessages returns the messages whose withdraw root check is failed with the failure reason.
+func withdrawRootFailedMessages(messages []*orm.MessengerMessageMatch, reason string) []*orm.MessengerMessageMatch {
+	failedMessages := make([]*orm.MessengerMessageMatch, 0, len(messages))
+	for _, message := range messages {
+		failedMessages = append(failedMessages, &orm.MessengerMessageMatch{
+			MessageHash:        message.MessageHash,
+			WithdrawRootStatus: int(types.WithdrawRootStatusTypeFailed),
+			FailureReason:      reason,
+			NextMessageNonce:   message.NextMessageNonce,
+			L2BlockNumber:      message.L2BlockNumber,
+		})
+	}
+	return failedMessages
 }
@@ -0,0 +1,87 @@
+package assembler
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scroll-tech/chain-monitor/internal/logic/alert"
+	"github.com/scroll-tech/chain-monitor/internal/orm"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+	"github.com/scroll-tech/chain-monitor/internal/utils/msgproof"
+	"github.com/scroll-tech/chain-monitor/internal/utils/testcontainer"
+)
+
+// withdrawRootService serves the withdraw roots stored in the l2 message queue by the block numbers.
+type withdrawRootService struct {
+	withdrawRoots map[uint64]common.Hash
+}
+
+func (s *withdrawRootService) GetStorageAt(_ common.Address, _ common.Hash, blockNumber hexutil.Big) (common.Hash, error) {
+	withdrawRoot, ok := s.withdrawRoots[blockNumber.ToInt().Uint64()]
+	if !ok {
+		return common.Hash{}, fmt.Errorf("unknown block %v", blockNumber.ToInt())
+	}
+	return withdrawRoot, nil
+}
+
+func TestCheckL2WithdrawRoots(t *testing.T) {
+	ctx := context.Background()
+	db := testcontainer.SetupDB(ctx, t)
+	messengerMessageMatchOrm := orm.NewMessengerMessageMatch(db)
+	c := &MessageMatchAssembler{messengerMessageMatchOrm: messengerMessageMatchOrm}
+
+	// one message is sent in each of the blocks 10 to 13.
+	var messageHashes []common.Hash
+	for i := uint64(0); i < 4; i++ {
+		messageHash := common.BigToHash(new(big.Int).SetUint64(i + 1))
+		messageHashes = append(messageHashes, messageHash)
+		_, err := messengerMessageMatchOrm.InsertOrUpdateEventInfo(ctx, types.Layer2, orm.MessengerMessageMatch{MessageHash: messageHash.Hex(),
+			L2EventType: int(types.L2SentMessage), L2BlockNumber: 10 + i, L2TxHash: messageHash.Hex(), NextMessageNonce: i + 1})
+		assert.NoError(t, err)
+	}
+
+	// the withdraw root of block 11 mismatches, the trie diverges from it on.
+	withdrawTrie := msgproof.NewWithdrawTrie()
+	withdrawTrie.AppendMessages(messageHashes[:1])
+	service := &withdrawRootService{withdrawRoots: map[uint64]common.Hash{10: withdrawTrie.MessageRoot(), 11: common.HexToHash("0x11"), 12: common.HexToHash("0x12"), 13: common.HexToHash("0x13")}}
+	server := rpc.NewServer()
+	assert.NoError(t, server.RegisterName("eth", service))
+	defer server.Stop()
+	client := rpc.DialInProc(server)
+
+	result, err := c.checkL2WithdrawRoots(ctx, 10, 12, client, common.HexToAddress("0x5300000000000000000000000000000000000000"))
+	assert.NoError(t, err)
+	assert.Equal(t, messageHashes[0].Hex(), result.LastMessage.MessageHash)
+	assert.Len(t, result.Alerts, 1)
+	assert.Equal(t, alert.CategoryWithdrawRoot, result.Alerts[0].Category)
+	assert.Equal(t, uint64(11), result.Alerts[0].BlockNumber)
+	var failedMessageHashes []string
+	for _, message := range result.FailedMessages {
+		assert.Equal(t, int(types.WithdrawRootStatusTypeFailed), message.WithdrawRootStatus)
+		assert.Contains(t, message.FailureReason, "Layer2 withdraw root check: mismatch at block 11")
+		failedMessageHashes = append(failedMessageHashes, message.MessageHash)
+	}
+	assert.Equal(t, []string{messageHashes[1].Hex(), messageHashes[2].Hex()}, failedMessageHashes)
+
+	// the check result is stored as the watcher does.
+	assert.NoError(t, messengerMessageMatchOrm.UpdateMsgProofAndStatus(ctx, result.LastMessage))
+	for _, message := range result.FailedMessages {
+		assert.NoError(t, messengerMessageMatchOrm.UpdateMsgProofAndStatus(ctx, message))
+	}
+
+	// the messages after the diverged ones are failed without alerting again.
+	result, err = c.checkL2WithdrawRoots(ctx, 13, 13, client, common.HexToAddress("0x5300000000000000000000000000000000000000"))
+	assert.NoError(t, err)
+	assert.Nil(t, result.LastMessage)
+	assert.Empty(t, result.Alerts)
+	assert.Len(t, result.FailedMessages, 1)
+	assert.Equal(t, messageHashes[3].Hex(), result.FailedMessages[0].MessageHash)
+	assert.Contains(t, result.FailedMessages[0].FailureReason, "withdraw trie diverged before")
+}
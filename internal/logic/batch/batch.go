@@ -0,0 +1,168 @@
+package batch
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"github.com/scroll-tech/chain-monitor/internal/config"
+	"github.com/scroll-tech/chain-monitor/internal/logic/alert"
+	"github.com/scroll-tech/chain-monitor/internal/logic/events"
+	messagematch "github.com/scroll-tech/chain-monitor/internal/logic/message_match"
+	"github.com/scroll-tech/chain-monitor/internal/orm"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+)
+
+const (
+	// the max number of finalized batches checked each time.
+	finalizedBatchCheckLimit = 100
+	// the max number of unsettled batches whose status is checked each time.
+	batchStatusCheckLimit = 100
+)
+
+var (
+	batchWithdrawRootCheckedIndex = promauto.With(prometheus.DefaultRegisterer).NewGauge(prometheus.GaugeOpts{
+		Name: "batch_withdraw_root_checked_index",
+		Help: "The index of the latest finalized batch whose withdraw root is checked.",
+	})
+	batchWithdrawRootMismatchTotal = promauto.With(prometheus.DefaultRegisterer).NewCounter(prometheus.CounterOpts{
+		Name: "batch_withdraw_root_mismatch_total",
+		Help: "The total number of finalized batches whose withdraw root mismatches the one computed locally.",
+	})
+	batchStatusCheckedTotal = promauto.With(prometheus.DefaultRegisterer).NewCounterVec(prometheus.CounterOpts{
+		Name: "batch_status_checked_total",
+		Help: "The total number of batch status checks by the status.",
+	}, []string{"status"})
+	batchFinalizedUnverifiedTotal = promauto.With(prometheus.DefaultRegisterer).NewCounterVec(prometheus.CounterOpts{
+		Name: "batch_finalized_unverified_total",
+		Help: "The total number of batches finalized on l1 while their status is not valid.",
+	}, []string{"status"})
+)
+
+// LogicBatch checks the batches committed and finalized on the l1 scroll chain.
+// The metrics are shared by the instances, since the logic is used by both the watchers and the api.
+type LogicBatch struct {
+	db                *gorm.DB
+	batchOrm          *orm.Batch
+	messageMatchLogic *messagematch.LogicMessageMatch
+	l2StartNumber     uint64
+}
+
+// NewLogicBatch creates a new LogicBatch instance.
+func NewLogicBatch(cfg *config.Config, db *gorm.DB) *LogicBatch {
+	return &LogicBatch{
+		db:                db,
+		batchOrm:          orm.NewBatch(db),
+		messageMatchLogic: messagematch.NewMessageMatchLogic(cfg, db),
+		l2StartNumber:     cfg.L2Config.StartNumber,
+	}
+}
+
+// InsertOrUpdateBatches applies the batch events in the order they are emitted, so that a batch reverted
+// and committed again in the same block range ends up with the info of the last commit.
+func (b *LogicBatch) InsertOrUpdateBatches(ctx context.Context, batchEvents []*events.BatchEventUnmarshaler, dbTX ...*gorm.DB) error {
+	sort.Slice(batchEvents, func(i, j int) bool {
+		if batchEvents[i].Number != batchEvents[j].Number {
+			return batchEvents[i].Number < batchEvents[j].Number
+		}
+		return batchEvents[i].Index < batchEvents[j].Index
+	})
+
+	for _, event := range batchEvents {
+		switch event.Type {
+		case types.L1CommitBatch:
+			batch := orm.Batch{
+				BatchIndex:        event.BatchIndex,
+				BatchHash:         event.BatchHash.Hex(),
+				StartBlockNumber:  event.StartBlockNumber,
+				EndBlockNumber:    event.EndBlockNumber,
+				CommitBlockNumber: event.Number,
+				CommitTxHash:      event.TxHash.Hex(),
+			}
+			if err := b.batchOrm.InsertOrUpdateCommittedBatch(ctx, batch, dbTX...); err != nil {
+				return fmt.Errorf("insert or update committed batch failed, batch index: %v, err: %w", event.BatchIndex, err)
+			}
+		case types.L1FinalizeBatch:
+			var finalizeBlockTime *time.Time
+			if !event.BlockTime.IsZero() {
+				finalizeBlockTime = &event.BlockTime
+			}
+			batch := orm.Batch{
+				BatchIndex:          event.BatchIndex,
+				BatchHash:           event.BatchHash.Hex(),
+				FinalizeBlockNumber: event.Number,
+				FinalizeTxHash:      event.TxHash.Hex(),
+				FinalizeBlockTime:   finalizeBlockTime,
+				StateRoot:           event.StateRoot.Hex(),
+				WithdrawRoot:        event.WithdrawRoot.Hex(),
+			}
+			if err := b.batchOrm.InsertOrUpdateFinalizedBatch(ctx, batch, dbTX...); err != nil {
+				return fmt.Errorf("insert or update finalized batch failed, batch index: %v, err: %w", event.BatchIndex, err)
+			}
+		case types.L1RevertBatch:
+			if err := b.batchOrm.UpdateRevertedBatch(ctx, event.BatchIndex, event.Number, event.TxHash.Hex(), dbTX...); err != nil {
+				return fmt.Errorf("update reverted batch failed, batch index: %v, err: %w", event.BatchIndex, err)
+			}
+		}
+	}
+	return nil
+}
+
+// CheckFinalizedWithdrawRoots checks the withdraw roots of the finalized batches against the withdraw roots computed
+// from the ingested l2 sent messages at the last l2 block of the batches. The batches are checked once the l2 blocks
+// are ingested, and a mismatch is alerted as a critical alert since the forged withdrawals can be proved with the root.
+func (b *LogicBatch) CheckFinalizedWithdrawRoots(ctx context.Context) {
+	l2BlockNumber, err := b.messageMatchLogic.GetLatestBlockNumber(ctx, types.Layer2)
+	if err != nil {
+		log.Error("LogicBatch.CheckFinalizedWithdrawRoots get l2 latest block number failed", "error", err)
+		return
+	}
+
+	batches, err := b.batchOrm.GetUncheckedFinalizedBatches(ctx, b.l2StartNumber, finalizedBatchCheckLimit)
+	if err != nil {
+		log.Error("LogicBatch.CheckFinalizedWithdrawRoots get unchecked finalized batches failed", "error", err)
+		return
+	}
+
+	for _, batch := range batches {
+		if batch.EndBlockNumber > l2BlockNumber {
+			return
+		}
+
+		localWithdrawRoot, err := b.messageMatchLogic.GetWithdrawRootAtBlock(ctx, batch.EndBlockNumber)
+		if err != nil {
+			log.Error("LogicBatch.CheckFinalizedWithdrawRoots compute withdraw root failed", "batch index", batch.BatchIndex, "l2 block number", batch.EndBlockNumber, "error", err)
+			return
+		}
+
+		status := types.WithdrawRootStatusTypeValid
+		if localWithdrawRoot != common.HexToHash(batch.WithdrawRoot) {
+			status = types.WithdrawRootStatusTypeFailed
+			batchWithdrawRootMismatchTotal.Inc()
+			alert.Notify(alert.FinalizedWithdrawRootAlert(batch, localWithdrawRoot))
+			log.Error("finalized withdraw root mismatch", "batch index", batch.BatchIndex, "l2 block number", batch.EndBlockNumber,
+				"finalized withdraw root", batch.WithdrawRoot, "local withdraw root", localWithdrawRoot.Hex())
+		}
+
+		if err := b.batchOrm.UpdateWithdrawRootStatus(ctx, batch.BatchIndex, localWithdrawRoot.Hex(), status); err != nil {
+			log.Error("LogicBatch.CheckFinalizedWithdrawRoots update withdraw root status failed", "batch index", batch.BatchIndex, "error", err)
+			return
+		}
+
+		// The mismatch is alerted above, the batch becomes invalid even if it's valid before it's finalized.
+		if status == types.WithdrawRootStatusTypeFailed {
+			if err := b.batchOrm.UpdateStatus(ctx, batch.BatchIndex, types.BatchStatusTypeInvalid, true); err != nil {
+				log.Error("LogicBatch.CheckFinalizedWithdrawRoots update batch status failed", "batch index", batch.BatchIndex, "error", err)
+				return
+			}
+		}
+		batchWithdrawRootCheckedIndex.Set(float64(batch.BatchIndex))
+	}
+}
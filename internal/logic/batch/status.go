@@ -0,0 +1,179 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"github.com/scroll-tech/chain-monitor/internal/logic/alert"
+	messagematch "github.com/scroll-tech/chain-monitor/internal/logic/message_match"
+	"github.com/scroll-tech/chain-monitor/internal/orm"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+)
+
+// ErrBatchNotFound the batch is not committed or its commit event is not ingested yet.
+var ErrBatchNotFound = errors.New("batch not found")
+
+// BatchView the batch with the statuses rendered by name.
+type BatchView struct {
+	BatchIndex uint64 `json:"batch_index"`
+	BatchHash  string `json:"batch_hash"`
+	Status     string `json:"status"`
+
+	StartBlockNumber uint64 `json:"start_block_number"`
+	EndBlockNumber   uint64 `json:"end_block_number"`
+
+	CommitBlockNumber   uint64 `json:"commit_block_number"`
+	CommitTxHash        string `json:"commit_tx_hash"`
+	FinalizeBlockNumber uint64 `json:"finalize_block_number,omitempty"`
+	FinalizeTxHash      string `json:"finalize_tx_hash,omitempty"`
+	RevertBlockNumber   uint64 `json:"revert_block_number,omitempty"`
+	RevertTxHash        string `json:"revert_tx_hash,omitempty"`
+
+	WithdrawRoot       string `json:"withdraw_root,omitempty"`
+	LocalWithdrawRoot  string `json:"local_withdraw_root,omitempty"`
+	WithdrawRootStatus string `json:"withdraw_root_status"`
+
+	StatusUpdatedAt *time.Time `json:"status_updated_at,omitempty"`
+
+	// the violations found in the l2 blocks of the batch when it's queried.
+	Violations []*messagematch.Violation `json:"violations"`
+}
+
+// GetBatch get the batch and its status of the batch index, with the violations which make it invalid.
+func (b *LogicBatch) GetBatch(ctx context.Context, batchIndex uint64) (*BatchView, error) {
+	batch, err := b.batchOrm.GetBatchByIndex(ctx, batchIndex)
+	if err != nil {
+		return nil, err
+	}
+	if batch == nil {
+		return nil, ErrBatchNotFound
+	}
+
+	violations := make([]*messagematch.Violation, 0)
+	if batch.Status != int(types.BatchStatusTypeValid) {
+		l2BlockNumber, err := b.messageMatchLogic.GetLatestBlockNumber(ctx, types.Layer2)
+		if err != nil {
+			return nil, err
+		}
+		_, batchViolations, err := b.batchStatus(ctx, *batch, l2BlockNumber)
+		if err != nil {
+			return nil, err
+		}
+		violations = append(violations, batchViolations...)
+	}
+
+	return &BatchView{
+		BatchIndex:          batch.BatchIndex,
+		BatchHash:           batch.BatchHash,
+		Status:              types.BatchStatus(batch.Status).String(),
+		StartBlockNumber:    batch.StartBlockNumber,
+		EndBlockNumber:      batch.EndBlockNumber,
+		CommitBlockNumber:   batch.CommitBlockNumber,
+		CommitTxHash:        batch.CommitTxHash,
+		FinalizeBlockNumber: batch.FinalizeBlockNumber,
+		FinalizeTxHash:      batch.FinalizeTxHash,
+		RevertBlockNumber:   batch.RevertBlockNumber,
+		RevertTxHash:        batch.RevertTxHash,
+		WithdrawRoot:        batch.WithdrawRoot,
+		LocalWithdrawRoot:   batch.LocalWithdrawRoot,
+		WithdrawRootStatus:  types.WithdrawRootStatus(batch.WithdrawRootStatus).String(),
+		StatusUpdatedAt:     batch.StatusUpdatedAt,
+		Violations:          violations,
+	}, nil
+}
+
+// CheckBatchStatuses computes the status of the latest batches which are not valid yet from the message matches of their
+// l2 blocks. A batch finalized on l1 while its status is pending or invalid is alerted, and the alert is resolved once
+// the batch becomes valid. A committed batch whose l2 block range is unknown is alerted once when it's checked.
+func (b *LogicBatch) CheckBatchStatuses(ctx context.Context) {
+	l2BlockNumber, err := b.messageMatchLogic.GetLatestBlockNumber(ctx, types.Layer2)
+	if err != nil {
+		log.Error("LogicBatch.CheckBatchStatuses get l2 latest block number failed", "error", err)
+		return
+	}
+
+	batches, err := b.batchOrm.GetUnsettledBatches(ctx, b.l2StartNumber, batchStatusCheckLimit)
+	if err != nil {
+		log.Error("LogicBatch.CheckBatchStatuses get unsettled batches failed", "error", err)
+		return
+	}
+
+	for _, batch := range batches {
+		status, _, err := b.batchStatus(ctx, batch, l2BlockNumber)
+		if err != nil {
+			log.Error("LogicBatch.CheckBatchStatuses get batch status failed", "batch index", batch.BatchIndex, "error", err)
+			return
+		}
+		batchStatusCheckedTotal.WithLabelValues(status.String()).Inc()
+
+		// The batch whose l2 block range is unknown is alerted once, it's settled until the batch is committed again.
+		finalizeChecked := batch.FinalizeChecked
+		if status == types.BatchStatusTypeUnknownRange {
+			if batch.CommitBlockNumber > 0 {
+				alert.Notify(alert.BatchBlockRangeUnknownAlert(batch))
+				log.Error("the l2 block range of the committed batch is unknown", "batch index", batch.BatchIndex,
+					"commit block number", batch.CommitBlockNumber, "commit tx hash", batch.CommitTxHash)
+			}
+		} else if batch.FinalizeBlockNumber > 0 {
+			if status != types.BatchStatusTypeValid && (!batch.FinalizeChecked || status != types.BatchStatus(batch.Status)) {
+				batchFinalizedUnverifiedTotal.WithLabelValues(status.String()).Inc()
+				alert.Notify(alert.BatchFinalizedAlert(batch, status))
+				log.Error("batch finalized before it's valid", "batch index", batch.BatchIndex, "status", status.String(),
+					"finalize block number", batch.FinalizeBlockNumber, "finalize tx hash", batch.FinalizeTxHash)
+				finalizeChecked = true
+			}
+			if status == types.BatchStatusTypeValid && batch.FinalizeChecked {
+				alert.Resolve(ctx, alert.Fingerprint(alert.CategoryBatchFinalized, types.Layer1, "", batch.FinalizeBlockNumber))
+			}
+		}
+
+		if status == types.BatchStatus(batch.Status) && finalizeChecked == batch.FinalizeChecked {
+			continue
+		}
+		if err := b.batchOrm.UpdateStatus(ctx, batch.BatchIndex, status, finalizeChecked); err != nil {
+			log.Error("LogicBatch.CheckBatchStatuses update batch status failed", "batch index", batch.BatchIndex, "error", err)
+			return
+		}
+	}
+}
+
+// batchStatus the batch is invalid if there is any failed check in its l2 blocks, and pending until its l2 blocks are
+// ingested and checked and, if it's finalized, its withdraw root is checked. The batch without the l2 block range, which
+// can't be decoded from the commit tx or is committed before the start block, can't be checked.
+func (b *LogicBatch) batchStatus(ctx context.Context, batch orm.Batch, l2BlockNumber uint64) (types.BatchStatus, []*messagematch.Violation, error) {
+	if batch.EndBlockNumber == 0 {
+		return types.BatchStatusTypeUnknownRange, nil, nil
+	}
+
+	violations, err := b.messageMatchLogic.GetBlocksViolations(ctx, batch.StartBlockNumber, batch.EndBlockNumber)
+	if err != nil {
+		return types.BatchStatusTypePending, nil, err
+	}
+
+	if batch.WithdrawRootStatus == int(types.WithdrawRootStatusTypeFailed) {
+		violations = append(violations, &messagematch.Violation{
+			Table:         batch.TableName(),
+			Check:         messagematch.ViolationCheckWithdrawRoot,
+			Status:        messagematch.ViolationStatusFailed,
+			Layer:         types.Layer1.String(),
+			Column:        "withdraw_root_status",
+			L1BlockNumber: batch.FinalizeBlockNumber,
+			L1TxHash:      batch.FinalizeTxHash,
+			L2BlockNumber: batch.EndBlockNumber,
+		})
+	}
+
+	if status := messagematch.ViolationsStatus(violations); status != types.BatchStatusTypeValid {
+		return status, violations, nil
+	}
+	if batch.EndBlockNumber > l2BlockNumber {
+		return types.BatchStatusTypePending, violations, nil
+	}
+	if batch.FinalizeBlockNumber > 0 && batch.WithdrawRootStatus == int(types.WithdrawRootStatusTypeUnchecked) {
+		return types.BatchStatusTypePending, violations, nil
+	}
+	return types.BatchStatusTypeValid, violations, nil
+}
@@ -0,0 +1,110 @@
+package batch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scroll-tech/chain-monitor/internal/config"
+	"github.com/scroll-tech/chain-monitor/internal/logic/alert"
+	"github.com/scroll-tech/chain-monitor/internal/orm"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+	"github.com/scroll-tech/chain-monitor/internal/utils/testcontainer"
+)
+
+func TestLogicBatch_BatchStatus(t *testing.T) {
+	ctx := context.Background()
+	db := testcontainer.SetupDB(ctx, t)
+	b := NewLogicBatch(&config.Config{L1Config: &config.L1Config{}, L2Config: &config.L2Config{}}, db)
+
+	failedMessage := orm.MessengerMessageMatch{
+		MessageHash:   "0x1",
+		L2EventType:   int(types.L2SentMessage),
+		L2BlockNumber: 55,
+		L2TxHash:      "0xa1",
+		L2BlockStatus: int(types.BlockStatusTypeFailed),
+	}
+	_, err := orm.NewMessengerMessageMatch(db).InsertOrUpdateEventInfo(ctx, types.Layer2, failedMessage)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		batch      orm.Batch
+		wantStatus types.BatchStatus
+	}{
+		{"unknownRange", orm.Batch{BatchIndex: 1, CommitBlockNumber: 100}, types.BatchStatusTypeUnknownRange},
+		{"valid", orm.Batch{BatchIndex: 2, StartBlockNumber: 10, EndBlockNumber: 20}, types.BatchStatusTypeValid},
+		{"notIngested", orm.Batch{BatchIndex: 3, StartBlockNumber: 190, EndBlockNumber: 210}, types.BatchStatusTypePending},
+		{"failedMessage", orm.Batch{BatchIndex: 4, StartBlockNumber: 50, EndBlockNumber: 60}, types.BatchStatusTypeInvalid},
+		{"withdrawRootUnchecked", orm.Batch{BatchIndex: 5, StartBlockNumber: 10, EndBlockNumber: 20, FinalizeBlockNumber: 300}, types.BatchStatusTypePending},
+		{"withdrawRootValid", orm.Batch{BatchIndex: 6, StartBlockNumber: 10, EndBlockNumber: 20, FinalizeBlockNumber: 300, WithdrawRootStatus: int(types.WithdrawRootStatusTypeValid)}, types.BatchStatusTypeValid},
+		{"withdrawRootFailed", orm.Batch{BatchIndex: 7, StartBlockNumber: 10, EndBlockNumber: 20, FinalizeBlockNumber: 300, WithdrawRootStatus: int(types.WithdrawRootStatusTypeFailed)}, types.BatchStatusTypeInvalid},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			status, _, err := b.batchStatus(ctx, test.batch, 200)
+			assert.NoError(t, err)
+			assert.Equal(t, test.wantStatus, status)
+		})
+	}
+}
+
+func TestLogicBatch_CheckBatchStatuses(t *testing.T) {
+	ctx := context.Background()
+	db := testcontainer.SetupDB(ctx, t)
+	b := NewLogicBatch(&config.Config{L1Config: &config.L1Config{}, L2Config: &config.L2Config{}}, db)
+
+	var alerts []alert.Alert
+	previous := alert.Use(alert.NewManagerWithSinks(alert.NewFuncSink("stdout", func(a alert.Alert) { alerts = append(alerts, a) })))
+	defer alert.Use(previous)
+
+	assert.NoError(t, orm.NewSyncCursor(db).InsertOrUpdateSyncCursor(ctx, types.Layer2, types.CheckerTypeContract, 200, "0x1"))
+	failedMessage := orm.MessengerMessageMatch{
+		MessageHash:   "0x1",
+		L2EventType:   int(types.L2SentMessage),
+		L2BlockNumber: 55,
+		L2TxHash:      "0xa1",
+		L2BlockStatus: int(types.BlockStatusTypeFailed),
+	}
+	_, err := orm.NewMessengerMessageMatch(db).InsertOrUpdateEventInfo(ctx, types.Layer2, failedMessage)
+	assert.NoError(t, err)
+
+	// batch 1 is committed before the start block, batch 2's block range can't be decoded, and batch 3 is finalized while
+	// its l2 blocks fail the checks.
+	batchOrm := orm.NewBatch(db)
+	assert.NoError(t, batchOrm.InsertOrUpdateFinalizedBatch(ctx, orm.Batch{BatchIndex: 1, BatchHash: "0xb1", FinalizeBlockNumber: 110, FinalizeTxHash: "0xf1"}))
+	assert.NoError(t, batchOrm.InsertOrUpdateCommittedBatch(ctx, orm.Batch{BatchIndex: 2, BatchHash: "0xb2", CommitBlockNumber: 120, CommitTxHash: "0xc2"}))
+	assert.NoError(t, batchOrm.InsertOrUpdateCommittedBatch(ctx, orm.Batch{BatchIndex: 3, BatchHash: "0xb3", StartBlockNumber: 50, EndBlockNumber: 60, CommitBlockNumber: 130, CommitTxHash: "0xc3"}))
+	assert.NoError(t, batchOrm.InsertOrUpdateFinalizedBatch(ctx, orm.Batch{BatchIndex: 3, BatchHash: "0xb3", FinalizeBlockNumber: 140, FinalizeTxHash: "0xf3"}))
+
+	// every batch is alerted once, no matter how many times it's checked.
+	b.CheckBatchStatuses(ctx)
+	b.CheckBatchStatuses(ctx)
+	assert.Len(t, alerts, 2)
+	categories := map[alert.Category]uint64{}
+	for _, a := range alerts {
+		categories[a.Category] = a.BlockNumber
+	}
+	assert.Equal(t, map[alert.Category]uint64{alert.CategoryBatchBlockRange: 120, alert.CategoryBatchFinalized: 140}, categories)
+
+	wantStatuses := map[uint64]types.BatchStatus{
+		1: types.BatchStatusTypeUnknownRange,
+		2: types.BatchStatusTypeUnknownRange,
+		3: types.BatchStatusTypeInvalid,
+	}
+	for batchIndex, wantStatus := range wantStatuses {
+		batch, err := batchOrm.GetBatchByIndex(ctx, batchIndex)
+		assert.NoError(t, err)
+		assert.Equal(t, int(wantStatus), batch.Status)
+	}
+
+	// the batch committed again is checked again.
+	assert.NoError(t, batchOrm.InsertOrUpdateCommittedBatch(ctx, orm.Batch{BatchIndex: 2, BatchHash: "0xb2", StartBlockNumber: 10, EndBlockNumber: 20, CommitBlockNumber: 150, CommitTxHash: "0xc4"}))
+	b.CheckBatchStatuses(ctx)
+	assert.Len(t, alerts, 2)
+	batch, err := batchOrm.GetBatchByIndex(ctx, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int(types.BatchStatusTypeValid), batch.Status)
+}
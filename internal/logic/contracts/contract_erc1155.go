@@ -2,6 +2,7 @@ package contracts
 
 import (
 	"context"
+	"fmt"
 	"math/big"
 
 	"github.com/scroll-tech/go-ethereum"
@@ -17,143 +18,167 @@ import (
 
 func (l *Contracts) l1Erc1155Filter(_ context.Context, opts *bind.FilterOpts) ([]types.WrapIterator, error) {
 	var iterators []types.WrapIterator
+	for _, gateway := range l.l1Contracts.erc1155GatewayMappings {
+		erc1155Gateway, exist := l.l1Contracts.erc1155Gateways[gateway.name]
+		if !exist {
+			err := fmt.Errorf("can't get erc1155 filter failed, gateway name:%v, address:%v", gateway.name, gateway.address)
+			log.Error("get erc1155 event filter from l1 contracts failed", "err", err)
+			return nil, err
+		}
 
-	// deposit
-	depositIter, err := l.l1Contracts.ERC1155Gateway.FilterDepositERC1155(opts, nil, nil, nil)
-	if err != nil {
-		log.Error("get erc1155 gateway deposit iterator failed", "error", err)
-		return nil, err
-	}
+		gatewayOpts, active := gatewayFilterOpts(opts, l.l1Contracts.activationBlocks, gateway.address)
+		if !active {
+			continue
+		}
 
-	depositWrapIter := types.WrapIterator{
-		Iter:      depositIter,
-		EventType: types.L1DepositERC1155,
-	}
-	iterators = append(iterators, depositWrapIter)
+		// deposit
+		depositIter, err := erc1155Gateway.FilterDepositERC1155(gatewayOpts, nil, nil, nil)
+		if err != nil {
+			log.Error("get erc1155 gateway deposit iterator failed", "name", gateway.name, "address", gateway.address, "error", err)
+			return nil, err
+		}
 
-	// batch deposit
-	batchDepositIter, err := l.l1Contracts.ERC1155Gateway.FilterBatchDepositERC1155(opts, nil, nil, nil)
-	if err != nil {
-		log.Error("get erc1155 gateway batch deposit iterator failed", "error", err)
-		return nil, err
-	}
+		depositWrapIter := types.WrapIterator{
+			Iter:      depositIter,
+			EventType: types.L1DepositERC1155,
+		}
+		iterators = append(iterators, depositWrapIter)
 
-	batchDepositWrapIter := types.WrapIterator{
-		Iter:      batchDepositIter,
-		EventType: types.L1BatchDepositERC1155,
-	}
-	iterators = append(iterators, batchDepositWrapIter)
+		// batch deposit
+		batchDepositIter, err := erc1155Gateway.FilterBatchDepositERC1155(gatewayOpts, nil, nil, nil)
+		if err != nil {
+			log.Error("get erc1155 gateway batch deposit iterator failed", "name", gateway.name, "address", gateway.address, "error", err)
+			return nil, err
+		}
 
-	// finalizeWithdraw
-	finalizeWithdrawIter, err := l.l1Contracts.ERC1155Gateway.FilterFinalizeWithdrawERC1155(opts, nil, nil, nil)
-	if err != nil {
-		log.Error("get erc1155 gateway finalizeWithdraw iterator failed", "error", err)
-		return nil, err
-	}
+		batchDepositWrapIter := types.WrapIterator{
+			Iter:      batchDepositIter,
+			EventType: types.L1BatchDepositERC1155,
+		}
+		iterators = append(iterators, batchDepositWrapIter)
 
-	finalizeWithdrawWrapIter := types.WrapIterator{
-		Iter:      finalizeWithdrawIter,
-		EventType: types.L1FinalizeWithdrawERC1155,
-	}
-	iterators = append(iterators, finalizeWithdrawWrapIter)
+		// finalizeWithdraw
+		finalizeWithdrawIter, err := erc1155Gateway.FilterFinalizeWithdrawERC1155(gatewayOpts, nil, nil, nil)
+		if err != nil {
+			log.Error("get erc1155 gateway finalizeWithdraw iterator failed", "name", gateway.name, "address", gateway.address, "error", err)
+			return nil, err
+		}
 
-	// finalize batch Withdraw
-	finalizeBatchWithdrawIter, err := l.l1Contracts.ERC1155Gateway.FilterFinalizeBatchWithdrawERC1155(opts, nil, nil, nil)
-	if err != nil {
-		log.Error("get erc1155 gateway finalizeWithdraw iterator failed", "error", err)
-		return nil, err
-	}
+		finalizeWithdrawWrapIter := types.WrapIterator{
+			Iter:      finalizeWithdrawIter,
+			EventType: types.L1FinalizeWithdrawERC1155,
+		}
+		iterators = append(iterators, finalizeWithdrawWrapIter)
 
-	finalizeBatchWithdrawWrapIter := types.WrapIterator{
-		Iter:      finalizeBatchWithdrawIter,
-		EventType: types.L1FinalizeBatchWithdrawERC1155,
-	}
-	iterators = append(iterators, finalizeBatchWithdrawWrapIter)
+		// finalize batch Withdraw
+		finalizeBatchWithdrawIter, err := erc1155Gateway.FilterFinalizeBatchWithdrawERC1155(gatewayOpts, nil, nil, nil)
+		if err != nil {
+			log.Error("get erc1155 gateway finalizeWithdraw iterator failed", "name", gateway.name, "address", gateway.address, "error", err)
+			return nil, err
+		}
 
-	// refund
-	refundIter, err := l.l1Contracts.ERC1155Gateway.FilterRefundERC1155(opts, nil, nil)
-	if err != nil {
-		log.Error("get erc1155 gateway refund iterator failed", "error", err)
-		return nil, err
-	}
+		finalizeBatchWithdrawWrapIter := types.WrapIterator{
+			Iter:      finalizeBatchWithdrawIter,
+			EventType: types.L1FinalizeBatchWithdrawERC1155,
+		}
+		iterators = append(iterators, finalizeBatchWithdrawWrapIter)
 
-	refundWrapIter := types.WrapIterator{
-		Iter:      refundIter,
-		EventType: types.L1RefundERC1155,
-	}
-	iterators = append(iterators, refundWrapIter)
+		// refund
+		refundIter, err := erc1155Gateway.FilterRefundERC1155(gatewayOpts, nil, nil)
+		if err != nil {
+			log.Error("get erc1155 gateway refund iterator failed", "name", gateway.name, "address", gateway.address, "error", err)
+			return nil, err
+		}
 
-	// batch refund
-	batchRefundIter, err := l.l1Contracts.ERC1155Gateway.FilterBatchRefundERC1155(opts, nil, nil)
-	if err != nil {
-		log.Error("get erc1155 gateway refund iterator failed", "error", err)
-		return nil, err
-	}
+		refundWrapIter := types.WrapIterator{
+			Iter:      refundIter,
+			EventType: types.L1RefundERC1155,
+		}
+		iterators = append(iterators, refundWrapIter)
 
-	batchRefundWrapIter := types.WrapIterator{
-		Iter:      batchRefundIter,
-		EventType: types.L1BatchRefundERC1155,
-	}
-	iterators = append(iterators, batchRefundWrapIter)
+		// batch refund
+		batchRefundIter, err := erc1155Gateway.FilterBatchRefundERC1155(gatewayOpts, nil, nil)
+		if err != nil {
+			log.Error("get erc1155 gateway refund iterator failed", "name", gateway.name, "address", gateway.address, "error", err)
+			return nil, err
+		}
 
+		batchRefundWrapIter := types.WrapIterator{
+			Iter:      batchRefundIter,
+			EventType: types.L1BatchRefundERC1155,
+		}
+		iterators = append(iterators, batchRefundWrapIter)
+	}
 	return iterators, nil
 }
 
 func (l *Contracts) l2Erc1155Filter(_ context.Context, opts *bind.FilterOpts) ([]types.WrapIterator, error) {
 	var iterators []types.WrapIterator
+	for _, gateway := range l.l2Contracts.erc1155GatewayMappings {
+		erc1155Gateway, exist := l.l2Contracts.erc1155Gateways[gateway.name]
+		if !exist {
+			err := fmt.Errorf("can't get erc1155 filter failed, gateway name:%v, address:%v", gateway.name, gateway.address)
+			log.Error("get erc1155 event filter from l2 contracts failed", "err", err)
+			return nil, err
+		}
 
-	// withdraw
-	withdrawIter, err := l.l2Contracts.ERC1155Gateway.FilterWithdrawERC1155(opts, nil, nil, nil)
-	if err != nil {
-		log.Error("get erc1155 gateway withdraw iterator failed", "error", err)
-		return nil, err
-	}
+		gatewayOpts, active := gatewayFilterOpts(opts, l.l2Contracts.activationBlocks, gateway.address)
+		if !active {
+			continue
+		}
 
-	withdrawWrapIter := types.WrapIterator{
-		Iter:      withdrawIter,
-		EventType: types.L2WithdrawERC1155,
-	}
-	iterators = append(iterators, withdrawWrapIter)
+		// withdraw
+		withdrawIter, err := erc1155Gateway.FilterWithdrawERC1155(gatewayOpts, nil, nil, nil)
+		if err != nil {
+			log.Error("get erc1155 gateway withdraw iterator failed", "name", gateway.name, "address", gateway.address, "error", err)
+			return nil, err
+		}
 
-	// batch withdraw
-	batchWithdrawIter, err := l.l2Contracts.ERC1155Gateway.FilterBatchWithdrawERC1155(opts, nil, nil, nil)
-	if err != nil {
-		log.Error("get erc1155 gateway batch withdraw iterator failed", "error", err)
-		return nil, err
-	}
+		withdrawWrapIter := types.WrapIterator{
+			Iter:      withdrawIter,
+			EventType: types.L2WithdrawERC1155,
+		}
+		iterators = append(iterators, withdrawWrapIter)
 
-	batchWithdrawWrapIter := types.WrapIterator{
-		Iter:      batchWithdrawIter,
-		EventType: types.L2BatchWithdrawERC1155,
-	}
-	iterators = append(iterators, batchWithdrawWrapIter)
+		// batch withdraw
+		batchWithdrawIter, err := erc1155Gateway.FilterBatchWithdrawERC1155(gatewayOpts, nil, nil, nil)
+		if err != nil {
+			log.Error("get erc1155 gateway batch withdraw iterator failed", "name", gateway.name, "address", gateway.address, "error", err)
+			return nil, err
+		}
 
-	// finalizeDeposit
-	finalizeDepositIter, err := l.l2Contracts.ERC1155Gateway.FilterFinalizeDepositERC1155(opts, nil, nil, nil)
-	if err != nil {
-		log.Error("get erc1155 gateway finalize deposit iterator failed", "error", err)
-		return nil, err
-	}
+		batchWithdrawWrapIter := types.WrapIterator{
+			Iter:      batchWithdrawIter,
+			EventType: types.L2BatchWithdrawERC1155,
+		}
+		iterators = append(iterators, batchWithdrawWrapIter)
 
-	finalizeWithdrawWrapIter := types.WrapIterator{
-		Iter:      finalizeDepositIter,
-		EventType: types.L2FinalizeDepositERC1155,
-	}
-	iterators = append(iterators, finalizeWithdrawWrapIter)
+		// finalizeDeposit
+		finalizeDepositIter, err := erc1155Gateway.FilterFinalizeDepositERC1155(gatewayOpts, nil, nil, nil)
+		if err != nil {
+			log.Error("get erc1155 gateway finalize deposit iterator failed", "name", gateway.name, "address", gateway.address, "error", err)
+			return nil, err
+		}
 
-	// batch finalize Deposit
-	batchFinalizeDepositIter, err := l.l2Contracts.ERC1155Gateway.FilterFinalizeBatchDepositERC1155(opts, nil, nil, nil)
-	if err != nil {
-		log.Error("get erc1155 gateway finalize batch deposit iterator failed", "error", err)
-		return nil, err
-	}
+		finalizeWithdrawWrapIter := types.WrapIterator{
+			Iter:      finalizeDepositIter,
+			EventType: types.L2FinalizeDepositERC1155,
+		}
+		iterators = append(iterators, finalizeWithdrawWrapIter)
 
-	batchFinalizeWithdrawWrapIter := types.WrapIterator{
-		Iter:      batchFinalizeDepositIter,
-		EventType: types.L2FinalizeBatchDepositERC1155,
-	}
-	iterators = append(iterators, batchFinalizeWithdrawWrapIter)
+		// batch finalize Deposit
+		batchFinalizeDepositIter, err := erc1155Gateway.FilterFinalizeBatchDepositERC1155(gatewayOpts, nil, nil, nil)
+		if err != nil {
+			log.Error("get erc1155 gateway finalize batch deposit iterator failed", "name", gateway.name, "address", gateway.address, "error", err)
+			return nil, err
+		}
 
+		batchFinalizeWithdrawWrapIter := types.WrapIterator{
+			Iter:      batchFinalizeDepositIter,
+			EventType: types.L2FinalizeBatchDepositERC1155,
+		}
+		iterators = append(iterators, batchFinalizeWithdrawWrapIter)
+	}
 	return iterators, nil
 }
 
@@ -189,6 +214,7 @@ func (l *Contracts) getL1Erc1155GatewayTransferSingle(ctx context.Context, start
 		return nil, err
 	}
 
+	gatewayAddressMap := gatewayAddresses(l.l1Contracts.erc1155GatewayMappings)
 	var transferEvents []events.EventUnmarshaler
 	for _, vLog := range logs {
 		event := iscrollerc1155.Iscrollerc1155TransferSingle{}
@@ -197,7 +223,7 @@ func (l *Contracts) getL1Erc1155GatewayTransferSingle(ctx context.Context, start
 			continue
 		}
 
-		if l.l1Contracts.ERC1155GatewayAddress == event.From {
+		if _, ok := gatewayAddressMap[event.From]; ok {
 			transferEvents = append(transferEvents, &events.ERC1155GatewayEventUnmarshaler{
 				TokenIds:     []*big.Int{event.Id},
 				Amounts:      []*big.Int{new(big.Int).Neg(event.Value)},
@@ -208,7 +234,7 @@ func (l *Contracts) getL1Erc1155GatewayTransferSingle(ctx context.Context, start
 			})
 		}
 
-		if l.l1Contracts.ERC1155GatewayAddress == event.To {
+		if _, ok := gatewayAddressMap[event.To]; ok {
 			transferEvents = append(transferEvents, &events.ERC1155GatewayEventUnmarshaler{
 				TokenIds:     []*big.Int{event.Id},
 				Amounts:      []*big.Int{event.Value},
@@ -239,6 +265,7 @@ func (l *Contracts) getL1Erc1155GatewayTransferBatch(ctx context.Context, startB
 		return nil, err
 	}
 
+	gatewayAddressMap := gatewayAddresses(l.l1Contracts.erc1155GatewayMappings)
 	var transferEvents []events.EventUnmarshaler
 	for _, vLog := range logs {
 		event := iscrollerc1155.Iscrollerc1155TransferBatch{}
@@ -247,7 +274,7 @@ func (l *Contracts) getL1Erc1155GatewayTransferBatch(ctx context.Context, startB
 			continue
 		}
 
-		if l.l1Contracts.ERC1155GatewayAddress == event.From {
+		if _, ok := gatewayAddressMap[event.From]; ok {
 			var tmpValues []*big.Int
 			for _, v := range event.Values {
 				tmpValues = append(tmpValues, new(big.Int).Neg(v))
@@ -262,7 +289,7 @@ func (l *Contracts) getL1Erc1155GatewayTransferBatch(ctx context.Context, startB
 			})
 		}
 
-		if l.l1Contracts.ERC1155GatewayAddress == event.To {
+		if _, ok := gatewayAddressMap[event.To]; ok {
 			transferEvents = append(transferEvents, &events.ERC1155GatewayEventUnmarshaler{
 				TokenIds:     event.Ids,
 				Amounts:      event.Values,
@@ -359,6 +386,7 @@ func (l *Contracts) getL2Erc1155GatewayTransferBatch(ctx context.Context, startB
 		return nil, err
 	}
 
+	gatewayAddressMap := gatewayAddresses(l.l2Contracts.erc1155GatewayMappings)
 	var transferEvents []events.EventUnmarshaler
 	for _, vLog := range logs {
 		event := iscrollerc1155.Iscrollerc1155TransferBatch{}
@@ -367,7 +395,7 @@ func (l *Contracts) getL2Erc1155GatewayTransferBatch(ctx context.Context, startB
 			continue
 		}
 
-		if event.From == l.l2Contracts.ERC1155GatewayAddress {
+		if _, ok := gatewayAddressMap[event.From]; ok {
 			var tmpValues []*big.Int
 			for _, v := range event.Values {
 				tmpValues = append(tmpValues, new(big.Int).Neg(v))
@@ -382,7 +410,7 @@ func (l *Contracts) getL2Erc1155GatewayTransferBatch(ctx context.Context, startB
 			})
 		}
 
-		if event.To == l.l2Contracts.ERC1155GatewayAddress {
+		if _, ok := gatewayAddressMap[event.To]; ok {
 			transferEvents = append(transferEvents, &events.ERC1155GatewayEventUnmarshaler{
 				TokenIds:     event.Ids,
 				Amounts:      event.Values,
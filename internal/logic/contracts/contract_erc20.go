@@ -20,17 +20,22 @@ func (l *Contracts) l1Erc20Filter(_ context.Context, opts *bind.FilterOpts) ([]t
 	var iterators []types.WrapIterator
 	erc20TokenList := l.l1Contracts.erc20GatewayTokens
 	for _, erc20Token := range erc20TokenList {
-		gatewayFilter, filterExist := l.l1Contracts.erc20Gateways[erc20Token.tokenType]
+		gatewayFilter, filterExist := l.l1Contracts.erc20Gateways[erc20Token.name]
 		if !filterExist {
-			err := fmt.Errorf("can't get erc20 filter failed, erc20Token type:%v, address:%v", erc20Token.tokenType, erc20Token.address)
+			err := fmt.Errorf("can't get erc20 filter failed, erc20Token name:%v, address:%v", erc20Token.name, erc20Token.address)
 			log.Error("get erc20 event filter from l1 contracts failed", "err", err)
 			return nil, err
 		}
 
+		gatewayOpts, active := gatewayFilterOpts(opts, l.l1Contracts.activationBlocks, erc20Token.address)
+		if !active {
+			continue
+		}
+
 		// deposit
-		depositIter, err := gatewayFilter.FilterDepositERC20(opts, nil, nil, nil)
+		depositIter, err := gatewayFilter.FilterDepositERC20(gatewayOpts, nil, nil, nil)
 		if err != nil {
-			log.Error("get erc20 gateway deposit iterator failed", "token type", erc20Token.tokenType, "address", erc20Token.address, "error", err)
+			log.Error("get erc20 gateway deposit iterator failed", "name", erc20Token.name, "address", erc20Token.address, "error", err)
 			return nil, err
 		}
 
@@ -41,9 +46,9 @@ func (l *Contracts) l1Erc20Filter(_ context.Context, opts *bind.FilterOpts) ([]t
 		iterators = append(iterators, depositWrapIter)
 
 		// finalizeWithdraw
-		finalizeWithdrawIter, err := gatewayFilter.FilterFinalizeWithdrawERC20(opts, nil, nil, nil)
+		finalizeWithdrawIter, err := gatewayFilter.FilterFinalizeWithdrawERC20(gatewayOpts, nil, nil, nil)
 		if err != nil {
-			log.Error("get erc20 gateway finalizeWithdraw iterator failed", "token type", erc20Token.tokenType, "address", erc20Token.address, "error", err)
+			log.Error("get erc20 gateway finalizeWithdraw iterator failed", "name", erc20Token.name, "address", erc20Token.address, "error", err)
 			return nil, err
 		}
 
@@ -54,9 +59,9 @@ func (l *Contracts) l1Erc20Filter(_ context.Context, opts *bind.FilterOpts) ([]t
 		iterators = append(iterators, finalizeWithdrawWrapIter)
 
 		// refund
-		refundIter, err := gatewayFilter.FilterRefundERC20(opts, nil, nil)
+		refundIter, err := gatewayFilter.FilterRefundERC20(gatewayOpts, nil, nil)
 		if err != nil {
-			log.Error("get erc20 gateway refund iterator failed", "token type", erc20Token.tokenType, "address", erc20Token.address, "error", err)
+			log.Error("get erc20 gateway refund iterator failed", "name", erc20Token.name, "address", erc20Token.address, "error", err)
 			return nil, err
 		}
 
@@ -74,17 +79,22 @@ func (l *Contracts) l2Erc20Filter(_ context.Context, opts *bind.FilterOpts) ([]t
 	erc20TokenList := l.l2Contracts.erc20GatewayTokens
 
 	for _, erc20Token := range erc20TokenList {
-		gatewayFilter, filterExist := l.l2Contracts.erc20Gateways[erc20Token.tokenType]
+		gatewayFilter, filterExist := l.l2Contracts.erc20Gateways[erc20Token.name]
 		if !filterExist {
-			err := fmt.Errorf("can't get erc20 filter failed, erc20Token type:%v, address:%v", erc20Token.tokenType, erc20Token.address)
+			err := fmt.Errorf("can't get erc20 filter failed, erc20Token name:%v, address:%v", erc20Token.name, erc20Token.address)
 			log.Error("get erc20 event filter from l1 contracts failed", "err", err)
 			return nil, err
 		}
 
+		gatewayOpts, active := gatewayFilterOpts(opts, l.l2Contracts.activationBlocks, erc20Token.address)
+		if !active {
+			continue
+		}
+
 		// withdraw
-		depositIter, err := gatewayFilter.FilterWithdrawERC20(opts, nil, nil, nil)
+		depositIter, err := gatewayFilter.FilterWithdrawERC20(gatewayOpts, nil, nil, nil)
 		if err != nil {
-			log.Error("get erc20 gateway deposit iterator failed", "token type", erc20Token.tokenType, "address", erc20Token.address, "error", err)
+			log.Error("get erc20 gateway deposit iterator failed", "name", erc20Token.name, "address", erc20Token.address, "error", err)
 			return nil, err
 		}
 
@@ -95,9 +105,9 @@ func (l *Contracts) l2Erc20Filter(_ context.Context, opts *bind.FilterOpts) ([]t
 		iterators = append(iterators, depositWrapIter)
 
 		// finalizeDeposit
-		finalizeWithdrawIter, err := gatewayFilter.FilterFinalizeDepositERC20(opts, nil, nil, nil)
+		finalizeWithdrawIter, err := gatewayFilter.FilterFinalizeDepositERC20(gatewayOpts, nil, nil, nil)
 		if err != nil {
-			log.Error("get erc20 gateway finalizeWithdraw iterator failed", "token type", erc20Token.tokenType, "address", erc20Token.address, "error", err)
+			log.Error("get erc20 gateway finalizeWithdraw iterator failed", "name", erc20Token.name, "address", erc20Token.address, "error", err)
 			return nil, err
 		}
 
@@ -126,10 +136,7 @@ func (l *Contracts) getL1Erc20GatewayTransfer(ctx context.Context, startBlockNum
 		return nil, err
 	}
 
-	tokenAddressMap := make(map[common.Address]struct{})
-	for _, token := range l.l1Contracts.erc20GatewayTokens {
-		tokenAddressMap[token.address] = struct{}{}
-	}
+	tokenAddressMap := gatewayAddresses(l.l1Contracts.erc20GatewayTokens)
 
 	var transferEvents []events.EventUnmarshaler
 	for _, vLog := range logs {
@@ -2,6 +2,7 @@ package contracts
 
 import (
 	"context"
+	"fmt"
 	"math/big"
 
 	"github.com/scroll-tech/go-ethereum"
@@ -17,143 +18,167 @@ import (
 
 func (l *Contracts) l1Erc721Filter(_ context.Context, opts *bind.FilterOpts) ([]types.WrapIterator, error) {
 	var iterators []types.WrapIterator
+	for _, gateway := range l.l1Contracts.erc721GatewayMappings {
+		erc721Gateway, exist := l.l1Contracts.erc721Gateways[gateway.name]
+		if !exist {
+			err := fmt.Errorf("can't get erc721 filter failed, gateway name:%v, address:%v", gateway.name, gateway.address)
+			log.Error("get erc721 event filter from l1 contracts failed", "err", err)
+			return nil, err
+		}
 
-	// deposit
-	depositIter, err := l.l1Contracts.erc721Gateway.FilterDepositERC721(opts, nil, nil, nil)
-	if err != nil {
-		log.Error("get erc721 gateway deposit iterator failed", "error", err)
-		return nil, err
-	}
+		gatewayOpts, active := gatewayFilterOpts(opts, l.l1Contracts.activationBlocks, gateway.address)
+		if !active {
+			continue
+		}
 
-	depositWrapIter := types.WrapIterator{
-		Iter:      depositIter,
-		EventType: types.L1DepositERC721,
-	}
-	iterators = append(iterators, depositWrapIter)
+		// deposit
+		depositIter, err := erc721Gateway.FilterDepositERC721(gatewayOpts, nil, nil, nil)
+		if err != nil {
+			log.Error("get erc721 gateway deposit iterator failed", "name", gateway.name, "address", gateway.address, "error", err)
+			return nil, err
+		}
 
-	// batch deposit
-	batchDepositIter, err := l.l1Contracts.erc721Gateway.FilterBatchDepositERC721(opts, nil, nil, nil)
-	if err != nil {
-		log.Error("get erc721 gateway batch deposit iterator failed", "error", err)
-		return nil, err
-	}
+		depositWrapIter := types.WrapIterator{
+			Iter:      depositIter,
+			EventType: types.L1DepositERC721,
+		}
+		iterators = append(iterators, depositWrapIter)
 
-	batchDepositWrapIter := types.WrapIterator{
-		Iter:      batchDepositIter,
-		EventType: types.L1BatchDepositERC721,
-	}
-	iterators = append(iterators, batchDepositWrapIter)
+		// batch deposit
+		batchDepositIter, err := erc721Gateway.FilterBatchDepositERC721(gatewayOpts, nil, nil, nil)
+		if err != nil {
+			log.Error("get erc721 gateway batch deposit iterator failed", "name", gateway.name, "address", gateway.address, "error", err)
+			return nil, err
+		}
 
-	// finalizeWithdraw
-	finalizeWithdrawIter, err := l.l1Contracts.erc721Gateway.FilterFinalizeWithdrawERC721(opts, nil, nil, nil)
-	if err != nil {
-		log.Error("get erc721 gateway finalizeWithdraw iterator failed", "error", err)
-		return nil, err
-	}
+		batchDepositWrapIter := types.WrapIterator{
+			Iter:      batchDepositIter,
+			EventType: types.L1BatchDepositERC721,
+		}
+		iterators = append(iterators, batchDepositWrapIter)
 
-	finalizeWithdrawWrapIter := types.WrapIterator{
-		Iter:      finalizeWithdrawIter,
-		EventType: types.L1FinalizeWithdrawERC721,
-	}
-	iterators = append(iterators, finalizeWithdrawWrapIter)
+		// finalizeWithdraw
+		finalizeWithdrawIter, err := erc721Gateway.FilterFinalizeWithdrawERC721(gatewayOpts, nil, nil, nil)
+		if err != nil {
+			log.Error("get erc721 gateway finalizeWithdraw iterator failed", "name", gateway.name, "address", gateway.address, "error", err)
+			return nil, err
+		}
 
-	// finalize batch Withdraw
-	finalizeBatchWithdrawIter, err := l.l1Contracts.erc721Gateway.FilterFinalizeBatchWithdrawERC721(opts, nil, nil, nil)
-	if err != nil {
-		log.Error("get erc721 gateway finalizeWithdraw iterator failed", "error", err)
-		return nil, err
-	}
+		finalizeWithdrawWrapIter := types.WrapIterator{
+			Iter:      finalizeWithdrawIter,
+			EventType: types.L1FinalizeWithdrawERC721,
+		}
+		iterators = append(iterators, finalizeWithdrawWrapIter)
 
-	finalizeBatchWithdrawWrapIter := types.WrapIterator{
-		Iter:      finalizeBatchWithdrawIter,
-		EventType: types.L1FinalizeBatchWithdrawERC721,
-	}
-	iterators = append(iterators, finalizeBatchWithdrawWrapIter)
+		// finalize batch Withdraw
+		finalizeBatchWithdrawIter, err := erc721Gateway.FilterFinalizeBatchWithdrawERC721(gatewayOpts, nil, nil, nil)
+		if err != nil {
+			log.Error("get erc721 gateway finalizeWithdraw iterator failed", "name", gateway.name, "address", gateway.address, "error", err)
+			return nil, err
+		}
 
-	// refund
-	refundIter, err := l.l1Contracts.erc721Gateway.FilterRefundERC721(opts, nil, nil)
-	if err != nil {
-		log.Error("get erc721 gateway refund iterator failed", "error", err)
-		return nil, err
-	}
+		finalizeBatchWithdrawWrapIter := types.WrapIterator{
+			Iter:      finalizeBatchWithdrawIter,
+			EventType: types.L1FinalizeBatchWithdrawERC721,
+		}
+		iterators = append(iterators, finalizeBatchWithdrawWrapIter)
 
-	refundWrapIter := types.WrapIterator{
-		Iter:      refundIter,
-		EventType: types.L1RefundERC721,
-	}
-	iterators = append(iterators, refundWrapIter)
+		// refund
+		refundIter, err := erc721Gateway.FilterRefundERC721(gatewayOpts, nil, nil)
+		if err != nil {
+			log.Error("get erc721 gateway refund iterator failed", "name", gateway.name, "address", gateway.address, "error", err)
+			return nil, err
+		}
 
-	// batch refund
-	batchRefundIter, err := l.l1Contracts.erc721Gateway.FilterBatchRefundERC721(opts, nil, nil)
-	if err != nil {
-		log.Error("get erc721 gateway refund iterator failed", "error", err)
-		return nil, err
-	}
+		refundWrapIter := types.WrapIterator{
+			Iter:      refundIter,
+			EventType: types.L1RefundERC721,
+		}
+		iterators = append(iterators, refundWrapIter)
 
-	batchRefundWrapIter := types.WrapIterator{
-		Iter:      batchRefundIter,
-		EventType: types.L1BatchRefundERC721,
-	}
-	iterators = append(iterators, batchRefundWrapIter)
+		// batch refund
+		batchRefundIter, err := erc721Gateway.FilterBatchRefundERC721(gatewayOpts, nil, nil)
+		if err != nil {
+			log.Error("get erc721 gateway refund iterator failed", "name", gateway.name, "address", gateway.address, "error", err)
+			return nil, err
+		}
 
+		batchRefundWrapIter := types.WrapIterator{
+			Iter:      batchRefundIter,
+			EventType: types.L1BatchRefundERC721,
+		}
+		iterators = append(iterators, batchRefundWrapIter)
+	}
 	return iterators, nil
 }
 
 func (l *Contracts) l2Erc721Filter(_ context.Context, opts *bind.FilterOpts) ([]types.WrapIterator, error) {
 	var iterators []types.WrapIterator
+	for _, gateway := range l.l2Contracts.erc721GatewayMappings {
+		erc721Gateway, exist := l.l2Contracts.erc721Gateways[gateway.name]
+		if !exist {
+			err := fmt.Errorf("can't get erc721 filter failed, gateway name:%v, address:%v", gateway.name, gateway.address)
+			log.Error("get erc721 event filter from l2 contracts failed", "err", err)
+			return nil, err
+		}
 
-	// withdraw
-	withdrawIter, err := l.l2Contracts.erc721Gateway.FilterWithdrawERC721(opts, nil, nil, nil)
-	if err != nil {
-		log.Error("get erc721 gateway withdraw iterator failed", "error", err)
-		return nil, err
-	}
+		gatewayOpts, active := gatewayFilterOpts(opts, l.l2Contracts.activationBlocks, gateway.address)
+		if !active {
+			continue
+		}
 
-	withdrawWrapIter := types.WrapIterator{
-		Iter:      withdrawIter,
-		EventType: types.L2WithdrawERC721,
-	}
-	iterators = append(iterators, withdrawWrapIter)
+		// withdraw
+		withdrawIter, err := erc721Gateway.FilterWithdrawERC721(gatewayOpts, nil, nil, nil)
+		if err != nil {
+			log.Error("get erc721 gateway withdraw iterator failed", "name", gateway.name, "address", gateway.address, "error", err)
+			return nil, err
+		}
 
-	// batch withdraw
-	batchWithdrawIter, err := l.l2Contracts.erc721Gateway.FilterBatchWithdrawERC721(opts, nil, nil, nil)
-	if err != nil {
-		log.Error("get erc721 gateway batch withdraw iterator failed", "error", err)
-		return nil, err
-	}
+		withdrawWrapIter := types.WrapIterator{
+			Iter:      withdrawIter,
+			EventType: types.L2WithdrawERC721,
+		}
+		iterators = append(iterators, withdrawWrapIter)
 
-	batchWithdrawWrapIter := types.WrapIterator{
-		Iter:      batchWithdrawIter,
-		EventType: types.L2BatchWithdrawERC721,
-	}
-	iterators = append(iterators, batchWithdrawWrapIter)
+		// batch withdraw
+		batchWithdrawIter, err := erc721Gateway.FilterBatchWithdrawERC721(gatewayOpts, nil, nil, nil)
+		if err != nil {
+			log.Error("get erc721 gateway batch withdraw iterator failed", "name", gateway.name, "address", gateway.address, "error", err)
+			return nil, err
+		}
 
-	// finalizeDeposit
-	finalizeDepositIter, err := l.l2Contracts.erc721Gateway.FilterFinalizeDepositERC721(opts, nil, nil, nil)
-	if err != nil {
-		log.Error("get erc721 gateway finalize deposit iterator failed", "error", err)
-		return nil, err
-	}
+		batchWithdrawWrapIter := types.WrapIterator{
+			Iter:      batchWithdrawIter,
+			EventType: types.L2BatchWithdrawERC721,
+		}
+		iterators = append(iterators, batchWithdrawWrapIter)
 
-	finalizeWithdrawWrapIter := types.WrapIterator{
-		Iter:      finalizeDepositIter,
-		EventType: types.L2FinalizeDepositERC721,
-	}
-	iterators = append(iterators, finalizeWithdrawWrapIter)
+		// finalizeDeposit
+		finalizeDepositIter, err := erc721Gateway.FilterFinalizeDepositERC721(gatewayOpts, nil, nil, nil)
+		if err != nil {
+			log.Error("get erc721 gateway finalize deposit iterator failed", "name", gateway.name, "address", gateway.address, "error", err)
+			return nil, err
+		}
 
-	// batch finalize Deposit
-	batchFinalizeDepositIter, err := l.l2Contracts.erc721Gateway.FilterFinalizeBatchDepositERC721(opts, nil, nil, nil)
-	if err != nil {
-		log.Error("get erc721 gateway finalize batch deposit iterator failed", "error", err)
-		return nil, err
-	}
+		finalizeWithdrawWrapIter := types.WrapIterator{
+			Iter:      finalizeDepositIter,
+			EventType: types.L2FinalizeDepositERC721,
+		}
+		iterators = append(iterators, finalizeWithdrawWrapIter)
 
-	batchFinalizeWithdrawWrapIter := types.WrapIterator{
-		Iter:      batchFinalizeDepositIter,
-		EventType: types.L2FinalizeBatchDepositERC721,
-	}
-	iterators = append(iterators, batchFinalizeWithdrawWrapIter)
+		// batch finalize Deposit
+		batchFinalizeDepositIter, err := erc721Gateway.FilterFinalizeBatchDepositERC721(gatewayOpts, nil, nil, nil)
+		if err != nil {
+			log.Error("get erc721 gateway finalize batch deposit iterator failed", "name", gateway.name, "address", gateway.address, "error", err)
+			return nil, err
+		}
 
+		batchFinalizeWithdrawWrapIter := types.WrapIterator{
+			Iter:      batchFinalizeDepositIter,
+			EventType: types.L2FinalizeBatchDepositERC721,
+		}
+		iterators = append(iterators, batchFinalizeWithdrawWrapIter)
+	}
 	return iterators, nil
 }
 
@@ -173,6 +198,7 @@ func (l *Contracts) getL1Erc721GatewayTransfer(ctx context.Context, startBlockNu
 		return nil, err
 	}
 
+	gatewayAddressMap := gatewayAddresses(l.l1Contracts.erc721GatewayMappings)
 	var transferEvents []events.EventUnmarshaler
 	for _, vLog := range logs {
 		event := iscrollerc721.Iscrollerc721Transfer{}
@@ -181,7 +207,7 @@ func (l *Contracts) getL1Erc721GatewayTransfer(ctx context.Context, startBlockNu
 			continue
 		}
 
-		if l.l1Contracts.erc721GatewayAddress == event.From {
+		if _, ok := gatewayAddressMap[event.From]; ok {
 			transferEvents = append(transferEvents, &events.ERC721GatewayEventUnmarshaler{
 				TokenIds:     []*big.Int{event.TokenId},
 				Amounts:      []*big.Int{new(big.Int).Neg(big.NewInt(1))},
@@ -192,7 +218,7 @@ func (l *Contracts) getL1Erc721GatewayTransfer(ctx context.Context, startBlockNu
 			})
 		}
 
-		if l.l1Contracts.erc721GatewayAddress == event.To {
+		if _, ok := gatewayAddressMap[event.To]; ok {
 			transferEvents = append(transferEvents, &events.ERC721GatewayEventUnmarshaler{
 				TokenIds:     []*big.Int{event.TokenId},
 				Amounts:      []*big.Int{big.NewInt(1)},
@@ -0,0 +1,112 @@
+package contracts
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/scroll-tech/go-ethereum/accounts/abi/bind"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"github.com/scroll-tech/chain-monitor/internal/types"
+)
+
+func (l *Contracts) l1ETHFilter(_ context.Context, opts *bind.FilterOpts) ([]types.WrapIterator, error) {
+	var iterators []types.WrapIterator
+	for _, gateway := range l.l1Contracts.ethGatewayMappings {
+		ethGateway, exist := l.l1Contracts.ethGateways[gateway.name]
+		if !exist {
+			err := fmt.Errorf("can't get eth filter failed, gateway name:%v, address:%v", gateway.name, gateway.address)
+			log.Error("get eth event filter from l1 contracts failed", "err", err)
+			return nil, err
+		}
+
+		gatewayOpts, active := gatewayFilterOpts(opts, l.l1Contracts.activationBlocks, gateway.address)
+		if !active {
+			continue
+		}
+
+		// deposit
+		depositIter, err := ethGateway.FilterDepositETH(gatewayOpts, nil, nil)
+		if err != nil {
+			log.Error("get eth gateway deposit iterator failed", "name", gateway.name, "address", gateway.address, "error", err)
+			return nil, err
+		}
+
+		depositWrapIter := types.WrapIterator{
+			Iter:      depositIter,
+			EventType: types.L1DepositETH,
+		}
+		iterators = append(iterators, depositWrapIter)
+
+		// finalizeWithdraw
+		finalizeWithdrawIter, err := ethGateway.FilterFinalizeWithdrawETH(gatewayOpts, nil, nil)
+		if err != nil {
+			log.Error("get eth gateway finalizeWithdraw iterator failed", "name", gateway.name, "address", gateway.address, "error", err)
+			return nil, err
+		}
+
+		finalizeWithdrawWrapIter := types.WrapIterator{
+			Iter:      finalizeWithdrawIter,
+			EventType: types.L1FinalizeWithdrawETH,
+		}
+		iterators = append(iterators, finalizeWithdrawWrapIter)
+
+		// refund
+		refundIter, err := ethGateway.FilterRefundETH(gatewayOpts, nil)
+		if err != nil {
+			log.Error("get eth gateway refund iterator failed", "name", gateway.name, "address", gateway.address, "error", err)
+			return nil, err
+		}
+
+		refundWrapIter := types.WrapIterator{
+			Iter:      refundIter,
+			EventType: types.L1RefundETH,
+		}
+		iterators = append(iterators, refundWrapIter)
+	}
+	return iterators, nil
+}
+
+func (l *Contracts) l2ETHFilter(_ context.Context, opts *bind.FilterOpts) ([]types.WrapIterator, error) {
+	var iterators []types.WrapIterator
+	for _, gateway := range l.l2Contracts.ethGatewayMappings {
+		ethGateway, exist := l.l2Contracts.ethGateways[gateway.name]
+		if !exist {
+			err := fmt.Errorf("can't get eth filter failed, gateway name:%v, address:%v", gateway.name, gateway.address)
+			log.Error("get eth event filter from l2 contracts failed", "err", err)
+			return nil, err
+		}
+
+		gatewayOpts, active := gatewayFilterOpts(opts, l.l2Contracts.activationBlocks, gateway.address)
+		if !active {
+			continue
+		}
+
+		// withdraw
+		withdrawIter, err := ethGateway.FilterWithdrawETH(gatewayOpts, nil, nil)
+		if err != nil {
+			log.Error("get eth gateway withdraw iterator failed", "name", gateway.name, "address", gateway.address, "error", err)
+			return nil, err
+		}
+
+		withdrawWrapIter := types.WrapIterator{
+			Iter:      withdrawIter,
+			EventType: types.L2WithdrawETH,
+		}
+		iterators = append(iterators, withdrawWrapIter)
+
+		// finalizeDeposit
+		finalizeDepositIter, err := ethGateway.FilterFinalizeDepositETH(gatewayOpts, nil, nil)
+		if err != nil {
+			log.Error("get eth gateway finalizeDeposit iterator failed", "name", gateway.name, "address", gateway.address, "error", err)
+			return nil, err
+		}
+
+		finalizeDepositWrapIter := types.WrapIterator{
+			Iter:      finalizeDepositIter,
+			EventType: types.L2FinalizeDepositETH,
+		}
+		iterators = append(iterators, finalizeDepositWrapIter)
+	}
+	return iterators, nil
+}
@@ -0,0 +1,54 @@
+package contracts
+
+import (
+	"context"
+
+	"github.com/scroll-tech/go-ethereum/accounts/abi/bind"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"github.com/scroll-tech/chain-monitor/internal/types"
+)
+
+func (l *Contracts) l1MessageQueueFilter(_ context.Context, opts *bind.FilterOpts) ([]types.WrapIterator, error) {
+	if l.l1Contracts.messageQueue == nil {
+		return nil, nil
+	}
+
+	var iterators []types.WrapIterator
+	queueTransactionIter, err := l.l1Contracts.messageQueue.FilterQueueTransaction(opts, nil, nil)
+	if err != nil {
+		log.Error("get message queue queueTransaction iterator failed", "address", l.l1Contracts.messageQueueAddress, "error", err)
+		return nil, err
+	}
+
+	queueTransactionWrapIter := types.WrapIterator{
+		Iter:      queueTransactionIter,
+		EventType: types.L1QueueTransaction,
+	}
+	iterators = append(iterators, queueTransactionWrapIter)
+
+	dequeueTransactionIter, err := l.l1Contracts.messageQueue.FilterDequeueTransaction(opts)
+	if err != nil {
+		log.Error("get message queue dequeueTransaction iterator failed", "address", l.l1Contracts.messageQueueAddress, "error", err)
+		return nil, err
+	}
+
+	dequeueTransactionWrapIter := types.WrapIterator{
+		Iter:      dequeueTransactionIter,
+		EventType: types.L1DequeueTransaction,
+	}
+	iterators = append(iterators, dequeueTransactionWrapIter)
+
+	dropTransactionIter, err := l.l1Contracts.messageQueue.FilterDropTransaction(opts)
+	if err != nil {
+		log.Error("get message queue dropTransaction iterator failed", "address", l.l1Contracts.messageQueueAddress, "error", err)
+		return nil, err
+	}
+
+	dropTransactionWrapIter := types.WrapIterator{
+		Iter:      dropTransactionIter,
+		EventType: types.L1DropTransaction,
+	}
+	iterators = append(iterators, dropTransactionWrapIter)
+	return iterators, nil
+}
@@ -34,6 +34,18 @@ func (l *Contracts) l1MessengerFilter(_ context.Context, opts *bind.FilterOpts)
 		EventType: types.L1RelayedMessage,
 	}
 	iterators = append(iterators, relayedMessageWrapIter)
+
+	failedRelayedMessageIter, err := l.l1Contracts.messenger.FilterFailedRelayedMessage(opts, nil)
+	if err != nil {
+		log.Error("get messenger failedRelayedMessage iterator failed", "error", err)
+		return nil, err
+	}
+
+	failedRelayedMessageWrapIter := types.WrapIterator{
+		Iter:      failedRelayedMessageIter,
+		EventType: types.L1FailedRelayedMessage,
+	}
+	iterators = append(iterators, failedRelayedMessageWrapIter)
 	return iterators, nil
 }
 
@@ -62,5 +74,17 @@ func (l *Contracts) l2MessengerFilter(_ context.Context, opts *bind.FilterOpts)
 		EventType: types.L2RelayedMessage,
 	}
 	iterators = append(iterators, relayedMessageWrapIter)
+
+	failedRelayedMessageIter, err := l.l2Contracts.messenger.FilterFailedRelayedMessage(opts, nil)
+	if err != nil {
+		log.Error("get messenger failedRelayedMessage iterator failed", "error", err)
+		return nil, err
+	}
+
+	failedRelayedMessageWrapIter := types.WrapIterator{
+		Iter:      failedRelayedMessageIter,
+		EventType: types.L2FailedRelayedMessage,
+	}
+	iterators = append(iterators, failedRelayedMessageWrapIter)
 	return iterators, nil
 }
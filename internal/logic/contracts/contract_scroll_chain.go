@@ -0,0 +1,192 @@
+package contracts
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/scroll-tech/go-ethereum/accounts/abi"
+	"github.com/scroll-tech/go-ethereum/accounts/abi/bind"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"github.com/scroll-tech/chain-monitor/internal/types"
+)
+
+const (
+	// the size of the block context in the encoded chunk, the block number is the first 8 bytes of it.
+	blockContextSize = 60
+	// the parent batch header starts with the version (1 byte) and the batch index (8 bytes).
+	parentBatchHeaderMinSize = 9
+
+	// commitBatchMethods the commit methods of the scroll chain versions, the generated binding only knows the first one.
+	commitBatchMethods = `[
+	{"type":"function","name":"commitBatch","inputs":[{"name":"version","type":"uint8"},{"name":"parentBatchHeader","type":"bytes"},{"name":"chunks","type":"bytes[]"},{"name":"skippedL1MessageBitmap","type":"bytes"}],"outputs":[]},
+	{"type":"function","name":"commitBatchWithBlobProof","inputs":[{"name":"version","type":"uint8"},{"name":"parentBatchHeader","type":"bytes"},{"name":"chunks","type":"bytes[]"},{"name":"skippedL1MessageBitmap","type":"bytes"},{"name":"blobDataProof","type":"bytes"}],"outputs":[]},
+	{"type":"function","name":"commitBatches","inputs":[{"name":"version","type":"uint8"},{"name":"parentBatchHash","type":"bytes32"},{"name":"lastBatchHash","type":"bytes32"}],"outputs":[]}
+]`
+)
+
+// ErrUnknownCommitBatchCalldata the block range of the batch can't be decoded from the calldata of the commit tx.
+var ErrUnknownCommitBatchCalldata = errors.New("unknown commit batch calldata")
+
+var commitBatchABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(commitBatchMethods))
+	if err != nil {
+		panic(fmt.Sprintf("parse commit batch abi failed, err: %v", err))
+	}
+	return parsed
+}()
+
+func (l *Contracts) l1ScrollChainFilter(_ context.Context, opts *bind.FilterOpts) ([]types.WrapIterator, error) {
+	if l.l1Contracts.scrollChain == nil {
+		return nil, nil
+	}
+
+	var iterators []types.WrapIterator
+	commitBatchIter, err := l.l1Contracts.scrollChain.FilterCommitBatch(opts, nil, nil)
+	if err != nil {
+		log.Error("get scroll chain commitBatch iterator failed", "address", l.l1Contracts.scrollChainAddress, "error", err)
+		return nil, err
+	}
+
+	commitBatchWrapIter := types.WrapIterator{
+		Iter:      commitBatchIter,
+		EventType: types.L1CommitBatch,
+	}
+	iterators = append(iterators, commitBatchWrapIter)
+
+	finalizeBatchIter, err := l.l1Contracts.scrollChain.FilterFinalizeBatch(opts, nil, nil)
+	if err != nil {
+		log.Error("get scroll chain finalizeBatch iterator failed", "address", l.l1Contracts.scrollChainAddress, "error", err)
+		return nil, err
+	}
+
+	finalizeBatchWrapIter := types.WrapIterator{
+		Iter:      finalizeBatchIter,
+		EventType: types.L1FinalizeBatch,
+	}
+	iterators = append(iterators, finalizeBatchWrapIter)
+
+	revertBatchIter, err := l.l1Contracts.scrollChain.FilterRevertBatch(opts, nil, nil)
+	if err != nil {
+		log.Error("get scroll chain revertBatch iterator failed", "address", l.l1Contracts.scrollChainAddress, "error", err)
+		return nil, err
+	}
+
+	revertBatchWrapIter := types.WrapIterator{
+		Iter:      revertBatchIter,
+		EventType: types.L1RevertBatch,
+	}
+	iterators = append(iterators, revertBatchWrapIter)
+	return iterators, nil
+}
+
+// GetCommitBatchBlockRange decodes the commit calldata of the batch in the commit tx, and returns the first and the last
+// l2 block number of the batch. The commit txs sent by the proxy contracts are traced to find the calls to the scroll chain.
+func (l *Contracts) GetCommitBatchBlockRange(ctx context.Context, txHash common.Hash, batchIndex uint64) (uint64, uint64, error) {
+	tx, _, err := l.l1Contracts.client.TransactionByHash(ctx, txHash)
+	if err != nil {
+		return 0, 0, fmt.Errorf("get commit batch tx failed, tx hash: %v, err: %w", txHash.Hex(), err)
+	}
+
+	if tx.To() != nil && *tx.To() == l.l1Contracts.scrollChainAddress {
+		return decodeCommitBatchBlockRange(tx.Data(), batchIndex)
+	}
+
+	var trace callFrame
+	if err := l.l1Contracts.rpcClient.CallContext(ctx, &trace, "debug_traceTransaction", txHash, map[string]string{"tracer": "callTracer"}); err != nil {
+		return 0, 0, fmt.Errorf("%w: trace the commit tx sent by %v failed, err: %v", ErrUnknownCommitBatchCalldata, tx.To(), err)
+	}
+
+	decodeErr := fmt.Errorf("%w: no call to the scroll chain in the commit tx sent by %v", ErrUnknownCommitBatchCalldata, tx.To())
+	for _, calldata := range trace.callsTo(l.l1Contracts.scrollChainAddress) {
+		startBlockNumber, endBlockNumber, err := decodeCommitBatchBlockRange(calldata, batchIndex)
+		if err == nil {
+			return startBlockNumber, endBlockNumber, nil
+		}
+		decodeErr = err
+	}
+	return 0, 0, decodeErr
+}
+
+// callFrame is a call of the callTracer trace.
+type callFrame struct {
+	To    *common.Address `json:"to"`
+	Input hexutil.Bytes   `json:"input"`
+	Calls []callFrame     `json:"calls"`
+}
+
+// callsTo returns the calldata of the calls to the address in the call tree, in the order they're called.
+func (f *callFrame) callsTo(address common.Address) [][]byte {
+	var calldata [][]byte
+	if f.To != nil && *f.To == address {
+		calldata = append(calldata, f.Input)
+	}
+	for i := range f.Calls {
+		calldata = append(calldata, f.Calls[i].callsTo(address)...)
+	}
+	return calldata
+}
+
+// decodeCommitBatchBlockRange decodes the commitBatch or commitBatchWithBlobProof calldata of the batch. Both encode the
+// block contexts in the chunks, the batch index is the one of the parent batch header plus one. The commitBatches calldata
+// of the later versions only commits the batch hashes, the block range is in the blobs which can't be decoded.
+func decodeCommitBatchBlockRange(calldata []byte, batchIndex uint64) (uint64, uint64, error) {
+	if len(calldata) < 4 {
+		return 0, 0, ErrUnknownCommitBatchCalldata
+	}
+	method, err := commitBatchABI.MethodById(calldata[:4])
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: unknown method %x", ErrUnknownCommitBatchCalldata, calldata[:4])
+	}
+	if method.Name == "commitBatches" {
+		return 0, 0, fmt.Errorf("%w: the block range of %s isn't in the calldata", ErrUnknownCommitBatchCalldata, method.Name)
+	}
+
+	args, err := method.Inputs.Unpack(calldata[4:])
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: unpack %s calldata failed, err: %v", ErrUnknownCommitBatchCalldata, method.Name, err)
+	}
+	parentBatchHeader, ok := args[1].([]byte)
+	if !ok || len(parentBatchHeader) < parentBatchHeaderMinSize {
+		return 0, 0, fmt.Errorf("%w: invalid parent batch header", ErrUnknownCommitBatchCalldata)
+	}
+	if parentBatchIndex := binary.BigEndian.Uint64(parentBatchHeader[1:9]); parentBatchIndex+1 != batchIndex {
+		return 0, 0, fmt.Errorf("%w: the calldata commits batch %d, expected: %d", ErrUnknownCommitBatchCalldata, parentBatchIndex+1, batchIndex)
+	}
+	chunks, ok := args[2].([][]byte)
+	if !ok || len(chunks) == 0 {
+		return 0, 0, fmt.Errorf("%w: %s calldata has no chunks", ErrUnknownCommitBatchCalldata, method.Name)
+	}
+
+	startBlockNumber, _, err := decodeChunkBlockRange(chunks[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: %v", ErrUnknownCommitBatchCalldata, err)
+	}
+	_, endBlockNumber, err := decodeChunkBlockRange(chunks[len(chunks)-1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: %v", ErrUnknownCommitBatchCalldata, err)
+	}
+	return startBlockNumber, endBlockNumber, nil
+}
+
+// decodeChunkBlockRange decodes the chunk, which is encoded as the number of blocks (1 byte) followed by the block contexts.
+func decodeChunkBlockRange(chunk []byte) (uint64, uint64, error) {
+	if len(chunk) < 1 || chunk[0] == 0 {
+		return 0, 0, fmt.Errorf("chunk has no blocks")
+	}
+
+	numBlocks := int(chunk[0])
+	if len(chunk) < 1+numBlocks*blockContextSize {
+		return 0, 0, fmt.Errorf("chunk is too short, num blocks: %d, length: %d", numBlocks, len(chunk))
+	}
+
+	startBlockNumber := binary.BigEndian.Uint64(chunk[1:9])
+	lastBlockContext := 1 + (numBlocks-1)*blockContextSize
+	endBlockNumber := binary.BigEndian.Uint64(chunk[lastBlockContext : lastBlockContext+8])
+	return startBlockNumber, endBlockNumber, nil
+}
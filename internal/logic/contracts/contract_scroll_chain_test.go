@@ -0,0 +1,130 @@
+package contracts
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/iscrollchain"
+)
+
+// encodeChunk encodes the chunk with the block contexts of the block numbers, the other fields of the block context are left zero.
+func encodeChunk(blockNumbers ...uint64) []byte {
+	chunk := make([]byte, 1+len(blockNumbers)*blockContextSize)
+	chunk[0] = byte(len(blockNumbers))
+	for i, blockNumber := range blockNumbers {
+		binary.BigEndian.PutUint64(chunk[1+i*blockContextSize:], blockNumber)
+	}
+	return chunk
+}
+
+// encodeBatchHeader encodes the v0 batch header, which is 89 bytes plus the skipped l1 message bitmap.
+func encodeBatchHeader(batchIndex uint64) []byte {
+	header := make([]byte, 89)
+	binary.BigEndian.PutUint64(header[1:9], batchIndex)
+	return header
+}
+
+func TestDecodeChunkBlockRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		chunk     []byte
+		wantStart uint64
+		wantEnd   uint64
+		wantErr   bool
+	}{
+		{"singleBlock", encodeChunk(100), 100, 100, false},
+		{"multipleBlocks", encodeChunk(100, 101, 102), 100, 102, false},
+		{"withTransactions", append(encodeChunk(7, 8), make([]byte, 300)...), 7, 8, false},
+		{"empty", nil, 0, 0, true},
+		{"noBlocks", []byte{0}, 0, 0, true},
+		{"tooShort", encodeChunk(100, 101)[:100], 0, 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			start, end, err := decodeChunkBlockRange(test.chunk)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.wantStart, start)
+			assert.Equal(t, test.wantEnd, end)
+		})
+	}
+}
+
+func TestDecodeCommitBatchBlockRange(t *testing.T) {
+	scrollChainABI, err := iscrollchain.IscrollchainMetaData.GetAbi()
+	assert.NoError(t, err)
+
+	chunks := [][]byte{encodeChunk(100, 101), encodeChunk(102), encodeChunk(103, 104, 105)}
+	commitBatch, err := scrollChainABI.Pack("commitBatch", uint8(0), encodeBatchHeader(9), chunks, []byte{})
+	assert.NoError(t, err)
+	commitBatchWithBlobProof, err := commitBatchABI.Pack("commitBatchWithBlobProof", uint8(3), encodeBatchHeader(9), chunks, []byte{}, make([]byte, 160))
+	assert.NoError(t, err)
+	commitBatches, err := commitBatchABI.Pack("commitBatches", uint8(7), common.HexToHash("0x1"), common.HexToHash("0x2"))
+	assert.NoError(t, err)
+	shortChunk, err := scrollChainABI.Pack("commitBatch", uint8(0), encodeBatchHeader(9), [][]byte{encodeChunk(100)[:30]}, []byte{})
+	assert.NoError(t, err)
+	noChunks, err := scrollChainABI.Pack("commitBatch", uint8(0), encodeBatchHeader(9), [][]byte{}, []byte{})
+	assert.NoError(t, err)
+	finalizeBatch, err := scrollChainABI.Pack("finalizeBatchWithProof", encodeBatchHeader(10), common.Hash{}, common.Hash{}, common.Hash{}, []byte{})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		calldata   []byte
+		batchIndex uint64
+		wantStart  uint64
+		wantEnd    uint64
+		wantErr    bool
+	}{
+		{"commitBatch", commitBatch, 10, 100, 105, false},
+		{"commitBatchWithBlobProof", commitBatchWithBlobProof, 10, 100, 105, false},
+		{"anotherBatch", commitBatch, 11, 0, 0, true},
+		{"commitBatches", commitBatches, 10, 0, 0, true},
+		{"shortChunk", shortChunk, 10, 0, 0, true},
+		{"noChunks", noChunks, 10, 0, 0, true},
+		{"unknownMethod", finalizeBatch, 10, 0, 0, true},
+		{"truncated", commitBatch[:40], 10, 0, 0, true},
+		{"empty", nil, 10, 0, 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			start, end, err := decodeCommitBatchBlockRange(test.calldata, test.batchIndex)
+			if test.wantErr {
+				assert.True(t, errors.Is(err, ErrUnknownCommitBatchCalldata))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.wantStart, start)
+			assert.Equal(t, test.wantEnd, end)
+		})
+	}
+}
+
+func TestCallFrame_CallsTo(t *testing.T) {
+	trace := `{
+		"to": "0x0000000000000000000000000000000000000001",
+		"input": "0x01",
+		"calls": [
+			{"to": "0x0000000000000000000000000000000000000002", "input": "0x02"},
+			{"to": "0x0000000000000000000000000000000000000003", "input": "0x03", "calls": [
+				{"to": "0x0000000000000000000000000000000000000002", "input": "0x04"}
+			]}
+		]
+	}`
+	var frame callFrame
+	assert.NoError(t, json.Unmarshal([]byte(trace), &frame))
+
+	calldata := frame.callsTo(common.HexToAddress("0x2"))
+	assert.Equal(t, [][]byte{{0x02}, {0x04}}, calldata)
+	assert.Empty(t, frame.callsTo(common.HexToAddress("0x4")))
+}
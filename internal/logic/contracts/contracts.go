@@ -5,7 +5,9 @@ import (
 	"fmt"
 
 	"github.com/scroll-tech/go-ethereum/accounts/abi/bind"
+	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/ethclient"
+	"github.com/scroll-tech/go-ethereum/rpc"
 
 	"github.com/scroll-tech/chain-monitor/internal/config"
 	"github.com/scroll-tech/chain-monitor/internal/logic/events"
@@ -20,10 +22,10 @@ type Contracts struct {
 
 // NewContracts creates a new instance of Contracts which can be used to filter log fetchers
 // from L1 and L2 smart contracts.
-func NewContracts(l1Client, l2Client *ethclient.Client) *Contracts {
+func NewContracts(l1Client, l2Client *rpc.Client) *Contracts {
 	c := &Contracts{
 		l1Contracts: newL1Contracts(l1Client),
-		l2Contracts: newL2Contracts(l2Client),
+		l2Contracts: newL2Contracts(ethclient.NewClient(l2Client)),
 	}
 	return c
 }
@@ -45,6 +47,8 @@ func (l *Contracts) Register(conf *config.Config) error {
 func (l *Contracts) Iterator(ctx context.Context, opts *bind.FilterOpts, layerType types.LayerType, txEventCategory types.EventCategory) ([]types.WrapIterator, error) {
 	if layerType == types.Layer1 {
 		switch txEventCategory {
+		case types.ETHEventCategory:
+			return l.l1ETHFilter(ctx, opts)
 		case types.ERC20EventCategory:
 			return l.l1Erc20Filter(ctx, opts)
 		case types.ERC721EventCategory:
@@ -53,11 +57,17 @@ func (l *Contracts) Iterator(ctx context.Context, opts *bind.FilterOpts, layerTy
 			return l.l1Erc1155Filter(ctx, opts)
 		case types.MessengerEventCategory:
 			return l.l1MessengerFilter(ctx, opts)
+		case types.BatchEventCategory:
+			return l.l1ScrollChainFilter(ctx, opts)
+		case types.MessageQueueEventCategory:
+			return l.l1MessageQueueFilter(ctx, opts)
 		}
 	}
 
 	if layerType == types.Layer2 {
 		switch txEventCategory {
+		case types.ETHEventCategory:
+			return l.l2ETHFilter(ctx, opts)
 		case types.ERC20EventCategory:
 			return l.l2Erc20Filter(ctx, opts)
 		case types.ERC721EventCategory:
@@ -78,6 +88,9 @@ func (l *Contracts) Iterator(ctx context.Context, opts *bind.FilterOpts, layerTy
 func (l *Contracts) GetGatewayTransfer(ctx context.Context, startBlockNumber, endBlockNumber uint64, layerType types.LayerType, txEventCategory types.EventCategory) ([]events.EventUnmarshaler, error) {
 	if layerType == types.Layer1 {
 		switch txEventCategory {
+		case types.ETHEventCategory:
+			// ETH has no transfer events, the eth balance is checked by the messenger balance checker.
+			return nil, nil
 		case types.ERC20EventCategory:
 			return l.getL1Erc20GatewayTransfer(ctx, startBlockNumber, endBlockNumber)
 		case types.ERC721EventCategory:
@@ -89,6 +102,9 @@ func (l *Contracts) GetGatewayTransfer(ctx context.Context, startBlockNumber, en
 
 	if layerType == types.Layer2 {
 		switch txEventCategory {
+		case types.ETHEventCategory:
+			// ETH has no transfer events, the eth balance is checked by the messenger balance checker.
+			return nil, nil
 		case types.ERC20EventCategory:
 			return l.getL2Erc20GatewayTransfer(ctx, startBlockNumber, endBlockNumber)
 		case types.ERC721EventCategory:
@@ -100,3 +116,19 @@ func (l *Contracts) GetGatewayTransfer(ctx context.Context, startBlockNumber, en
 
 	return nil, fmt.Errorf("invalid type, layerType: %v, txEventCategory: %v", layerType, txEventCategory)
 }
+
+// gatewayFilterOpts returns the filter options of the gateway, which start from the activation block of the gateway.
+// Returns false if the blocks to filter end before the activation block.
+func gatewayFilterOpts(opts *bind.FilterOpts, activationBlocks map[common.Address]uint64, gatewayAddress common.Address) (*bind.FilterOpts, bool) {
+	activationBlock := activationBlocks[gatewayAddress]
+	if opts.Start >= activationBlock {
+		return opts, true
+	}
+	if opts.End != nil && *opts.End < activationBlock {
+		return nil, false
+	}
+
+	gatewayOpts := *opts
+	gatewayOpts.Start = activationBlock
+	return &gatewayOpts, true
+}
@@ -0,0 +1,211 @@
+package contracts
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/accounts/abi/bind"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scroll-tech/chain-monitor/internal/config"
+	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/il1ethgateway"
+	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/iscrollerc721"
+	"github.com/scroll-tech/chain-monitor/internal/logic/events"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+)
+
+// chainService serves the logs of a chain.
+type chainService struct {
+	logs []gethTypes.Log
+}
+
+type logQuery struct {
+	FromBlock *hexutil.Big     `json:"fromBlock"`
+	ToBlock   *hexutil.Big     `json:"toBlock"`
+	Addresses []common.Address `json:"address"`
+	Topics    [][]common.Hash  `json:"topics"`
+}
+
+func (s *chainService) GetLogs(query logQuery) ([]gethTypes.Log, error) {
+	addresses := make(map[common.Address]bool)
+	for _, address := range query.Addresses {
+		addresses[address] = true
+	}
+
+	logs := []gethTypes.Log{}
+	for _, vLog := range s.logs {
+		if len(addresses) > 0 && !addresses[vLog.Address] {
+			continue
+		}
+		if vLog.BlockNumber < query.FromBlock.ToInt().Uint64() || vLog.BlockNumber > query.ToBlock.ToInt().Uint64() {
+			continue
+		}
+		if len(query.Topics) > 0 && len(query.Topics[0]) > 0 && query.Topics[0][0] != vLog.Topics[0] {
+			continue
+		}
+		logs = append(logs, vLog)
+	}
+	return logs, nil
+}
+
+func newChainClient(t *testing.T, service *chainService) *rpc.Client {
+	server := rpc.NewServer()
+	assert.NoError(t, server.RegisterName("eth", service))
+	t.Cleanup(server.Stop)
+	return rpc.DialInProc(server)
+}
+
+func newGatewayContracts(t *testing.T, service *chainService, cfg *config.Config) *Contracts {
+	client := newChainClient(t, service)
+	c := NewContracts(client, client)
+	assert.NoError(t, c.Register(cfg))
+	return c
+}
+
+// depositETHLog packs the l1 eth gateway DepositETH event of the block.
+func depositETHLog(t *testing.T, gateway common.Address, amount int64, blockNumber uint64) gethTypes.Log {
+	gatewayABI, err := il1ethgateway.Il1ethgatewayMetaData.GetAbi()
+	assert.NoError(t, err)
+	event := gatewayABI.Events["DepositETH"]
+	data, err := event.Inputs.NonIndexed().Pack(big.NewInt(amount), []byte{})
+	assert.NoError(t, err)
+	return gethTypes.Log{
+		Address:     gateway,
+		Topics:      []common.Hash{event.ID, common.BytesToHash(common.HexToAddress("0x1").Bytes()), common.BytesToHash(common.HexToAddress("0x2").Bytes())},
+		Data:        data,
+		BlockNumber: blockNumber,
+	}
+}
+
+// erc721TransferLog packs the erc721 Transfer event of the token.
+func erc721TransferLog(t *testing.T, token, from, to common.Address, tokenID int64, blockNumber uint64) gethTypes.Log {
+	erc721ABI, err := iscrollerc721.Iscrollerc721MetaData.GetAbi()
+	assert.NoError(t, err)
+	return gethTypes.Log{
+		Address:     token,
+		Topics:      []common.Hash{erc721ABI.Events["Transfer"].ID, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes()), common.BigToHash(big.NewInt(tokenID))},
+		BlockNumber: blockNumber,
+	}
+}
+
+func TestContracts_Register(t *testing.T) {
+	l1ETHGateway, l1ERC721Gateway, l2ERC1155Gateway := common.HexToAddress("0x1001"), common.HexToAddress("0x1002"), common.HexToAddress("0x2001")
+	cfg := &config.Config{
+		L1Config: &config.L1Config{L1Contracts: &config.L1Contracts{Gateway: config.Gateway{ETHGateway: l1ETHGateway, ERC721Gateway: l1ERC721Gateway}}},
+		L2Config: &config.L2Config{L2Contracts: &config.L2Contracts{Gateway: config.Gateway{ERC1155Gateway: l2ERC1155Gateway}}},
+		Gateways: []*config.GatewayConfig{
+			{Name: "eth_gateway_v2", Layer: config.GatewayLayerL1, Address: common.HexToAddress("0x1003"), TokenStandard: config.GatewayKindETH, ActivationBlock: 100},
+			{Name: "erc721_gateway_v2", Layer: config.GatewayLayerL1, Address: common.HexToAddress("0x1004"), TokenStandard: config.GatewayKindERC721},
+			// the gateway is registered by its abi kind rather than its token standard.
+			{Name: "wrapped_eth_gateway", Layer: config.GatewayLayerL1, Address: common.HexToAddress("0x1005"), TokenStandard: config.GatewayKindERC20, ABIKind: config.GatewayKindETH},
+			{Name: "erc1155_gateway_v2", Layer: config.GatewayLayerL2, Address: common.HexToAddress("0x2002"), TokenStandard: config.GatewayKindERC1155},
+		},
+	}
+	c := newGatewayContracts(t, &chainService{}, cfg)
+
+	assert.Equal(t, []gatewayMapping{
+		{name: "eth_gateway_v2", address: common.HexToAddress("0x1003")},
+		{name: "wrapped_eth_gateway", address: common.HexToAddress("0x1005")},
+		{name: "eth_gateway", address: l1ETHGateway},
+	}, c.l1Contracts.ethGatewayMappings)
+	assert.Len(t, c.l1Contracts.ethGateways, 3)
+	assert.Empty(t, c.l1Contracts.erc20GatewayTokens)
+	assert.Equal(t, []gatewayMapping{
+		{name: "erc721_gateway_v2", address: common.HexToAddress("0x1004")},
+		{name: "erc721_gateway", address: l1ERC721Gateway},
+	}, c.l1Contracts.erc721GatewayMappings)
+	assert.Len(t, c.l1Contracts.erc721Gateways, 2)
+	assert.Equal(t, uint64(100), c.l1Contracts.activationBlocks[common.HexToAddress("0x1003")])
+
+	assert.Equal(t, []gatewayMapping{
+		{name: "erc1155_gateway_v2", address: common.HexToAddress("0x2002")},
+		{name: "erc1155_gateway", address: l2ERC1155Gateway},
+	}, c.l2Contracts.erc1155GatewayMappings)
+	assert.Len(t, c.l2Contracts.erc1155Gateways, 2)
+	assert.Empty(t, c.l2Contracts.ethGatewayMappings)
+}
+
+func TestContracts_Iterator(t *testing.T) {
+	ethGateway, onboardedGateway := common.HexToAddress("0x1001"), common.HexToAddress("0x1003")
+	service := &chainService{logs: []gethTypes.Log{
+		depositETHLog(t, ethGateway, 1, 50),
+		// the deposit before the activation block of the onboarded gateway isn't filtered.
+		depositETHLog(t, onboardedGateway, 2, 50),
+		depositETHLog(t, onboardedGateway, 3, 150),
+	}}
+	c := newGatewayContracts(t, service, &config.Config{
+		L1Config: &config.L1Config{L1Contracts: &config.L1Contracts{Gateway: config.Gateway{ETHGateway: ethGateway}}},
+		L2Config: &config.L2Config{L2Contracts: &config.L2Contracts{}},
+		Gateways: []*config.GatewayConfig{
+			{Name: "eth_gateway_v2", Layer: config.GatewayLayerL1, Address: onboardedGateway, TokenStandard: config.GatewayKindETH, ActivationBlock: 100},
+		},
+	})
+
+	deposits := func(iterators []types.WrapIterator) map[common.Address][]int64 {
+		amounts := make(map[common.Address][]int64)
+		for _, iterator := range iterators {
+			if iterator.EventType != types.L1DepositETH {
+				continue
+			}
+			iter := iterator.Iter.(*il1ethgateway.Il1ethgatewayDepositETHIterator)
+			for iter.Next() {
+				amounts[iter.Event.Raw.Address] = append(amounts[iter.Event.Raw.Address], iter.Event.Amount.Int64())
+			}
+		}
+		return amounts
+	}
+
+	tests := []struct {
+		name          string
+		end           uint64
+		wantIterators int
+		wantDeposits  map[common.Address][]int64
+	}{
+		{"bothGateways", 200, 6, map[common.Address][]int64{onboardedGateway: {3}, ethGateway: {1}}},
+		{"beforeActivation", 80, 3, map[common.Address][]int64{ethGateway: {1}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			iterators, err := c.Iterator(context.Background(), &bind.FilterOpts{Start: 0, End: &test.end}, types.Layer1, types.ETHEventCategory)
+			assert.NoError(t, err)
+			assert.Len(t, iterators, test.wantIterators)
+			assert.Equal(t, test.wantDeposits, deposits(iterators))
+		})
+	}
+}
+
+func TestContracts_GetL1Erc721GatewayTransfer(t *testing.T) {
+	erc721Gateway, onboardedGateway := common.HexToAddress("0x1002"), common.HexToAddress("0x1004")
+	token, user := common.HexToAddress("0x3001"), common.HexToAddress("0x1")
+	service := &chainService{logs: []gethTypes.Log{
+		erc721TransferLog(t, token, user, erc721Gateway, 1, 10),
+		erc721TransferLog(t, token, onboardedGateway, user, 2, 11),
+		// the transfers between the users aren't gateway transfers.
+		erc721TransferLog(t, token, user, common.HexToAddress("0x2"), 3, 12),
+	}}
+	c := newGatewayContracts(t, service, &config.Config{
+		L1Config: &config.L1Config{L1Contracts: &config.L1Contracts{Gateway: config.Gateway{ERC721Gateway: erc721Gateway}}},
+		L2Config: &config.L2Config{L2Contracts: &config.L2Contracts{}},
+		Gateways: []*config.GatewayConfig{
+			{Name: "erc721_gateway_v2", Layer: config.GatewayLayerL1, Address: onboardedGateway, TokenStandard: config.GatewayKindERC721},
+		},
+	})
+
+	transferEvents, err := c.GetGatewayTransfer(context.Background(), 0, 20, types.Layer1, types.ERC721EventCategory)
+	assert.NoError(t, err)
+	assert.Len(t, transferEvents, 2)
+
+	deposited := transferEvents[0].(*events.ERC721GatewayEventUnmarshaler)
+	assert.Equal(t, []*big.Int{big.NewInt(1)}, deposited.TokenIds)
+	assert.Equal(t, []*big.Int{big.NewInt(1)}, deposited.Amounts)
+	withdrawn := transferEvents[1].(*events.ERC721GatewayEventUnmarshaler)
+	assert.Equal(t, []*big.Int{big.NewInt(2)}, withdrawn.TokenIds)
+	assert.Equal(t, []*big.Int{big.NewInt(-1)}, withdrawn.Amounts)
+	assert.Equal(t, token, withdrawn.TokenAddress)
+}
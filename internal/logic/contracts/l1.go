@@ -6,38 +6,70 @@ import (
 	"github.com/scroll-tech/go-ethereum/common"
 	"github.com/scroll-tech/go-ethereum/ethclient"
 	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rpc"
 
 	"github.com/scroll-tech/chain-monitor/internal/config"
 	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/il1erc1155gateway"
 	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/il1erc20gateway"
 	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/il1erc721gateway"
+	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/il1ethgateway"
+	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/il1messagequeue"
 	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/il1scrollmessenger"
-	"github.com/scroll-tech/chain-monitor/internal/types"
+	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/iscrollchain"
 )
 
-type erc20GatewayMapping struct {
-	tokenType types.ERC20
-	address   common.Address
+// gatewayMapping the name and the address of a registered gateway, the gateways are filtered in the registered order.
+type gatewayMapping struct {
+	name    string
+	address common.Address
+}
+
+// gatewayAddresses returns the addresses of the registered gateways.
+func gatewayAddresses(gateways []gatewayMapping) map[common.Address]struct{} {
+	addresses := make(map[common.Address]struct{}, len(gateways))
+	for _, gateway := range gateways {
+		addresses[gateway.address] = struct{}{}
+	}
+	return addresses
 }
 
 type l1Contracts struct {
 	client *ethclient.Client
+	// the raw rpc client, the commit txs sent by the proxy contracts are traced by it.
+	rpcClient *rpc.Client
 
 	messenger *il1scrollmessenger.Il1scrollmessenger
 
-	erc20Gateways      map[types.ERC20]*il1erc20gateway.Il1erc20gateway
-	erc20GatewayTokens []erc20GatewayMapping
+	scrollChain        *iscrollchain.Iscrollchain
+	scrollChainAddress common.Address
+
+	messageQueue        *il1messagequeue.Il1messagequeue
+	messageQueueAddress common.Address
+
+	ethGateways        map[string]*il1ethgateway.Il1ethgateway
+	ethGatewayMappings []gatewayMapping
+
+	erc20Gateways      map[string]*il1erc20gateway.Il1erc20gateway
+	erc20GatewayTokens []gatewayMapping
+
+	erc721Gateways         map[string]*il1erc721gateway.Il1erc721gateway
+	erc721GatewayMappings  []gatewayMapping
+	erc1155Gateways        map[string]*il1erc1155gateway.Il1erc1155gateway
+	erc1155GatewayMappings []gatewayMapping
 
-	erc721Gateway         *il1erc721gateway.Il1erc721gateway
-	erc721GatewayAddress  common.Address
-	ERC1155Gateway        *il1erc1155gateway.Il1erc1155gateway
-	ERC1155GatewayAddress common.Address
+	// the activation blocks of the gateways, before which the gateway events aren't filtered.
+	activationBlocks map[common.Address]uint64
 }
 
-func newL1Contracts(c *ethclient.Client) *l1Contracts {
+func newL1Contracts(c *rpc.Client) *l1Contracts {
 	return &l1Contracts{
-		client:        c,
-		erc20Gateways: make(map[types.ERC20]*il1erc20gateway.Il1erc20gateway),
+		client:           ethclient.NewClient(c),
+		rpcClient:        c,
+		ethGateways:      make(map[string]*il1ethgateway.Il1ethgateway),
+		erc20Gateways:    make(map[string]*il1erc20gateway.Il1erc20gateway),
+		erc721Gateways:   make(map[string]*il1erc721gateway.Il1erc721gateway),
+		erc1155Gateways:  make(map[string]*il1erc1155gateway.Il1erc1155gateway),
+		activationBlocks: make(map[common.Address]uint64),
 	}
 }
 
@@ -49,84 +81,147 @@ func (l *l1Contracts) register(conf *config.Config) error {
 		return fmt.Errorf("register l2 scroll messenger contract failed, address:%v, err:%w", conf.L1Config.L1Contracts.ScrollMessenger.Hex(), err)
 	}
 
-	erc20Gateways := []struct {
-		address common.Address
-		token   types.ERC20
-	}{
-		{conf.L1Config.L1Contracts.WETHGateway, types.WETH},
-		{conf.L1Config.L1Contracts.StandardERC20Gateway, types.StandardERC20},
-		{conf.L1Config.L1Contracts.CustomERC20Gateway, types.CustomERC20},
-		{conf.L1Config.L1Contracts.DAIGateway, types.DAI},
-		{conf.L1Config.L1Contracts.USDCGateway, types.USDC},
-		{conf.L1Config.L1Contracts.LIDOGateway, types.LIDO},
+	scrollChainAddress := conf.L1Config.L1Contracts.ScrollChain
+	if err := l.registerScrollChain(scrollChainAddress); err != nil {
+		log.Error("registerScrollChain failed", "address", scrollChainAddress, "err", err)
+		return err
+	}
+
+	messageQueueAddress := conf.L1Config.L1Contracts.MessageQueue
+	if err := l.registerMessageQueue(messageQueueAddress); err != nil {
+		log.Error("registerMessageQueue failed", "address", messageQueueAddress, "err", err)
+		return err
 	}
 
-	for _, gw := range erc20Gateways {
-		if err := l.registerERC20Gateway(gw.address, gw.token); err != nil {
-			log.Error("registerERC20Gateway failed", "address", gw.address, "token", gw.token, "err", err)
+	for _, gateway := range conf.GatewayRegistry() {
+		if gateway.Layer != config.GatewayLayerL1 {
+			continue
+		}
+		if err := l.registerGateway(gateway); err != nil {
+			log.Error("registerGateway failed", "name", gateway.Name, "address", gateway.Address, "abi kind", gateway.ABIKind, "err", err)
 			return err
 		}
 	}
 
-	erc721GatewayAddress := conf.L1Config.L1Contracts.ERC721Gateway
-	if err := l.registerERC721Gateway(erc721GatewayAddress); err != nil {
-		log.Error("registerERC721Gateway failed", "address", erc721GatewayAddress, "err", err)
-		return err
+	return nil
+}
+
+func (l *l1Contracts) registerScrollChain(scrollChainAddress common.Address) error {
+	if scrollChainAddress == (common.Address{}) {
+		log.Warn("l1 scroll chain unconfigured", "address", scrollChainAddress)
+		return nil
+	}
+
+	l.scrollChainAddress = scrollChainAddress
+
+	scrollChain, err := iscrollchain.NewIscrollchain(scrollChainAddress, l.client)
+	if err != nil {
+		return fmt.Errorf("l1 register scroll chain contract failed, err:%w", err)
 	}
+	l.scrollChain = scrollChain
+	return nil
+}
+
+func (l *l1Contracts) registerMessageQueue(messageQueueAddress common.Address) error {
+	if messageQueueAddress == (common.Address{}) {
+		log.Warn("l1 message queue unconfigured", "address", messageQueueAddress)
+		return nil
+	}
+
+	l.messageQueueAddress = messageQueueAddress
 
-	erc1155GatewayAddress := conf.L1Config.L1Contracts.ERC1155Gateway
-	if err := l.registerERC1155Gateway(erc1155GatewayAddress); err != nil {
-		log.Error("registerERC1155Gateway failed", "address", erc1155GatewayAddress, "err", err)
+	messageQueue, err := il1messagequeue.NewIl1messagequeue(messageQueueAddress, l.client)
+	if err != nil {
+		return fmt.Errorf("l1 register message queue contract failed, err:%w", err)
+	}
+	l.messageQueue = messageQueue
+	return nil
+}
+
+// registerGateway registers the gateway of the gateway registry by its abi kind, which selects the filter and the
+// unmarshaler of the gateway events. Many gateways of each abi kind can be registered on a layer.
+func (l *l1Contracts) registerGateway(gateway *config.GatewayConfig) error {
+	var err error
+	switch gateway.ABIKind {
+	case config.GatewayKindETH:
+		err = l.registerETHGateway(gateway.Address, gateway.Name)
+	case config.GatewayKindERC20:
+		err = l.registerERC20Gateway(gateway.Address, gateway.Name)
+	case config.GatewayKindERC721:
+		err = l.registerERC721Gateway(gateway.Address, gateway.Name)
+	case config.GatewayKindERC1155:
+		err = l.registerERC1155Gateway(gateway.Address, gateway.Name)
+	default:
+		err = fmt.Errorf("l1 gateway %s has unknown abi kind: %s", gateway.Name, gateway.ABIKind)
+	}
+	if err != nil {
 		return err
 	}
 
+	l.activationBlocks[gateway.Address] = gateway.ActivationBlock
 	return nil
 }
 
-func (l *l1Contracts) registerERC20Gateway(gatewayAddress common.Address, tokenType types.ERC20) error {
+func (l *l1Contracts) registerETHGateway(gatewayAddress common.Address, name string) error {
 	if gatewayAddress == (common.Address{}) {
-		log.Warn("l1 erc20 gateway unconfigured", "address", gatewayAddress, "token type", tokenType.String())
+		log.Warn("l1 eth gateway unconfigured", "address", gatewayAddress, "name", name)
 		return nil
 	}
-	erc20Gateway, err := il1erc20gateway.NewIl1erc20gateway(gatewayAddress, l.client)
+	ethGateway, err := il1ethgateway.NewIl1ethgateway(gatewayAddress, l.client)
 	if err != nil {
-		return fmt.Errorf("l1 register erc20 gateway contract failed, err:%w", err)
+		return fmt.Errorf("l1 register eth gateway contract failed, err:%w", err)
 	}
 
-	l.erc20Gateways[tokenType] = erc20Gateway
-	l.erc20GatewayTokens = append(l.erc20GatewayTokens, erc20GatewayMapping{tokenType: tokenType, address: gatewayAddress})
+	l.ethGateways[name] = ethGateway
+	l.ethGatewayMappings = append(l.ethGatewayMappings, gatewayMapping{name: name, address: gatewayAddress})
 
 	return nil
 }
 
-func (l *l1Contracts) registerERC721Gateway(gatewayAddress common.Address) error {
+func (l *l1Contracts) registerERC20Gateway(gatewayAddress common.Address, name string) error {
 	if gatewayAddress == (common.Address{}) {
-		log.Warn("l1 erc721 gateway unconfigured", "address", gatewayAddress)
+		log.Warn("l1 erc20 gateway unconfigured", "address", gatewayAddress, "name", name)
 		return nil
 	}
+	erc20Gateway, err := il1erc20gateway.NewIl1erc20gateway(gatewayAddress, l.client)
+	if err != nil {
+		return fmt.Errorf("l1 register erc20 gateway contract failed, err:%w", err)
+	}
+
+	l.erc20Gateways[name] = erc20Gateway
+	l.erc20GatewayTokens = append(l.erc20GatewayTokens, gatewayMapping{name: name, address: gatewayAddress})
 
-	l.erc721GatewayAddress = gatewayAddress
+	return nil
+}
 
-	erc721Gateways, err := il1erc721gateway.NewIl1erc721gateway(gatewayAddress, l.client)
+func (l *l1Contracts) registerERC721Gateway(gatewayAddress common.Address, name string) error {
+	if gatewayAddress == (common.Address{}) {
+		log.Warn("l1 erc721 gateway unconfigured", "address", gatewayAddress, "name", name)
+		return nil
+	}
+	erc721Gateway, err := il1erc721gateway.NewIl1erc721gateway(gatewayAddress, l.client)
 	if err != nil {
 		return fmt.Errorf("l1 register erc721 gateway contract failed, err:%w", err)
 	}
-	l.erc721Gateway = erc721Gateways
+
+	l.erc721Gateways[name] = erc721Gateway
+	l.erc721GatewayMappings = append(l.erc721GatewayMappings, gatewayMapping{name: name, address: gatewayAddress})
+
 	return nil
 }
 
-func (l *l1Contracts) registerERC1155Gateway(gatewayAddress common.Address) error {
+func (l *l1Contracts) registerERC1155Gateway(gatewayAddress common.Address, name string) error {
 	if gatewayAddress == (common.Address{}) {
-		log.Warn("l1 erc1155 gateway unconfigured", "address", gatewayAddress)
+		log.Warn("l1 erc1155 gateway unconfigured", "address", gatewayAddress, "name", name)
 		return nil
 	}
-
-	l.ERC1155GatewayAddress = gatewayAddress
-
-	erc1155Gateways, err := il1erc1155gateway.NewIl1erc1155gateway(gatewayAddress, l.client)
+	erc1155Gateway, err := il1erc1155gateway.NewIl1erc1155gateway(gatewayAddress, l.client)
 	if err != nil {
 		return fmt.Errorf("l1 register erc1155 gateway contract failed, err:%w", err)
 	}
-	l.ERC1155Gateway = erc1155Gateways
+
+	l.erc1155Gateways[name] = erc1155Gateway
+	l.erc1155GatewayMappings = append(l.erc1155GatewayMappings, gatewayMapping{name: name, address: gatewayAddress})
+
 	return nil
 }
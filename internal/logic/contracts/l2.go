@@ -11,8 +11,8 @@ import (
 	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/il2erc1155gateway"
 	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/il2erc20gateway"
 	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/il2erc721gateway"
+	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/il2ethgateway"
 	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/il2scrollmessenger"
-	"github.com/scroll-tech/chain-monitor/internal/types"
 )
 
 type l2Contracts struct {
@@ -20,20 +20,29 @@ type l2Contracts struct {
 
 	messenger *il2scrollmessenger.Il2scrollmessenger
 
-	erc20Gateways      map[types.ERC20]*il2erc20gateway.Il2erc20gateway
-	erc20GatewayTokens []erc20GatewayMapping
+	ethGateways        map[string]*il2ethgateway.Il2ethgateway
+	ethGatewayMappings []gatewayMapping
 
-	erc721Gateway         *il2erc721gateway.Il2erc721gateway
-	erc721GatewayAddress  common.Address
-	ERC1155Gateway        *il2erc1155gateway.Il2erc1155gateway
-	ERC1155GatewayAddress common.Address
-	WETHGatewayAddress    common.Address
+	erc20Gateways      map[string]*il2erc20gateway.Il2erc20gateway
+	erc20GatewayTokens []gatewayMapping
+
+	erc721Gateways         map[string]*il2erc721gateway.Il2erc721gateway
+	erc721GatewayMappings  []gatewayMapping
+	erc1155Gateways        map[string]*il2erc1155gateway.Il2erc1155gateway
+	erc1155GatewayMappings []gatewayMapping
+
+	// the activation blocks of the gateways, before which the gateway events aren't filtered.
+	activationBlocks map[common.Address]uint64
 }
 
 func newL2Contracts(c *ethclient.Client) *l2Contracts {
 	return &l2Contracts{
-		client:        c,
-		erc20Gateways: make(map[types.ERC20]*il2erc20gateway.Il2erc20gateway),
+		client:           c,
+		ethGateways:      make(map[string]*il2ethgateway.Il2ethgateway),
+		erc20Gateways:    make(map[string]*il2erc20gateway.Il2erc20gateway),
+		erc721Gateways:   make(map[string]*il2erc721gateway.Il2erc721gateway),
+		erc1155Gateways:  make(map[string]*il2erc1155gateway.Il2erc1155gateway),
+		activationBlocks: make(map[common.Address]uint64),
 	}
 }
 
@@ -45,46 +54,62 @@ func (l *l2Contracts) register(conf *config.Config) error {
 		return fmt.Errorf("register l2 scroll messenger contract failed, address:%v, err:%w", conf.L2Config.L2Contracts.ScrollMessenger.Hex(), err)
 	}
 
-	erc20Gateways := []struct {
-		Address common.Address
-		Token   types.ERC20
-	}{
-		{conf.L2Config.L2Contracts.WETHGateway, types.WETH},
-		{conf.L2Config.L2Contracts.StandardERC20Gateway, types.StandardERC20},
-		{conf.L2Config.L2Contracts.CustomERC20Gateway, types.CustomERC20},
-		{conf.L2Config.L2Contracts.DAIGateway, types.DAI},
-		{conf.L2Config.L2Contracts.USDCGateway, types.USDC},
-		{conf.L2Config.L2Contracts.LIDOGateway, types.LIDO},
-	}
-
-	for _, gw := range erc20Gateways {
-		if gw.Token == types.WETH {
-			l.WETHGatewayAddress = gw.Address
+	for _, gateway := range conf.GatewayRegistry() {
+		if gateway.Layer != config.GatewayLayerL2 {
+			continue
 		}
-		if err := l.registerERC20Gateway(gw.Address, gw.Token); err != nil {
-			log.Error("registerERC20Gateway failed", "address", gw.Address, "token", gw.Token, "err", err)
+		if err := l.registerGateway(gateway); err != nil {
+			log.Error("registerGateway failed", "name", gateway.Name, "address", gateway.Address, "abi kind", gateway.ABIKind, "err", err)
 			return err
 		}
 	}
 
-	erc721GatewayAddress := conf.L2Config.L2Contracts.ERC721Gateway
-	if err := l.registerERC721Gateway(erc721GatewayAddress); err != nil {
-		log.Error("registerERC721Gateway failed", "address", erc721GatewayAddress, "err", err)
+	return nil
+}
+
+// registerGateway registers the gateway of the gateway registry by its abi kind, which selects the filter and the
+// unmarshaler of the gateway events. Many gateways of each abi kind can be registered on a layer.
+func (l *l2Contracts) registerGateway(gateway *config.GatewayConfig) error {
+	var err error
+	switch gateway.ABIKind {
+	case config.GatewayKindETH:
+		err = l.registerETHGateway(gateway.Address, gateway.Name)
+	case config.GatewayKindERC20:
+		err = l.registerERC20Gateway(gateway.Address, gateway.Name)
+	case config.GatewayKindERC721:
+		err = l.registerERC721Gateway(gateway.Address, gateway.Name)
+	case config.GatewayKindERC1155:
+		err = l.registerERC1155Gateway(gateway.Address, gateway.Name)
+	default:
+		err = fmt.Errorf("l2 gateway %s has unknown abi kind: %s", gateway.Name, gateway.ABIKind)
+	}
+	if err != nil {
 		return err
 	}
 
-	erc1155GatewayAddress := conf.L2Config.L2Contracts.ERC1155Gateway
-	if err := l.registerERC1155Gateway(erc1155GatewayAddress); err != nil {
-		log.Error("registerERC1155Gateway failed", "address", erc1155GatewayAddress, "err", err)
-		return err
+	l.activationBlocks[gateway.Address] = gateway.ActivationBlock
+	return nil
+}
+
+func (l *l2Contracts) registerETHGateway(gatewayAddress common.Address, name string) error {
+	if gatewayAddress == (common.Address{}) {
+		log.Warn("l2 eth gateway unconfigured", "address", gatewayAddress, "name", name)
+		return nil
+	}
+	ethGateway, err := il2ethgateway.NewIl2ethgateway(gatewayAddress, l.client)
+	if err != nil {
+		return fmt.Errorf("l2 register eth gateway contract failed, err:%w", err)
 	}
 
+	l.ethGateways[name] = ethGateway
+	l.ethGatewayMappings = append(l.ethGatewayMappings, gatewayMapping{name: name, address: gatewayAddress})
+
 	return nil
 }
 
-func (l *l2Contracts) registerERC20Gateway(gatewayAddress common.Address, tokenType types.ERC20) error {
+func (l *l2Contracts) registerERC20Gateway(gatewayAddress common.Address, name string) error {
 	if gatewayAddress == (common.Address{}) {
-		log.Warn("l2 erc20 gateway unconfigured", "address", gatewayAddress, "token type", tokenType.String())
+		log.Warn("l2 erc20 gateway unconfigured", "address", gatewayAddress, "name", name)
 		return nil
 	}
 	erc20Gateway, err := il2erc20gateway.NewIl2erc20gateway(gatewayAddress, l.client)
@@ -93,40 +118,40 @@ func (l *l2Contracts) registerERC20Gateway(gatewayAddress common.Address, tokenT
 		return fmt.Errorf("register erc20 gateway contract failed, err:%w", err)
 	}
 
-	l.erc20Gateways[tokenType] = erc20Gateway
-	l.erc20GatewayTokens = append(l.erc20GatewayTokens, erc20GatewayMapping{tokenType: tokenType, address: gatewayAddress})
+	l.erc20Gateways[name] = erc20Gateway
+	l.erc20GatewayTokens = append(l.erc20GatewayTokens, gatewayMapping{name: name, address: gatewayAddress})
 
 	return nil
 }
 
-func (l *l2Contracts) registerERC721Gateway(gatewayAddress common.Address) error {
+func (l *l2Contracts) registerERC721Gateway(gatewayAddress common.Address, name string) error {
 	if gatewayAddress == (common.Address{}) {
-		log.Warn("l2 erc721 gateway unconfigured", "address", gatewayAddress)
+		log.Warn("l2 erc721 gateway unconfigured", "address", gatewayAddress, "name", name)
 		return nil
 	}
-
-	l.erc721GatewayAddress = gatewayAddress
-
-	erc721Gateways, err := il2erc721gateway.NewIl2erc721gateway(gatewayAddress, l.client)
+	erc721Gateway, err := il2erc721gateway.NewIl2erc721gateway(gatewayAddress, l.client)
 	if err != nil {
 		return fmt.Errorf("l2 register erc721 gateway contract failed, err:%w", err)
 	}
-	l.erc721Gateway = erc721Gateways
+
+	l.erc721Gateways[name] = erc721Gateway
+	l.erc721GatewayMappings = append(l.erc721GatewayMappings, gatewayMapping{name: name, address: gatewayAddress})
+
 	return nil
 }
 
-func (l *l2Contracts) registerERC1155Gateway(gatewayAddress common.Address) error {
+func (l *l2Contracts) registerERC1155Gateway(gatewayAddress common.Address, name string) error {
 	if gatewayAddress == (common.Address{}) {
-		log.Warn("l2 erc1155 gateway unconfigured", "address", gatewayAddress)
+		log.Warn("l2 erc1155 gateway unconfigured", "address", gatewayAddress, "name", name)
 		return nil
 	}
-
-	l.ERC1155GatewayAddress = gatewayAddress
-
-	erc1155Gateways, err := il2erc1155gateway.NewIl2erc1155gateway(gatewayAddress, l.client)
+	erc1155Gateway, err := il2erc1155gateway.NewIl2erc1155gateway(gatewayAddress, l.client)
 	if err != nil {
 		return fmt.Errorf("l2 register erc1155 gateway contract failed, err:%w", err)
 	}
-	l.ERC1155Gateway = erc1155Gateways
+
+	l.erc1155Gateways[name] = erc1155Gateway
+	l.erc1155GatewayMappings = append(l.erc1155GatewayMappings, gatewayMapping{name: name, address: gatewayAddress})
+
 	return nil
 }
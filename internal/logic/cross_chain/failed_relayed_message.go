@@ -0,0 +1,172 @@
+package crosschain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"github.com/scroll-tech/chain-monitor/internal/config"
+	"github.com/scroll-tech/chain-monitor/internal/logic/alert"
+	"github.com/scroll-tech/chain-monitor/internal/orm"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+	"github.com/scroll-tech/chain-monitor/internal/utils"
+)
+
+const (
+	defaultMaxFailedCount  uint64 = 3
+	defaultRelayTimeWindow uint64 = 1800
+)
+
+// LogicFailedRelayedMessage is a struct for checking the messages which failed to be relayed.
+// A failed relayed message is alerted once it failed too many times, or it hasn't been
+// relayed successfully within the relay time window after the first failure.
+type LogicFailedRelayedMessage struct {
+	db                       *gorm.DB
+	failedRelayedMessageOrm  *orm.FailedRelayedMessage
+	messengerMessageMatchOrm *orm.MessengerMessageMatch
+	maxFailedCount           uint64
+	relayTimeWindow          time.Duration
+
+	failedRelayedMessageCheckTotal *prometheus.CounterVec
+}
+
+// NewLogicFailedRelayedMessage is a constructor for LogicFailedRelayedMessage.
+func NewLogicFailedRelayedMessage(cfg *config.FailedRelayedMessageConfig, db *gorm.DB) *LogicFailedRelayedMessage {
+	maxFailedCount := defaultMaxFailedCount
+	relayTimeWindow := defaultRelayTimeWindow
+	if cfg != nil {
+		if cfg.MaxFailedCount != 0 {
+			maxFailedCount = cfg.MaxFailedCount
+		}
+		if cfg.RelayTimeWindow != 0 {
+			relayTimeWindow = cfg.RelayTimeWindow
+		}
+	}
+
+	return &LogicFailedRelayedMessage{
+		db:                       db,
+		failedRelayedMessageOrm:  orm.NewFailedRelayedMessage(db),
+		messengerMessageMatchOrm: orm.NewMessengerMessageMatch(db),
+		maxFailedCount:           maxFailedCount,
+		relayTimeWindow:          time.Duration(relayTimeWindow) * time.Second,
+
+		failedRelayedMessageCheckTotal: promauto.With(prometheus.DefaultRegisterer).NewCounterVec(prometheus.CounterOpts{
+			Name: "cross_chain_checked_failed_relayed_message_total",
+			Help: "the total number of failed relayed message checked",
+		}, []string{"layer"}),
+	}
+}
+
+// CheckFailedRelayedMessage checks the unalerted failed relayed messages of the given layer,
+// and resolves the alerts of the alerted ones which have been relayed since.
+func (c *LogicFailedRelayedMessage) CheckFailedRelayedMessage(ctx context.Context, layerType types.LayerType) {
+	c.resolveRelayedMessages(ctx, layerType)
+
+	failedMessages, err := c.failedRelayedMessageOrm.GetUnalertedFailedRelayedMessages(ctx, layerType, 1000)
+	if err != nil {
+		log.Error("CheckFailedRelayedMessage.GetUnalertedFailedRelayedMessages failed", "layer", layerType, "error", err)
+		return
+	}
+
+	if len(failedMessages) == 0 {
+		return
+	}
+
+	relayedMessages, err := c.getRelayedMessages(ctx, layerType, failedMessages)
+	if err != nil {
+		log.Error("CheckFailedRelayedMessage.getRelayedMessages failed", "layer", layerType, "error", err)
+		return
+	}
+
+	var relayedIds []int64
+	var alertedIds []int64
+	for _, failedMessage := range failedMessages {
+		c.failedRelayedMessageCheckTotal.WithLabelValues(layerType.String()).Inc()
+		if relayedMessages[failedMessage.MessageHash] {
+			relayedIds = append(relayedIds, failedMessage.ID)
+			continue
+		}
+
+		var reason string
+		if failedMessage.FailedCount >= c.maxFailedCount {
+			reason = fmt.Sprintf("failed to be relayed for %d times", failedMessage.FailedCount)
+		} else if utils.NowUTC().Sub(failedMessage.FirstFailedAt) > c.relayTimeWindow {
+			reason = fmt.Sprintf("not relayed successfully within %s after the first failure", c.relayTimeWindow.String())
+		} else {
+			continue
+		}
+
+		alert.Notify(alert.FailedRelayedMessageAlert(failedMessage, reason))
+		alertedIds = append(alertedIds, failedMessage.ID)
+	}
+
+	if err = c.failedRelayedMessageOrm.UpdateStatus(ctx, relayedIds, types.FailedRelayedMessageStatusTypeRelayed); err != nil {
+		log.Error("CheckFailedRelayedMessage UpdateStatus relayed failed", "layer", layerType, "error", err)
+		return
+	}
+
+	if err = c.failedRelayedMessageOrm.UpdateStatus(ctx, alertedIds, types.FailedRelayedMessageStatusTypeAlerted); err != nil {
+		log.Error("CheckFailedRelayedMessage UpdateStatus alerted failed", "layer", layerType, "error", err)
+		return
+	}
+}
+
+func (c *LogicFailedRelayedMessage) resolveRelayedMessages(ctx context.Context, layerType types.LayerType) {
+	failedMessages, err := c.failedRelayedMessageOrm.GetAlertedFailedRelayedMessages(ctx, layerType, 1000)
+	if err != nil {
+		log.Error("CheckFailedRelayedMessage.GetAlertedFailedRelayedMessages failed", "layer", layerType, "error", err)
+		return
+	}
+
+	if len(failedMessages) == 0 {
+		return
+	}
+
+	relayedMessages, err := c.getRelayedMessages(ctx, layerType, failedMessages)
+	if err != nil {
+		log.Error("CheckFailedRelayedMessage.getRelayedMessages failed", "layer", layerType, "error", err)
+		return
+	}
+
+	var relayedIds []int64
+	var fingerprints []string
+	for _, failedMessage := range failedMessages {
+		if relayedMessages[failedMessage.MessageHash] {
+			relayedIds = append(relayedIds, failedMessage.ID)
+			fingerprints = append(fingerprints, alert.Fingerprint(alert.CategoryFailedRelayedMessage, layerType, failedMessage.MessageHash, 0))
+		}
+	}
+
+	if err = c.failedRelayedMessageOrm.UpdateStatus(ctx, relayedIds, types.FailedRelayedMessageStatusTypeRelayed); err != nil {
+		log.Error("CheckFailedRelayedMessage UpdateStatus relayed failed", "layer", layerType, "error", err)
+		return
+	}
+	alert.Resolve(ctx, fingerprints...)
+}
+
+// getRelayedMessages returns the message hashes of the failed messages which have been relayed successfully.
+func (c *LogicFailedRelayedMessage) getRelayedMessages(ctx context.Context, layerType types.LayerType, failedMessages []orm.FailedRelayedMessage) (map[string]bool, error) {
+	var messageHashes []string
+	for _, failedMessage := range failedMessages {
+		messageHashes = append(messageHashes, failedMessage.MessageHash)
+	}
+
+	messageMatches, err := c.messengerMessageMatchOrm.GetMessageMatchesByMessageHashes(ctx, messageHashes)
+	if err != nil {
+		return nil, err
+	}
+
+	relayedMessages := make(map[string]bool)
+	for _, messageMatch := range messageMatches {
+		if (layerType == types.Layer1 && messageMatch.L1EventType == int(types.L1RelayedMessage)) ||
+			(layerType == types.Layer2 && messageMatch.L2EventType == int(types.L2RelayedMessage)) {
+			relayedMessages[messageMatch.MessageHash] = true
+		}
+	}
+	return relayedMessages, nil
+}
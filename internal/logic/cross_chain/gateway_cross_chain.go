@@ -2,14 +2,17 @@ package crosschain
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/ethclient"
 	"github.com/scroll-tech/go-ethereum/log"
 	"gorm.io/gorm"
 
-	"github.com/scroll-tech/chain-monitor/internal/logic/slack"
+	"github.com/scroll-tech/chain-monitor/internal/config"
+	"github.com/scroll-tech/chain-monitor/internal/logic/alert"
 	"github.com/scroll-tech/chain-monitor/internal/orm"
 	"github.com/scroll-tech/chain-monitor/internal/types"
 )
@@ -23,14 +26,16 @@ type LogicGatewayCrossChain struct {
 	db                          *gorm.DB
 	gatewayMessageOrm           *orm.GatewayMessageMatch
 	checker                     *GatewayCrossEventMatcher
+	tokenPairChecker            *TokenPairChecker
 	crossChainGatewayCheckTotal *prometheus.CounterVec
 }
 
 // NewLogicGatewayCrossChain is a constructor for Logic.
-func NewLogicGatewayCrossChain(db *gorm.DB) *LogicGatewayCrossChain {
+func NewLogicGatewayCrossChain(cfg *config.Config, db *gorm.DB, l2Client *ethclient.Client) *LogicGatewayCrossChain {
 	return &LogicGatewayCrossChain{
 		db:                db,
 		checker:           NewGatewayCrossEventMatcher(),
+		tokenPairChecker:  NewTokenPairChecker(cfg, db, l2Client),
 		gatewayMessageOrm: orm.NewGatewayMessageMatch(db),
 
 		crossChainGatewayCheckTotal: promauto.With(prometheus.DefaultRegisterer).NewCounterVec(prometheus.CounterOpts{
@@ -40,6 +45,11 @@ func NewLogicGatewayCrossChain(db *gorm.DB) *LogicGatewayCrossChain {
 	}
 }
 
+// LoadTokenPairs persists the configured token pairs checked against the gateway messages.
+func (c *LogicGatewayCrossChain) LoadTokenPairs(ctx context.Context) error {
+	return c.tokenPairChecker.Load(ctx)
+}
+
 // CheckCrossChainGatewayMessage is a method for checking cross-chain messages.
 func (c *LogicGatewayCrossChain) CheckCrossChainGatewayMessage(ctx context.Context, layerType types.LayerType) {
 	messages, err := c.gatewayMessageOrm.GetUncheckedAndDoubleLayerValidGatewayMessageMatches(ctx, layerType, 1000)
@@ -56,18 +66,43 @@ func (c *LogicGatewayCrossChain) CheckCrossChainGatewayMessage(ctx context.Conte
 	log.Info("checking cross chain gateway messages", "layer", layerType.String(), "number of messages", len(messages))
 
 	var messageMatchIds []int64
+	var fingerprints []string
 	for _, message := range messages {
 		c.crossChainGatewayCheckTotal.WithLabelValues(layerType.String()).Inc()
 		checkResult := c.checker.GatewayCrossChainCheck(layerType, message)
+		// The token pair is only checked by the layer which finalizes the cross chain transfer, to avoid duplicated alerts.
+		if checkResult == types.MismatchTypeValid && c.checker.isFinalizeEvent(layerType, message) {
+			paired, checkErr := c.tokenPairChecker.TokenPairCheck(ctx, message)
+			if checkErr != nil {
+				log.Error("CheckCrossChainGatewayMessage.TokenPairCheck failed", "message hash", message.MessageHash, "error", checkErr)
+				continue
+			}
+			if !paired {
+				checkResult = types.MismatchTypeTokenPairNotMatch
+			}
+		}
+
 		if checkResult == types.MismatchTypeValid {
 			messageMatchIds = append(messageMatchIds, message.ID)
+			fingerprints = append(fingerprints, alert.Fingerprint(alert.CategoryCrossChainGateway, layerType, message.MessageHash, 0))
 			continue
 		}
-		slack.Notify(slack.MrkDwnGatewayCrossChainMessage(message, checkResult))
+		alert.Notify(alert.GatewayCrossChainAlert(layerType, message, checkResult))
+		if err = c.gatewayMessageOrm.UpdateCrossChainStatusFailed(ctx, message.ID, layerType, crossChainFailureReason(layerType, checkResult)); err != nil {
+			log.Error("Logic.CheckCrossChainMessage UpdateCrossChainStatusFailed failed", "message hash", message.MessageHash, "error", err)
+		}
 	}
 
 	if err = c.gatewayMessageOrm.UpdateCrossChainStatus(ctx, messageMatchIds, layerType, types.CrossChainStatusTypeValid); err != nil {
 		log.Error("Logic.CheckCrossChainMessage UpdateCrossChainStatus failed", "error", err)
 		return
 	}
+
+	// The messages which were alerted before may pass the check once the events are re-ingested.
+	alert.Resolve(ctx, fingerprints...)
+}
+
+// crossChainFailureReason the failure reason recorded in the message match whose cross chain check is failed.
+func crossChainFailureReason(layer types.LayerType, checkResult types.MismatchType) string {
+	return fmt.Sprintf("%s cross chain check: %s", layer.String(), checkResult.String())
 }
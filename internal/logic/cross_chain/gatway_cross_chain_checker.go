@@ -20,6 +20,9 @@ func NewGatewayCrossEventMatcher() *GatewayCrossEventMatcher {
 		eventMatchMap: make(map[types.EventType]types.EventType),
 	}
 
+	c.eventMatchMap[types.L2FinalizeDepositETH] = types.L1DepositETH
+	c.eventMatchMap[types.L1FinalizeWithdrawETH] = types.L2WithdrawETH
+
 	c.eventMatchMap[types.L2FinalizeDepositERC20] = types.L1DepositERC20
 	c.eventMatchMap[types.L1FinalizeWithdrawERC20] = types.L2WithdrawERC20
 
@@ -49,6 +52,19 @@ func (c *GatewayCrossEventMatcher) GatewayCrossChainCheck(layer types.LayerType,
 	return types.MismatchTypeValid
 }
 
+// isFinalizeEvent checks whether the event of the message match in the layer finalizes a cross chain transfer.
+func (c *GatewayCrossEventMatcher) isFinalizeEvent(layer types.LayerType, messageMatch orm.GatewayMessageMatch) bool {
+	var eventType types.EventType
+	switch layer {
+	case types.Layer1:
+		eventType = types.EventType(messageMatch.L1EventType)
+	case types.Layer2:
+		eventType = types.EventType(messageMatch.L2EventType)
+	}
+	_, isPresent := c.eventMatchMap[eventType]
+	return isPresent
+}
+
 // checkL1EventAndAmountMatchL2 checks that every L1FinalizeWithdraw/L1RelayedMessage has a corresponding L2 event.
 func (c *GatewayCrossEventMatcher) checkL1EventAndAmountMatchL2(messageMatch orm.GatewayMessageMatch) types.MismatchType {
 	if !c.checkL1EventMatchL2(messageMatch) {
@@ -14,7 +14,7 @@ import (
 	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
 
-	"github.com/scroll-tech/chain-monitor/internal/logic/slack"
+	"github.com/scroll-tech/chain-monitor/internal/logic/alert"
 	"github.com/scroll-tech/chain-monitor/internal/orm"
 	"github.com/scroll-tech/chain-monitor/internal/types"
 )
@@ -25,11 +25,13 @@ const ethBalanceGap = 50
 type LogicMessengerCrossChain struct {
 	db                  *gorm.DB
 	messengerMessageOrm *orm.MessengerMessageMatch
+	l1ETHRefundOrm      *orm.L1ETHRefund
 	l1Client            *ethclient.Client
 	l2Client            *ethclient.Client
 	l1MessengerAddr     common.Address
 	l2MessengerAddr     common.Address
 	checker             *MessengerCrossEventMatcher
+	relayCheckers       map[types.LayerType]*relayMessageChecker
 
 	crossChainETHTotal    *prometheus.CounterVec
 	startMessengerBalance uint64
@@ -40,11 +42,13 @@ func NewLogicMessengerCrossChain(db *gorm.DB, l1Client, l2Client *ethclient.Clie
 	return &LogicMessengerCrossChain{
 		db:                    db,
 		messengerMessageOrm:   orm.NewMessengerMessageMatch(db),
+		l1ETHRefundOrm:        orm.NewL1ETHRefund(db),
 		l1Client:              l1Client,
 		l2Client:              l2Client,
 		l1MessengerAddr:       l1MessengerAddr,
 		l2MessengerAddr:       l2MessengerAddr,
 		checker:               NewMessengerCrossEventMatcher(),
+		relayCheckers:         newRelayMessageCheckers(l1Client, l2Client, l1MessengerAddr, l2MessengerAddr),
 		startMessengerBalance: startMessengerBalance,
 
 		crossChainETHTotal: promauto.With(prometheus.DefaultRegisterer).NewCounterVec(prometheus.CounterOpts{
@@ -54,6 +58,52 @@ func NewLogicMessengerCrossChain(db *gorm.DB, l1Client, l2Client *ethclient.Clie
 	}
 }
 
+// CheckCrossChainMessengerMessage checks the relayed messages against the sent messages of the other layer, and updates
+// the cross chain statuses of the layer.
+func (c *LogicMessengerCrossChain) CheckCrossChainMessengerMessage(ctx context.Context, layerType types.LayerType) {
+	messages, err := c.messengerMessageOrm.GetUncheckedAndDoubleLayerValidMessengerMessageMatches(ctx, layerType, 1000)
+	if err != nil {
+		log.Error("CheckCrossChainMessengerMessage.GetUncheckedAndDoubleLayerValidMessengerMessageMatches failed", "error", err)
+		return
+	}
+
+	if len(messages) == 0 {
+		return
+	}
+
+	var messageMatchIds []int64
+	for i := range messages {
+		message := messages[i]
+		checkResult := c.checker.MessengerCrossChainCheck(layerType, &message)
+		// The relay tx input on the layer must carry the message sent on the other layer.
+		if txHash := relayTxHash(layerType, &message); checkResult == types.MismatchTypeValid && txHash != "" {
+			relayed, checkErr := c.relayCheckers[layerType].check(ctx, txHash, message.MessageHash)
+			if checkErr != nil {
+				log.Error("CheckCrossChainMessengerMessage check relay tx failed", "message hash", message.MessageHash, "error", checkErr)
+				continue
+			}
+			if !relayed {
+				checkResult = types.MismatchTypeMessageHashNotMatch
+			}
+		}
+		if checkResult == types.MismatchTypeValid {
+			messageMatchIds = append(messageMatchIds, message.ID)
+			continue
+		}
+		alert.Notify(alert.MessengerCrossChainAlert(layerType, message, checkResult))
+		if err = c.messengerMessageOrm.UpdateCrossChainStatusFailed(ctx, message.ID, layerType, crossChainFailureReason(layerType, checkResult)); err != nil {
+			log.Error("CheckCrossChainMessengerMessage.UpdateCrossChainStatusFailed failed", "message hash", message.MessageHash, "error", err)
+		}
+	}
+
+	if len(messageMatchIds) == 0 {
+		return
+	}
+	if err = c.messengerMessageOrm.UpdateCrossChainStatus(ctx, messageMatchIds, layerType, types.CrossChainStatusTypeValid); err != nil {
+		log.Error("CheckCrossChainMessengerMessage.UpdateCrossChainStatus failed", "error", err)
+	}
+}
+
 // CheckETHBalance checks the ETH balance for the given Ethereum layer (either Layer1 or Layer2).
 func (c *LogicMessengerCrossChain) CheckETHBalance(ctx context.Context, layerType types.LayerType) {
 	log.Info("CheckETHBalance started", "layer type", layerType)
@@ -64,7 +114,7 @@ func (c *LogicMessengerCrossChain) CheckETHBalance(ctx context.Context, layerTyp
 		return
 	}
 
-	startBalance, err := c.messengerMessageOrm.GetETHCheckStartBlockNumberAndBalance(ctx, layerType)
+	checkedBlockNumber, startBalance, err := c.messengerMessageOrm.GetETHCheckStartBlockNumberAndBalance(ctx, layerType)
 	if err != nil {
 		log.Error("c.messageOrm GetETHCheckStartBlockNumberAndBalance failed", "layer type", layerType, "error", err)
 		return
@@ -153,11 +203,26 @@ func (c *LogicMessengerCrossChain) CheckETHBalance(ctx context.Context, layerTyp
 		endBlockNumber = truncatedMessageMatches[len(truncatedMessageMatches)-1].L2BlockNumber
 	}
 
-	c.checkETH(ctx, layerType, startBlockNumber, endBlockNumber, latestBlockNumber, startBalance, truncatedMessageMatches)
+	// The refunds of the dropped messages since the last checked block are taken out of the l1 messenger balance.
+	var blockRefunds map[uint64]*big.Int
+	if layerType == types.Layer1 {
+		refunds, refundErr := c.l1ETHRefundOrm.GetRefundsByBlockRange(ctx, checkedBlockNumber+1, endBlockNumber)
+		if refundErr != nil {
+			log.Error("CheckETHBalance.GetRefundsByBlockRange failed", "start", checkedBlockNumber+1, "end", endBlockNumber, "error", refundErr)
+			return
+		}
+		blockRefunds, err = newBlockRefunds(truncatedMessageMatches, refunds)
+		if err != nil {
+			log.Error("CheckETHBalance sum the l1 eth refunds failed", "start", checkedBlockNumber+1, "end", endBlockNumber, "error", err)
+			return
+		}
+	}
+
+	c.checkETH(ctx, layerType, startBlockNumber, endBlockNumber, latestBlockNumber, startBalance, truncatedMessageMatches, blockRefunds)
 	log.Info("CheckETHBalance completed", "layer type", layerType, "start", startBlockNumber, "end", endBlockNumber)
 }
 
-func (c *LogicMessengerCrossChain) checkETH(ctx context.Context, layer types.LayerType, startBlockNumber, endBlockNumber, latestBlockNumber uint64, startBalance *big.Int, messages []*orm.MessengerMessageMatch) {
+func (c *LogicMessengerCrossChain) checkETH(ctx context.Context, layer types.LayerType, startBlockNumber, endBlockNumber, latestBlockNumber uint64, startBalance *big.Int, messages []*orm.MessengerMessageMatch, blockRefunds map[uint64]*big.Int) {
 	var messengerAddr common.Address
 	var client *ethclient.Client
 	if layer == types.Layer1 {
@@ -173,7 +238,7 @@ func (c *LogicMessengerCrossChain) checkETH(ctx context.Context, layer types.Lay
 	// because balanceAt can't get the too early block balance, so only can compute the locally l1 messenger balance and
 	// update the l1_messenger_eth_balance/l2_messenger_eth_balance
 	if layer == types.Layer1 && endBlockNumber+ethBalanceGap < latestBlockNumber {
-		c.computeBlockBalance(ctx, layer, messages, startBalance)
+		c.computeBlockBalance(ctx, layer, messages, startBalance, blockRefunds)
 		return
 	}
 
@@ -183,80 +248,77 @@ func (c *LogicMessengerCrossChain) checkETH(ctx context.Context, layer types.Lay
 		return
 	}
 
-	ok, expectedEndBalance, actualBalance, err := c.checkBalance(layer, startBalance, endBalance, messages)
+	ok, expectedEndBalance, actualBalance, err := c.checkBalance(layer, startBalance, endBalance, messages, blockRefunds)
 	if err != nil {
 		log.Error("checkLayer1Balance failed", "startBlock", startBlockNumber, "endBlock", endBlockNumber, "expectedEndBalance", expectedEndBalance, "actualBalance", actualBalance, "err", err)
 		return
 	}
 
 	if !ok {
-		c.checkBlockBalanceOneByOne(ctx, client, messengerAddr, layer, messages)
+		c.checkBlockBalanceOneByOne(ctx, client, messengerAddr, layer, startBalance, messages, blockRefunds)
 		return
 	}
 
 	// get all the eth status valid, and update the eth balance status and eth balance
-	c.computeBlockBalance(ctx, layer, messages, startBalance)
+	c.computeBlockBalance(ctx, layer, messages, startBalance, blockRefunds)
 }
 
-func (c *LogicMessengerCrossChain) checkBlockBalanceOneByOne(ctx context.Context, client *ethclient.Client, messengerAddr common.Address, layer types.LayerType, messages []*orm.MessengerMessageMatch) {
-	var startBalance *big.Int
-	var startIndex int
-	for idx, message := range messages {
-		c.checker.MessengerCrossChainCheck(layer, message)
-
-		var blockNumber uint64
-		if layer == types.Layer1 {
-			blockNumber = message.L1BlockNumber
-		} else {
-			blockNumber = message.L2BlockNumber
+// checkBlockBalanceOneByOne checks the messenger balance block by block after the balance of the range mismatches. The
+// messages of the mismatched blocks are marked failed with the actual balances, so the next blocks are checked from them.
+func (c *LogicMessengerCrossChain) checkBlockBalanceOneByOne(ctx context.Context, client *ethclient.Client, messengerAddr common.Address, layer types.LayerType, startBalance *big.Int, messages []*orm.MessengerMessageMatch, blockRefunds map[uint64]*big.Int) {
+	var updateETHMessageMatches []orm.MessengerMessageMatch
+	lastBlockBalance := startBalance
+	for start := 0; start < len(messages); {
+		blockNumber := messageBlockNumber(layer, messages[start])
+		end := start + 1
+		for end < len(messages) && messageBlockNumber(layer, messages[end]) == blockNumber {
+			end++
 		}
 
-		tmpBalance, err := client.BalanceAt(ctx, messengerAddr, new(big.Int).SetUint64(blockNumber))
+		actualBalance, err := client.BalanceAt(ctx, messengerAddr, new(big.Int).SetUint64(blockNumber))
 		if err != nil {
 			log.Error("get balance failed", "block number", blockNumber, "err", err)
-			continue
-		}
-
-		startBalance = tmpBalance
-		startIndex = idx
-		break
-	}
-
-	for i := startIndex + 1; i < len(messages); i++ {
-		var blockNumber uint64
-		if layer == types.Layer1 {
-			blockNumber = messages[i].L1BlockNumber
-		} else {
-			blockNumber = messages[i].L2BlockNumber
+			break
 		}
 
-		if layer == types.Layer1 && (i+1 != len(messages) && (messages[i].L1BlockNumber == messages[i+1].L1BlockNumber)) {
-			continue
+		ok, expectedBalance, _, err := c.checkBalance(layer, lastBlockBalance, actualBalance, messages[start:end], blockRefunds)
+		if err != nil {
+			log.Error("balance check failed", "block", blockNumber, "err", err)
+			break
 		}
 
-		if layer == types.Layer2 && (i+1 != len(messages) && (messages[i].L2BlockNumber == messages[i+1].L2BlockNumber)) {
-			continue
+		status := types.ETHBalanceStatusTypeValid
+		var failureReason string
+		if !ok {
+			alert.Notify(alert.CrossChainETHAlert(layer, messages[end-1], expectedBalance, actualBalance))
+			status = types.ETHBalanceStatusTypeFailed
+			failureReason = fmt.Sprintf("%s eth balance check: expected messenger balance %s, actual balance %s at block %d",
+				layer.String(), expectedBalance.String(), actualBalance.String(), blockNumber)
 		}
-
-		endBalance, err := client.BalanceAt(ctx, messengerAddr, new(big.Int).SetUint64(blockNumber))
-		if err != nil {
-			continue
+		for _, message := range messages[start:end] {
+			updateETHMessageMatches = append(updateETHMessageMatches, newETHBalanceMessageMatch(layer, message.ID, actualBalance, status, failureReason))
 		}
 
-		ok, expectedEndBalance, actualBalance, err := c.checkBalance(layer, startBalance, endBalance, messages[startIndex:i+1])
-		if !ok || err != nil {
-			log.Error("balance check failed", "block", blockNumber, "expectedEndBalance", expectedEndBalance.String(), "actualBalance", actualBalance.String())
-			slack.MrkDwnETHGatewayMessage(messages[i], expectedEndBalance, actualBalance)
-			continue
-		}
+		lastBlockBalance = actualBalance
+		start = end
 	}
+
+	c.updateETHBalances(ctx, layer, updateETHMessageMatches)
 }
 
-func (c *LogicMessengerCrossChain) checkBalance(layer types.LayerType, startBalance, endBalance *big.Int, messages []*orm.MessengerMessageMatch) (bool, *big.Int, *big.Int, error) {
+func (c *LogicMessengerCrossChain) checkBalance(layer types.LayerType, startBalance, endBalance *big.Int, messages []*orm.MessengerMessageMatch, blockRefunds map[uint64]*big.Int) (bool, *big.Int, *big.Int, error) {
 	balanceDiff := big.NewInt(0)
+	lastBlockNumber := uint64(0)
 	for _, message := range messages {
 		c.crossChainETHTotal.WithLabelValues(layer.String()).Inc()
 
+		if blockNumber := messageBlockNumber(layer, message); blockNumber != lastBlockNumber {
+			if refund, ok := blockRefunds[blockNumber]; ok {
+				balanceDiff = new(big.Int).Sub(balanceDiff, refund)
+			}
+			lastBlockNumber = blockNumber
+		}
+
 		var amount *big.Int
 		var ok bool
 		amount, ok = new(big.Int).SetString(message.ETHAmount, 10)
@@ -294,11 +356,9 @@ func (c *LogicMessengerCrossChain) checkBalance(layer types.LayerType, startBala
 	return false, expectedEndBalance, endBalance, nil
 }
 
-func (c *LogicMessengerCrossChain) computeBlockBalance(ctx context.Context, layer types.LayerType, messages []*orm.MessengerMessageMatch, messengerETHBalance *big.Int) {
+func (c *LogicMessengerCrossChain) computeBlockBalance(ctx context.Context, layer types.LayerType, messages []*orm.MessengerMessageMatch, messengerETHBalance *big.Int, blockRefunds map[uint64]*big.Int) {
 	blockNumberAmountMap := make(map[uint64]*big.Int)
 	for _, message := range messages {
-		c.checker.MessengerCrossChainCheck(layer, message)
-
 		if layer == types.Layer1 {
 			if _, ok := blockNumberAmountMap[message.L1BlockNumber]; !ok {
 				blockNumberAmountMap[message.L1BlockNumber] = new(big.Int)
@@ -351,21 +411,21 @@ func (c *LogicMessengerCrossChain) computeBlockBalance(ctx context.Context, laye
 
 		if blockNumber != lastBlockNumber {
 			lastBlockBalance.Add(lastBlockBalance, blockNumberAmountMap[blockNumber])
+			if refund, ok := blockRefunds[blockNumber]; ok {
+				lastBlockBalance.Sub(lastBlockBalance, refund)
+			}
 			lastBlockNumber = blockNumber
 		}
 
 		// update the db
-		mm := orm.MessengerMessageMatch{ID: v.ID}
-		if layer == types.Layer1 {
-			mm.L1MessengerETHBalance = decimal.NewFromBigInt(lastBlockBalance, 0)
-			mm.L1ETHBalanceStatus = int(types.ETHBalanceStatusTypeValid)
-		} else {
-			mm.L2MessengerETHBalance = decimal.NewFromBigInt(lastBlockBalance, 0)
-			mm.L2ETHBalanceStatus = int(types.ETHBalanceStatusTypeValid)
-		}
-		updateETHMessageMatches = append(updateETHMessageMatches, mm)
+		updateETHMessageMatches = append(updateETHMessageMatches, newETHBalanceMessageMatch(layer, v.ID, lastBlockBalance, types.ETHBalanceStatusTypeValid, ""))
 	}
 
+	c.updateETHBalances(ctx, layer, updateETHMessageMatches)
+}
+
+// updateETHBalances updates the eth balances and the eth balance statuses of the message matches in a transaction.
+func (c *LogicMessengerCrossChain) updateETHBalances(ctx context.Context, layer types.LayerType, updateETHMessageMatches []orm.MessengerMessageMatch) {
 	// Sort the updateETHMessageMatches slice by id to prevent "ERROR: deadlock detected (SQLSTATE 40P01)"
 	// when simultaneously updating rows of postgres in a transaction by L1 & L2 eth balance checkers.
 	sort.Slice(updateETHMessageMatches, func(i, j int) bool {
@@ -386,6 +446,53 @@ func (c *LogicMessengerCrossChain) computeBlockBalance(ctx context.Context, laye
 	}
 }
 
+// newBlockRefunds sums the l1 eth refunds up by the message blocks. The refunds of the blocks without any message are
+// taken by the next message block, so the balance of a message block counts the refunds since the last message block.
+func newBlockRefunds(messages []*orm.MessengerMessageMatch, refunds []orm.L1ETHRefund) (map[uint64]*big.Int, error) {
+	blockRefunds := make(map[uint64]*big.Int)
+	var i int
+	for _, refund := range refunds {
+		amount, ok := new(big.Int).SetString(refund.Amount, 10)
+		if !ok {
+			return nil, fmt.Errorf("database id:%d invalid refund amount value: %v", refund.ID, refund.Amount)
+		}
+
+		for i < len(messages) && messages[i].L1BlockNumber < refund.BlockNumber {
+			i++
+		}
+		if i == len(messages) {
+			// the refunds after the last message block are checked with the next messages.
+			break
+		}
+
+		blockNumber := messages[i].L1BlockNumber
+		if _, ok := blockRefunds[blockNumber]; !ok {
+			blockRefunds[blockNumber] = new(big.Int)
+		}
+		blockRefunds[blockNumber].Add(blockRefunds[blockNumber], amount)
+	}
+	return blockRefunds, nil
+}
+
+func newETHBalanceMessageMatch(layer types.LayerType, id int64, messengerETHBalance *big.Int, status types.ETHBalanceStatus, failureReason string) orm.MessengerMessageMatch {
+	mm := orm.MessengerMessageMatch{ID: id, FailureReason: failureReason}
+	if layer == types.Layer1 {
+		mm.L1MessengerETHBalance = decimal.NewFromBigInt(messengerETHBalance, 0)
+		mm.L1ETHBalanceStatus = int(status)
+	} else {
+		mm.L2MessengerETHBalance = decimal.NewFromBigInt(messengerETHBalance, 0)
+		mm.L2ETHBalanceStatus = int(status)
+	}
+	return mm
+}
+
+func messageBlockNumber(layer types.LayerType, message *orm.MessengerMessageMatch) uint64 {
+	if layer == types.Layer1 {
+		return message.L1BlockNumber
+	}
+	return message.L2BlockNumber
+}
+
 func (c *LogicMessengerCrossChain) getLatestBlockNumber(ctx context.Context, layerType types.LayerType) (uint64, error) {
 	switch layerType {
 	case types.Layer1:
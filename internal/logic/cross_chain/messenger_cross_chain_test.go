@@ -0,0 +1,66 @@
+package crosschain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scroll-tech/chain-monitor/internal/orm"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+)
+
+func TestNewBlockRefunds(t *testing.T) {
+	messages := []*orm.MessengerMessageMatch{
+		{ID: 1, L1EventType: int(types.L1SentMessage), L1BlockNumber: 10, ETHAmount: "100"},
+		{ID: 2, L1EventType: int(types.L1SentMessage), L1BlockNumber: 10, ETHAmount: "100"},
+		{ID: 3, L1EventType: int(types.L1RelayedMessage), L1BlockNumber: 14, ETHAmount: "30"},
+	}
+	refunds := []orm.L1ETHRefund{
+		{ID: 1, BlockNumber: 8, Amount: "1"},
+		{ID: 2, BlockNumber: 10, Amount: "2"},
+		{ID: 3, BlockNumber: 12, Amount: "4"},
+		{ID: 4, BlockNumber: 14, Amount: "8"},
+		{ID: 5, BlockNumber: 15, Amount: "16"},
+	}
+
+	// the refunds before a message block are taken by it, the refunds after the last message block are left.
+	blockRefunds, err := newBlockRefunds(messages, refunds)
+	assert.NoError(t, err)
+	assert.Equal(t, map[uint64]*big.Int{10: big.NewInt(3), 14: big.NewInt(12)}, blockRefunds)
+
+	_, err = newBlockRefunds(messages, []orm.L1ETHRefund{{ID: 6, BlockNumber: 10, Amount: "0x1"}})
+	assert.ErrorContains(t, err, "database id:6 invalid refund amount value: 0x1")
+}
+
+func TestCheckBalance_L1Refunds(t *testing.T) {
+	c := &LogicMessengerCrossChain{
+		crossChainETHTotal: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_cross_chain_checked_eth_total"}, []string{"layer"}),
+	}
+
+	messages := []*orm.MessengerMessageMatch{
+		{ID: 1, L1EventType: int(types.L1SentMessage), L1BlockNumber: 10, ETHAmount: "100"},
+		{ID: 2, L1EventType: int(types.L1RelayedMessage), L1BlockNumber: 11, ETHAmount: "30"},
+		{ID: 3, L1EventType: int(types.L1SentMessage), L1BlockNumber: 12, ETHAmount: "0"},
+		{ID: 4, L1EventType: int(types.L1SentMessage), L1BlockNumber: 12, ETHAmount: "0"},
+	}
+	blockRefunds := map[uint64]*big.Int{12: big.NewInt(25)}
+
+	ok, expected, _, err := c.checkBalance(types.Layer1, big.NewInt(1000), big.NewInt(1045), messages, blockRefunds)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, big.NewInt(1045), expected)
+
+	// the refunds left out of the messenger balance are caught.
+	ok, expected, _, err = c.checkBalance(types.Layer1, big.NewInt(1000), big.NewInt(1070), messages, blockRefunds)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, big.NewInt(1045), expected)
+
+	// the refunds of a block are taken once, the balance of the block is checked from the balance of the last block.
+	ok, expected, _, err = c.checkBalance(types.Layer1, big.NewInt(1070), big.NewInt(1045), messages[2:], blockRefunds)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, big.NewInt(1045), expected)
+}
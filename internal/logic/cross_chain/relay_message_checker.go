@@ -0,0 +1,115 @@
+package crosschain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/scroll-tech/go-ethereum/accounts/abi"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/ethclient"
+	"github.com/scroll-tech/go-ethereum/log"
+
+	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/il1scrollmessenger"
+	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/il2scrollmessenger"
+	"github.com/scroll-tech/chain-monitor/internal/orm"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+	"github.com/scroll-tech/chain-monitor/internal/utils"
+)
+
+// errUnknownRelayCalldata is returned if the relay tx input isn't a relay call of the messenger.
+var errUnknownRelayCalldata = errors.New("unknown relay calldata")
+
+// relayMessageChecker checks the message relayed on the destination layer is the one sent on the source layer, by
+// hashing the message fields of the relay tx input, relayMessage(from,to,value,nonce,message) on l2 and
+// relayMessageWithProof(from,to,value,nonce,message,proof) on l1.
+type relayMessageChecker struct {
+	client        *ethclient.Client
+	messengerAddr common.Address
+	messengerABI  *abi.ABI
+}
+
+// newRelayMessageCheckers returns the relay message checkers of the layers where the messages are relayed.
+func newRelayMessageCheckers(l1Client, l2Client *ethclient.Client, l1MessengerAddr, l2MessengerAddr common.Address) map[types.LayerType]*relayMessageChecker {
+	return map[types.LayerType]*relayMessageChecker{
+		types.Layer1: newRelayMessageChecker(types.Layer1, l1Client, l1MessengerAddr),
+		types.Layer2: newRelayMessageChecker(types.Layer2, l2Client, l2MessengerAddr),
+	}
+}
+
+func newRelayMessageChecker(layer types.LayerType, client *ethclient.Client, messengerAddr common.Address) *relayMessageChecker {
+	metaData := il1scrollmessenger.Il1scrollmessengerMetaData
+	if layer == types.Layer2 {
+		metaData = il2scrollmessenger.Il2scrollmessengerMetaData
+	}
+	messengerABI, err := metaData.GetAbi()
+	if err != nil {
+		log.Crit("get messenger abi failed", "layer", layer.String(), "error", err)
+	}
+	return &relayMessageChecker{
+		client:        client,
+		messengerAddr: messengerAddr,
+		messengerABI:  messengerABI,
+	}
+}
+
+// check returns whether the message relayed by the tx hashes to the sent message hash. The relay tx which isn't sent to
+// the messenger, such as the one relaying through a contract wallet, can't be decoded and is skipped. The error is only
+// returned if the relay tx can't be fetched.
+func (r *relayMessageChecker) check(ctx context.Context, relayTxHash string, messageHash string) (bool, error) {
+	tx, _, err := r.client.TransactionByHash(ctx, common.HexToHash(relayTxHash))
+	if err != nil {
+		return false, fmt.Errorf("get relay tx failed, tx hash: %v, err: %w", relayTxHash, err)
+	}
+	if tx.To() == nil || *tx.To() != r.messengerAddr {
+		log.Warn("relay tx isn't sent to the messenger, skip the message hash check", "tx hash", relayTxHash, "to", tx.To(), "message hash", messageHash)
+		return true, nil
+	}
+
+	relayedMessageHash, err := decodeRelayMessageHash(r.messengerABI, tx.Data())
+	if err != nil {
+		log.Error("decode relay tx input failed", "tx hash", relayTxHash, "message hash", messageHash, "error", err)
+		return false, nil
+	}
+	return relayedMessageHash == common.HexToHash(messageHash), nil
+}
+
+// decodeRelayMessageHash returns the hash of the message fields of the relay calldata.
+func decodeRelayMessageHash(messengerABI *abi.ABI, calldata []byte) (common.Hash, error) {
+	if len(calldata) < 4 {
+		return common.Hash{}, fmt.Errorf("%w: calldata length %d", errUnknownRelayCalldata, len(calldata))
+	}
+	method, err := messengerABI.MethodById(calldata[:4])
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("%w: %v", errUnknownRelayCalldata, err)
+	}
+	if method.Name != "relayMessage" && method.Name != "relayMessageWithProof" {
+		return common.Hash{}, fmt.Errorf("%w: method %v", errUnknownRelayCalldata, method.Name)
+	}
+
+	args, err := method.Inputs.Unpack(calldata[4:])
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("%w: unpack %v failed, err: %v", errUnknownRelayCalldata, method.Name, err)
+	}
+	from, fromOk := args[0].(common.Address)
+	to, toOk := args[1].(common.Address)
+	value, valueOk := args[2].(*big.Int)
+	nonce, nonceOk := args[3].(*big.Int)
+	message, messageOk := args[4].([]byte)
+	if !fromOk || !toOk || !valueOk || !nonceOk || !messageOk {
+		return common.Hash{}, fmt.Errorf("%w: invalid %v arguments", errUnknownRelayCalldata, method.Name)
+	}
+	return utils.ComputeMessageHash(from, to, value, nonce, message), nil
+}
+
+// relayTxHash returns the relay tx hash of the message relayed on the layer, empty if the message isn't relayed on it.
+func relayTxHash(layer types.LayerType, message *orm.MessengerMessageMatch) string {
+	if layer == types.Layer1 && types.EventType(message.L1EventType) == types.L1RelayedMessage {
+		return message.L1TxHash
+	}
+	if layer == types.Layer2 && types.EventType(message.L2EventType) == types.L2RelayedMessage {
+		return message.L2TxHash
+	}
+	return ""
+}
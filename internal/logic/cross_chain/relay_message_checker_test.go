@@ -0,0 +1,95 @@
+package crosschain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/crypto"
+	"github.com/scroll-tech/go-ethereum/ethclient"
+	"github.com/scroll-tech/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/il1scrollmessenger"
+	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/il2scrollmessenger"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+	"github.com/scroll-tech/chain-monitor/internal/utils"
+)
+
+// relayTxService serves the relay txs by the tx hashes.
+type relayTxService struct {
+	txs map[common.Hash]*gethTypes.Transaction
+}
+
+func (s *relayTxService) GetTransactionByHash(hash common.Hash) (*gethTypes.Transaction, error) {
+	tx, ok := s.txs[hash]
+	if !ok {
+		return nil, fmt.Errorf("unknown tx %v", hash)
+	}
+	return tx, nil
+}
+
+func TestRelayMessageChecker_Check(t *testing.T) {
+	l1MessengerAddr := common.HexToAddress("0x6774Bcbd5ceCeF1336b5300fb5186a12DDD8b367")
+	l2MessengerAddr := common.HexToAddress("0x781e90f1c8Fc4611c9b7497C3B47F99Ef6969CbC")
+	from, to := common.HexToAddress("0x1"), common.HexToAddress("0x2")
+	value, nonce, message := big.NewInt(100), big.NewInt(7), []byte{0xde, 0xad}
+	messageHash := utils.ComputeMessageHash(from, to, value, nonce, message).Hex()
+
+	l1MessengerABI, err := il1scrollmessenger.Il1scrollmessengerMetaData.GetAbi()
+	assert.NoError(t, err)
+	l2MessengerABI, err := il2scrollmessenger.Il2scrollmessengerMetaData.GetAbi()
+	assert.NoError(t, err)
+	relayMessage, err := l2MessengerABI.Pack("relayMessage", from, to, value, nonce, message)
+	assert.NoError(t, err)
+	tamperedRelayMessage, err := l2MessengerABI.Pack("relayMessage", from, to, big.NewInt(1000), nonce, message)
+	assert.NoError(t, err)
+	sendMessage, err := l2MessengerABI.Pack("sendMessage", to, value, message, big.NewInt(0), from)
+	assert.NoError(t, err)
+	relayMessageWithProof, err := l1MessengerABI.Pack("relayMessageWithProof", from, to, value, nonce, message,
+		il1scrollmessenger.IL1ScrollMessengerL2MessageProof{BatchIndex: big.NewInt(1), MerkleProof: []byte{}})
+	assert.NoError(t, err)
+
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	service := &relayTxService{txs: make(map[common.Hash]*gethTypes.Transaction)}
+	newTx := func(to common.Address, data []byte) string {
+		tx, signErr := gethTypes.SignTx(gethTypes.NewTransaction(uint64(len(service.txs)), to, big.NewInt(0), 1000000, big.NewInt(1), data), gethTypes.HomesteadSigner{}, key)
+		assert.NoError(t, signErr)
+		service.txs[tx.Hash()] = tx
+		return tx.Hash().Hex()
+	}
+	server := rpc.NewServer()
+	assert.NoError(t, server.RegisterName("eth", service))
+	defer server.Stop()
+	client := ethclient.NewClient(rpc.DialInProc(server))
+
+	checkers := newRelayMessageCheckers(client, client, l1MessengerAddr, l2MessengerAddr)
+	tests := []struct {
+		name        string
+		layer       types.LayerType
+		relayTxHash string
+		wantRelayed bool
+	}{
+		{"l2Relayed", types.Layer2, newTx(l2MessengerAddr, relayMessage), true},
+		{"l2Tampered", types.Layer2, newTx(l2MessengerAddr, tamperedRelayMessage), false},
+		{"l2NotRelayCall", types.Layer2, newTx(l2MessengerAddr, sendMessage), false},
+		{"l1RelayedWithProof", types.Layer1, newTx(l1MessengerAddr, relayMessageWithProof), true},
+		// the relay tx through a contract wallet can't be decoded.
+		{"notSentToMessenger", types.Layer1, newTx(common.HexToAddress("0x3"), []byte{0x01}), true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			relayed, err := checkers[test.layer].check(context.Background(), test.relayTxHash, messageHash)
+			assert.NoError(t, err)
+			assert.Equal(t, test.wantRelayed, relayed)
+		})
+	}
+
+	_, err = checkers[types.Layer2].check(context.Background(), common.HexToHash("0x4").Hex(), messageHash)
+	assert.Error(t, err)
+}
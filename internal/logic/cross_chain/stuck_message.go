@@ -0,0 +1,193 @@
+package crosschain
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"github.com/scroll-tech/chain-monitor/internal/config"
+	"github.com/scroll-tech/chain-monitor/internal/logic/alert"
+	"github.com/scroll-tech/chain-monitor/internal/orm"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+	"github.com/scroll-tech/chain-monitor/internal/utils"
+)
+
+const (
+	defaultL1ToL2Threshold uint64 = 1800
+	defaultL2ToL1Threshold uint64 = 86400
+
+	stuckMessageBatchSize = 1000
+)
+
+// pendingMessageAgeBuckets the upper bounds of the pending message age histogram.
+var pendingMessageAgeBuckets = []time.Duration{
+	time.Minute,
+	5 * time.Minute,
+	15 * time.Minute,
+	30 * time.Minute,
+	time.Hour,
+	3 * time.Hour,
+	6 * time.Hour,
+	12 * time.Hour,
+	24 * time.Hour,
+	3 * 24 * time.Hour,
+	7 * 24 * time.Hour,
+}
+
+// pendingMessageAges is registered once, the collectors of the LogicStuckMessage instances share it.
+var pendingMessageAges = newPendingMessageAgeCollector()
+
+func init() {
+	prometheus.DefaultRegisterer.MustRegister(pendingMessageAges)
+}
+
+// LogicStuckMessage is a struct for checking the messages which are sent but not relayed on the other layer in time.
+// A l1 sent message is stuck if it isn't relayed on l2 within the l1 to l2 threshold since the block time it's sent,
+// and a l2 sent message is stuck if it isn't relayed on l1 within the l2 to l1 threshold since the block time the batch
+// including it is finalized, it can't be relayed before. The stuck messages are marked in db once they're alerted.
+type LogicStuckMessage struct {
+	messengerMessageMatchOrm *orm.MessengerMessageMatch
+	alertOrm                 *orm.Alert
+	thresholds               map[types.LayerType]time.Duration
+}
+
+// NewLogicStuckMessage is a constructor for LogicStuckMessage.
+func NewLogicStuckMessage(cfg *config.MessageSLAConfig, db *gorm.DB) *LogicStuckMessage {
+	l1ToL2Threshold := defaultL1ToL2Threshold
+	l2ToL1Threshold := defaultL2ToL1Threshold
+	if cfg != nil {
+		if cfg.L1ToL2Threshold != 0 {
+			l1ToL2Threshold = cfg.L1ToL2Threshold
+		}
+		if cfg.L2ToL1Threshold != 0 {
+			l2ToL1Threshold = cfg.L2ToL1Threshold
+		}
+	}
+
+	return &LogicStuckMessage{
+		messengerMessageMatchOrm: orm.NewMessengerMessageMatch(db),
+		alertOrm:                 orm.NewAlert(db),
+		thresholds: map[types.LayerType]time.Duration{
+			types.Layer1: time.Duration(l1ToL2Threshold) * time.Second,
+			types.Layer2: time.Duration(l2ToL1Threshold) * time.Second,
+		},
+	}
+}
+
+// CheckStuckMessage checks the messages sent on the given layer which are not relayed on the other layer yet.
+// It updates the pending message age histogram, alerts the messages exceeding the threshold, and resolves
+// the alerts of the messages which have been relayed since.
+func (c *LogicStuckMessage) CheckStuckMessage(ctx context.Context, layerType types.LayerType) {
+	ages, err := c.messengerMessageMatchOrm.GetPendingMessageAges(ctx, layerType, pendingMessageAgeBuckets)
+	if err != nil {
+		log.Error("CheckStuckMessage.GetPendingMessageAges failed", "layer", layerType, "error", err)
+	} else {
+		pendingMessageAges.set(layerType, ages)
+	}
+
+	c.resolveRelayedMessages(ctx, layerType)
+
+	threshold := c.thresholds[layerType]
+	now := utils.NowUTC()
+	messages, err := c.messengerMessageMatchOrm.GetPendingMessages(ctx, layerType, now.Add(-threshold), stuckMessageBatchSize)
+	if err != nil {
+		log.Error("CheckStuckMessage.GetPendingMessages failed", "layer", layerType, "error", err)
+		return
+	}
+
+	// The remaining stuck messages are alerted in the next check if there are more than the batch size.
+	var messageHashes []string
+	for _, message := range messages {
+		alert.Notify(alert.StuckMessageAlert(layerType, message.MessengerMessageMatch, now.Sub(message.PendingSince), threshold))
+		messageHashes = append(messageHashes, message.MessageHash)
+	}
+	if err := c.messengerMessageMatchOrm.UpdateStuckAlertedAt(ctx, messageHashes); err != nil {
+		log.Error("CheckStuckMessage.UpdateStuckAlertedAt failed", "layer", layerType, "error", err)
+	}
+}
+
+func (c *LogicStuckMessage) resolveRelayedMessages(ctx context.Context, layerType types.LayerType) {
+	firingAlerts, err := c.alertOrm.GetFiringAlerts(ctx, string(alert.CategoryStuckMessage), stuckMessageBatchSize)
+	if err != nil {
+		log.Error("CheckStuckMessage.GetFiringAlerts failed", "layer", layerType, "error", err)
+		return
+	}
+
+	var messageHashes []string
+	for _, firingAlert := range firingAlerts {
+		if types.LayerType(firingAlert.Layer) == layerType {
+			messageHashes = append(messageHashes, firingAlert.MessageHash)
+		}
+	}
+	if len(messageHashes) == 0 {
+		return
+	}
+
+	messageMatches, err := c.messengerMessageMatchOrm.GetMessageMatchesByMessageHashes(ctx, messageHashes)
+	if err != nil {
+		log.Error("CheckStuckMessage.GetMessageMatchesByMessageHashes failed", "layer", layerType, "error", err)
+		return
+	}
+
+	var fingerprints []string
+	for _, messageMatch := range messageMatches {
+		if (layerType == types.Layer1 && messageMatch.L2EventType != int(types.EventTypeUnknown)) ||
+			(layerType == types.Layer2 && messageMatch.L1EventType != int(types.EventTypeUnknown)) {
+			fingerprints = append(fingerprints, alert.Fingerprint(alert.CategoryStuckMessage, layerType, messageMatch.MessageHash, 0))
+		}
+	}
+	alert.Resolve(ctx, fingerprints...)
+}
+
+// pendingMessageAgeCollector exposes the age distribution of the pending messages as a histogram,
+// it's a snapshot of the last check rather than an accumulation of the observations.
+type pendingMessageAgeCollector struct {
+	desc *prometheus.Desc
+
+	mu   sync.Mutex
+	ages map[types.LayerType]*orm.PendingMessageAges
+}
+
+func newPendingMessageAgeCollector() *pendingMessageAgeCollector {
+	return &pendingMessageAgeCollector{
+		desc: prometheus.NewDesc(
+			"cross_chain_pending_message_age_seconds",
+			"The age of the messages which are sent but not relayed on the other layer yet.",
+			[]string{"layer"}, nil,
+		),
+		ages: make(map[types.LayerType]*orm.PendingMessageAges),
+	}
+}
+
+func (c *pendingMessageAgeCollector) set(layer types.LayerType, ages *orm.PendingMessageAges) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ages[layer] = ages
+}
+
+// Describe implements prometheus.Collector.
+func (c *pendingMessageAgeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect implements prometheus.Collector.
+func (c *pendingMessageAgeCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for layer, ages := range c.ages {
+		buckets := make(map[float64]uint64, len(pendingMessageAgeBuckets))
+		for i, bound := range pendingMessageAgeBuckets {
+			buckets[bound.Seconds()] = ages.BucketCounts[i]
+		}
+		metric, err := prometheus.NewConstHistogram(c.desc, ages.Count, ages.AgeSum, buckets, layer.String())
+		if err != nil {
+			log.Error("create pending message age histogram failed", "layer", layer, "error", err)
+			continue
+		}
+		ch <- metric
+	}
+}
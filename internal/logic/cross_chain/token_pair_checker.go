@@ -0,0 +1,198 @@
+package crosschain
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/scroll-tech/go-ethereum/accounts/abi/bind"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/ethclient"
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"github.com/scroll-tech/chain-monitor/internal/config"
+	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/il2erc20gateway"
+	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/iscrollerc1155"
+	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/iscrollerc721"
+	"github.com/scroll-tech/chain-monitor/internal/orm"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+)
+
+// TokenPairChecker checks the l1 token and the l2 token of the gateway message are paired.
+// The configured token pairs take precedence, otherwise the token pair is learned from
+// the first checked gateway message of the token, once it's verified by the registered l2 gateways.
+type TokenPairChecker struct {
+	tokenPairOrm *orm.TokenPair
+	l2Client     *ethclient.Client
+
+	configuredTokenPairs []*config.TokenPair
+	configuredL1ToL2     map[string]string
+	configuredL2ToL1     map[string]string
+	// the registered l2 gateways by the token standards.
+	l2Gateways map[string][]common.Address
+}
+
+// NewTokenPairChecker initializes a new instance of TokenPairChecker.
+func NewTokenPairChecker(cfg *config.Config, db *gorm.DB, l2Client *ethclient.Client) *TokenPairChecker {
+	c := &TokenPairChecker{
+		tokenPairOrm:         orm.NewTokenPair(db),
+		l2Client:             l2Client,
+		configuredTokenPairs: cfg.TokenPairs,
+		configuredL1ToL2:     make(map[string]string),
+		configuredL2ToL1:     make(map[string]string),
+		l2Gateways:           make(map[string][]common.Address),
+	}
+
+	for _, tokenPair := range cfg.TokenPairs {
+		c.configuredL1ToL2[tokenPair.L1Token.Hex()] = tokenPair.L2Token.Hex()
+		c.configuredL2ToL1[tokenPair.L2Token.Hex()] = tokenPair.L1Token.Hex()
+	}
+	for _, gateway := range cfg.GatewayRegistry() {
+		if gateway.Layer == config.GatewayLayerL2 {
+			c.l2Gateways[gateway.TokenStandard] = append(c.l2Gateways[gateway.TokenStandard], gateway.Address)
+		}
+	}
+	return c
+}
+
+// Load persists the configured token pairs, which replace the conflicting learned ones.
+func (c *TokenPairChecker) Load(ctx context.Context) error {
+	var tokenPairs []orm.TokenPair
+	for _, tokenPair := range c.configuredTokenPairs {
+		tokenPairs = append(tokenPairs, orm.TokenPair{
+			TokenType:      int(types.TokenTypeERC20),
+			L1TokenAddress: tokenPair.L1Token.Hex(),
+			L2TokenAddress: tokenPair.L2Token.Hex(),
+		})
+	}
+	if len(tokenPairs) == 0 {
+		return nil
+	}
+	return c.tokenPairOrm.InsertOrUpdateConfiguredTokenPairs(ctx, tokenPairs)
+}
+
+// TokenPairCheck checks whether the l1 token address and the l2 token address of the message match are paired.
+func (c *TokenPairChecker) TokenPairCheck(ctx context.Context, messageMatch orm.GatewayMessageMatch) (bool, error) {
+	// eth has no token address, and the message matches before the token address columns are added are skipped.
+	if types.TokenType(messageMatch.TokenType) == types.TokenTypeETH || messageMatch.L1TokenAddress == "" || messageMatch.L2TokenAddress == "" {
+		return true, nil
+	}
+
+	if expectedL2Token, ok := c.configuredL1ToL2[messageMatch.L1TokenAddress]; ok {
+		return expectedL2Token == messageMatch.L2TokenAddress, nil
+	}
+
+	if expectedL1Token, ok := c.configuredL2ToL1[messageMatch.L2TokenAddress]; ok {
+		return expectedL1Token == messageMatch.L1TokenAddress, nil
+	}
+
+	tokenPairs, err := c.tokenPairOrm.GetTokenPairsByTokenAddress(ctx, messageMatch.L1TokenAddress, messageMatch.L2TokenAddress)
+	if err != nil {
+		return false, fmt.Errorf("get token pairs failed, err:%w", err)
+	}
+
+	if len(tokenPairs) == 0 {
+		// A forged token pair of the first message would be trusted by the following messages, so the token pair is
+		// only learned once the registered l2 gateways agree with it.
+		verified, verifyErr := c.verifyTokenPair(ctx, messageMatch)
+		if verifyErr != nil {
+			return false, fmt.Errorf("verify token pair failed, err:%w", verifyErr)
+		}
+		if !verified {
+			log.Warn("token pair isn't verified by the registered l2 gateways", "token type", types.TokenType(messageMatch.TokenType).String(), "l1 token", messageMatch.L1TokenAddress, "l2 token", messageMatch.L2TokenAddress)
+			return false, nil
+		}
+
+		tokenPair := orm.TokenPair{
+			TokenType:      messageMatch.TokenType,
+			L1TokenAddress: messageMatch.L1TokenAddress,
+			L2TokenAddress: messageMatch.L2TokenAddress,
+			Source:         int(types.TokenPairSourceTypeLearned),
+		}
+		affectRows, insertErr := c.tokenPairOrm.InsertTokenPair(ctx, tokenPair)
+		if insertErr != nil {
+			return false, fmt.Errorf("insert learned token pair failed, err:%w", insertErr)
+		}
+
+		if affectRows == 1 {
+			log.Info("learned new token pair", "token type", types.TokenType(messageMatch.TokenType).String(), "l1 token", messageMatch.L1TokenAddress, "l2 token", messageMatch.L2TokenAddress)
+			return true, nil
+		}
+
+		// the token pair is learned by others concurrently, check with it.
+		tokenPairs, err = c.tokenPairOrm.GetTokenPairsByTokenAddress(ctx, messageMatch.L1TokenAddress, messageMatch.L2TokenAddress)
+		if err != nil {
+			return false, fmt.Errorf("get token pairs failed, err:%w", err)
+		}
+	}
+
+	for _, tokenPair := range tokenPairs {
+		if tokenPair.L1TokenAddress != messageMatch.L1TokenAddress || tokenPair.L2TokenAddress != messageMatch.L2TokenAddress {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// verifyTokenPair checks the token pair against the counterpart known by the registered l2 gateways. The erc20 gateways
+// map the l2 tokens to the l1 tokens, while the nft gateways don't, so the l2 nft is asked for its gateway and counterpart.
+func (c *TokenPairChecker) verifyTokenPair(ctx context.Context, messageMatch orm.GatewayMessageMatch) (bool, error) {
+	l1Token, l2Token := common.HexToAddress(messageMatch.L1TokenAddress), common.HexToAddress(messageMatch.L2TokenAddress)
+	opts := &bind.CallOpts{Context: ctx}
+
+	var tokenStandard string
+	var gateway, counterpart common.Address
+	switch types.TokenType(messageMatch.TokenType) {
+	case types.TokenTypeERC20:
+		for _, l2Gateway := range c.l2Gateways[config.GatewayKindERC20] {
+			caller, err := il2erc20gateway.NewIl2erc20gatewayCaller(l2Gateway, c.l2Client)
+			if err != nil {
+				return false, err
+			}
+			l1Counterpart, err := caller.GetL1ERC20Address(opts, l2Token)
+			if err != nil {
+				return false, fmt.Errorf("get l1 erc20 address from l2 gateway %s failed, err:%w", l2Gateway.Hex(), err)
+			}
+			if l1Counterpart == l1Token {
+				return true, nil
+			}
+		}
+		return false, nil
+	case types.TokenTypeERC721:
+		caller, err := iscrollerc721.NewIscrollerc721Caller(l2Token, c.l2Client)
+		if err != nil {
+			return false, err
+		}
+		if gateway, err = caller.Gateway(opts); err != nil {
+			return false, fmt.Errorf("get gateway of l2 erc721 failed, err:%w", err)
+		}
+		if counterpart, err = caller.Counterpart(opts); err != nil {
+			return false, fmt.Errorf("get counterpart of l2 erc721 failed, err:%w", err)
+		}
+		tokenStandard = config.GatewayKindERC721
+	case types.TokenTypeERC1155:
+		caller, err := iscrollerc1155.NewIscrollerc1155Caller(l2Token, c.l2Client)
+		if err != nil {
+			return false, err
+		}
+		if gateway, err = caller.Gateway(opts); err != nil {
+			return false, fmt.Errorf("get gateway of l2 erc1155 failed, err:%w", err)
+		}
+		if counterpart, err = caller.Counterpart(opts); err != nil {
+			return false, fmt.Errorf("get counterpart of l2 erc1155 failed, err:%w", err)
+		}
+		tokenStandard = config.GatewayKindERC1155
+	default:
+		return false, nil
+	}
+
+	if counterpart != l1Token {
+		return false, nil
+	}
+	for _, l2Gateway := range c.l2Gateways[tokenStandard] {
+		if l2Gateway == gateway {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,294 @@
+package crosschain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/accounts/abi"
+	"github.com/scroll-tech/go-ethereum/accounts/abi/bind"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/ethclient"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rpc"
+	"gorm.io/gorm"
+
+	"github.com/scroll-tech/chain-monitor/internal/config"
+	"github.com/scroll-tech/chain-monitor/internal/logic/alert"
+	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/iscrollerc20"
+	"github.com/scroll-tech/chain-monitor/internal/orm"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+	"github.com/scroll-tech/chain-monitor/internal/utils"
+)
+
+const (
+	defaultTokenSupplyThresholdRatio = 0.001
+	// the default cap of the threshold in tokens, so that the threshold of a large custody balance isn't large as well.
+	defaultTokenSupplyMaxThreshold = 1000
+
+	erc20DecimalsABI = `[{"type":"function","name":"decimals","inputs":[],"outputs":[{"name":"","type":"uint8"}],"stateMutability":"view"}]`
+)
+
+// LogicTokenSupply checks the invariant of the erc20 tokens bridged by the gateways: the tokens held by the l1 gateway
+// (or the configured escrow) equal the total supply of the l2 bridged token, plus the deposits locked on l1 but not
+// minted on l2 yet, plus the withdrawals burned on l2 but not released on l1 yet. Both layers are checked at the
+// finalized heights which are ingested by the contract controller, so the balances aren't reorged and the in flight
+// amounts can be counted from the gateway message matches.
+type LogicTokenSupply struct {
+	l1Client               *ethclient.Client
+	l2Client               *ethclient.Client
+	tokenPairOrm           *orm.TokenPair
+	gatewayMessageMatchOrm *orm.GatewayMessageMatch
+	syncCursorOrm          *orm.SyncCursor
+
+	configuredTokenPairs []*config.TokenPair
+	// the l1 gateways which hold the bridged tokens, by their l2 counterpart gateways.
+	l1Gateways     map[common.Address]common.Address
+	thresholdRatio *big.Float
+	// the cap of the threshold in tokens, which is scaled by the decimals of the l1 token.
+	maxThreshold *big.Float
+	decimalsABI  abi.ABI
+	// the decimals of the l1 tokens, which don't change.
+	decimals map[common.Address]uint8
+
+	tokenSupplyL1CustodyBalance *prometheus.GaugeVec
+	tokenSupplyL2TotalSupply    *prometheus.GaugeVec
+	tokenSupplyDifference       *prometheus.GaugeVec
+}
+
+// NewLogicTokenSupply is a constructor for LogicTokenSupply.
+func NewLogicTokenSupply(cfg *config.Config, db *gorm.DB, l1Client, l2Client *ethclient.Client) *LogicTokenSupply {
+	thresholdRatio := defaultTokenSupplyThresholdRatio
+	if cfg.TokenSupplyConfig != nil && cfg.TokenSupplyConfig.ThresholdRatio != 0 {
+		thresholdRatio = cfg.TokenSupplyConfig.ThresholdRatio
+	}
+	maxThreshold := float64(defaultTokenSupplyMaxThreshold)
+	if cfg.TokenSupplyConfig != nil && cfg.TokenSupplyConfig.MaxThreshold != 0 {
+		maxThreshold = cfg.TokenSupplyConfig.MaxThreshold
+	}
+
+	// The non custodial gateways, e.g. the weth gateway, unwrap the tokens to eth on l1, which are held by the messenger instead.
+	l1Gateways := make(map[common.Address]common.Address)
+	for _, pair := range cfg.GatewayPairs() {
+		if pair.L1.TokenStandard != config.GatewayKindERC20 || pair.L1.NonCustodial {
+			continue
+		}
+		l1Gateways[pair.L2.Address] = pair.L1.Address
+	}
+
+	return &LogicTokenSupply{
+		l1Client:               l1Client,
+		l2Client:               l2Client,
+		tokenPairOrm:           orm.NewTokenPair(db),
+		gatewayMessageMatchOrm: orm.NewGatewayMessageMatch(db),
+		syncCursorOrm:          orm.NewSyncCursor(db),
+		configuredTokenPairs:   cfg.TokenPairs,
+		l1Gateways:             l1Gateways,
+		thresholdRatio:         big.NewFloat(thresholdRatio),
+		maxThreshold:           big.NewFloat(maxThreshold),
+		decimalsABI:            mustParseABI(erc20DecimalsABI),
+		decimals:               make(map[common.Address]uint8),
+
+		tokenSupplyL1CustodyBalance: promauto.With(prometheus.DefaultRegisterer).NewGaugeVec(prometheus.GaugeOpts{
+			Name: "token_supply_l1_custody_balance",
+			Help: "The balance of the bridged token held by the l1 gateway or escrow.",
+		}, []string{"l1_token", "l2_token"}),
+		tokenSupplyL2TotalSupply: promauto.With(prometheus.DefaultRegisterer).NewGaugeVec(prometheus.GaugeOpts{
+			Name: "token_supply_l2_total_supply",
+			Help: "The total supply of the l2 bridged token.",
+		}, []string{"l1_token", "l2_token"}),
+		tokenSupplyDifference: promauto.With(prometheus.DefaultRegisterer).NewGaugeVec(prometheus.GaugeOpts{
+			Name: "token_supply_difference",
+			Help: "The l1 custody balance minus the l2 total supply and the in flight amounts of the bridged token.",
+		}, []string{"l1_token", "l2_token"}),
+	}
+}
+
+// CheckTokenSupply checks the custody balances of the configured and learned erc20 token pairs against their l2 supplies.
+func (c *LogicTokenSupply) CheckTokenSupply(ctx context.Context) {
+	l1BlockNumber, l2BlockNumber, err := c.checkedBlockNumbers(ctx)
+	if err != nil {
+		log.Error("LogicTokenSupply.CheckTokenSupply get finalized and ingested block numbers failed", "error", err)
+		return
+	}
+	if l1BlockNumber == 0 || l2BlockNumber == 0 {
+		return
+	}
+
+	tokenPairs, err := c.tokenPairs(ctx)
+	if err != nil {
+		log.Error("LogicTokenSupply.CheckTokenSupply get token pairs failed", "error", err)
+		return
+	}
+
+	for _, tokenPair := range tokenPairs {
+		if err := c.checkTokenPair(ctx, tokenPair, l1BlockNumber, l2BlockNumber); err != nil {
+			log.Error("LogicTokenSupply.CheckTokenSupply check token pair failed", "l1 token", tokenPair.L1Token.Hex(), "l2 token", tokenPair.L2Token.Hex(), "error", err)
+		}
+	}
+}
+
+func (c *LogicTokenSupply) checkTokenPair(ctx context.Context, tokenPair *config.TokenPair, l1BlockNumber, l2BlockNumber uint64) error {
+	l2Token, err := iscrollerc20.NewIscrollerc20Caller(tokenPair.L2Token, c.l2Client)
+	if err != nil {
+		return fmt.Errorf("new l2 token caller failed, err: %w", err)
+	}
+	l2Opts := bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(l2BlockNumber)}
+
+	custody := tokenPair.L1Custody
+	if custody == (common.Address{}) {
+		l2Gateway, gatewayErr := l2Token.Gateway(&l2Opts)
+		if gatewayErr != nil {
+			return fmt.Errorf("get gateway of l2 token failed, err: %w", gatewayErr)
+		}
+		var ok bool
+		if custody, ok = c.l1Gateways[l2Gateway]; !ok {
+			log.Debug("the l2 token isn't bridged by an erc20 gateway holding the tokens on l1", "l2 token", tokenPair.L2Token.Hex(), "l2 gateway", l2Gateway.Hex())
+			return nil
+		}
+	}
+
+	totalSupply, err := l2Token.TotalSupply(&l2Opts)
+	if err != nil {
+		return fmt.Errorf("get total supply of l2 token failed, err: %w", err)
+	}
+
+	l1Token, err := iscrollerc20.NewIscrollerc20Caller(tokenPair.L1Token, c.l1Client)
+	if err != nil {
+		return fmt.Errorf("new l1 token caller failed, err: %w", err)
+	}
+	custodyBalance, err := l1Token.BalanceOf(&bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(l1BlockNumber)}, custody)
+	if err != nil {
+		return fmt.Errorf("get custody balance of l1 token failed, err: %w", err)
+	}
+
+	pendingDeposits, pendingWithdrawals, err := c.gatewayMessageMatchOrm.GetERC20InFlightAmounts(ctx, tokenPair.L1Token.Hex(), tokenPair.L2Token.Hex(), l1BlockNumber, l2BlockNumber)
+	if err != nil {
+		return err
+	}
+
+	difference := new(big.Int).Sub(custodyBalance, totalSupply)
+	difference.Sub(difference, pendingDeposits.BigInt())
+	difference.Sub(difference, pendingWithdrawals.BigInt())
+
+	l1TokenLabel, l2TokenLabel := tokenPair.L1Token.Hex(), tokenPair.L2Token.Hex()
+	c.tokenSupplyL1CustodyBalance.WithLabelValues(l1TokenLabel, l2TokenLabel).Set(bigIntToFloat64(custodyBalance))
+	c.tokenSupplyL2TotalSupply.WithLabelValues(l1TokenLabel, l2TokenLabel).Set(bigIntToFloat64(totalSupply))
+	c.tokenSupplyDifference.WithLabelValues(l1TokenLabel, l2TokenLabel).Set(bigIntToFloat64(difference))
+
+	threshold, err := c.threshold(ctx, tokenPair.L1Token, custodyBalance)
+	if err != nil {
+		return err
+	}
+	if new(big.Float).SetInt(new(big.Int).Abs(difference)).Cmp(threshold) <= 0 {
+		alert.Resolve(ctx, alert.Fingerprint(alert.CategoryTokenSupply, types.Layer2, l2TokenLabel, 0))
+		return nil
+	}
+
+	info := alert.TokenSupplyInfo{
+		L1Token:            tokenPair.L1Token,
+		L2Token:            tokenPair.L2Token,
+		L1Custody:          custody,
+		L1BlockNumber:      l1BlockNumber,
+		L2BlockNumber:      l2BlockNumber,
+		CustodyBalance:     custodyBalance,
+		TotalSupply:        totalSupply,
+		PendingDeposits:    pendingDeposits.BigInt(),
+		PendingWithdrawals: pendingWithdrawals.BigInt(),
+		Difference:         difference,
+	}
+	alert.Notify(alert.TokenSupplyAlert(info))
+	log.Error("l1 custody balance doesn't match l2 bridged token supply", "l1 token", l1TokenLabel, "l2 token", l2TokenLabel,
+		"custody balance", custodyBalance, "total supply", totalSupply, "pending deposits", pendingDeposits, "pending withdrawals", pendingWithdrawals)
+	return nil
+}
+
+// threshold returns threshold_ratio of the custody balance, capped at max_threshold tokens.
+func (c *LogicTokenSupply) threshold(ctx context.Context, l1Token common.Address, custodyBalance *big.Int) (*big.Float, error) {
+	decimals, ok := c.decimals[l1Token]
+	if !ok {
+		var out []interface{}
+		token := bind.NewBoundContract(l1Token, c.decimalsABI, c.l1Client, nil, nil)
+		if err := token.Call(&bind.CallOpts{Context: ctx}, &out, "decimals"); err != nil {
+			return nil, fmt.Errorf("get decimals of l1 token failed, err: %w", err)
+		}
+		decimals = *abi.ConvertType(out[0], new(uint8)).(*uint8)
+		c.decimals[l1Token] = decimals
+	}
+
+	threshold := new(big.Float).Mul(new(big.Float).SetInt(custodyBalance), c.thresholdRatio)
+	unit := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	if maxThreshold := new(big.Float).Mul(c.maxThreshold, unit); threshold.Cmp(maxThreshold) > 0 {
+		threshold = maxThreshold
+	}
+	return threshold, nil
+}
+
+// checkedBlockNumbers returns the finalized heights which are ingested by the contract controller on both layers,
+// 0 if a layer isn't ingested yet.
+func (c *LogicTokenSupply) checkedBlockNumbers(ctx context.Context) (uint64, uint64, error) {
+	var blockNumbers [2]uint64
+	for i, layer := range []types.LayerType{types.Layer1, types.Layer2} {
+		cursor, err := c.syncCursorOrm.GetSyncCursor(ctx, layer, types.CheckerTypeContract)
+		if err != nil {
+			return 0, 0, err
+		}
+		if cursor == nil {
+			continue
+		}
+
+		client := c.l1Client
+		if layer == types.Layer2 {
+			client = c.l2Client
+		}
+		finalizedBlockNumber, err := utils.GetLatestConfirmedBlockNumber(ctx, client, rpc.FinalizedBlockNumber)
+		if err != nil {
+			return 0, 0, fmt.Errorf("get %v finalized block number failed, err: %w", layer, err)
+		}
+
+		blockNumbers[i] = cursor.BlockNumber
+		if finalizedBlockNumber < blockNumbers[i] {
+			blockNumbers[i] = finalizedBlockNumber
+		}
+	}
+	return blockNumbers[0], blockNumbers[1], nil
+}
+
+// tokenPairs returns the configured token pairs, and the learned erc20 token pairs which aren't configured.
+func (c *LogicTokenSupply) tokenPairs(ctx context.Context) ([]*config.TokenPair, error) {
+	learnedTokenPairs, err := c.tokenPairOrm.GetTokenPairs(ctx, types.TokenTypeERC20)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenPairs := append([]*config.TokenPair{}, c.configuredTokenPairs...)
+	configured := make(map[common.Address]bool)
+	for _, tokenPair := range c.configuredTokenPairs {
+		configured[tokenPair.L1Token] = true
+		configured[tokenPair.L2Token] = true
+	}
+	for _, tokenPair := range learnedTokenPairs {
+		l1Token, l2Token := common.HexToAddress(tokenPair.L1TokenAddress), common.HexToAddress(tokenPair.L2TokenAddress)
+		if configured[l1Token] || configured[l2Token] {
+			continue
+		}
+		tokenPairs = append(tokenPairs, &config.TokenPair{L1Token: l1Token, L2Token: l2Token})
+	}
+	return tokenPairs, nil
+}
+
+func mustParseABI(raw string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(raw))
+	if err != nil {
+		panic(fmt.Sprintf("parse abi failed, err: %v", err))
+	}
+	return parsed
+}
+
+func bigIntToFloat64(value *big.Int) float64 {
+	f, _ := new(big.Float).SetInt(value).Float64()
+	return f
+}
@@ -0,0 +1,135 @@
+package crosschain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	"github.com/scroll-tech/go-ethereum/ethclient"
+	"github.com/scroll-tech/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scroll-tech/chain-monitor/internal/config"
+	"github.com/scroll-tech/chain-monitor/internal/logic/alert"
+	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/iscrollerc20"
+	"github.com/scroll-tech/chain-monitor/internal/orm"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+	"github.com/scroll-tech/chain-monitor/internal/utils/testcontainer"
+)
+
+// tokenService serves the eth_call of the token methods used by the token supply check.
+type tokenService struct {
+	balances    map[string]*big.Int
+	totalSupply *big.Int
+	gateway     common.Address
+}
+
+func (s *tokenService) Call(args map[string]interface{}, _ string) (hexutil.Bytes, error) {
+	data, err := hexutil.Decode(args["data"].(string))
+	if err != nil {
+		return nil, err
+	}
+	tokenABI, err := iscrollerc20.Iscrollerc20MetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+
+	if method, methodErr := tokenABI.MethodById(data[:4]); methodErr == nil {
+		switch method.Name {
+		case "gateway":
+			return method.Outputs.Pack(s.gateway)
+		case "totalSupply":
+			return method.Outputs.Pack(s.totalSupply)
+		case "balanceOf":
+			inputs, unpackErr := method.Inputs.Unpack(data[4:])
+			if unpackErr != nil {
+				return nil, unpackErr
+			}
+			return method.Outputs.Pack(s.balances[inputs[0].(common.Address).Hex()])
+		}
+	}
+	decimalsABI := mustParseABI(erc20DecimalsABI)
+	if method, methodErr := decimalsABI.MethodById(data[:4]); methodErr == nil {
+		return method.Outputs.Pack(uint8(18))
+	}
+	return nil, fmt.Errorf("unknown method %x", data[:4])
+}
+
+func newTokenClient(t *testing.T, service *tokenService) *ethclient.Client {
+	server := rpc.NewServer()
+	assert.NoError(t, server.RegisterName("eth", service))
+	t.Cleanup(server.Stop)
+	return ethclient.NewClient(rpc.DialInProc(server))
+}
+
+func tokens(amount int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(amount), big.NewInt(1e18))
+}
+
+func TestLogicTokenSupply_CheckTokenPair(t *testing.T) {
+	ctx := context.Background()
+	db := testcontainer.SetupDB(ctx, t)
+
+	var alerts []alert.Alert
+	previous := alert.Use(alert.NewManagerWithSinks(alert.NewFuncSink("stdout", func(a alert.Alert) { alerts = append(alerts, a) })))
+	defer alert.Use(previous)
+
+	l1Token, l2Token := common.HexToAddress("0x11"), common.HexToAddress("0x21")
+	l1Gateway, l2Gateway := common.HexToAddress("0x12"), common.HexToAddress("0x22")
+
+	// 10 tokens are deposited but not minted on l2, and 5 tokens are withdrawn but not released on l1.
+	gatewayMessageMatchOrm := orm.NewGatewayMessageMatch(db)
+	_, err := gatewayMessageMatchOrm.InsertOrUpdateEventInfo(ctx, types.Layer1, orm.GatewayMessageMatch{MessageHash: "0x1", TokenType: int(types.TokenTypeERC20),
+		L1EventType: int(types.L1DepositERC20), L1BlockNumber: 100, L1TokenAddress: l1Token.Hex(), L1Amounts: tokens(10).String()})
+	assert.NoError(t, err)
+	_, err = gatewayMessageMatchOrm.InsertOrUpdateEventInfo(ctx, types.Layer2, orm.GatewayMessageMatch{MessageHash: "0x2", TokenType: int(types.TokenTypeERC20),
+		L2EventType: int(types.L2WithdrawERC20), L2BlockNumber: 50, L2TokenAddress: l2Token.Hex(), L2Amounts: tokens(5).String()})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		custodyBalance *big.Int
+		totalSupply    *big.Int
+		wantAlert      bool
+	}{
+		{"balanced", tokens(1000), tokens(985), false},
+		{"withinRatio", tokens(1000), new(big.Int).Add(tokens(985), big.NewInt(5e17)), false},
+		{"beyondRatio", tokens(1000), tokens(983), true},
+		// 0.1% of the custody balance is 10000 tokens, the threshold is capped at 1000 tokens.
+		{"beyondMaxThreshold", tokens(10000000), tokens(9997985), true},
+		{"withinMaxThreshold", tokens(10000000), tokens(9999485), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			alerts = nil
+			l1Service := &tokenService{balances: map[string]*big.Int{l1Gateway.Hex(): test.custodyBalance}}
+			l2Service := &tokenService{totalSupply: test.totalSupply, gateway: l2Gateway}
+			c := &LogicTokenSupply{
+				l1Client:               newTokenClient(t, l1Service),
+				l2Client:               newTokenClient(t, l2Service),
+				gatewayMessageMatchOrm: gatewayMessageMatchOrm,
+				l1Gateways:             map[common.Address]common.Address{l2Gateway: l1Gateway},
+				thresholdRatio:         big.NewFloat(defaultTokenSupplyThresholdRatio),
+				maxThreshold:           big.NewFloat(defaultTokenSupplyMaxThreshold),
+				decimalsABI:            mustParseABI(erc20DecimalsABI),
+				decimals:               make(map[common.Address]uint8),
+
+				tokenSupplyL1CustodyBalance: prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_token_supply_l1_custody_balance"}, []string{"l1_token", "l2_token"}),
+				tokenSupplyL2TotalSupply:    prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_token_supply_l2_total_supply"}, []string{"l1_token", "l2_token"}),
+				tokenSupplyDifference:       prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_token_supply_difference"}, []string{"l1_token", "l2_token"}),
+			}
+
+			err := c.checkTokenPair(ctx, &config.TokenPair{L1Token: l1Token, L2Token: l2Token}, 200, 100)
+			assert.NoError(t, err)
+			assert.Equal(t, test.wantAlert, len(alerts) == 1)
+			for _, a := range alerts {
+				assert.Equal(t, alert.CategoryTokenSupply, a.Category)
+			}
+		})
+	}
+}
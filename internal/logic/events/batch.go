@@ -0,0 +1,86 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+
+	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/iscrollchain"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+)
+
+// BatchEventUnmarshaler is a struct representing the unmarshalled data of a batch event
+// raised by the L1 scroll chain contract.
+type BatchEventUnmarshaler struct {
+	Layer        types.LayerType
+	Type         types.EventType
+	Number       uint64
+	TxHash       common.Hash
+	Index        uint
+	BatchIndex   uint64
+	BatchHash    common.Hash
+	StateRoot    common.Hash
+	WithdrawRoot common.Hash
+
+	// the l2 block range of the committed batch, which is decoded from the commit calldata.
+	StartBlockNumber uint64
+	EndBlockNumber   uint64
+
+	// the block time of the finalize event, which is set by the watcher.
+	BlockTime time.Time
+}
+
+// Unmarshal takes a context, layer type, and a list of iterators, and unmarshals the batch events
+// from the L1 scroll chain contract.
+func (e *BatchEventUnmarshaler) Unmarshal(context context.Context, layerType types.LayerType, iterators []types.WrapIterator) []EventUnmarshaler {
+	var events []EventUnmarshaler
+	for _, it := range iterators {
+		for it.Iter.Next() {
+			events = append(events, e.batch(layerType, it.Iter, it.EventType))
+		}
+	}
+	return events
+}
+
+func (e *BatchEventUnmarshaler) batch(layerType types.LayerType, it types.Iterator, eventType types.EventType) EventUnmarshaler {
+	var event EventUnmarshaler
+	switch eventType {
+	case types.L1CommitBatch:
+		iter := it.(*iscrollchain.IscrollchainCommitBatchIterator)
+		event = &BatchEventUnmarshaler{
+			Layer:      layerType,
+			Type:       eventType,
+			Number:     iter.Event.Raw.BlockNumber,
+			TxHash:     iter.Event.Raw.TxHash,
+			Index:      iter.Event.Raw.Index,
+			BatchIndex: iter.Event.BatchIndex.Uint64(),
+			BatchHash:  iter.Event.BatchHash,
+		}
+	case types.L1FinalizeBatch:
+		iter := it.(*iscrollchain.IscrollchainFinalizeBatchIterator)
+		event = &BatchEventUnmarshaler{
+			Layer:        layerType,
+			Type:         eventType,
+			Number:       iter.Event.Raw.BlockNumber,
+			TxHash:       iter.Event.Raw.TxHash,
+			Index:        iter.Event.Raw.Index,
+			BatchIndex:   iter.Event.BatchIndex.Uint64(),
+			BatchHash:    iter.Event.BatchHash,
+			StateRoot:    iter.Event.StateRoot,
+			WithdrawRoot: iter.Event.WithdrawRoot,
+		}
+	case types.L1RevertBatch:
+		iter := it.(*iscrollchain.IscrollchainRevertBatchIterator)
+		event = &BatchEventUnmarshaler{
+			Layer:      layerType,
+			Type:       eventType,
+			Number:     iter.Event.Raw.BlockNumber,
+			TxHash:     iter.Event.Raw.TxHash,
+			Index:      iter.Event.Raw.Index,
+			BatchIndex: iter.Event.BatchIndex.Uint64(),
+			BatchHash:  iter.Event.BatchHash,
+		}
+	}
+	return event
+}
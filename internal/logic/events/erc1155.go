@@ -22,6 +22,11 @@ type ERC1155GatewayEventUnmarshaler struct {
 	Index        uint
 	MessageHash  common.Hash
 	TokenAddress common.Address
+	// the gateway emitting the event, the token address of the other layer, the sender and the recipient, not set for the refund events.
+	GatewayAddress          common.Address
+	CounterpartTokenAddress common.Address
+	From                    common.Address
+	To                      common.Address
 }
 
 // Unmarshal takes a context, layer type, and a list of iterators and unmarshals each iterator
@@ -42,50 +47,66 @@ func (e *ERC1155GatewayEventUnmarshaler) erc1155(layerType types.LayerType, it t
 	case types.L1DepositERC1155:
 		iter := it.(*il1erc1155gateway.Il1erc1155gatewayDepositERC1155Iterator)
 		event = &ERC1155GatewayEventUnmarshaler{
-			Layer:        layerType,
-			Type:         eventType,
-			Number:       iter.Event.Raw.BlockNumber,
-			TxHash:       iter.Event.Raw.TxHash,
-			TokenIds:     []*big.Int{iter.Event.TokenId},
-			Amounts:      []*big.Int{iter.Event.Amount},
-			Index:        iter.Event.Raw.Index,
-			TokenAddress: iter.Event.L1Token,
+			Layer:                   layerType,
+			Type:                    eventType,
+			Number:                  iter.Event.Raw.BlockNumber,
+			TxHash:                  iter.Event.Raw.TxHash,
+			TokenIds:                []*big.Int{iter.Event.TokenId},
+			Amounts:                 []*big.Int{iter.Event.Amount},
+			Index:                   iter.Event.Raw.Index,
+			TokenAddress:            iter.Event.L1Token,
+			CounterpartTokenAddress: iter.Event.L2Token,
+			GatewayAddress:          iter.Event.Raw.Address,
+			From:                    iter.Event.From,
+			To:                      iter.Event.To,
 		}
 	case types.L1BatchDepositERC1155:
 		iter := it.(*il1erc1155gateway.Il1erc1155gatewayBatchDepositERC1155Iterator)
 		event = &ERC1155GatewayEventUnmarshaler{
-			Layer:        layerType,
-			Type:         eventType,
-			Number:       iter.Event.Raw.BlockNumber,
-			TxHash:       iter.Event.Raw.TxHash,
-			TokenIds:     iter.Event.TokenIds,
-			Amounts:      iter.Event.Amounts,
-			Index:        iter.Event.Raw.Index,
-			TokenAddress: iter.Event.L1Token,
+			Layer:                   layerType,
+			Type:                    eventType,
+			Number:                  iter.Event.Raw.BlockNumber,
+			TxHash:                  iter.Event.Raw.TxHash,
+			TokenIds:                iter.Event.TokenIds,
+			Amounts:                 iter.Event.Amounts,
+			Index:                   iter.Event.Raw.Index,
+			TokenAddress:            iter.Event.L1Token,
+			CounterpartTokenAddress: iter.Event.L2Token,
+			GatewayAddress:          iter.Event.Raw.Address,
+			From:                    iter.Event.From,
+			To:                      iter.Event.To,
 		}
 	case types.L1FinalizeWithdrawERC1155:
 		iter := it.(*il1erc1155gateway.Il1erc1155gatewayFinalizeWithdrawERC1155Iterator)
 		event = &ERC1155GatewayEventUnmarshaler{
-			Layer:        layerType,
-			Type:         eventType,
-			Number:       iter.Event.Raw.BlockNumber,
-			TxHash:       iter.Event.Raw.TxHash,
-			TokenIds:     []*big.Int{iter.Event.TokenId},
-			Amounts:      []*big.Int{iter.Event.Amount},
-			Index:        iter.Event.Raw.Index,
-			TokenAddress: iter.Event.L1Token,
+			Layer:                   layerType,
+			Type:                    eventType,
+			Number:                  iter.Event.Raw.BlockNumber,
+			TxHash:                  iter.Event.Raw.TxHash,
+			TokenIds:                []*big.Int{iter.Event.TokenId},
+			Amounts:                 []*big.Int{iter.Event.Amount},
+			Index:                   iter.Event.Raw.Index,
+			TokenAddress:            iter.Event.L1Token,
+			CounterpartTokenAddress: iter.Event.L2Token,
+			GatewayAddress:          iter.Event.Raw.Address,
+			From:                    iter.Event.From,
+			To:                      iter.Event.To,
 		}
 	case types.L1FinalizeBatchWithdrawERC1155:
 		iter := it.(*il1erc1155gateway.Il1erc1155gatewayFinalizeBatchWithdrawERC1155Iterator)
 		event = &ERC1155GatewayEventUnmarshaler{
-			Layer:        layerType,
-			Type:         eventType,
-			Number:       iter.Event.Raw.BlockNumber,
-			TxHash:       iter.Event.Raw.TxHash,
-			TokenIds:     iter.Event.TokenIds,
-			Amounts:      iter.Event.Amounts,
-			Index:        iter.Event.Raw.Index,
-			TokenAddress: iter.Event.L1Token,
+			Layer:                   layerType,
+			Type:                    eventType,
+			Number:                  iter.Event.Raw.BlockNumber,
+			TxHash:                  iter.Event.Raw.TxHash,
+			TokenIds:                iter.Event.TokenIds,
+			Amounts:                 iter.Event.Amounts,
+			Index:                   iter.Event.Raw.Index,
+			TokenAddress:            iter.Event.L1Token,
+			CounterpartTokenAddress: iter.Event.L2Token,
+			GatewayAddress:          iter.Event.Raw.Address,
+			From:                    iter.Event.From,
+			To:                      iter.Event.To,
 		}
 	case types.L1RefundERC1155:
 		iter := it.(*il1erc1155gateway.Il1erc1155gatewayRefundERC1155Iterator)
@@ -114,50 +135,66 @@ func (e *ERC1155GatewayEventUnmarshaler) erc1155(layerType types.LayerType, it t
 	case types.L2WithdrawERC1155:
 		iter := it.(*il2erc1155gateway.Il2erc1155gatewayWithdrawERC1155Iterator)
 		event = &ERC1155GatewayEventUnmarshaler{
-			Layer:        layerType,
-			Type:         eventType,
-			Number:       iter.Event.Raw.BlockNumber,
-			TxHash:       iter.Event.Raw.TxHash,
-			TokenIds:     []*big.Int{iter.Event.TokenId},
-			Amounts:      []*big.Int{iter.Event.Amount},
-			Index:        iter.Event.Raw.Index,
-			TokenAddress: iter.Event.L2Token,
+			Layer:                   layerType,
+			Type:                    eventType,
+			Number:                  iter.Event.Raw.BlockNumber,
+			TxHash:                  iter.Event.Raw.TxHash,
+			TokenIds:                []*big.Int{iter.Event.TokenId},
+			Amounts:                 []*big.Int{iter.Event.Amount},
+			Index:                   iter.Event.Raw.Index,
+			TokenAddress:            iter.Event.L2Token,
+			CounterpartTokenAddress: iter.Event.L1Token,
+			GatewayAddress:          iter.Event.Raw.Address,
+			From:                    iter.Event.From,
+			To:                      iter.Event.To,
 		}
 	case types.L2BatchWithdrawERC1155:
 		iter := it.(*il2erc1155gateway.Il2erc1155gatewayBatchWithdrawERC1155Iterator)
 		event = &ERC1155GatewayEventUnmarshaler{
-			Layer:        layerType,
-			Type:         eventType,
-			Number:       iter.Event.Raw.BlockNumber,
-			TxHash:       iter.Event.Raw.TxHash,
-			TokenIds:     iter.Event.TokenIds,
-			Amounts:      iter.Event.Amounts,
-			Index:        iter.Event.Raw.Index,
-			TokenAddress: iter.Event.L2Token,
+			Layer:                   layerType,
+			Type:                    eventType,
+			Number:                  iter.Event.Raw.BlockNumber,
+			TxHash:                  iter.Event.Raw.TxHash,
+			TokenIds:                iter.Event.TokenIds,
+			Amounts:                 iter.Event.Amounts,
+			Index:                   iter.Event.Raw.Index,
+			TokenAddress:            iter.Event.L2Token,
+			CounterpartTokenAddress: iter.Event.L1Token,
+			GatewayAddress:          iter.Event.Raw.Address,
+			From:                    iter.Event.From,
+			To:                      iter.Event.To,
 		}
 	case types.L2FinalizeDepositERC1155:
 		iter := it.(*il2erc1155gateway.Il2erc1155gatewayFinalizeDepositERC1155Iterator)
 		event = &ERC1155GatewayEventUnmarshaler{
-			Layer:        layerType,
-			Type:         eventType,
-			Number:       iter.Event.Raw.BlockNumber,
-			TxHash:       iter.Event.Raw.TxHash,
-			TokenIds:     []*big.Int{iter.Event.TokenId},
-			Amounts:      []*big.Int{iter.Event.Amount},
-			Index:        iter.Event.Raw.Index,
-			TokenAddress: iter.Event.L2Token,
+			Layer:                   layerType,
+			Type:                    eventType,
+			Number:                  iter.Event.Raw.BlockNumber,
+			TxHash:                  iter.Event.Raw.TxHash,
+			TokenIds:                []*big.Int{iter.Event.TokenId},
+			Amounts:                 []*big.Int{iter.Event.Amount},
+			Index:                   iter.Event.Raw.Index,
+			TokenAddress:            iter.Event.L2Token,
+			CounterpartTokenAddress: iter.Event.L1Token,
+			GatewayAddress:          iter.Event.Raw.Address,
+			From:                    iter.Event.From,
+			To:                      iter.Event.To,
 		}
 	case types.L2FinalizeBatchDepositERC1155:
 		iter := it.(*il2erc1155gateway.Il2erc1155gatewayFinalizeBatchDepositERC1155Iterator)
 		event = &ERC1155GatewayEventUnmarshaler{
-			Layer:        layerType,
-			Type:         eventType,
-			Number:       iter.Event.Raw.BlockNumber,
-			TxHash:       iter.Event.Raw.TxHash,
-			TokenIds:     iter.Event.TokenIds,
-			Amounts:      iter.Event.Amounts,
-			Index:        iter.Event.Raw.Index,
-			TokenAddress: iter.Event.L2Token,
+			Layer:                   layerType,
+			Type:                    eventType,
+			Number:                  iter.Event.Raw.BlockNumber,
+			TxHash:                  iter.Event.Raw.TxHash,
+			TokenIds:                iter.Event.TokenIds,
+			Amounts:                 iter.Event.Amounts,
+			Index:                   iter.Event.Raw.Index,
+			TokenAddress:            iter.Event.L2Token,
+			CounterpartTokenAddress: iter.Event.L1Token,
+			GatewayAddress:          iter.Event.Raw.Address,
+			From:                    iter.Event.From,
+			To:                      iter.Event.To,
 		}
 	}
 	return event
@@ -21,6 +21,13 @@ type ERC20GatewayEventUnmarshaler struct {
 	Index        uint
 	MessageHash  common.Hash
 	TokenAddress common.Address
+	// the gateway emitting the event, the token address of the other layer, the sender, the recipient and the data passed to
+	// the recipient, only the gateway is set for the refund events.
+	GatewayAddress          common.Address
+	CounterpartTokenAddress common.Address
+	From                    common.Address
+	To                      common.Address
+	Data                    []byte
 }
 
 // Unmarshal takes a context, layer type, and a list of iterators and unmarshals each iterator
@@ -41,57 +48,76 @@ func (e *ERC20GatewayEventUnmarshaler) erc20(layerType types.LayerType, it types
 	case types.L1DepositERC20:
 		iter := it.(*il1erc20gateway.Il1erc20gatewayDepositERC20Iterator)
 		event = &ERC20GatewayEventUnmarshaler{
-			Layer:        layerType,
-			Type:         eventType,
-			Number:       iter.Event.Raw.BlockNumber,
-			TxHash:       iter.Event.Raw.TxHash,
-			Amount:       iter.Event.Amount,
-			Index:        iter.Event.Raw.Index,
-			TokenAddress: iter.Event.L1Token,
+			Layer:                   layerType,
+			Type:                    eventType,
+			Number:                  iter.Event.Raw.BlockNumber,
+			TxHash:                  iter.Event.Raw.TxHash,
+			Amount:                  iter.Event.Amount,
+			Index:                   iter.Event.Raw.Index,
+			TokenAddress:            iter.Event.L1Token,
+			CounterpartTokenAddress: iter.Event.L2Token,
+			GatewayAddress:          iter.Event.Raw.Address,
+			From:                    iter.Event.From,
+			To:                      iter.Event.To,
+			Data:                    iter.Event.Data,
 		}
 	case types.L1FinalizeWithdrawERC20:
 		iter := it.(*il1erc20gateway.Il1erc20gatewayFinalizeWithdrawERC20Iterator)
 		event = &ERC20GatewayEventUnmarshaler{
-			Layer:        layerType,
-			Type:         eventType,
-			Number:       iter.Event.Raw.BlockNumber,
-			TxHash:       iter.Event.Raw.TxHash,
-			Amount:       iter.Event.Amount,
-			Index:        iter.Event.Raw.Index,
-			TokenAddress: iter.Event.L1Token,
+			Layer:                   layerType,
+			Type:                    eventType,
+			Number:                  iter.Event.Raw.BlockNumber,
+			TxHash:                  iter.Event.Raw.TxHash,
+			Amount:                  iter.Event.Amount,
+			Index:                   iter.Event.Raw.Index,
+			TokenAddress:            iter.Event.L1Token,
+			CounterpartTokenAddress: iter.Event.L2Token,
+			GatewayAddress:          iter.Event.Raw.Address,
+			From:                    iter.Event.From,
+			To:                      iter.Event.To,
 		}
 	case types.L1RefundERC20:
 		iter := it.(*il1erc20gateway.Il1erc20gatewayRefundERC20Iterator)
 		event = &ERC20GatewayEventUnmarshaler{
-			Layer:        layerType,
-			Type:         eventType,
-			Number:       iter.Event.Raw.BlockNumber,
-			TxHash:       iter.Event.Raw.TxHash,
-			Amount:       iter.Event.Amount,
-			Index:        iter.Event.Raw.Index,
-			TokenAddress: iter.Event.Token,
+			Layer:          layerType,
+			Type:           eventType,
+			Number:         iter.Event.Raw.BlockNumber,
+			TxHash:         iter.Event.Raw.TxHash,
+			Amount:         iter.Event.Amount,
+			Index:          iter.Event.Raw.Index,
+			GatewayAddress: iter.Event.Raw.Address,
+			TokenAddress:   iter.Event.Token,
 		}
 	case types.L2WithdrawERC20:
 		iter := it.(*il2erc20gateway.Il2erc20gatewayWithdrawERC20Iterator)
 		event = &ERC20GatewayEventUnmarshaler{
-			Layer:        layerType,
-			Type:         eventType,
-			Number:       iter.Event.Raw.BlockNumber,
-			TxHash:       iter.Event.Raw.TxHash,
-			Amount:       iter.Event.Amount,
-			Index:        iter.Event.Raw.Index,
-			TokenAddress: iter.Event.L2Token,
+			Layer:                   layerType,
+			Type:                    eventType,
+			Number:                  iter.Event.Raw.BlockNumber,
+			TxHash:                  iter.Event.Raw.TxHash,
+			Amount:                  iter.Event.Amount,
+			Index:                   iter.Event.Raw.Index,
+			TokenAddress:            iter.Event.L2Token,
+			CounterpartTokenAddress: iter.Event.L1Token,
+			GatewayAddress:          iter.Event.Raw.Address,
+			From:                    iter.Event.From,
+			To:                      iter.Event.To,
+			Data:                    iter.Event.Data,
 		}
 	case types.L2FinalizeDepositERC20:
 		iter := it.(*il2erc20gateway.Il2erc20gatewayFinalizeDepositERC20Iterator)
 		event = &ERC20GatewayEventUnmarshaler{
-			Layer:        layerType,
-			Type:         eventType,
-			Number:       iter.Event.Raw.BlockNumber,
-			TxHash:       iter.Event.Raw.TxHash,
-			Amount:       iter.Event.Amount,
-			Index:        iter.Event.Raw.Index,
-			TokenAddress: iter.Event.L2Token,
+			Layer:                   layerType,
+			Type:                    eventType,
+			Number:                  iter.Event.Raw.BlockNumber,
+			TxHash:                  iter.Event.Raw.TxHash,
+			Amount:                  iter.Event.Amount,
+			Index:                   iter.Event.Raw.Index,
+			TokenAddress:            iter.Event.L2Token,
+			CounterpartTokenAddress: iter.Event.L1Token,
+			GatewayAddress:          iter.Event.Raw.Address,
+			From:                    iter.Event.From,
+			To:                      iter.Event.To,
 		}
 	}
 	return event
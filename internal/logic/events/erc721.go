@@ -22,6 +22,11 @@ type ERC721GatewayEventUnmarshaler struct {
 	Index        uint
 	MessageHash  common.Hash
 	TokenAddress common.Address
+	// the gateway emitting the event, the token address of the other layer, the sender and the recipient, not set for the refund events.
+	GatewayAddress          common.Address
+	CounterpartTokenAddress common.Address
+	From                    common.Address
+	To                      common.Address
 }
 
 // Unmarshal takes a context, layer type, and a list of iterators and unmarshals each iterator
@@ -42,47 +47,63 @@ func (e *ERC721GatewayEventUnmarshaler) erc721(layerType types.LayerType, it typ
 	case types.L1DepositERC721:
 		iter := it.(*il1erc721gateway.Il1erc721gatewayDepositERC721Iterator)
 		event = &ERC721GatewayEventUnmarshaler{
-			Layer:        layerType,
-			Type:         eventType,
-			Number:       iter.Event.Raw.BlockNumber,
-			TxHash:       iter.Event.Raw.TxHash,
-			TokenIds:     []*big.Int{iter.Event.TokenId},
-			Index:        iter.Event.Raw.Index,
-			TokenAddress: iter.Event.L1Token,
+			Layer:                   layerType,
+			Type:                    eventType,
+			Number:                  iter.Event.Raw.BlockNumber,
+			TxHash:                  iter.Event.Raw.TxHash,
+			TokenIds:                []*big.Int{iter.Event.TokenId},
+			Index:                   iter.Event.Raw.Index,
+			TokenAddress:            iter.Event.L1Token,
+			CounterpartTokenAddress: iter.Event.L2Token,
+			GatewayAddress:          iter.Event.Raw.Address,
+			From:                    iter.Event.From,
+			To:                      iter.Event.To,
 		}
 	case types.L1BatchDepositERC721:
 		iter := it.(*il1erc721gateway.Il1erc721gatewayBatchDepositERC721Iterator)
 		event = &ERC721GatewayEventUnmarshaler{
-			Layer:        layerType,
-			Type:         eventType,
-			Number:       iter.Event.Raw.BlockNumber,
-			TxHash:       iter.Event.Raw.TxHash,
-			TokenIds:     iter.Event.TokenIds,
-			Index:        iter.Event.Raw.Index,
-			TokenAddress: iter.Event.L1Token,
+			Layer:                   layerType,
+			Type:                    eventType,
+			Number:                  iter.Event.Raw.BlockNumber,
+			TxHash:                  iter.Event.Raw.TxHash,
+			TokenIds:                iter.Event.TokenIds,
+			Index:                   iter.Event.Raw.Index,
+			TokenAddress:            iter.Event.L1Token,
+			CounterpartTokenAddress: iter.Event.L2Token,
+			GatewayAddress:          iter.Event.Raw.Address,
+			From:                    iter.Event.From,
+			To:                      iter.Event.To,
 		}
 
 	case types.L1FinalizeWithdrawERC721:
 		iter := it.(*il1erc721gateway.Il1erc721gatewayFinalizeWithdrawERC721Iterator)
 		event = &ERC721GatewayEventUnmarshaler{
-			Layer:        layerType,
-			Type:         eventType,
-			Number:       iter.Event.Raw.BlockNumber,
-			TxHash:       iter.Event.Raw.TxHash,
-			TokenIds:     []*big.Int{iter.Event.TokenId},
-			Index:        iter.Event.Raw.Index,
-			TokenAddress: iter.Event.L1Token,
+			Layer:                   layerType,
+			Type:                    eventType,
+			Number:                  iter.Event.Raw.BlockNumber,
+			TxHash:                  iter.Event.Raw.TxHash,
+			TokenIds:                []*big.Int{iter.Event.TokenId},
+			Index:                   iter.Event.Raw.Index,
+			TokenAddress:            iter.Event.L1Token,
+			CounterpartTokenAddress: iter.Event.L2Token,
+			GatewayAddress:          iter.Event.Raw.Address,
+			From:                    iter.Event.From,
+			To:                      iter.Event.To,
 		}
 	case types.L1FinalizeBatchWithdrawERC721:
 		iter := it.(*il1erc721gateway.Il1erc721gatewayFinalizeBatchWithdrawERC721Iterator)
 		event = &ERC721GatewayEventUnmarshaler{
-			Layer:        layerType,
-			Type:         eventType,
-			Number:       iter.Event.Raw.BlockNumber,
-			TxHash:       iter.Event.Raw.TxHash,
-			TokenIds:     iter.Event.TokenIds,
-			Index:        iter.Event.Raw.Index,
-			TokenAddress: iter.Event.L1Token,
+			Layer:                   layerType,
+			Type:                    eventType,
+			Number:                  iter.Event.Raw.BlockNumber,
+			TxHash:                  iter.Event.Raw.TxHash,
+			TokenIds:                iter.Event.TokenIds,
+			Index:                   iter.Event.Raw.Index,
+			TokenAddress:            iter.Event.L1Token,
+			CounterpartTokenAddress: iter.Event.L2Token,
+			GatewayAddress:          iter.Event.Raw.Address,
+			From:                    iter.Event.From,
+			To:                      iter.Event.To,
 		}
 	case types.L1RefundERC721:
 		iter := it.(*il1erc721gateway.Il1erc721gatewayRefundERC721Iterator)
@@ -109,46 +130,62 @@ func (e *ERC721GatewayEventUnmarshaler) erc721(layerType types.LayerType, it typ
 	case types.L2WithdrawERC721:
 		iter := it.(*il2erc721gateway.Il2erc721gatewayWithdrawERC721Iterator)
 		event = &ERC721GatewayEventUnmarshaler{
-			Layer:        layerType,
-			Type:         eventType,
-			Number:       iter.Event.Raw.BlockNumber,
-			TxHash:       iter.Event.Raw.TxHash,
-			TokenIds:     []*big.Int{iter.Event.TokenId},
-			Index:        iter.Event.Raw.Index,
-			TokenAddress: iter.Event.L2Token,
+			Layer:                   layerType,
+			Type:                    eventType,
+			Number:                  iter.Event.Raw.BlockNumber,
+			TxHash:                  iter.Event.Raw.TxHash,
+			TokenIds:                []*big.Int{iter.Event.TokenId},
+			Index:                   iter.Event.Raw.Index,
+			TokenAddress:            iter.Event.L2Token,
+			CounterpartTokenAddress: iter.Event.L1Token,
+			GatewayAddress:          iter.Event.Raw.Address,
+			From:                    iter.Event.From,
+			To:                      iter.Event.To,
 		}
 	case types.L2BatchWithdrawERC721:
 		iter := it.(*il2erc721gateway.Il2erc721gatewayBatchWithdrawERC721Iterator)
 		event = &ERC721GatewayEventUnmarshaler{
-			Layer:        layerType,
-			Type:         eventType,
-			Number:       iter.Event.Raw.BlockNumber,
-			TxHash:       iter.Event.Raw.TxHash,
-			TokenIds:     iter.Event.TokenIds,
-			Index:        iter.Event.Raw.Index,
-			TokenAddress: iter.Event.L2Token,
+			Layer:                   layerType,
+			Type:                    eventType,
+			Number:                  iter.Event.Raw.BlockNumber,
+			TxHash:                  iter.Event.Raw.TxHash,
+			TokenIds:                iter.Event.TokenIds,
+			Index:                   iter.Event.Raw.Index,
+			TokenAddress:            iter.Event.L2Token,
+			CounterpartTokenAddress: iter.Event.L1Token,
+			GatewayAddress:          iter.Event.Raw.Address,
+			From:                    iter.Event.From,
+			To:                      iter.Event.To,
 		}
 	case types.L2FinalizeDepositERC721:
 		iter := it.(*il2erc721gateway.Il2erc721gatewayFinalizeDepositERC721Iterator)
 		event = &ERC721GatewayEventUnmarshaler{
-			Layer:        layerType,
-			Type:         eventType,
-			Number:       iter.Event.Raw.BlockNumber,
-			TxHash:       iter.Event.Raw.TxHash,
-			TokenIds:     []*big.Int{iter.Event.TokenId},
-			Index:        iter.Event.Raw.Index,
-			TokenAddress: iter.Event.L2Token,
+			Layer:                   layerType,
+			Type:                    eventType,
+			Number:                  iter.Event.Raw.BlockNumber,
+			TxHash:                  iter.Event.Raw.TxHash,
+			TokenIds:                []*big.Int{iter.Event.TokenId},
+			Index:                   iter.Event.Raw.Index,
+			TokenAddress:            iter.Event.L2Token,
+			CounterpartTokenAddress: iter.Event.L1Token,
+			GatewayAddress:          iter.Event.Raw.Address,
+			From:                    iter.Event.From,
+			To:                      iter.Event.To,
 		}
 	case types.L2FinalizeBatchDepositERC721:
 		iter := it.(*il2erc721gateway.Il2erc721gatewayFinalizeBatchDepositERC721Iterator)
 		event = &ERC721GatewayEventUnmarshaler{
-			Layer:        layerType,
-			Type:         eventType,
-			Number:       iter.Event.Raw.BlockNumber,
-			TxHash:       iter.Event.Raw.TxHash,
-			TokenIds:     iter.Event.TokenIds,
-			Index:        iter.Event.Raw.Index,
-			TokenAddress: iter.Event.L2Token,
+			Layer:                   layerType,
+			Type:                    eventType,
+			Number:                  iter.Event.Raw.BlockNumber,
+			TxHash:                  iter.Event.Raw.TxHash,
+			TokenIds:                iter.Event.TokenIds,
+			Index:                   iter.Event.Raw.Index,
+			TokenAddress:            iter.Event.L2Token,
+			CounterpartTokenAddress: iter.Event.L1Token,
+			GatewayAddress:          iter.Event.Raw.Address,
+			From:                    iter.Event.From,
+			To:                      iter.Event.To,
 		}
 	}
 	return event
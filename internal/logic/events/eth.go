@@ -0,0 +1,113 @@
+package events
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/scroll-tech/go-ethereum/common"
+
+	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/il1ethgateway"
+	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/il2ethgateway"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+)
+
+// ETHGatewayEventUnmarshaler is a struct that helps unmarshal events from the ETH Gateway.
+type ETHGatewayEventUnmarshaler struct {
+	Layer       types.LayerType
+	Type        types.EventType
+	Number      uint64
+	TxHash      common.Hash
+	Amount      *big.Int
+	Index       uint
+	MessageHash common.Hash
+	// the gateway emitting the event, the sender, the recipient and the data passed to the recipient, only the gateway is
+	// set for the refund events.
+	GatewayAddress common.Address
+	From           common.Address
+	To             common.Address
+	Data           []byte
+}
+
+// Unmarshal takes a context, layer type, and a list of iterators and unmarshals each iterator
+// into an EventUnmarshaler, returning a list of these unmarshalled events.
+func (e *ETHGatewayEventUnmarshaler) Unmarshal(context context.Context, layerType types.LayerType, iterators []types.WrapIterator) []EventUnmarshaler {
+	var events []EventUnmarshaler
+	for _, it := range iterators {
+		for it.Iter.Next() {
+			events = append(events, e.eth(layerType, it.Iter, it.EventType))
+		}
+	}
+	return events
+}
+
+func (e *ETHGatewayEventUnmarshaler) eth(layerType types.LayerType, it types.Iterator, eventType types.EventType) EventUnmarshaler {
+	var event EventUnmarshaler
+	switch eventType {
+	case types.L1DepositETH:
+		iter := it.(*il1ethgateway.Il1ethgatewayDepositETHIterator)
+		event = &ETHGatewayEventUnmarshaler{
+			Layer:          layerType,
+			Type:           eventType,
+			Number:         iter.Event.Raw.BlockNumber,
+			TxHash:         iter.Event.Raw.TxHash,
+			Amount:         iter.Event.Amount,
+			GatewayAddress: iter.Event.Raw.Address,
+			From:           iter.Event.From,
+			To:             iter.Event.To,
+			Data:           iter.Event.Data,
+			Index:          iter.Event.Raw.Index,
+		}
+	case types.L1FinalizeWithdrawETH:
+		iter := it.(*il1ethgateway.Il1ethgatewayFinalizeWithdrawETHIterator)
+		event = &ETHGatewayEventUnmarshaler{
+			Layer:          layerType,
+			Type:           eventType,
+			Number:         iter.Event.Raw.BlockNumber,
+			TxHash:         iter.Event.Raw.TxHash,
+			Amount:         iter.Event.Amount,
+			GatewayAddress: iter.Event.Raw.Address,
+			From:           iter.Event.From,
+			To:             iter.Event.To,
+			Index:          iter.Event.Raw.Index,
+		}
+	case types.L1RefundETH:
+		iter := it.(*il1ethgateway.Il1ethgatewayRefundETHIterator)
+		event = &ETHGatewayEventUnmarshaler{
+			Layer:          layerType,
+			Type:           eventType,
+			Number:         iter.Event.Raw.BlockNumber,
+			TxHash:         iter.Event.Raw.TxHash,
+			Amount:         iter.Event.Amount,
+			Index:          iter.Event.Raw.Index,
+			GatewayAddress: iter.Event.Raw.Address,
+		}
+	case types.L2WithdrawETH:
+		iter := it.(*il2ethgateway.Il2ethgatewayWithdrawETHIterator)
+		event = &ETHGatewayEventUnmarshaler{
+			Layer:          layerType,
+			Type:           eventType,
+			Number:         iter.Event.Raw.BlockNumber,
+			TxHash:         iter.Event.Raw.TxHash,
+			Amount:         iter.Event.Amount,
+			GatewayAddress: iter.Event.Raw.Address,
+			From:           iter.Event.From,
+			To:             iter.Event.To,
+			Data:           iter.Event.Data,
+			Index:          iter.Event.Raw.Index,
+		}
+	case types.L2FinalizeDepositETH:
+		iter := it.(*il2ethgateway.Il2ethgatewayFinalizeDepositETHIterator)
+		event = &ETHGatewayEventUnmarshaler{
+			Layer:          layerType,
+			Type:           eventType,
+			Number:         iter.Event.Raw.BlockNumber,
+			TxHash:         iter.Event.Raw.TxHash,
+			Amount:         iter.Event.Amount,
+			GatewayAddress: iter.Event.Raw.Address,
+			From:           iter.Event.From,
+			To:             iter.Event.To,
+			Index:          iter.Event.Raw.Index,
+		}
+	}
+	return event
+}
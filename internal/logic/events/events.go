@@ -20,10 +20,13 @@ func NewEventGather() *EventGather {
 		gathers: make(map[types.EventCategory]EventUnmarshaler),
 	}
 
+	g.gathers[types.ETHEventCategory] = &ETHGatewayEventUnmarshaler{}
 	g.gathers[types.ERC20EventCategory] = &ERC20GatewayEventUnmarshaler{}
 	g.gathers[types.ERC721EventCategory] = &ERC721GatewayEventUnmarshaler{}
 	g.gathers[types.ERC1155EventCategory] = &ERC1155GatewayEventUnmarshaler{}
 	g.gathers[types.MessengerEventCategory] = &MessengerEventUnmarshaler{}
+	g.gathers[types.BatchEventCategory] = &BatchEventUnmarshaler{}
+	g.gathers[types.MessageQueueEventCategory] = &MessageQueueEventUnmarshaler{}
 
 	return g
 }
@@ -0,0 +1,88 @@
+package events
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/scroll-tech/go-ethereum/common"
+
+	"github.com/scroll-tech/chain-monitor/internal/logic/contracts/abi/il1messagequeue"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+)
+
+// MessageQueueEventUnmarshaler is a struct representing the unmarshalled data of a message queue event
+// raised by the L1 message queue contract.
+type MessageQueueEventUnmarshaler struct {
+	Layer  types.LayerType
+	Type   types.EventType
+	Number uint64
+	TxHash common.Hash
+	Index  uint
+
+	// the enqueued message of QueueTransaction, the queue index is also set for DropTransaction.
+	QueueIndex uint64
+	Sender     common.Address
+	Target     common.Address
+	Value      *big.Int
+	GasLimit   *big.Int
+
+	// the popped messages of DequeueTransaction, the bit i of the bitmap is set if the message start index + i is skipped.
+	StartIndex    uint64
+	Count         uint64
+	SkippedBitmap *big.Int
+}
+
+// Unmarshal takes a context, layer type, and a list of iterators, and unmarshals the message queue events
+// from the L1 message queue contract.
+func (e *MessageQueueEventUnmarshaler) Unmarshal(context context.Context, layerType types.LayerType, iterators []types.WrapIterator) []EventUnmarshaler {
+	var events []EventUnmarshaler
+	for _, it := range iterators {
+		for it.Iter.Next() {
+			events = append(events, e.messageQueue(layerType, it.Iter, it.EventType))
+		}
+	}
+	return events
+}
+
+func (e *MessageQueueEventUnmarshaler) messageQueue(layerType types.LayerType, it types.Iterator, eventType types.EventType) EventUnmarshaler {
+	var event EventUnmarshaler
+	switch eventType {
+	case types.L1QueueTransaction:
+		iter := it.(*il1messagequeue.Il1messagequeueQueueTransactionIterator)
+		event = &MessageQueueEventUnmarshaler{
+			Layer:      layerType,
+			Type:       eventType,
+			Number:     iter.Event.Raw.BlockNumber,
+			TxHash:     iter.Event.Raw.TxHash,
+			Index:      iter.Event.Raw.Index,
+			QueueIndex: iter.Event.QueueIndex,
+			Sender:     iter.Event.Sender,
+			Target:     iter.Event.Target,
+			Value:      iter.Event.Value,
+			GasLimit:   iter.Event.GasLimit,
+		}
+	case types.L1DequeueTransaction:
+		iter := it.(*il1messagequeue.Il1messagequeueDequeueTransactionIterator)
+		event = &MessageQueueEventUnmarshaler{
+			Layer:         layerType,
+			Type:          eventType,
+			Number:        iter.Event.Raw.BlockNumber,
+			TxHash:        iter.Event.Raw.TxHash,
+			Index:         iter.Event.Raw.Index,
+			StartIndex:    iter.Event.StartIndex.Uint64(),
+			Count:         iter.Event.Count.Uint64(),
+			SkippedBitmap: iter.Event.SkippedBitmap,
+		}
+	case types.L1DropTransaction:
+		iter := it.(*il1messagequeue.Il1messagequeueDropTransactionIterator)
+		event = &MessageQueueEventUnmarshaler{
+			Layer:      layerType,
+			Type:       eventType,
+			Number:     iter.Event.Raw.BlockNumber,
+			TxHash:     iter.Event.Raw.TxHash,
+			Index:      iter.Event.Raw.Index,
+			QueueIndex: iter.Event.Index.Uint64(),
+		}
+	}
+	return event
+}
@@ -19,6 +19,8 @@ type MessengerEventUnmarshaler struct {
 	Number       uint64
 	TxHash       common.Hash
 	Index        uint
+	Sender       common.Address
+	Target       common.Address
 	MessageNonce *big.Int
 	Message      []byte
 	MessageHash  common.Hash
@@ -49,6 +51,8 @@ func (e *MessengerEventUnmarshaler) messenger(layerType types.LayerType, it type
 			Number:       iter.Event.Raw.BlockNumber,
 			TxHash:       iter.Event.Raw.TxHash,
 			Index:        iter.Event.Raw.Index,
+			Sender:       iter.Event.Sender,
+			Target:       iter.Event.Target,
 			MessageNonce: iter.Event.MessageNonce,
 			Message:      iter.Event.Message,
 			MessageHash:  msgHash,
@@ -63,6 +67,8 @@ func (e *MessengerEventUnmarshaler) messenger(layerType types.LayerType, it type
 			Number:       iter.Event.Raw.BlockNumber,
 			TxHash:       iter.Event.Raw.TxHash,
 			Index:        iter.Event.Raw.Index,
+			Sender:       iter.Event.Sender,
+			Target:       iter.Event.Target,
 			MessageNonce: iter.Event.MessageNonce,
 			Message:      iter.Event.Message,
 			MessageHash:  msgHash,
@@ -88,6 +94,26 @@ func (e *MessengerEventUnmarshaler) messenger(layerType types.LayerType, it type
 			Index:       iter.Event.Raw.Index,
 			MessageHash: iter.Event.MessageHash,
 		}
+	case types.L1FailedRelayedMessage:
+		iter := it.(*il1scrollmessenger.Il1scrollmessengerFailedRelayedMessageIterator)
+		event = &MessengerEventUnmarshaler{
+			Layer:       layerType,
+			Type:        eventType,
+			Number:      iter.Event.Raw.BlockNumber,
+			TxHash:      iter.Event.Raw.TxHash,
+			Index:       iter.Event.Raw.Index,
+			MessageHash: iter.Event.MessageHash,
+		}
+	case types.L2FailedRelayedMessage:
+		iter := it.(*il2scrollmessenger.Il2scrollmessengerFailedRelayedMessageIterator)
+		event = &MessengerEventUnmarshaler{
+			Layer:       layerType,
+			Type:        eventType,
+			Number:      iter.Event.Raw.BlockNumber,
+			TxHash:      iter.Event.Raw.TxHash,
+			Index:       iter.Event.Raw.Index,
+			MessageHash: iter.Event.MessageHash,
+		}
 	}
 	return event
 }
@@ -4,11 +4,10 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/scroll-tech/go-ethereum/log"
 	"gorm.io/gorm"
 
 	"github.com/scroll-tech/chain-monitor/internal/config"
-	"github.com/scroll-tech/chain-monitor/internal/logic/slack"
+	"github.com/scroll-tech/chain-monitor/internal/logic/alert"
 	"github.com/scroll-tech/chain-monitor/internal/orm"
 	"github.com/scroll-tech/chain-monitor/internal/types"
 	"github.com/scroll-tech/chain-monitor/internal/utils"
@@ -20,6 +19,8 @@ type LogicMessageMatch struct {
 	conf                     *config.Config
 	gatewayMessageMatchOrm   *orm.GatewayMessageMatch
 	messengerMessageMatchOrm *orm.MessengerMessageMatch
+	failedRelayedMessageOrm  *orm.FailedRelayedMessage
+	syncCursorOrm            *orm.SyncCursor
 }
 
 // NewMessageMatchLogic initializes a new instance of Logic with an instance of orm.GatewayMessageMatch/orm.MessengerMessageMatch
@@ -29,87 +30,23 @@ func NewMessageMatchLogic(cfg *config.Config, db *gorm.DB) *LogicMessageMatch {
 		conf:                     cfg,
 		gatewayMessageMatchOrm:   orm.NewGatewayMessageMatch(db),
 		messengerMessageMatchOrm: orm.NewMessengerMessageMatch(db),
+		failedRelayedMessageOrm:  orm.NewFailedRelayedMessage(db),
+		syncCursorOrm:            orm.NewSyncCursor(db),
 	}
 }
 
-// GetBlocksStatus get the status from start block number to end block number
-func (t *LogicMessageMatch) GetBlocksStatus(ctx context.Context, startBlockNumber, endBlockNumber uint64) (bool, bool) {
-	gatewayCheck := t.checkGateway(ctx, startBlockNumber, endBlockNumber)
-	messengerCheck := !t.checkMessenger(ctx, startBlockNumber, endBlockNumber)
-	return gatewayCheck, messengerCheck
-}
-
-func (t *LogicMessageMatch) checkGateway(ctx context.Context, startBlockNumber, endBlockNumber uint64) bool {
-	gatewayMessageMatches, err := t.gatewayMessageMatchOrm.GetBlocksStatus(ctx, startBlockNumber, endBlockNumber)
-	if err != nil {
-		log.Error("LogicMessageMatch.gatewayMessageMatches failed", "start block number", startBlockNumber, "end block number", endBlockNumber, "error", err)
-		return false
-	}
-
-	if len(gatewayMessageMatches) == 0 {
-		return true
-	}
-
-	for _, gatewayMessageMatch := range gatewayMessageMatches {
-		if gatewayMessageMatch.L2EventType == int(types.L2WithdrawERC20) ||
-			gatewayMessageMatch.L2EventType == int(types.L2WithdrawERC721) ||
-			gatewayMessageMatch.L2EventType == int(types.L2WithdrawERC1155) ||
-			gatewayMessageMatch.L2EventType == int(types.L2BatchWithdrawERC721) ||
-			gatewayMessageMatch.L2EventType == int(types.L2BatchWithdrawERC1155) {
-			if gatewayMessageMatch.L2BlockNumber == 0 ||
-				gatewayMessageMatch.L2BlockStatus == int(types.BlockStatusTypeInvalid) {
-				return false
-			}
-		}
-
-		if gatewayMessageMatch.L1BlockNumber != 0 && gatewayMessageMatch.L2BlockNumber != 0 {
-			if gatewayMessageMatch.L1BlockStatus == int(types.BlockStatusTypeInvalid) ||
-				gatewayMessageMatch.L2BlockStatus == int(types.BlockStatusTypeInvalid) ||
-				gatewayMessageMatch.L1CrossChainStatus == int(types.CrossChainStatusTypeInvalid) ||
-				gatewayMessageMatch.L2CrossChainStatus == int(types.CrossChainStatusTypeInvalid) {
-				return false
-			}
-		}
-	}
-	return true
-}
-
-func (t *LogicMessageMatch) checkMessenger(ctx context.Context, startBlockNumber, endBlockNumber uint64) bool {
-	messengerMessageMatches, err := t.messengerMessageMatchOrm.GetBlocksStatus(ctx, startBlockNumber, endBlockNumber)
-	if err != nil {
-		log.Error("LogicMessageMatch.messengerMessageMatches failed", "start block number", startBlockNumber, "end block number", endBlockNumber, "error", err)
-		return false
-	}
-
-	if len(messengerMessageMatches) == 0 {
-		return true
+// GetLatestBlockNumber retrieves the latest block number for a given layer type.
+// The sync cursor takes precedence, and the latest valid message match is used for the databases without the sync cursor.
+func (t *LogicMessageMatch) GetLatestBlockNumber(ctx context.Context, layer types.LayerType) (uint64, error) {
+	cursor, cursorErr := t.syncCursorOrm.GetSyncCursor(ctx, layer, types.CheckerTypeContract)
+	if cursorErr != nil {
+		return 0, cursorErr
 	}
 
-	for _, messengerMessageMatch := range messengerMessageMatches {
-		if messengerMessageMatch.L2EventType == int(types.L2SentMessage) {
-			if messengerMessageMatch.L2BlockNumber == 0 ||
-				messengerMessageMatch.L2BlockStatus == int(types.BlockStatusTypeInvalid) ||
-				messengerMessageMatch.L2ETHBalanceStatus == int(types.ETHBalanceStatusTypeInvalid) {
-				return false
-			}
-		}
-
-		if messengerMessageMatch.L1BlockNumber != 0 && messengerMessageMatch.L2BlockNumber != 0 {
-			if messengerMessageMatch.L1BlockStatus == int(types.BlockStatusTypeInvalid) ||
-				messengerMessageMatch.L2BlockStatus == int(types.BlockStatusTypeInvalid) ||
-				messengerMessageMatch.L1CrossChainStatus == int(types.CrossChainStatusTypeInvalid) ||
-				messengerMessageMatch.L2CrossChainStatus == int(types.CrossChainStatusTypeInvalid) ||
-				messengerMessageMatch.L1ETHBalanceStatus == int(types.ETHBalanceStatusTypeInvalid) ||
-				messengerMessageMatch.L2ETHBalanceStatus == int(types.ETHBalanceStatusTypeInvalid) {
-				return false
-			}
-		}
+	if cursor != nil {
+		return cursor.BlockNumber, nil
 	}
-	return true
-}
 
-// GetLatestBlockNumber retrieves the latest block number for a given layer type.
-func (t *LogicMessageMatch) GetLatestBlockNumber(ctx context.Context, layer types.LayerType) (uint64, error) {
 	blockValidMessageMatch, blockValidErr := t.messengerMessageMatchOrm.GetLatestBlockValidMessageMatch(ctx, layer)
 	if blockValidErr != nil {
 		return 0, blockValidErr
@@ -120,7 +57,7 @@ func (t *LogicMessageMatch) GetLatestBlockNumber(ctx context.Context, layer type
 	}
 
 	if layer == types.Layer2 && blockValidMessageMatch == nil {
-		return 0, nil
+		return t.conf.L2Config.StartNumber, nil
 	}
 
 	var number uint64
@@ -134,16 +71,26 @@ func (t *LogicMessageMatch) GetLatestBlockNumber(ctx context.Context, layer type
 	return number, nil
 }
 
-// InsertOrUpdateMessageMatches insert or update the gateway/messenger event info
-func (t *LogicMessageMatch) InsertOrUpdateMessageMatches(ctx context.Context, layer types.LayerType, gatewayMessageMatches []orm.GatewayMessageMatch, messengerMessageMatches []orm.MessengerMessageMatch) error {
+// InsertOrUpdateMessageMatches insert or update the gateway/messenger event info and the failed relayed messages,
+// the block statuses of the layer are valid unless the message matches are marked failed by the checks.
+func (t *LogicMessageMatch) InsertOrUpdateMessageMatches(ctx context.Context, layer types.LayerType, gatewayMessageMatches []orm.GatewayMessageMatch, messengerMessageMatches []orm.MessengerMessageMatch, failedRelayedMessages []orm.FailedRelayedMessageEvent, dbTX ...*gorm.DB) error {
+	db := t.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+
 	var effectRows int64
-	err := t.db.Transaction(func(tx *gorm.DB) error {
+	err := db.Transaction(func(tx *gorm.DB) error {
 		for _, message := range messengerMessageMatches {
 			if layer == types.Layer1 {
-				message.L1BlockStatus = int(types.BlockStatusTypeValid)
+				if message.L1BlockStatus != int(types.BlockStatusTypeFailed) {
+					message.L1BlockStatus = int(types.BlockStatusTypeValid)
+				}
 				message.L1BlockStatusUpdatedAt = utils.NowUTC()
 			} else {
-				message.L2BlockStatus = int(types.BlockStatusTypeValid)
+				if message.L2BlockStatus != int(types.BlockStatusTypeFailed) {
+					message.L2BlockStatus = int(types.BlockStatusTypeValid)
+				}
 				message.L2BlockStatusUpdatedAt = utils.NowUTC()
 			}
 			effectRow, err := t.messengerMessageMatchOrm.InsertOrUpdateEventInfo(ctx, layer, message, tx)
@@ -152,7 +99,7 @@ func (t *LogicMessageMatch) InsertOrUpdateMessageMatches(ctx context.Context, la
 			}
 
 			if effectRow == 0 {
-				slack.Notify(slack.MrkDwnMessengerMessageMatchDuplicated(layer, message))
+				alert.Notify(alert.MessengerMessageMatchDuplicatedAlert(layer, message))
 				return fmt.Errorf("messenger event orm insert duplicated")
 			}
 			effectRows += effectRow
@@ -160,10 +107,14 @@ func (t *LogicMessageMatch) InsertOrUpdateMessageMatches(ctx context.Context, la
 
 		for _, message := range gatewayMessageMatches {
 			if layer == types.Layer1 {
-				message.L1BlockStatus = int(types.BlockStatusTypeValid)
+				if message.L1BlockStatus != int(types.BlockStatusTypeFailed) {
+					message.L1BlockStatus = int(types.BlockStatusTypeValid)
+				}
 				message.L1BlockStatusUpdatedAt = utils.NowUTC()
 			} else {
-				message.L2BlockStatus = int(types.BlockStatusTypeValid)
+				if message.L2BlockStatus != int(types.BlockStatusTypeFailed) {
+					message.L2BlockStatus = int(types.BlockStatusTypeValid)
+				}
 				message.L2BlockStatusUpdatedAt = utils.NowUTC()
 			}
 			effectRow, err := t.gatewayMessageMatchOrm.InsertOrUpdateEventInfo(ctx, layer, message, tx)
@@ -172,11 +123,18 @@ func (t *LogicMessageMatch) InsertOrUpdateMessageMatches(ctx context.Context, la
 			}
 
 			if effectRow == 0 {
-				slack.Notify(slack.MrkDwnGatewayMessageMatchDuplicated(layer, message))
+				alert.Notify(alert.GatewayMessageMatchDuplicatedAlert(layer, message))
 				return fmt.Errorf("gateway event orm insert duplicated")
 			}
 			effectRows += effectRow
 		}
+
+		// The FailedRelayedMessage events of rescanned blocks are recorded already, so the effect rows are not checked.
+		for _, message := range failedRelayedMessages {
+			if _, err := t.failedRelayedMessageOrm.InsertOrUpdateFailedRelayedMessage(ctx, message, tx); err != nil {
+				return fmt.Errorf("failed relayed message orm insert failed, err: %w, layer:%s", err, layer.String())
+			}
+		}
 		return nil
 	})
 	if err != nil {
@@ -188,3 +146,59 @@ func (t *LogicMessageMatch) InsertOrUpdateMessageMatches(ctx context.Context, la
 	}
 	return nil
 }
+
+// UpsertMessageMatches insert or overwrite the gateway/messenger event info of the layer, it's used by rescan to
+// rewrite the results of the blocks which have been processed by the watcher.
+func (t *LogicMessageMatch) UpsertMessageMatches(ctx context.Context, layer types.LayerType, gatewayMessageMatches []orm.GatewayMessageMatch, messengerMessageMatches []orm.MessengerMessageMatch, failedRelayedMessages []orm.FailedRelayedMessageEvent) error {
+	err := t.db.Transaction(func(tx *gorm.DB) error {
+		for _, message := range messengerMessageMatches {
+			if layer == types.Layer1 {
+				if message.L1BlockStatus != int(types.BlockStatusTypeFailed) {
+					message.L1BlockStatus = int(types.BlockStatusTypeValid)
+				}
+				message.L1BlockStatusUpdatedAt = utils.NowUTC()
+			} else {
+				if message.L2BlockStatus != int(types.BlockStatusTypeFailed) {
+					message.L2BlockStatus = int(types.BlockStatusTypeValid)
+				}
+				message.L2BlockStatusUpdatedAt = utils.NowUTC()
+			}
+			if _, err := t.messengerMessageMatchOrm.UpsertEventInfo(ctx, layer, message, tx); err != nil {
+				return fmt.Errorf("messenger event orm upsert failed, err: %w, layer:%s", err, layer.String())
+			}
+		}
+
+		for _, message := range gatewayMessageMatches {
+			if layer == types.Layer1 {
+				if message.L1BlockStatus != int(types.BlockStatusTypeFailed) {
+					message.L1BlockStatus = int(types.BlockStatusTypeValid)
+				}
+				message.L1BlockStatusUpdatedAt = utils.NowUTC()
+			} else {
+				if message.L2BlockStatus != int(types.BlockStatusTypeFailed) {
+					message.L2BlockStatus = int(types.BlockStatusTypeValid)
+				}
+				message.L2BlockStatusUpdatedAt = utils.NowUTC()
+			}
+			if _, err := t.gatewayMessageMatchOrm.UpsertEventInfo(ctx, layer, message, tx); err != nil {
+				return fmt.Errorf("gateway event orm upsert failed, err: %w, layer:%s", err, layer.String())
+			}
+		}
+
+		for _, message := range failedRelayedMessages {
+			if _, err := t.failedRelayedMessageOrm.InsertOrUpdateFailedRelayedMessage(ctx, message, tx); err != nil {
+				return fmt.Errorf("failed relayed message orm insert failed, err: %w, layer:%s", err, layer.String())
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("upsert event info failed, err:%w", err)
+	}
+	return nil
+}
+
+// UpdateSyncCursor updates the sync cursor of the contract checker to the last fully processed block.
+func (t *LogicMessageMatch) UpdateSyncCursor(ctx context.Context, layer types.LayerType, blockNumber uint64, blockHash string, dbTX ...*gorm.DB) error {
+	return t.syncCursorOrm.InsertOrUpdateSyncCursor(ctx, layer, types.CheckerTypeContract, blockNumber, blockHash, dbTX...)
+}
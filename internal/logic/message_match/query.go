@@ -0,0 +1,254 @@
+package messagematch
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+
+	"github.com/scroll-tech/chain-monitor/internal/orm"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+)
+
+const (
+	defaultPageSize = 20
+)
+
+// MessengerMessageMatchView the messenger message match with the event types and statuses rendered by name.
+type MessengerMessageMatchView struct {
+	ID          int64  `json:"id"`
+	MessageHash string `json:"message_hash"`
+
+	L1EventType   string `json:"l1_event_type"`
+	L1BlockNumber uint64 `json:"l1_block_number"`
+	L1TxHash      string `json:"l1_tx_hash"`
+	L2EventType   string `json:"l2_event_type"`
+	L2BlockNumber uint64 `json:"l2_block_number"`
+	L2TxHash      string `json:"l2_tx_hash"`
+
+	ETHAmount       string `json:"eth_amount"`
+	ETHAmountStatus string `json:"eth_amount_status"`
+
+	L1ETHBalanceStatus string `json:"l1_eth_balance_status"`
+	L2ETHBalanceStatus string `json:"l2_eth_balance_status"`
+	L1BlockStatus      string `json:"l1_block_status"`
+	L2BlockStatus      string `json:"l2_block_status"`
+	L1CrossChainStatus string `json:"l1_cross_chain_status"`
+	L2CrossChainStatus string `json:"l2_cross_chain_status"`
+	WithdrawRootStatus string `json:"withdraw_root_status"`
+	FailureReason      string `json:"failure_reason,omitempty"`
+
+	// only set once the sent message event is ingested.
+	Sender       string  `json:"sender,omitempty"`
+	Target       string  `json:"target,omitempty"`
+	MessageNonce *uint64 `json:"message_nonce,omitempty"`
+	Calldata     string  `json:"calldata,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// GatewayMessageMatchView the gateway message match with the event types and statuses rendered by name.
+type GatewayMessageMatchView struct {
+	ID          int64  `json:"id"`
+	MessageHash string `json:"message_hash"`
+	TokenType   string `json:"token_type"`
+
+	L1EventType    string `json:"l1_event_type"`
+	L1BlockNumber  uint64 `json:"l1_block_number"`
+	L1TxHash       string `json:"l1_tx_hash"`
+	L1TokenAddress string `json:"l1_token_address"`
+	L1TokenIds     string `json:"l1_token_ids"`
+	L1Amounts      string `json:"l1_amounts"`
+	L2EventType    string `json:"l2_event_type"`
+	L2BlockNumber  uint64 `json:"l2_block_number"`
+	L2TxHash       string `json:"l2_tx_hash"`
+	L2TokenAddress string `json:"l2_token_address"`
+	L2TokenIds     string `json:"l2_token_ids"`
+	L2Amounts      string `json:"l2_amounts"`
+
+	L1BlockStatus      string `json:"l1_block_status"`
+	L2BlockStatus      string `json:"l2_block_status"`
+	L1CrossChainStatus string `json:"l1_cross_chain_status"`
+	L2CrossChainStatus string `json:"l2_cross_chain_status"`
+	FailureReason      string `json:"failure_reason,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// MessageMatches the messenger and gateway message matches of a query.
+type MessageMatches struct {
+	Messenger []*MessengerMessageMatchView `json:"messenger"`
+	Gateway   []*GatewayMessageMatchView   `json:"gateway"`
+}
+
+// MessageMatchPage a page of the message match listing.
+type MessageMatchPage struct {
+	Total     int64                        `json:"total"`
+	Page      int                          `json:"page"`
+	PageSize  int                          `json:"page_size"`
+	Messenger []*MessengerMessageMatchView `json:"messenger,omitempty"`
+	Gateway   []*GatewayMessageMatchView   `json:"gateway,omitempty"`
+}
+
+// GetMessageMatches get the messenger and gateway message matches of the message hash.
+func (t *LogicMessageMatch) GetMessageMatches(ctx context.Context, messageHash string) (*MessageMatches, error) {
+	messageHash = normalizeHash(messageHash)
+	messengerMessages, err := t.messengerMessageMatchOrm.GetMessageMatchesByMessageHashes(ctx, []string{messageHash})
+	if err != nil {
+		return nil, err
+	}
+
+	gatewayMessages, err := t.gatewayMessageMatchOrm.GetGatewayMessageMatchesByMessageHashes(ctx, []string{messageHash})
+	if err != nil {
+		return nil, err
+	}
+	return newMessageMatches(messengerMessages, gatewayMessages), nil
+}
+
+// GetMessageMatchesByTxHash get the messenger and gateway message matches whose l1 or l2 tx hash is txHash.
+func (t *LogicMessageMatch) GetMessageMatchesByTxHash(ctx context.Context, txHash string) (*MessageMatches, error) {
+	txHash = normalizeHash(txHash)
+	messengerMessages, err := t.messengerMessageMatchOrm.GetMessageMatchesByTxHash(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	gatewayMessages, err := t.gatewayMessageMatchOrm.GetGatewayMessageMatchesByTxHash(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	return newMessageMatches(messengerMessages, gatewayMessages), nil
+}
+
+// ListMessageMatches lists the messenger or gateway message matches by the filters of the param, from the latest one.
+func (t *LogicMessageMatch) ListMessageMatches(ctx context.Context, param *types.MessageMatchListParam) (*MessageMatchPage, error) {
+	page := &MessageMatchPage{Page: param.Page, PageSize: param.PageSize}
+	if page.Page == 0 {
+		page.Page = 1
+	}
+	if page.PageSize == 0 {
+		page.PageSize = defaultPageSize
+	}
+
+	filter := orm.MessageMatchFilter{
+		L1BlockStatus:      param.L1BlockStatus,
+		L2BlockStatus:      param.L2BlockStatus,
+		L1CrossChainStatus: param.L1CrossChainStatus,
+		L2CrossChainStatus: param.L2CrossChainStatus,
+		WithdrawRootStatus: param.WithdrawRootStatus,
+		TokenType:          param.TokenType,
+		Layer:              types.LayerType(param.Layer),
+		StartBlockNumber:   param.StartBlockNumber,
+		EndBlockNumber:     param.EndBlockNumber,
+	}
+	if param.TokenAddress != "" {
+		filter.TokenAddress = common.HexToAddress(param.TokenAddress).Hex()
+	}
+
+	offset := (page.Page - 1) * page.PageSize
+	switch param.Kind {
+	case "messenger":
+		messages, total, err := t.messengerMessageMatchOrm.ListMessageMatches(ctx, filter, offset, page.PageSize)
+		if err != nil {
+			return nil, err
+		}
+		page.Total = total
+		page.Messenger = newMessageMatches(messages, nil).Messenger
+	case "gateway":
+		messages, total, err := t.gatewayMessageMatchOrm.ListGatewayMessageMatches(ctx, filter, offset, page.PageSize)
+		if err != nil {
+			return nil, err
+		}
+		page.Total = total
+		page.Gateway = newMessageMatches(nil, messages).Gateway
+	}
+	return page, nil
+}
+
+func normalizeHash(hash string) string {
+	return strings.ToLower(strings.TrimSpace(hash))
+}
+
+func newMessageMatches(messengerMessages []orm.MessengerMessageMatch, gatewayMessages []orm.GatewayMessageMatch) *MessageMatches {
+	matches := &MessageMatches{
+		Messenger: make([]*MessengerMessageMatchView, 0, len(messengerMessages)),
+		Gateway:   make([]*GatewayMessageMatchView, 0, len(gatewayMessages)),
+	}
+	for _, message := range messengerMessages {
+		matches.Messenger = append(matches.Messenger, newMessengerMessageMatchView(message))
+	}
+	for _, message := range gatewayMessages {
+		matches.Gateway = append(matches.Gateway, newGatewayMessageMatchView(message))
+	}
+	return matches
+}
+
+func newMessengerMessageMatchView(message orm.MessengerMessageMatch) *MessengerMessageMatchView {
+	view := &MessengerMessageMatchView{
+		ID:                 message.ID,
+		MessageHash:        message.MessageHash,
+		L1EventType:        types.EventType(message.L1EventType).String(),
+		L1BlockNumber:      message.L1BlockNumber,
+		L1TxHash:           message.L1TxHash,
+		L2EventType:        types.EventType(message.L2EventType).String(),
+		L2BlockNumber:      message.L2BlockNumber,
+		L2TxHash:           message.L2TxHash,
+		ETHAmount:          message.ETHAmount,
+		ETHAmountStatus:    types.ETHAmountStatus(message.ETHAmountStatus).String(),
+		L1ETHBalanceStatus: types.ETHBalanceStatus(message.L1ETHBalanceStatus).String(),
+		L2ETHBalanceStatus: types.ETHBalanceStatus(message.L2ETHBalanceStatus).String(),
+		L1BlockStatus:      types.BlockStatus(message.L1BlockStatus).String(),
+		L2BlockStatus:      types.BlockStatus(message.L2BlockStatus).String(),
+		L1CrossChainStatus: types.CrossChainStatusType(message.L1CrossChainStatus).String(),
+		L2CrossChainStatus: types.CrossChainStatusType(message.L2CrossChainStatus).String(),
+		WithdrawRootStatus: types.WithdrawRootStatus(message.WithdrawRootStatus).String(),
+		FailureReason:      message.FailureReason,
+		CreatedAt:          message.CreatedAt,
+		UpdatedAt:          message.UpdatedAt,
+	}
+	if message.Sender != "" {
+		view.Sender = message.Sender
+		view.Target = message.Target
+		view.Calldata = hexutil.Encode(message.Calldata)
+	}
+	if nonce, err := strconv.ParseUint(message.MessageNonce, 10, 64); err == nil {
+		view.MessageNonce = &nonce
+	} else if message.NextMessageNonce > 0 {
+		// the next message nonce is the message nonce + 1 to distinguish from the zero value.
+		nonce = message.NextMessageNonce - 1
+		view.MessageNonce = &nonce
+	}
+	return view
+}
+
+func newGatewayMessageMatchView(message orm.GatewayMessageMatch) *GatewayMessageMatchView {
+	return &GatewayMessageMatchView{
+		ID:                 message.ID,
+		MessageHash:        message.MessageHash,
+		TokenType:          types.TokenType(message.TokenType).String(),
+		L1EventType:        types.EventType(message.L1EventType).String(),
+		L1BlockNumber:      message.L1BlockNumber,
+		L1TxHash:           message.L1TxHash,
+		L1TokenAddress:     message.L1TokenAddress,
+		L1TokenIds:         message.L1TokenIds,
+		L1Amounts:          message.L1Amounts,
+		L2EventType:        types.EventType(message.L2EventType).String(),
+		L2BlockNumber:      message.L2BlockNumber,
+		L2TxHash:           message.L2TxHash,
+		L2TokenAddress:     message.L2TokenAddress,
+		L2TokenIds:         message.L2TokenIds,
+		L2Amounts:          message.L2Amounts,
+		L1BlockStatus:      types.BlockStatus(message.L1BlockStatus).String(),
+		L2BlockStatus:      types.BlockStatus(message.L2BlockStatus).String(),
+		L1CrossChainStatus: types.CrossChainStatusType(message.L1CrossChainStatus).String(),
+		L2CrossChainStatus: types.CrossChainStatusType(message.L2CrossChainStatus).String(),
+		FailureReason:      message.FailureReason,
+		CreatedAt:          message.CreatedAt,
+		UpdatedAt:          message.UpdatedAt,
+	}
+}
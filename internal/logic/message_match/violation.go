@@ -0,0 +1,212 @@
+package messagematch
+
+import (
+	"context"
+
+	"github.com/scroll-tech/chain-monitor/internal/orm"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+)
+
+// ViolationCheck the kind of the status check which is failed.
+type ViolationCheck string
+
+const (
+	// ViolationCheckBlock the block status check of the gateway and transfer events.
+	ViolationCheckBlock ViolationCheck = "block"
+	// ViolationCheckCrossChain the cross chain check of the l1 and l2 events.
+	ViolationCheckCrossChain ViolationCheck = "cross_chain"
+	// ViolationCheckETHBalance the eth balance check of the messenger.
+	ViolationCheckETHBalance ViolationCheck = "eth_balance"
+	// ViolationCheckWithdrawRoot the withdraw root check of the finalized batches.
+	ViolationCheckWithdrawRoot ViolationCheck = "withdraw_root"
+)
+
+// ViolationStatus the status of the check which is not valid.
+type ViolationStatus string
+
+const (
+	// ViolationStatusUnchecked the check is not done yet, which makes the l2 block range containing it pending.
+	ViolationStatusUnchecked ViolationStatus = "unchecked"
+	// ViolationStatusFailed the check is failed, which makes the l2 block range containing it invalid.
+	ViolationStatusFailed ViolationStatus = "failed"
+)
+
+// Violation is a status check of a row which is unchecked or failed.
+type Violation struct {
+	MessageHash string          `json:"message_hash,omitempty"`
+	Table       string          `json:"table"`
+	Check       ViolationCheck  `json:"check"`
+	Status      ViolationStatus `json:"status"`
+	Layer       string          `json:"layer"`
+	Column      string          `json:"column"`
+	// the failure reason of the row, empty if the check is unchecked.
+	Reason string `json:"reason,omitempty"`
+
+	L1BlockNumber uint64 `json:"l1_block_number,omitempty"`
+	L1TxHash      string `json:"l1_tx_hash,omitempty"`
+	L2BlockNumber uint64 `json:"l2_block_number,omitempty"`
+	L2TxHash      string `json:"l2_tx_hash,omitempty"`
+}
+
+// BlocksStatus the status of a l2 block range with the violations found in it. The range is invalid if there is any
+// failed check, and pending if there is any unchecked one or it's not fully ingested yet.
+type BlocksStatus struct {
+	Status     string       `json:"status"`
+	Violations []*Violation `json:"violations"`
+}
+
+// GetBlocksStatus get the status and the violations of the message matches from start block number to end block number.
+func (t *LogicMessageMatch) GetBlocksStatus(ctx context.Context, startBlockNumber, endBlockNumber uint64) (*BlocksStatus, error) {
+	l2BlockNumber, err := t.GetLatestBlockNumber(ctx, types.Layer2)
+	if err != nil {
+		return nil, err
+	}
+
+	violations, err := t.GetBlocksViolations(ctx, startBlockNumber, endBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	status := ViolationsStatus(violations)
+	if status == types.BatchStatusTypeValid && endBlockNumber > l2BlockNumber {
+		status = types.BatchStatusTypePending
+	}
+	return &BlocksStatus{Status: status.String(), Violations: violations}, nil
+}
+
+// ViolationsStatus returns invalid if any of the violations is failed, pending if any is unchecked, and valid otherwise.
+func ViolationsStatus(violations []*Violation) types.BatchStatus {
+	status := types.BatchStatusTypeValid
+	for _, violation := range violations {
+		if violation.Status == ViolationStatusFailed {
+			return types.BatchStatusTypeInvalid
+		}
+		status = types.BatchStatusTypePending
+	}
+	return status
+}
+
+// GetBlocksViolations collects all the unchecked and failed status checks of the gateway and messenger message matches
+// whose l2 block number is between start block number and end block number.
+func (t *LogicMessageMatch) GetBlocksViolations(ctx context.Context, startBlockNumber, endBlockNumber uint64) ([]*Violation, error) {
+	gatewayMessageMatches, err := t.gatewayMessageMatchOrm.GetBlocksStatus(ctx, startBlockNumber, endBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	messengerMessageMatches, err := t.messengerMessageMatchOrm.GetBlocksStatus(ctx, startBlockNumber, endBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	violations := make([]*Violation, 0)
+	for _, message := range gatewayMessageMatches {
+		violations = append(violations, gatewayViolations(message)...)
+	}
+	for _, message := range messengerMessageMatches {
+		violations = append(violations, messengerViolations(message)...)
+	}
+	return violations, nil
+}
+
+func gatewayViolations(message orm.GatewayMessageMatch) []*Violation {
+	row := Violation{
+		MessageHash:   message.MessageHash,
+		Table:         message.TableName(),
+		Reason:        message.FailureReason,
+		L1BlockNumber: message.L1BlockNumber,
+		L1TxHash:      message.L1TxHash,
+		L2BlockNumber: message.L2BlockNumber,
+		L2TxHash:      message.L2TxHash,
+	}
+
+	var violations []*Violation
+	withdraw := isGatewayWithdrawEvent(types.EventType(message.L2EventType))
+	relayed := message.L1BlockNumber != 0 && message.L2BlockNumber != 0
+	if (withdraw || relayed) && message.L2BlockStatus != int(types.BlockStatusTypeValid) {
+		violations = append(violations, newViolation(row, ViolationCheckBlock, types.Layer2, "l2_block_status", message.L2BlockStatus == int(types.BlockStatusTypeFailed)))
+	}
+	if !relayed {
+		return violations
+	}
+
+	if message.L1BlockStatus != int(types.BlockStatusTypeValid) {
+		violations = append(violations, newViolation(row, ViolationCheckBlock, types.Layer1, "l1_block_status", message.L1BlockStatus == int(types.BlockStatusTypeFailed)))
+	}
+	if message.L1CrossChainStatus != int(types.CrossChainStatusTypeValid) {
+		violations = append(violations, newViolation(row, ViolationCheckCrossChain, types.Layer1, "l1_cross_chain_status", message.L1CrossChainStatus == int(types.CrossChainStatusTypeFailed)))
+	}
+	if message.L2CrossChainStatus != int(types.CrossChainStatusTypeValid) {
+		violations = append(violations, newViolation(row, ViolationCheckCrossChain, types.Layer2, "l2_cross_chain_status", message.L2CrossChainStatus == int(types.CrossChainStatusTypeFailed)))
+	}
+	return violations
+}
+
+func messengerViolations(message orm.MessengerMessageMatch) []*Violation {
+	row := Violation{
+		MessageHash:   message.MessageHash,
+		Table:         message.TableName(),
+		Reason:        message.FailureReason,
+		L1BlockNumber: message.L1BlockNumber,
+		L1TxHash:      message.L1TxHash,
+		L2BlockNumber: message.L2BlockNumber,
+		L2TxHash:      message.L2TxHash,
+	}
+
+	var violations []*Violation
+	sent := message.L2EventType == int(types.L2SentMessage)
+	relayed := message.L1BlockNumber != 0 && message.L2BlockNumber != 0
+	if sent || relayed {
+		if message.L2BlockStatus != int(types.BlockStatusTypeValid) {
+			violations = append(violations, newViolation(row, ViolationCheckBlock, types.Layer2, "l2_block_status", message.L2BlockStatus == int(types.BlockStatusTypeFailed)))
+		}
+		if message.L2ETHBalanceStatus != int(types.ETHBalanceStatusTypeValid) {
+			violations = append(violations, newViolation(row, ViolationCheckETHBalance, types.Layer2, "l2_eth_balance_status", message.L2ETHBalanceStatus == int(types.ETHBalanceStatusTypeFailed)))
+		}
+	}
+	// the withdraw root is checked against the l2 sent messages only.
+	if sent && message.WithdrawRootStatus != int(types.WithdrawRootStatusTypeValid) {
+		violations = append(violations, newViolation(row, ViolationCheckWithdrawRoot, types.Layer2, "withdraw_root_status", message.WithdrawRootStatus == int(types.WithdrawRootStatusTypeFailed)))
+	}
+	if !relayed {
+		return violations
+	}
+
+	if message.L1BlockStatus != int(types.BlockStatusTypeValid) {
+		violations = append(violations, newViolation(row, ViolationCheckBlock, types.Layer1, "l1_block_status", message.L1BlockStatus == int(types.BlockStatusTypeFailed)))
+	}
+	if message.L1CrossChainStatus != int(types.CrossChainStatusTypeValid) {
+		violations = append(violations, newViolation(row, ViolationCheckCrossChain, types.Layer1, "l1_cross_chain_status", message.L1CrossChainStatus == int(types.CrossChainStatusTypeFailed)))
+	}
+	if message.L2CrossChainStatus != int(types.CrossChainStatusTypeValid) {
+		violations = append(violations, newViolation(row, ViolationCheckCrossChain, types.Layer2, "l2_cross_chain_status", message.L2CrossChainStatus == int(types.CrossChainStatusTypeFailed)))
+	}
+	if message.L1ETHBalanceStatus != int(types.ETHBalanceStatusTypeValid) {
+		violations = append(violations, newViolation(row, ViolationCheckETHBalance, types.Layer1, "l1_eth_balance_status", message.L1ETHBalanceStatus == int(types.ETHBalanceStatusTypeFailed)))
+	}
+	return violations
+}
+
+// newViolation creates the violation of the check from the row it's found in, the failure reason of the row is only
+// kept if the check is failed.
+func newViolation(row Violation, check ViolationCheck, layer types.LayerType, column string, failed bool) *Violation {
+	row.Check = check
+	row.Layer = layer.String()
+	row.Column = column
+	row.Status = ViolationStatusUnchecked
+	if failed {
+		row.Status = ViolationStatusFailed
+	} else {
+		row.Reason = ""
+	}
+	return &row
+}
+
+func isGatewayWithdrawEvent(eventType types.EventType) bool {
+	switch eventType {
+	case types.L2WithdrawETH, types.L2WithdrawERC20, types.L2WithdrawERC721, types.L2WithdrawERC1155,
+		types.L2BatchWithdrawERC721, types.L2BatchWithdrawERC1155:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,229 @@
+package messagematch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scroll-tech/chain-monitor/internal/orm"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+)
+
+// violationKey is the check, the layer, the column and the status of a violation.
+type violationKey struct {
+	check  ViolationCheck
+	layer  types.LayerType
+	column string
+	status ViolationStatus
+}
+
+func violationKeys(violations []*Violation) []violationKey {
+	var keys []violationKey
+	for _, violation := range violations {
+		layer := types.Layer1
+		if violation.Layer == types.Layer2.String() {
+			layer = types.Layer2
+		}
+		keys = append(keys, violationKey{check: violation.Check, layer: layer, column: violation.Column, status: violation.Status})
+	}
+	return keys
+}
+
+func TestGatewayViolations(t *testing.T) {
+	validWithdraw := orm.GatewayMessageMatch{
+		MessageHash:        "0x1",
+		L1EventType:        int(types.L1FinalizeWithdrawETH),
+		L1BlockNumber:      100,
+		L1TxHash:           "0xa1",
+		L2EventType:        int(types.L2WithdrawETH),
+		L2BlockNumber:      10,
+		L2TxHash:           "0xb1",
+		L1BlockStatus:      int(types.BlockStatusTypeValid),
+		L2BlockStatus:      int(types.BlockStatusTypeValid),
+		L1CrossChainStatus: int(types.CrossChainStatusTypeValid),
+		L2CrossChainStatus: int(types.CrossChainStatusTypeValid),
+	}
+
+	tests := []struct {
+		name           string
+		message        func() orm.GatewayMessageMatch
+		wantViolations []violationKey
+	}{
+		{"valid", func() orm.GatewayMessageMatch { return validWithdraw }, nil},
+		{
+			"pendingWithdraw", func() orm.GatewayMessageMatch {
+				return orm.GatewayMessageMatch{MessageHash: "0x1", L2EventType: int(types.L2WithdrawETH), L2BlockNumber: 10}
+			},
+			[]violationKey{{ViolationCheckBlock, types.Layer2, "l2_block_status", ViolationStatusUnchecked}},
+		},
+		{
+			// the deposit isn't relayed on l2 yet, the l1 checks are done after it's relayed.
+			"depositNotRelayed", func() orm.GatewayMessageMatch {
+				return orm.GatewayMessageMatch{MessageHash: "0x1", L1EventType: int(types.L1DepositETH), L1BlockNumber: 100}
+			},
+			nil,
+		},
+		{
+			"pendingCrossChain", func() orm.GatewayMessageMatch {
+				message := validWithdraw
+				message.L1CrossChainStatus = int(types.CrossChainStatusTypeUnchecked)
+				message.L2CrossChainStatus = int(types.CrossChainStatusTypeUnchecked)
+				return message
+			},
+			[]violationKey{
+				{ViolationCheckCrossChain, types.Layer1, "l1_cross_chain_status", ViolationStatusUnchecked},
+				{ViolationCheckCrossChain, types.Layer2, "l2_cross_chain_status", ViolationStatusUnchecked},
+			},
+		},
+		{
+			"invalid", func() orm.GatewayMessageMatch {
+				message := validWithdraw
+				message.L1BlockStatus = int(types.BlockStatusTypeFailed)
+				message.L2CrossChainStatus = int(types.CrossChainStatusTypeFailed)
+				message.FailureReason = "amount mismatch"
+				return message
+			},
+			[]violationKey{
+				{ViolationCheckBlock, types.Layer1, "l1_block_status", ViolationStatusFailed},
+				{ViolationCheckCrossChain, types.Layer2, "l2_cross_chain_status", ViolationStatusFailed},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			message := test.message()
+			violations := gatewayViolations(message)
+			assert.Equal(t, test.wantViolations, violationKeys(violations))
+			for _, violation := range violations {
+				assert.Equal(t, message.TableName(), violation.Table)
+				assert.Equal(t, message.MessageHash, violation.MessageHash)
+				assert.Equal(t, message.L2BlockNumber, violation.L2BlockNumber)
+				if violation.Status == ViolationStatusFailed {
+					assert.Equal(t, message.FailureReason, violation.Reason)
+				} else {
+					assert.Empty(t, violation.Reason)
+				}
+			}
+		})
+	}
+}
+
+func TestMessengerViolations(t *testing.T) {
+	validWithdraw := orm.MessengerMessageMatch{
+		MessageHash:        "0x1",
+		L1EventType:        int(types.L1RelayedMessage),
+		L1BlockNumber:      100,
+		L1TxHash:           "0xa1",
+		L2EventType:        int(types.L2SentMessage),
+		L2BlockNumber:      10,
+		L2TxHash:           "0xb1",
+		L1BlockStatus:      int(types.BlockStatusTypeValid),
+		L2BlockStatus:      int(types.BlockStatusTypeValid),
+		L1CrossChainStatus: int(types.CrossChainStatusTypeValid),
+		L2CrossChainStatus: int(types.CrossChainStatusTypeValid),
+		L1ETHBalanceStatus: int(types.ETHBalanceStatusTypeValid),
+		L2ETHBalanceStatus: int(types.ETHBalanceStatusTypeValid),
+		WithdrawRootStatus: int(types.WithdrawRootStatusTypeValid),
+	}
+
+	tests := []struct {
+		name           string
+		message        func() orm.MessengerMessageMatch
+		wantViolations []violationKey
+	}{
+		{"valid", func() orm.MessengerMessageMatch { return validWithdraw }, nil},
+		{
+			"pendingSentMessage", func() orm.MessengerMessageMatch {
+				return orm.MessengerMessageMatch{MessageHash: "0x1", L2EventType: int(types.L2SentMessage), L2BlockNumber: 10}
+			},
+			[]violationKey{
+				{ViolationCheckBlock, types.Layer2, "l2_block_status", ViolationStatusUnchecked},
+				{ViolationCheckETHBalance, types.Layer2, "l2_eth_balance_status", ViolationStatusUnchecked},
+				{ViolationCheckWithdrawRoot, types.Layer2, "withdraw_root_status", ViolationStatusUnchecked},
+			},
+		},
+		{
+			// the withdraw root is only checked against the l2 sent messages.
+			"relayedDeposit", func() orm.MessengerMessageMatch {
+				message := validWithdraw
+				message.L1EventType, message.L2EventType = int(types.L1SentMessage), int(types.L2RelayedMessage)
+				message.WithdrawRootStatus = int(types.WithdrawRootStatusTypeUnchecked)
+				return message
+			},
+			nil,
+		},
+		{
+			"pendingL1ETHBalance", func() orm.MessengerMessageMatch {
+				message := validWithdraw
+				message.L1ETHBalanceStatus = int(types.ETHBalanceStatusTypeUnchecked)
+				return message
+			},
+			[]violationKey{{ViolationCheckETHBalance, types.Layer1, "l1_eth_balance_status", ViolationStatusUnchecked}},
+		},
+		{
+			"invalidWithdrawRoot", func() orm.MessengerMessageMatch {
+				message := validWithdraw
+				message.WithdrawRootStatus = int(types.WithdrawRootStatusTypeFailed)
+				message.FailureReason = "withdraw root mismatch"
+				return message
+			},
+			[]violationKey{{ViolationCheckWithdrawRoot, types.Layer2, "withdraw_root_status", ViolationStatusFailed}},
+		},
+		{
+			"invalid", func() orm.MessengerMessageMatch {
+				message := validWithdraw
+				message.L2BlockStatus = int(types.BlockStatusTypeFailed)
+				message.L1CrossChainStatus = int(types.CrossChainStatusTypeFailed)
+				message.L2CrossChainStatus = int(types.CrossChainStatusTypeUnchecked)
+				message.FailureReason = "message mismatch"
+				return message
+			},
+			[]violationKey{
+				{ViolationCheckBlock, types.Layer2, "l2_block_status", ViolationStatusFailed},
+				{ViolationCheckCrossChain, types.Layer1, "l1_cross_chain_status", ViolationStatusFailed},
+				{ViolationCheckCrossChain, types.Layer2, "l2_cross_chain_status", ViolationStatusUnchecked},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			message := test.message()
+			violations := messengerViolations(message)
+			assert.Equal(t, test.wantViolations, violationKeys(violations))
+			for _, violation := range violations {
+				assert.Equal(t, message.TableName(), violation.Table)
+				assert.Equal(t, message.MessageHash, violation.MessageHash)
+				if violation.Status == ViolationStatusFailed {
+					assert.Equal(t, message.FailureReason, violation.Reason)
+				} else {
+					assert.Empty(t, violation.Reason)
+				}
+			}
+		})
+	}
+}
+
+func TestViolationsStatus(t *testing.T) {
+	unchecked := &Violation{Check: ViolationCheckBlock, Status: ViolationStatusUnchecked}
+	failed := &Violation{Check: ViolationCheckCrossChain, Status: ViolationStatusFailed}
+
+	tests := []struct {
+		name       string
+		violations []*Violation
+		wantStatus types.BatchStatus
+	}{
+		{"valid", nil, types.BatchStatusTypeValid},
+		{"pending", []*Violation{unchecked, unchecked}, types.BatchStatusTypePending},
+		{"invalid", []*Violation{failed}, types.BatchStatusTypeInvalid},
+		// a failed check makes the blocks invalid, even if other checks are not done yet.
+		{"invalidWithPending", []*Violation{unchecked, failed}, types.BatchStatusTypeInvalid},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.wantStatus, ViolationsStatus(test.violations))
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package messagematch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+
+	"github.com/scroll-tech/chain-monitor/internal/types"
+	"github.com/scroll-tech/chain-monitor/internal/utils/msgproof"
+)
+
+// ErrWithdrawProofNotFound the message is not a l2 sent message which has been ingested.
+var ErrWithdrawProofNotFound = errors.New("l2 sent message not found")
+
+// WithdrawProof the merkle proof of a l2 sent message against the withdraw root at the end of its l2 block.
+type WithdrawProof struct {
+	MessageHash   string        `json:"message_hash"`
+	MessageNonce  uint64        `json:"message_nonce"`
+	L2BlockNumber uint64        `json:"l2_block_number"`
+	L2TxHash      string        `json:"l2_tx_hash"`
+	WithdrawRoot  common.Hash   `json:"withdraw_root"`
+	Proof         hexutil.Bytes `json:"proof"`
+	// whether the withdraw root of the block has been checked against the l2 chain.
+	Verified bool `json:"verified"`
+}
+
+// GetWithdrawProof returns the withdraw proof of the l2 sent message. Only the last message of each withdraw root
+// check has a stored proof, the proofs of the others are reconstructed from the stored message hashes since
+// the nearest stored proof before them.
+func (t *LogicMessageMatch) GetWithdrawProof(ctx context.Context, messageHash string) (*WithdrawProof, error) {
+	messages, err := t.messengerMessageMatchOrm.GetMessageMatchesByMessageHashes(ctx, []string{normalizeHash(messageHash)})
+	if err != nil {
+		return nil, err
+	}
+	if len(messages) == 0 || messages[0].L2EventType != int(types.L2SentMessage) || messages[0].NextMessageNonce == 0 {
+		return nil, ErrWithdrawProofNotFound
+	}
+	message := messages[0]
+	nonce := message.NextMessageNonce - 1
+	msgHash := common.HexToHash(message.MessageHash)
+
+	latestValidMessage, err := t.messengerMessageMatchOrm.GetLatestValidL2SentMessageMatch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	withdrawProof := &WithdrawProof{
+		MessageHash:   message.MessageHash,
+		MessageNonce:  nonce,
+		L2BlockNumber: message.L2BlockNumber,
+		L2TxHash:      message.L2TxHash,
+		Verified:      latestValidMessage != nil && latestValidMessage.NextMessageNonce >= message.NextMessageNonce,
+	}
+
+	if message.WithdrawRootStatus == int(types.WithdrawRootStatusTypeValid) {
+		withdrawProof.Proof = message.MessageProof
+		withdrawProof.WithdrawRoot = msgproof.ComputeRootFromProof(msgproof.DecodeBytesToMerkleProof(message.MessageProof), nonce, msgHash)
+		return withdrawProof, nil
+	}
+
+	proof, root, err := t.reconstructWithdrawProof(ctx, message.NextMessageNonce, message.L2BlockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("reconstruct withdraw proof failed, message hash: %s, err: %w", message.MessageHash, err)
+	}
+	if msgproof.ComputeRootFromProof(msgproof.DecodeBytesToMerkleProof(proof), nonce, msgHash) != root {
+		return nil, fmt.Errorf("reconstructed withdraw proof mismatches the withdraw root, message hash: %s, root: %s", message.MessageHash, root.Hex())
+	}
+	withdrawProof.Proof = proof
+	withdrawProof.WithdrawRoot = root
+	return withdrawProof, nil
+}
+
+// reconstructWithdrawProof rebuilds the withdraw trie from the nearest stored proof before the message, and appends
+// the messages up to the end of the l2 block of the message.
+func (t *LogicMessageMatch) reconstructWithdrawProof(ctx context.Context, nextMessageNonce, l2BlockNumber uint64) ([]byte, common.Hash, error) {
+	withdrawTrie := msgproof.NewWithdrawTrie()
+	anchorMessage, err := t.messengerMessageMatchOrm.GetLatestValidL2SentMessageMatchBefore(ctx, nextMessageNonce)
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+	if anchorMessage != nil {
+		withdrawTrie.Initialize(anchorMessage.NextMessageNonce-1, common.HexToHash(anchorMessage.MessageHash), anchorMessage.MessageProof)
+	}
+	startNextMessageNonce := withdrawTrie.NextMessageNonce
+
+	messages, err := t.messengerMessageMatchOrm.GetL2SentMessagesAfterNonce(ctx, startNextMessageNonce, l2BlockNumber)
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+
+	var hashes []common.Hash
+	for i, message := range messages {
+		// the message nonces must be contiguous, otherwise some messages are not ingested.
+		expectedNextMessageNonce := startNextMessageNonce + uint64(i) + 1
+		if message.NextMessageNonce != expectedNextMessageNonce {
+			return nil, common.Hash{}, fmt.Errorf("l2 sent message of nonce %d not found", expectedNextMessageNonce-1)
+		}
+		hashes = append(hashes, common.HexToHash(message.MessageHash))
+	}
+	if uint64(len(hashes)) < nextMessageNonce-startNextMessageNonce {
+		return nil, common.Hash{}, fmt.Errorf("l2 sent message of nonce %d not found", nextMessageNonce-1)
+	}
+
+	proofs := withdrawTrie.AppendMessages(hashes)
+	return proofs[nextMessageNonce-1-startNextMessageNonce], withdrawTrie.MessageRoot(), nil
+}
+
+// GetWithdrawRootAtBlock computes the withdraw root at the end of the l2 block from the nearest stored proof at or
+// before the block, the l2 sent messages up to the block must have been ingested.
+func (t *LogicMessageMatch) GetWithdrawRootAtBlock(ctx context.Context, l2BlockNumber uint64) (common.Hash, error) {
+	withdrawTrie := msgproof.NewWithdrawTrie()
+	anchorMessage, err := t.messengerMessageMatchOrm.GetLatestValidL2SentMessageMatchAtBlock(ctx, l2BlockNumber)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if anchorMessage != nil {
+		withdrawTrie.Initialize(anchorMessage.NextMessageNonce-1, common.HexToHash(anchorMessage.MessageHash), anchorMessage.MessageProof)
+	}
+	startNextMessageNonce := withdrawTrie.NextMessageNonce
+
+	messages, err := t.messengerMessageMatchOrm.GetL2SentMessagesAfterNonce(ctx, startNextMessageNonce, l2BlockNumber)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	var hashes []common.Hash
+	for i, message := range messages {
+		expectedNextMessageNonce := startNextMessageNonce + uint64(i) + 1
+		if message.NextMessageNonce != expectedNextMessageNonce {
+			return common.Hash{}, fmt.Errorf("l2 sent message of nonce %d not found", expectedNextMessageNonce-1)
+		}
+		hashes = append(hashes, common.HexToHash(message.MessageHash))
+	}
+	withdrawTrie.AppendMessages(hashes)
+	return withdrawTrie.MessageRoot(), nil
+}
@@ -0,0 +1,307 @@
+package messagequeue
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+
+	"github.com/scroll-tech/chain-monitor/internal/config"
+	"github.com/scroll-tech/chain-monitor/internal/logic/alert"
+	"github.com/scroll-tech/chain-monitor/internal/logic/events"
+	"github.com/scroll-tech/chain-monitor/internal/orm"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+)
+
+var (
+	messageQueueEnforcedTransactionTotal = promauto.With(prometheus.DefaultRegisterer).NewCounter(prometheus.CounterOpts{
+		Name: "l1_message_queue_enforced_transaction_total",
+		Help: "The total number of l1 messages enqueued by the senders other than the l1 scroll messenger.",
+	})
+	messageQueueIncludedIndex = promauto.With(prometheus.DefaultRegisterer).NewGauge(prometheus.GaugeOpts{
+		Name: "l1_message_queue_included_index",
+		Help: "The latest queue index of the l1 messages popped by the committed batches, which are included on l2 or skipped.",
+	})
+	messageQueueSkippedTotal = promauto.With(prometheus.DefaultRegisterer).NewCounter(prometheus.CounterOpts{
+		Name: "l1_message_queue_skipped_total",
+		Help: "The total number of the l1 messages popped by the committed batches without being included on l2.",
+	})
+	messageQueueIndexViolationTotal = promauto.With(prometheus.DefaultRegisterer).NewCounterVec(prometheus.CounterOpts{
+		Name: "l1_message_queue_index_violation_total",
+		Help: "The total number of the l1 messages popped by the committed batches out of the queue order, by skipped or out of order.",
+	}, []string{"type"})
+	messengerSentMessageNonce = promauto.With(prometheus.DefaultRegisterer).NewGauge(prometheus.GaugeOpts{
+		Name: "l1_messenger_sent_message_nonce",
+		Help: "The latest message nonce of the l1 sent messages.",
+	})
+	messengerNonceViolationTotal = promauto.With(prometheus.DefaultRegisterer).NewCounterVec(prometheus.CounterOpts{
+		Name: "l1_messenger_nonce_violation_total",
+		Help: "The total number of the l1 sent messages whose nonces don't follow the last one, by gap or duplicated.",
+	}, []string{"type"})
+)
+
+// LogicMessageQueue ingests the l1 message queue events and checks the queue indexes of the l1 messages popped by the
+// committed batches, and the nonces of the l1 sent messages, which are the queue indexes of the messages.
+type LogicMessageQueue struct {
+	messageQueueOrm          *orm.L1MessageQueue
+	messengerMessageMatchOrm *orm.MessengerMessageMatch
+	l1MessengerAddress       common.Address
+}
+
+// NewLogicMessageQueue creates a new LogicMessageQueue instance.
+func NewLogicMessageQueue(cfg *config.Config, db *gorm.DB) *LogicMessageQueue {
+	return &LogicMessageQueue{
+		messageQueueOrm:          orm.NewL1MessageQueue(db),
+		messengerMessageMatchOrm: orm.NewMessengerMessageMatch(db),
+		l1MessengerAddress:       cfg.L1Config.L1Contracts.ScrollMessenger,
+	}
+}
+
+// InsertOrUpdateL1Events applies the l1 message queue events in the order they are emitted.
+func (l *LogicMessageQueue) InsertOrUpdateL1Events(ctx context.Context, queueEvents []*events.MessageQueueEventUnmarshaler, dbTX ...*gorm.DB) error {
+	sort.Slice(queueEvents, func(i, j int) bool {
+		if queueEvents[i].Number != queueEvents[j].Number {
+			return queueEvents[i].Number < queueEvents[j].Number
+		}
+		return queueEvents[i].Index < queueEvents[j].Index
+	})
+
+	for _, event := range queueEvents {
+		switch event.Type {
+		case types.L1QueueTransaction:
+			// The enforced transactions are sent to the message queue directly, bypassing the l1 scroll messenger.
+			if event.Sender != l.l1MessengerAddress {
+				messageQueueEnforcedTransactionTotal.Inc()
+				log.Info("l1 message enqueued by enforced transaction", "queue index", event.QueueIndex, "sender", event.Sender.Hex(), "tx hash", event.TxHash.Hex())
+			}
+
+			message := orm.L1MessageQueue{
+				QueueIndex:         event.QueueIndex,
+				Sender:             event.Sender.Hex(),
+				Target:             event.Target.Hex(),
+				Value:              event.Value.String(),
+				GasLimit:           event.GasLimit.String(),
+				EnqueueBlockNumber: event.Number,
+				EnqueueTxHash:      event.TxHash.Hex(),
+			}
+			if err := l.messageQueueOrm.InsertOrUpdateEnqueued(ctx, message, dbTX...); err != nil {
+				return fmt.Errorf("insert or update enqueued message failed, queue index: %v, err: %w", event.QueueIndex, err)
+			}
+		case types.L1DequeueTransaction:
+			var messages []orm.L1MessageQueue
+			for i := uint64(0); i < event.Count; i++ {
+				messages = append(messages, orm.L1MessageQueue{
+					QueueIndex:         event.StartIndex + i,
+					DequeueBlockNumber: event.Number,
+					DequeueTxHash:      event.TxHash.Hex(),
+					Skipped:            event.SkippedBitmap.Bit(int(i)) == 1,
+				})
+			}
+			if err := l.messageQueueOrm.InsertOrUpdateDequeued(ctx, messages, dbTX...); err != nil {
+				return fmt.Errorf("insert or update dequeued messages failed, start index: %v, count: %v, err: %w", event.StartIndex, event.Count, err)
+			}
+		case types.L1DropTransaction:
+			if err := l.messageQueueOrm.UpdateDropped(ctx, event.QueueIndex, event.Number, dbTX...); err != nil {
+				return fmt.Errorf("update dropped message failed, queue index: %v, err: %w", event.QueueIndex, err)
+			}
+		}
+	}
+	return nil
+}
+
+// CheckDequeuedMessages checks the l1 messages popped by the batches committed in the block ranges follow the queue indexes
+// contiguously from the last popped one. The messages of the l2 blocks of a batch are popped once it's committed, either
+// included on l2 or skipped in the bitmap. The skipped ones are legit and only counted, the missed queue indexes and the
+// ones popped again are returned as the alerts, which are notified once the events are stored. The error is only
+// returned if the last popped message can't be fetched.
+func (l *LogicMessageQueue) CheckDequeuedMessages(ctx context.Context, queueEvents []*events.MessageQueueEventUnmarshaler) ([]alert.Alert, error) {
+	var dequeueEvents []*events.MessageQueueEventUnmarshaler
+	for _, event := range queueEvents {
+		if event.Type == types.L1DequeueTransaction && event.Count > 0 {
+			dequeueEvents = append(dequeueEvents, event)
+		}
+	}
+	if len(dequeueEvents) == 0 {
+		return nil, nil
+	}
+
+	sort.Slice(dequeueEvents, func(i, j int) bool {
+		if dequeueEvents[i].Number != dequeueEvents[j].Number {
+			return dequeueEvents[i].Number < dequeueEvents[j].Number
+		}
+		return dequeueEvents[i].Index < dequeueEvents[j].Index
+	})
+
+	lastMessage, err := l.messageQueueOrm.GetLatestDequeuedMessage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// No l1 message is dequeued before, the first popped one is the start of the queue we know.
+	expectedQueueIndex := dequeueEvents[0].StartIndex
+	if lastMessage != nil {
+		expectedQueueIndex = lastMessage.QueueIndex + 1
+	}
+
+	var alerts []alert.Alert
+	for _, event := range dequeueEvents {
+		info := alert.MessageQueueInfo{
+			L1BlockNumber:      event.Number,
+			L1TxHash:           event.TxHash.Hex(),
+			StartQueueIndex:    event.StartIndex,
+			Count:              event.Count,
+			ExpectedQueueIndex: expectedQueueIndex,
+		}
+
+		switch {
+		case event.StartIndex > expectedQueueIndex:
+			messageQueueIndexViolationTotal.WithLabelValues("skipped").Inc()
+			alerts = append(alerts, alert.MessageQueueSkippedAlert(info))
+			log.Error("l1 messages missed by the committed batches", "l1 block number", event.Number, "l1 tx hash", event.TxHash.Hex(),
+				"from queue index", expectedQueueIndex, "to queue index", event.StartIndex-1)
+		case event.StartIndex < expectedQueueIndex:
+			messageQueueIndexViolationTotal.WithLabelValues("out_of_order").Inc()
+			alerts = append(alerts, alert.MessageQueueOutOfOrderAlert(info))
+			log.Error("l1 messages popped by the committed batches again", "l1 block number", event.Number, "l1 tx hash", event.TxHash.Hex(),
+				"start queue index", event.StartIndex, "expected queue index", expectedQueueIndex)
+		}
+
+		for i := uint64(0); i < event.Count; i++ {
+			if event.SkippedBitmap != nil && event.SkippedBitmap.Bit(int(i)) == 1 {
+				messageQueueSkippedTotal.Inc()
+				log.Info("l1 message skipped by the committed batch", "queue index", event.StartIndex+i, "l1 tx hash", event.TxHash.Hex())
+			}
+		}
+
+		if end := event.StartIndex + event.Count; end > expectedQueueIndex {
+			expectedQueueIndex = end
+		}
+	}
+	messageQueueIncludedIndex.Set(float64(expectedQueueIndex - 1))
+	return alerts, nil
+}
+
+// CheckL1SentMessageNonces checks the nonces of the l1 sent messages increase by one from the last ingested one, in the
+// order the messages are sent. The nonces skipped by the enforced transactions, which are enqueued bypassing the l1
+// scroll messenger, are not gaps. The missed nonces and the ones which are not greater than the last one are returned as
+// the alerts, which are notified once the messages are stored. The error is only returned if the ingested messages
+// can't be fetched.
+func (l *LogicMessageQueue) CheckL1SentMessageNonces(ctx context.Context, messengerMessageMatches []orm.MessengerMessageMatch, queueEvents []*events.MessageQueueEventUnmarshaler) ([]alert.Alert, error) {
+	var sentMessages []orm.MessengerMessageMatch
+	var messageNonces []uint64
+	for _, message := range messengerMessageMatches {
+		if message.L1EventType != int(types.L1SentMessage) {
+			continue
+		}
+		messageNonce, err := strconv.ParseUint(message.MessageNonce, 10, 64)
+		if err != nil {
+			log.Error("invalid message nonce of l1 sent message", "message hash", message.MessageHash, "nonce", message.MessageNonce, "err", err)
+			continue
+		}
+		sentMessages = append(sentMessages, message)
+		messageNonces = append(messageNonces, messageNonce)
+	}
+	if len(sentMessages) == 0 {
+		return nil, nil
+	}
+
+	// The messages of a block range are in the order they are sent, the block ranges are fetched concurrently.
+	order := make([]int, len(sentMessages))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return sentMessages[order[i]].L1BlockNumber < sentMessages[order[j]].L1BlockNumber
+	})
+
+	lastMessage, err := l.messengerMessageMatchOrm.GetLatestL1SentMessageMatch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// No l1 sent message nonce is ingested before, the first sent one is the start of the nonces we know.
+	expectedMessageNonce := messageNonces[order[0]]
+	if lastMessage != nil {
+		lastMessageNonce, parseErr := strconv.ParseUint(lastMessage.MessageNonce, 10, 64)
+		if parseErr == nil {
+			expectedMessageNonce = lastMessageNonce + 1
+		} else {
+			log.Error("invalid message nonce of last l1 sent message", "message hash", lastMessage.MessageHash, "nonce", lastMessage.MessageNonce, "err", parseErr)
+		}
+	}
+
+	enforcedQueueIndexes := make(map[uint64]bool)
+	for _, event := range queueEvents {
+		if event.Type == types.L1QueueTransaction && event.Sender != l.l1MessengerAddress {
+			enforcedQueueIndexes[event.QueueIndex] = true
+		}
+	}
+
+	var alerts []alert.Alert
+	for _, i := range order {
+		message, messageNonce := sentMessages[i], messageNonces[i]
+		info := alert.MessengerNonceInfo{
+			L1BlockNumber:        message.L1BlockNumber,
+			L1TxHash:             message.L1TxHash,
+			MessageHash:          message.MessageHash,
+			MessageNonce:         messageNonce,
+			ExpectedMessageNonce: expectedMessageNonce,
+		}
+
+		if messageNonce < expectedMessageNonce {
+			messengerNonceViolationTotal.WithLabelValues("duplicated").Inc()
+			alerts = append(alerts, alert.MessengerNonceDuplicatedAlert(info))
+			log.Error("l1 sent message nonce duplicated", "l1 block number", message.L1BlockNumber, "l1 tx hash", message.L1TxHash,
+				"message nonce", messageNonce, "expected message nonce", expectedMessageNonce)
+			continue
+		}
+
+		if messageNonce > expectedMessageNonce {
+			enforced, enforcedErr := l.isEnforcedQueueIndexes(ctx, expectedMessageNonce, messageNonce, enforcedQueueIndexes)
+			if enforcedErr != nil {
+				return nil, enforcedErr
+			}
+			if !enforced {
+				messengerNonceViolationTotal.WithLabelValues("gap").Inc()
+				alerts = append(alerts, alert.MessengerNonceGapAlert(info))
+				log.Error("l1 sent message nonces missed", "l1 block number", message.L1BlockNumber, "l1 tx hash", message.L1TxHash,
+					"from message nonce", expectedMessageNonce, "to message nonce", messageNonce-1)
+			}
+		}
+		expectedMessageNonce = messageNonce + 1
+	}
+	messengerSentMessageNonce.Set(float64(expectedMessageNonce - 1))
+	return alerts, nil
+}
+
+// isEnforcedQueueIndexes returns whether the queue indexes from start to end (exclusive) are all enqueued by the enforced
+// transactions, in the queue events of the block ranges or the ones ingested before.
+func (l *LogicMessageQueue) isEnforcedQueueIndexes(ctx context.Context, start, end uint64, enforcedQueueIndexes map[uint64]bool) (bool, error) {
+	var queueIndexes []uint64
+	for queueIndex := start; queueIndex < end; queueIndex++ {
+		if !enforcedQueueIndexes[queueIndex] {
+			queueIndexes = append(queueIndexes, queueIndex)
+		}
+	}
+	if len(queueIndexes) == 0 {
+		return true, nil
+	}
+
+	messages, err := l.messageQueueOrm.GetMessagesByQueueIndexes(ctx, queueIndexes)
+	if err != nil {
+		return false, err
+	}
+	var enforced int
+	for _, message := range messages {
+		if message.EnqueueBlockNumber != 0 && common.HexToAddress(message.Sender) != l.l1MessengerAddress {
+			enforced++
+		}
+	}
+	return enforced == len(queueIndexes), nil
+}
@@ -0,0 +1,173 @@
+package messagequeue
+
+import (
+	"context"
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scroll-tech/chain-monitor/internal/logic/events"
+	"github.com/scroll-tech/chain-monitor/internal/orm"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+	"github.com/scroll-tech/chain-monitor/internal/utils/testcontainer"
+)
+
+func dequeueEvent(number uint64, startIndex, count uint64, skippedBitmap int64) *events.MessageQueueEventUnmarshaler {
+	return &events.MessageQueueEventUnmarshaler{
+		Layer:         types.Layer1,
+		Type:          types.L1DequeueTransaction,
+		Number:        number,
+		TxHash:        common.BigToHash(new(big.Int).SetUint64(number)),
+		StartIndex:    startIndex,
+		Count:         count,
+		SkippedBitmap: big.NewInt(skippedBitmap),
+	}
+}
+
+func TestLogicMessageQueue_CheckDequeuedMessages(t *testing.T) {
+	ctx := context.Background()
+	db := testcontainer.SetupDB(ctx, t)
+	l := &LogicMessageQueue{
+		messageQueueOrm:          orm.NewL1MessageQueue(db),
+		messengerMessageMatchOrm: orm.NewMessengerMessageMatch(db),
+		l1MessengerAddress:       common.HexToAddress("0x6774Bcbd5ceCeF1336b5300fb5186a12DDD8b367"),
+	}
+
+	tests := []struct {
+		name        string
+		queueEvents []*events.MessageQueueEventUnmarshaler
+		wantTitles  []string
+	}{
+		{
+			// the events of the block ranges fetched concurrently are out of order, the skipped messages are legit.
+			name:        "contiguous",
+			queueEvents: []*events.MessageQueueEventUnmarshaler{dequeueEvent(102, 5, 3, 0b010), dequeueEvent(101, 0, 5, 0b11000)},
+		},
+		{
+			name:        "missed",
+			queueEvents: []*events.MessageQueueEventUnmarshaler{dequeueEvent(110, 10, 2, 0)},
+			wantTitles:  []string{"L1 messages missed by the committed batches"},
+		},
+		{
+			name:        "poppedAgain",
+			queueEvents: []*events.MessageQueueEventUnmarshaler{dequeueEvent(120, 11, 2, 0)},
+			wantTitles:  []string{"L1 messages popped by the committed batches out of order"},
+		},
+		{
+			name: "noDequeueEvents",
+			queueEvents: []*events.MessageQueueEventUnmarshaler{
+				{Layer: types.Layer1, Type: types.L1QueueTransaction, Number: 130, QueueIndex: 100, Value: big.NewInt(0), GasLimit: big.NewInt(1000000)},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			alerts, err := l.CheckDequeuedMessages(ctx, test.queueEvents)
+			assert.NoError(t, err)
+			var titles []string
+			for _, a := range alerts {
+				titles = append(titles, a.Title)
+			}
+			assert.Equal(t, test.wantTitles, titles)
+
+			// the checked events are stored as the watcher does.
+			assert.NoError(t, l.InsertOrUpdateL1Events(ctx, test.queueEvents))
+		})
+	}
+}
+
+func sentMessage(number uint64, messageHash string, nonce uint64) orm.MessengerMessageMatch {
+	return orm.MessengerMessageMatch{
+		MessageHash:   messageHash,
+		L1EventType:   int(types.L1SentMessage),
+		L1BlockNumber: number,
+		L1TxHash:      common.BigToHash(new(big.Int).SetUint64(number)).Hex(),
+		ETHAmount:     "0",
+		MessageNonce:  strconv.FormatUint(nonce, 10),
+	}
+}
+
+func enqueueEvent(number uint64, queueIndex uint64, sender common.Address) *events.MessageQueueEventUnmarshaler {
+	return &events.MessageQueueEventUnmarshaler{
+		Layer:      types.Layer1,
+		Type:       types.L1QueueTransaction,
+		Number:     number,
+		TxHash:     common.BigToHash(new(big.Int).SetUint64(number)),
+		QueueIndex: queueIndex,
+		Sender:     sender,
+		Value:      big.NewInt(0),
+		GasLimit:   big.NewInt(1000000),
+	}
+}
+
+func TestLogicMessageQueue_CheckL1SentMessageNonces(t *testing.T) {
+	ctx := context.Background()
+	db := testcontainer.SetupDB(ctx, t)
+	l := &LogicMessageQueue{
+		messageQueueOrm:          orm.NewL1MessageQueue(db),
+		messengerMessageMatchOrm: orm.NewMessengerMessageMatch(db),
+		l1MessengerAddress:       common.HexToAddress("0x6774Bcbd5ceCeF1336b5300fb5186a12DDD8b367"),
+	}
+	enforcedTxSender := common.HexToAddress("0x1")
+
+	// the enforced transaction of queue index 5 is ingested before.
+	assert.NoError(t, l.InsertOrUpdateL1Events(ctx, []*events.MessageQueueEventUnmarshaler{enqueueEvent(5, 5, enforcedTxSender)}))
+
+	tests := []struct {
+		name        string
+		messages    []orm.MessengerMessageMatch
+		queueEvents []*events.MessageQueueEventUnmarshaler
+		wantTitles  []string
+	}{
+		{
+			// the messages of the block ranges fetched concurrently are out of order.
+			name:     "contiguous",
+			messages: []orm.MessengerMessageMatch{sentMessage(11, "0x2", 2), sentMessage(10, "0x0", 0), sentMessage(10, "0x1", 1)},
+		},
+		{
+			// nonce 3 is enqueued by the enforced transaction in the block range, nonce 5 by the one ingested before.
+			name:        "enforcedTxExempt",
+			messages:    []orm.MessengerMessageMatch{sentMessage(20, "0x4", 4), sentMessage(21, "0x6", 6)},
+			queueEvents: []*events.MessageQueueEventUnmarshaler{enqueueEvent(19, 3, enforcedTxSender)},
+		},
+		{
+			name:        "gap",
+			messages:    []orm.MessengerMessageMatch{sentMessage(30, "0x9", 9)},
+			queueEvents: []*events.MessageQueueEventUnmarshaler{enqueueEvent(29, 7, l.l1MessengerAddress)},
+			wantTitles:  []string{"L1 sent message nonces missed"},
+		},
+		{
+			name:       "duplicated",
+			messages:   []orm.MessengerMessageMatch{sentMessage(31, "0x5", 5)},
+			wantTitles: []string{"L1 sent message nonce duplicated"},
+		},
+		{
+			// the last message is the one with the largest nonce, rather than the duplicated one in the latest block.
+			name:     "afterDuplicated",
+			messages: []orm.MessengerMessageMatch{sentMessage(40, "0xa", 10)},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			alerts, err := l.CheckL1SentMessageNonces(ctx, test.messages, test.queueEvents)
+			assert.NoError(t, err)
+			var titles []string
+			for _, a := range alerts {
+				titles = append(titles, a.Title)
+			}
+			assert.Equal(t, test.wantTitles, titles)
+
+			// the checked messages and events are stored as the watcher does.
+			for _, message := range test.messages {
+				_, err = l.messengerMessageMatchOrm.InsertOrUpdateEventInfo(ctx, types.Layer1, message)
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, l.InsertOrUpdateL1Events(ctx, test.queueEvents))
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package reorg
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/scroll-tech/go-ethereum"
+	"github.com/scroll-tech/go-ethereum/common"
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/scroll-tech/go-ethereum/rpc"
+	"gorm.io/gorm"
+
+	"github.com/scroll-tech/chain-monitor/internal/logic/alert"
+	"github.com/scroll-tech/chain-monitor/internal/orm"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+)
+
+const (
+	// the number of processed block hashes fetched each time when walking back to find the fork point.
+	reorgCheckPageSize = 32
+	// the blocks deeper than the finality depth are never reorged, so their processed block hashes are pruned.
+	finalityDepth uint64 = 1024
+)
+
+// LogicReorg detects the chain reorganization of the processed blocks, and rollbacks the ingested events after the fork point.
+type LogicReorg struct {
+	db                       *gorm.DB
+	processedBlockHashOrm    *orm.ProcessedBlockHash
+	messengerMessageMatchOrm *orm.MessengerMessageMatch
+	gatewayMessageMatchOrm   *orm.GatewayMessageMatch
+	failedRelayedMessageOrm  *orm.FailedRelayedMessage
+	l1ETHRefundOrm           *orm.L1ETHRefund
+	syncCursorOrm            *orm.SyncCursor
+	alertOrm                 *orm.Alert
+	alertOutboxOrm           *orm.AlertOutbox
+	batchOrm                 *orm.Batch
+	messageQueueOrm          *orm.L1MessageQueue
+
+	reorgDetectedTotal *prometheus.CounterVec
+}
+
+// NewLogicReorg creates a new LogicReorg instance.
+func NewLogicReorg(db *gorm.DB) *LogicReorg {
+	return &LogicReorg{
+		db:                       db,
+		processedBlockHashOrm:    orm.NewProcessedBlockHash(db),
+		messengerMessageMatchOrm: orm.NewMessengerMessageMatch(db),
+		gatewayMessageMatchOrm:   orm.NewGatewayMessageMatch(db),
+		failedRelayedMessageOrm:  orm.NewFailedRelayedMessage(db),
+		l1ETHRefundOrm:           orm.NewL1ETHRefund(db),
+		syncCursorOrm:            orm.NewSyncCursor(db),
+		alertOrm:                 orm.NewAlert(db),
+		alertOutboxOrm:           orm.NewAlertOutbox(db),
+		batchOrm:                 orm.NewBatch(db),
+		messageQueueOrm:          orm.NewL1MessageQueue(db),
+
+		reorgDetectedTotal: promauto.With(prometheus.DefaultRegisterer).NewCounterVec(prometheus.CounterOpts{
+			Name: "reorg_detected_total",
+			Help: "The total number of chain reorg detected.",
+		}, []string{"layer"}),
+	}
+}
+
+// RecordProcessedBlockHash records the hash of the last block of the processed block range, and prunes the processed
+// block hashes below the finality depth.
+func (r *LogicReorg) RecordProcessedBlockHash(ctx context.Context, layer types.LayerType, blockNumber uint64, blockHash common.Hash, dbTX ...*gorm.DB) error {
+	processedBlockHash := orm.ProcessedBlockHash{
+		Layer:       int(layer),
+		BlockNumber: blockNumber,
+		BlockHash:   blockHash.Hex(),
+	}
+	if err := r.processedBlockHashOrm.InsertOrUpdateProcessedBlockHash(ctx, processedBlockHash, dbTX...); err != nil {
+		return err
+	}
+
+	if blockNumber <= finalityDepth {
+		return nil
+	}
+	return r.processedBlockHashOrm.PruneProcessedBlockHashes(ctx, layer, blockNumber-finalityDepth, dbTX...)
+}
+
+// DetectAndRollback checks the processed block hashes against the canonical chain from the latest one.
+// If a reorg is detected, it rollbacks the ingested events after the fork point and returns the block number to re-ingest from.
+func (r *LogicReorg) DetectAndRollback(ctx context.Context, client *rpc.Client, layer types.LayerType) (uint64, bool, error) {
+	latestProcessedBlockHashes, err := r.processedBlockHashOrm.GetLatestProcessedBlockHashes(ctx, layer, 0, 1)
+	if err != nil {
+		return 0, false, fmt.Errorf("get latest processed block hashes failed, err: %w", err)
+	}
+	if len(latestProcessedBlockHashes) == 0 {
+		return 0, false, nil
+	}
+
+	latest := latestProcessedBlockHashes[0]
+	canonicalHashes, err := canonicalBlockHashes(ctx, client, latestProcessedBlockHashes)
+	if err != nil {
+		return 0, false, err
+	}
+	if canonicalHashes[0].Hex() == latest.BlockHash {
+		return 0, false, nil
+	}
+
+	r.reorgDetectedTotal.WithLabelValues(layer.String()).Inc()
+	reorgInfo := alert.ReorgInfo{
+		Layer:              layer,
+		ReorgBlockNumber:   latest.BlockNumber,
+		ProcessedBlockHash: common.HexToHash(latest.BlockHash),
+		CanonicalBlockHash: canonicalHashes[0],
+	}
+
+	// Walk back page by page to find the fork point, the canonical hashes of a page are fetched by one batch request.
+	oldestBlockNumber := latest.BlockNumber
+	for offset := 1; ; offset += reorgCheckPageSize {
+		processedBlockHashes, err := r.processedBlockHashOrm.GetLatestProcessedBlockHashes(ctx, layer, offset, reorgCheckPageSize)
+		if err != nil {
+			return 0, false, fmt.Errorf("get latest processed block hashes failed, err: %w", err)
+		}
+
+		if len(processedBlockHashes) == 0 {
+			break
+		}
+
+		canonicalHashes, err := canonicalBlockHashes(ctx, client, processedBlockHashes)
+		if err != nil {
+			return 0, false, err
+		}
+
+		for idx, processedBlockHash := range processedBlockHashes {
+			if canonicalHashes[idx].Hex() == processedBlockHash.BlockHash {
+				rollbackBlockNumber := processedBlockHash.BlockNumber + 1
+				if err := r.Rollback(ctx, layer, rollbackBlockNumber); err != nil {
+					return 0, false, err
+				}
+
+				reorgInfo.RollbackBlockNumber = rollbackBlockNumber
+				alert.Notify(alert.ReorgAlert(reorgInfo))
+				log.Warn("chain reorg detected, rollback the processed blocks", "layer", layer, "reorg block number", reorgInfo.ReorgBlockNumber, "rollback block number", rollbackBlockNumber)
+				return rollbackBlockNumber, true, nil
+			}
+			oldestBlockNumber = processedBlockHash.BlockNumber
+		}
+	}
+
+	// None of the processed block hashes is in the canonical chain, rollback all the processed blocks as far as we know.
+	if err := r.Rollback(ctx, layer, oldestBlockNumber); err != nil {
+		return 0, false, err
+	}
+
+	reorgInfo.RollbackBlockNumber = oldestBlockNumber
+	reorgInfo.Error = "no processed block hash is in the canonical chain, the fork point is unknown"
+	alert.Notify(alert.ReorgAlert(reorgInfo))
+	log.Error("chain reorg detected, but the fork point is unknown", "layer", layer, "rollback block number", oldestBlockNumber)
+	return oldestBlockNumber, true, nil
+}
+
+// canonicalBlockHashes fetches the canonical hashes of the processed blocks by one batch request.
+func canonicalBlockHashes(ctx context.Context, client *rpc.Client, processedBlockHashes []orm.ProcessedBlockHash) ([]common.Hash, error) {
+	headers := make([]*gethTypes.Header, len(processedBlockHashes))
+	batch := make([]rpc.BatchElem, len(processedBlockHashes))
+	for i, processedBlockHash := range processedBlockHashes {
+		batch[i] = rpc.BatchElem{
+			Method: "eth_getBlockByNumber",
+			Args:   []interface{}{hexutil.EncodeUint64(processedBlockHash.BlockNumber), false},
+			Result: &headers[i],
+		}
+	}
+
+	if err := client.BatchCallContext(ctx, batch); err != nil {
+		return nil, fmt.Errorf("get block headers failed, err: %w", err)
+	}
+
+	hashes := make([]common.Hash, len(processedBlockHashes))
+	for i, elem := range batch {
+		if elem.Error != nil {
+			return nil, fmt.Errorf("get block header failed, block number: %v, err: %w", processedBlockHashes[i].BlockNumber, elem.Error)
+		}
+		if headers[i] == nil {
+			return nil, fmt.Errorf("get block header failed, block number: %v, err: %w", processedBlockHashes[i].BlockNumber, ethereum.NotFound)
+		}
+		hashes[i] = headers[i].Hash()
+	}
+	return hashes, nil
+}
+
+// Rollback rollbacks the ingested events, the failed relays, the alerts and the processed block hashes of the layer whose
+// block number >= startBlockNumber, and moves the sync cursor back to the block before startBlockNumber.
+func (r *LogicReorg) Rollback(ctx context.Context, layer types.LayerType, startBlockNumber uint64) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := r.messengerMessageMatchOrm.RollbackEventInfo(ctx, layer, startBlockNumber, tx); err != nil {
+			return err
+		}
+
+		if err := r.gatewayMessageMatchOrm.RollbackEventInfo(ctx, layer, startBlockNumber, tx); err != nil {
+			return err
+		}
+
+		if err := r.failedRelayedMessageOrm.RollbackFailedRelayedMessages(ctx, layer, startBlockNumber, tx); err != nil {
+			return err
+		}
+
+		if err := r.l1ETHRefundOrm.Rollback(ctx, layer, startBlockNumber, tx); err != nil {
+			return err
+		}
+
+		if err := r.alertOrm.DeleteAlerts(ctx, layer, startBlockNumber, tx); err != nil {
+			return err
+		}
+
+		if err := r.alertOutboxOrm.DeletePendingAlertOutboxes(ctx, layer, startBlockNumber, tx); err != nil {
+			return err
+		}
+
+		if err := r.messageQueueOrm.Rollback(ctx, layer, startBlockNumber, tx); err != nil {
+			return err
+		}
+
+		if layer == types.Layer1 {
+			if err := r.batchOrm.RollbackBatches(ctx, startBlockNumber, tx); err != nil {
+				return err
+			}
+		}
+
+		if err := r.processedBlockHashOrm.DeleteProcessedBlockHashes(ctx, layer, startBlockNumber, tx); err != nil {
+			return err
+		}
+
+		if startBlockNumber == 0 {
+			return nil
+		}
+		return r.syncCursorOrm.InsertOrUpdateSyncCursor(ctx, layer, types.CheckerTypeContract, startBlockNumber-1, "", tx)
+	})
+	if err != nil {
+		return fmt.Errorf("rollback the processed blocks failed, layer: %v, start block number: %v, err: %w", layer, startBlockNumber, err)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package reorg
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/scroll-tech/go-ethereum/common/hexutil"
+	gethTypes "github.com/scroll-tech/go-ethereum/core/types"
+	"github.com/scroll-tech/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scroll-tech/chain-monitor/internal/logic/alert"
+	"github.com/scroll-tech/chain-monitor/internal/orm"
+	"github.com/scroll-tech/chain-monitor/internal/types"
+	"github.com/scroll-tech/chain-monitor/internal/utils/testcontainer"
+)
+
+// chainService serves the block headers of a chain, the blocks from the fork block number are of the fork.
+type chainService struct {
+	forkBlockNumber uint64
+}
+
+func (s *chainService) GetBlockByNumber(number hexutil.Uint64, _ bool) (*gethTypes.Header, error) {
+	return header(uint64(number), uint64(number) >= s.forkBlockNumber), nil
+}
+
+func header(number uint64, forked bool) *gethTypes.Header {
+	h := &gethTypes.Header{Number: new(big.Int).SetUint64(number), Difficulty: big.NewInt(0), Extra: []byte{}}
+	if forked {
+		h.Extra = []byte("fork")
+	}
+	return h
+}
+
+func newChainClient(t *testing.T, service *chainService) *rpc.Client {
+	server := rpc.NewServer()
+	assert.NoError(t, server.RegisterName("eth", service))
+	t.Cleanup(server.Stop)
+	return rpc.DialInProc(server)
+}
+
+func TestCanonicalBlockHashes(t *testing.T) {
+	client := newChainClient(t, &chainService{forkBlockNumber: 102})
+	processedBlockHashes := []orm.ProcessedBlockHash{{BlockNumber: 103}, {BlockNumber: 101}}
+
+	hashes, err := canonicalBlockHashes(context.Background(), client, processedBlockHashes)
+	assert.NoError(t, err)
+	assert.Equal(t, header(103, true).Hash(), hashes[0])
+	assert.Equal(t, header(101, false).Hash(), hashes[1])
+}
+
+func TestLogicReorg_DetectAndRollback(t *testing.T) {
+	var alerts []alert.Alert
+	previous := alert.Use(alert.NewManagerWithSinks(alert.NewFuncSink("stdout", func(a alert.Alert) { alerts = append(alerts, a) })))
+	defer alert.Use(previous)
+
+	ctx := context.Background()
+	db := testcontainer.SetupDB(ctx, t)
+	r := NewLogicReorg(db)
+	messengerOrm := orm.NewMessengerMessageMatch(db)
+	syncCursorOrm := orm.NewSyncCursor(db)
+
+	// the processed block hashes of the blocks [100, 140] on the chain before the reorg.
+	recordProcessedBlocks := func(startBlockNumber, endBlockNumber uint64) {
+		for blockNumber := startBlockNumber; blockNumber <= endBlockNumber; blockNumber++ {
+			assert.NoError(t, r.RecordProcessedBlockHash(ctx, types.Layer1, blockNumber, header(blockNumber, false).Hash()))
+		}
+		assert.NoError(t, syncCursorOrm.InsertOrUpdateSyncCursor(ctx, types.Layer1, types.CheckerTypeContract, endBlockNumber, header(endBlockNumber, false).Hash().Hex()))
+	}
+
+	tests := []struct {
+		name string
+		test func(t *testing.T)
+	}{
+		{
+			"noProcessedBlocks", func(t *testing.T) {
+				rollbackBlockNumber, reorged, err := r.DetectAndRollback(ctx, newChainClient(t, &chainService{forkBlockNumber: 0}), types.Layer1)
+				assert.NoError(t, err)
+				assert.False(t, reorged)
+				assert.Equal(t, uint64(0), rollbackBlockNumber)
+			},
+		},
+		{
+			"noReorg", func(t *testing.T) {
+				recordProcessedBlocks(100, 140)
+				rollbackBlockNumber, reorged, err := r.DetectAndRollback(ctx, newChainClient(t, &chainService{forkBlockNumber: 141}), types.Layer1)
+				assert.NoError(t, err)
+				assert.False(t, reorged)
+				assert.Equal(t, uint64(0), rollbackBlockNumber)
+				assert.Empty(t, alerts)
+			},
+		},
+		{
+			// the fork point is found on the second page of the walk back, the blocks [108, 139] are on the first page.
+			"forkPointOnSecondPage", func(t *testing.T) {
+				for _, message := range []orm.MessengerMessageMatch{
+					{MessageHash: "0x1", L1EventType: int(types.L1SentMessage), L1BlockNumber: 104},
+					{MessageHash: "0x2", L1EventType: int(types.L1SentMessage), L1BlockNumber: 105},
+				} {
+					_, err := messengerOrm.InsertOrUpdateEventInfo(ctx, types.Layer1, message)
+					assert.NoError(t, err)
+				}
+
+				rollbackBlockNumber, reorged, err := r.DetectAndRollback(ctx, newChainClient(t, &chainService{forkBlockNumber: 105}), types.Layer1)
+				assert.NoError(t, err)
+				assert.True(t, reorged)
+				assert.Equal(t, uint64(105), rollbackBlockNumber)
+
+				processedBlockHashes, err := r.processedBlockHashOrm.GetLatestProcessedBlockHashes(ctx, types.Layer1, 0, 1)
+				assert.NoError(t, err)
+				assert.Equal(t, uint64(104), processedBlockHashes[0].BlockNumber)
+
+				cursor, err := syncCursorOrm.GetSyncCursor(ctx, types.Layer1, types.CheckerTypeContract)
+				assert.NoError(t, err)
+				assert.Equal(t, uint64(104), cursor.BlockNumber)
+
+				// the message of the reorged block is rolled back, the message before the fork point is kept.
+				messages, err := messengerOrm.GetMessageMatchesByMessageHashes(ctx, []string{"0x1", "0x2"})
+				assert.NoError(t, err)
+				assert.Len(t, messages, 1)
+				assert.Equal(t, "0x1", messages[0].MessageHash)
+
+				assert.Len(t, alerts, 1)
+				assert.Equal(t, alert.SeverityWarning, alerts[0].Severity)
+				assert.Equal(t, uint64(140), alerts[0].BlockNumber)
+			},
+		},
+		{
+			"forkPointUnknown", func(t *testing.T) {
+				alerts = nil
+				rollbackBlockNumber, reorged, err := r.DetectAndRollback(ctx, newChainClient(t, &chainService{forkBlockNumber: 0}), types.Layer1)
+				assert.NoError(t, err)
+				assert.True(t, reorged)
+				assert.Equal(t, uint64(100), rollbackBlockNumber)
+
+				processedBlockHashes, err := r.processedBlockHashOrm.GetLatestProcessedBlockHashes(ctx, types.Layer1, 0, 1)
+				assert.NoError(t, err)
+				assert.Empty(t, processedBlockHashes)
+
+				cursor, err := syncCursorOrm.GetSyncCursor(ctx, types.Layer1, types.CheckerTypeContract)
+				assert.NoError(t, err)
+				assert.Equal(t, uint64(99), cursor.BlockNumber)
+				assert.Empty(t, cursor.BlockHash)
+
+				assert.Len(t, alerts, 1)
+				assert.Equal(t, alert.SeverityCritical, alerts[0].Severity)
+			},
+		},
+		{
+			"rollbackFromGenesis", func(t *testing.T) {
+				recordProcessedBlocks(0, 10)
+				assert.NoError(t, r.Rollback(ctx, types.Layer1, 0))
+
+				processedBlockHashes, err := r.processedBlockHashOrm.GetLatestProcessedBlockHashes(ctx, types.Layer1, 0, 1)
+				assert.NoError(t, err)
+				assert.Empty(t, processedBlockHashes)
+
+				// there is no block before the genesis block, the sync cursor isn't moved.
+				cursor, err := syncCursorOrm.GetSyncCursor(ctx, types.Layer1, types.CheckerTypeContract)
+				assert.NoError(t, err)
+				assert.Equal(t, uint64(10), cursor.BlockNumber)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, test.test)
+	}
+}
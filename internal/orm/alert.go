@@ -0,0 +1,181 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/scroll-tech/chain-monitor/internal/types"
+	"github.com/scroll-tech/chain-monitor/internal/utils"
+)
+
+// Alert contains the notified alerts, the identical alerts share the same fingerprint.
+type Alert struct {
+	db *gorm.DB `gorm:"column:-"`
+
+	ID          int64  `json:"id" gorm:"column:id"`
+	Fingerprint string `json:"fingerprint" gorm:"fingerprint"`
+	Category    string `json:"category" gorm:"category"`
+	Severity    string `json:"severity" gorm:"severity"`
+	Title       string `json:"title" gorm:"title"`
+	Layer       int    `json:"layer" gorm:"layer"`
+	MessageHash string `json:"message_hash" gorm:"message_hash"`
+	BlockNumber uint64 `json:"block_number" gorm:"block_number"`
+
+	// status
+	OccurrenceCount uint64 `json:"occurrence_count" gorm:"occurrence_count"`
+	Resolved        bool   `json:"resolved" gorm:"resolved"`
+
+	FirstSeenAt    time.Time      `json:"first_seen_at" gorm:"first_seen_at"`
+	LastSeenAt     time.Time      `json:"last_seen_at" gorm:"last_seen_at"`
+	LastNotifiedAt time.Time      `json:"last_notified_at" gorm:"last_notified_at"`
+	ResolvedAt     *time.Time     `json:"resolved_at" gorm:"resolved_at"`
+	CreatedAt      time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt      time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt      gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at"`
+}
+
+// NewAlert creates a new Alert database instance.
+func NewAlert(db *gorm.DB) *Alert {
+	return &Alert{db: db}
+}
+
+// TableName returns the table name for the Alert model.
+func (*Alert) TableName() string {
+	return "alert"
+}
+
+// GetAlertByFingerprint get the alert of the fingerprint, returns nil if the alert doesn't exist.
+func (a *Alert) GetAlertByFingerprint(ctx context.Context, fingerprint string) (*Alert, error) {
+	var alert Alert
+	db := a.db.WithContext(ctx)
+	db = db.Where("fingerprint = ?", fingerprint)
+	err := db.First(&alert).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		log.Warn("Alert.GetAlertByFingerprint failed", "error", err)
+		return nil, fmt.Errorf("Alert.GetAlertByFingerprint failed err:%w", err)
+	}
+	return &alert, nil
+}
+
+// GetFiringAlerts get the earliest alerts of the category which are not resolved yet.
+func (a *Alert) GetFiringAlerts(ctx context.Context, category string, limit int) ([]Alert, error) {
+	var alerts []Alert
+	db := a.db.WithContext(ctx)
+	db = db.Where("resolved = ?", false)
+	db = db.Where("category = ?", category)
+	db = db.Order("id asc")
+	db = db.Limit(limit)
+	if err := db.Find(&alerts).Error; err != nil {
+		log.Warn("Alert.GetFiringAlerts failed", "error", err)
+		return nil, fmt.Errorf("Alert.GetFiringAlerts failed err:%w", err)
+	}
+	return alerts, nil
+}
+
+// InsertOrUpdateOccurrence records the occurrence of the alert atomically, the concurrent occurrences of the identical
+// alert are serialized by the upsert of the fingerprint. The first occurrence and the refired occurrence of a resolved
+// alert are notified, the other occurrences are only notified again if the last notification is older than the throttle
+// window. It returns the recorded alert and whether the occurrence should be notified.
+func (a *Alert) InsertOrUpdateOccurrence(ctx context.Context, alert Alert, throttleWindow time.Duration) (*Alert, bool, error) {
+	// the timestamps are stored in microseconds, the notified time is compared with the returned one.
+	now := utils.NowUTC().Truncate(time.Microsecond)
+	alert.OccurrenceCount = 1
+	alert.Resolved = false
+	alert.FirstSeenAt = now
+	alert.LastSeenAt = now
+	alert.LastNotifiedAt = now
+
+	db := a.db.WithContext(ctx)
+	db = db.Model(&Alert{})
+	db = db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "fingerprint"}},
+		DoUpdates: clause.Set{
+			{Column: clause.Column{Name: "occurrence_count"}, Value: gorm.Expr("CASE WHEN alert.resolved THEN 1 ELSE alert.occurrence_count + 1 END")},
+			{Column: clause.Column{Name: "first_seen_at"}, Value: gorm.Expr("CASE WHEN alert.resolved THEN excluded.first_seen_at ELSE alert.first_seen_at END")},
+			{Column: clause.Column{Name: "last_seen_at"}, Value: gorm.Expr("excluded.last_seen_at")},
+			{Column: clause.Column{Name: "last_notified_at"}, Value: gorm.Expr("CASE WHEN alert.resolved OR alert.last_notified_at <= ? THEN excluded.last_notified_at ELSE alert.last_notified_at END", now.Add(-throttleWindow))},
+			{Column: clause.Column{Name: "resolved"}, Value: false},
+			{Column: clause.Column{Name: "resolved_at"}, Value: nil},
+		},
+	}, clause.Returning{})
+
+	if err := db.Create(&alert).Error; err != nil {
+		log.Warn("Alert.InsertOrUpdateOccurrence failed", "error", err)
+		return nil, false, fmt.Errorf("Alert.InsertOrUpdateOccurrence failed err:%w", err)
+	}
+	return &alert, alert.LastNotifiedAt.Equal(now), nil
+}
+
+// ClaimReminderAlerts claims the firing alerts which are not notified within the reminder interval, their notified time
+// is updated in the same statement, so an alert is only reminded by one of the concurrent callers.
+func (a *Alert) ClaimReminderAlerts(ctx context.Context, reminderInterval time.Duration, limit int) ([]Alert, error) {
+	now := utils.NowUTC()
+	subQuery := a.db.WithContext(ctx).Model(&Alert{})
+	subQuery = subQuery.Select("id")
+	subQuery = subQuery.Where("resolved = ?", false)
+	subQuery = subQuery.Where("last_notified_at <= ?", now.Add(-reminderInterval))
+	subQuery = subQuery.Order("id asc")
+	subQuery = subQuery.Limit(limit)
+	subQuery = subQuery.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+
+	var alerts []Alert
+	db := a.db.WithContext(ctx)
+	db = db.Model(&alerts)
+	db = db.Clauses(clause.Returning{})
+	db = db.Where("id in (?)", subQuery)
+	if err := db.Update("last_notified_at", now).Error; err != nil {
+		log.Warn("Alert.ClaimReminderAlerts failed", "error", err)
+		return nil, fmt.Errorf("Alert.ClaimReminderAlerts failed err:%w", err)
+	}
+	return alerts, nil
+}
+
+// ResolveAlerts marks the firing alerts of the fingerprints as resolved, and returns the alerts which are resolved by this call.
+func (a *Alert) ResolveAlerts(ctx context.Context, fingerprints []string) ([]Alert, error) {
+	if len(fingerprints) == 0 {
+		return nil, nil
+	}
+
+	var alerts []Alert
+	db := a.db.WithContext(ctx)
+	db = db.Model(&alerts)
+	db = db.Clauses(clause.Returning{})
+	db = db.Where("fingerprint in (?)", fingerprints)
+	db = db.Where("resolved = ?", false)
+
+	updateFields := map[string]interface{}{
+		"resolved":    true,
+		"resolved_at": utils.NowUTC(),
+	}
+
+	if err := db.Updates(updateFields).Error; err != nil {
+		log.Warn("Alert.ResolveAlerts failed", "error", err)
+		return nil, fmt.Errorf("Alert.ResolveAlerts failed err:%w", err)
+	}
+	return alerts, nil
+}
+
+// DeleteAlerts deletes the alerts of the layer whose block number >= startBlockNumber, the alerts are raised by the
+// blocks which are rolled back, so they're notified as new alerts if they're raised again by the canonical blocks.
+func (a *Alert) DeleteAlerts(ctx context.Context, layer types.LayerType, startBlockNumber uint64, dbTX ...*gorm.DB) error {
+	db := a.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+
+	db = db.WithContext(ctx)
+	db = db.Where("layer = ? AND block_number >= ? AND block_number > 0", layer, startBlockNumber)
+	if err := db.Unscoped().Delete(&Alert{}).Error; err != nil {
+		return fmt.Errorf("Alert.DeleteAlerts failed, layer: %v, start block number: %v, err: %w", layer, startBlockNumber, err)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/scroll-tech/chain-monitor/internal/types"
+	"github.com/scroll-tech/chain-monitor/internal/utils"
+)
+
+// AlertOutbox contains the alerts waiting to be delivered to the sinks, they survive the restarts.
+type AlertOutbox struct {
+	db *gorm.DB `gorm:"column:-"`
+
+	ID       int64  `json:"id" gorm:"column:id"`
+	Sink     string `json:"sink" gorm:"sink"`
+	Category string `json:"category" gorm:"category"`
+	Payload  string `json:"payload" gorm:"payload"`
+	// the layer and the block number of the alert, the pending alerts of the rolled back blocks are dropped.
+	Layer       int    `json:"layer" gorm:"layer"`
+	BlockNumber uint64 `json:"block_number" gorm:"block_number"`
+
+	// status
+	Status        int        `json:"status" gorm:"status"`
+	Attempts      int        `json:"attempts" gorm:"attempts"`
+	LastError     string     `json:"last_error" gorm:"last_error"`
+	NextAttemptAt time.Time  `json:"next_attempt_at" gorm:"next_attempt_at"`
+	SentAt        *time.Time `json:"sent_at" gorm:"sent_at"`
+
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at"`
+}
+
+// NewAlertOutbox creates a new AlertOutbox database instance.
+func NewAlertOutbox(db *gorm.DB) *AlertOutbox {
+	return &AlertOutbox{db: db}
+}
+
+// TableName returns the table name for the AlertOutbox model.
+func (*AlertOutbox) TableName() string {
+	return "alert_outbox"
+}
+
+// InsertAlertOutbox inserts the alert to deliver, it's delivered immediately by the sink.
+func (a *AlertOutbox) InsertAlertOutbox(ctx context.Context, outbox AlertOutbox) error {
+	db := a.db.WithContext(ctx)
+	db = db.Model(&AlertOutbox{})
+
+	outbox.Status = int(types.AlertOutboxStatusTypePending)
+	outbox.NextAttemptAt = utils.NowUTC()
+	if err := db.Create(&outbox).Error; err != nil {
+		log.Warn("AlertOutbox.InsertAlertOutbox failed", "error", err)
+		return fmt.Errorf("AlertOutbox.InsertAlertOutbox failed err:%w", err)
+	}
+	return nil
+}
+
+// ClaimPendingAlertOutboxes claims the earliest pending alerts of the sink which are due to be delivered.
+// The claimed alerts are not claimed again within the lease, so they are not delivered twice by the concurrent workers.
+func (a *AlertOutbox) ClaimPendingAlertOutboxes(ctx context.Context, sink string, limit int, lease time.Duration) ([]AlertOutbox, error) {
+	now := utils.NowUTC()
+	subQuery := a.db.WithContext(ctx).Model(&AlertOutbox{})
+	subQuery = subQuery.Select("id")
+	subQuery = subQuery.Where("sink = ?", sink)
+	subQuery = subQuery.Where("status = ?", types.AlertOutboxStatusTypePending)
+	subQuery = subQuery.Where("next_attempt_at <= ?", now)
+	subQuery = subQuery.Order("id asc")
+	subQuery = subQuery.Limit(limit)
+	subQuery = subQuery.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+
+	var outboxes []AlertOutbox
+	db := a.db.WithContext(ctx)
+	db = db.Model(&outboxes)
+	db = db.Clauses(clause.Returning{})
+	db = db.Where("id in (?)", subQuery)
+	if err := db.Update("next_attempt_at", now.Add(lease)).Error; err != nil {
+		log.Warn("AlertOutbox.ClaimPendingAlertOutboxes failed", "error", err)
+		return nil, fmt.Errorf("AlertOutbox.ClaimPendingAlertOutboxes failed err:%w", err)
+	}
+	return outboxes, nil
+}
+
+// CountPendingAlertOutboxes counts the pending alerts of the sink.
+func (a *AlertOutbox) CountPendingAlertOutboxes(ctx context.Context, sink string) (int64, error) {
+	var count int64
+	db := a.db.WithContext(ctx)
+	db = db.Model(&AlertOutbox{})
+	db = db.Where("sink = ?", sink)
+	db = db.Where("status = ?", types.AlertOutboxStatusTypePending)
+	if err := db.Count(&count).Error; err != nil {
+		log.Warn("AlertOutbox.CountPendingAlertOutboxes failed", "error", err)
+		return 0, fmt.Errorf("AlertOutbox.CountPendingAlertOutboxes failed err:%w", err)
+	}
+	return count, nil
+}
+
+// UpdateSent marks the alert as delivered.
+func (a *AlertOutbox) UpdateSent(ctx context.Context, id int64) error {
+	db := a.db.WithContext(ctx)
+	db = db.Model(&AlertOutbox{})
+	db = db.Where("id = ?", id)
+
+	updateFields := map[string]interface{}{
+		"status":   types.AlertOutboxStatusTypeSent,
+		"attempts": gorm.Expr("attempts + 1"),
+		"sent_at":  utils.NowUTC(),
+	}
+
+	if err := db.Updates(updateFields).Error; err != nil {
+		log.Warn("AlertOutbox.UpdateSent failed", "error", err)
+		return fmt.Errorf("AlertOutbox.UpdateSent failed err:%w", err)
+	}
+	return nil
+}
+
+// UpdateFailed records the failed attempt of the alert, the alert is retried at nextAttemptAt,
+// or moved to the dead letter if deadLetter is set.
+func (a *AlertOutbox) UpdateFailed(ctx context.Context, id int64, lastError string, nextAttemptAt time.Time, deadLetter bool) error {
+	db := a.db.WithContext(ctx)
+	db = db.Model(&AlertOutbox{})
+	db = db.Where("id = ?", id)
+
+	updateFields := map[string]interface{}{
+		"attempts":        gorm.Expr("attempts + 1"),
+		"last_error":      lastError,
+		"next_attempt_at": nextAttemptAt,
+	}
+	if deadLetter {
+		updateFields["status"] = types.AlertOutboxStatusTypeDeadLetter
+	}
+
+	if err := db.Updates(updateFields).Error; err != nil {
+		log.Warn("AlertOutbox.UpdateFailed failed", "error", err)
+		return fmt.Errorf("AlertOutbox.UpdateFailed failed err:%w", err)
+	}
+	return nil
+}
+
+// DeletePendingAlertOutboxes deletes the pending alerts of the layer whose block number >= startBlockNumber,
+// the alerts are raised by the blocks which are rolled back.
+func (a *AlertOutbox) DeletePendingAlertOutboxes(ctx context.Context, layer types.LayerType, startBlockNumber uint64, dbTX ...*gorm.DB) error {
+	db := a.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+
+	db = db.WithContext(ctx)
+	db = db.Where("layer = ? AND block_number >= ? AND block_number > 0", layer, startBlockNumber)
+	db = db.Where("status = ?", types.AlertOutboxStatusTypePending)
+	if err := db.Unscoped().Delete(&AlertOutbox{}).Error; err != nil {
+		return fmt.Errorf("AlertOutbox.DeletePendingAlertOutboxes failed, layer: %v, start block number: %v, err: %w", layer, startBlockNumber, err)
+	}
+	return nil
+}
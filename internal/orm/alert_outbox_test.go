@@ -0,0 +1,107 @@
+package orm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scroll-tech/chain-monitor/internal/types"
+	"github.com/scroll-tech/chain-monitor/internal/utils"
+	"github.com/scroll-tech/chain-monitor/internal/utils/testcontainer"
+)
+
+func TestAlertOutbox(t *testing.T) {
+	ctx := context.Background()
+	db := testcontainer.SetupDB(ctx, t)
+	alertOutboxOrm := NewAlertOutbox(db)
+
+	sink := "slack"
+	tests := []struct {
+		name string
+		test func(t *testing.T)
+	}{
+		{
+			"insertAlertOutbox", func(t *testing.T) {
+				err := alertOutboxOrm.InsertAlertOutbox(ctx, AlertOutbox{Sink: sink, Category: "withdraw_root", Payload: `{"title":"first"}`})
+				assert.NoError(t, err)
+				err = alertOutboxOrm.InsertAlertOutbox(ctx, AlertOutbox{Sink: sink, Category: "withdraw_root", Payload: `{"title":"second"}`})
+				assert.NoError(t, err)
+				err = alertOutboxOrm.InsertAlertOutbox(ctx, AlertOutbox{Sink: "webhook", Category: "withdraw_root", Payload: `{"title":"third"}`})
+				assert.NoError(t, err)
+
+				count, err := alertOutboxOrm.CountPendingAlertOutboxes(ctx, sink)
+				assert.NoError(t, err)
+				assert.Equal(t, count, int64(2))
+			},
+		},
+		{
+			"claimPendingAlertOutboxes", func(t *testing.T) {
+				outboxes, err := alertOutboxOrm.ClaimPendingAlertOutboxes(ctx, sink, 10, time.Minute)
+				assert.NoError(t, err)
+				assert.Len(t, outboxes, 2)
+				assert.Equal(t, outboxes[0].Sink, sink)
+
+				// The claimed alerts are not claimed again within the lease.
+				outboxes, err = alertOutboxOrm.ClaimPendingAlertOutboxes(ctx, sink, 10, time.Minute)
+				assert.NoError(t, err)
+				assert.Len(t, outboxes, 0)
+			},
+		},
+		{
+			"updateSentAndFailed", func(t *testing.T) {
+				var outboxes []AlertOutbox
+				err := db.Where("sink = ?", sink).Order("id asc").Find(&outboxes).Error
+				assert.NoError(t, err)
+				assert.Len(t, outboxes, 2)
+
+				err = alertOutboxOrm.UpdateSent(ctx, outboxes[0].ID)
+				assert.NoError(t, err)
+				err = alertOutboxOrm.UpdateFailed(ctx, outboxes[1].ID, "timeout", utils.NowUTC().Add(-time.Second), false)
+				assert.NoError(t, err)
+
+				// The failed alert is claimed again once it's due.
+				claimed, err := alertOutboxOrm.ClaimPendingAlertOutboxes(ctx, sink, 10, time.Minute)
+				assert.NoError(t, err)
+				assert.Len(t, claimed, 1)
+				assert.Equal(t, claimed[0].ID, outboxes[1].ID)
+				assert.Equal(t, claimed[0].Attempts, 1)
+				assert.Equal(t, claimed[0].LastError, "timeout")
+
+				err = alertOutboxOrm.UpdateFailed(ctx, outboxes[1].ID, "timeout", utils.NowUTC().Add(-time.Second), true)
+				assert.NoError(t, err)
+
+				count, err := alertOutboxOrm.CountPendingAlertOutboxes(ctx, sink)
+				assert.NoError(t, err)
+				assert.Equal(t, count, int64(0))
+
+				err = db.Where("sink = ?", sink).Order("id asc").Find(&outboxes).Error
+				assert.NoError(t, err)
+				assert.Equal(t, outboxes[0].Status, int(types.AlertOutboxStatusTypeSent))
+				assert.NotNil(t, outboxes[0].SentAt)
+				assert.Equal(t, outboxes[1].Status, int(types.AlertOutboxStatusTypeDeadLetter))
+				assert.Equal(t, outboxes[1].Attempts, 2)
+			},
+		},
+		{
+			"deletePendingAlertOutboxes", func(t *testing.T) {
+				for _, blockNumber := range []uint64{99, 100, 101} {
+					err := alertOutboxOrm.InsertAlertOutbox(ctx, AlertOutbox{Sink: "stdout", Category: "reorg", Payload: `{}`, Layer: int(types.Layer1), BlockNumber: blockNumber})
+					assert.NoError(t, err)
+				}
+
+				err := alertOutboxOrm.DeletePendingAlertOutboxes(ctx, types.Layer1, 100)
+				assert.NoError(t, err)
+
+				count, err := alertOutboxOrm.CountPendingAlertOutboxes(ctx, "stdout")
+				assert.NoError(t, err)
+				assert.Equal(t, count, int64(1))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, test.test)
+	}
+}
@@ -0,0 +1,139 @@
+package orm
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scroll-tech/chain-monitor/internal/types"
+	"github.com/scroll-tech/chain-monitor/internal/utils/testcontainer"
+)
+
+func TestAlert(t *testing.T) {
+	ctx := context.Background()
+	db := testcontainer.SetupDB(ctx, t)
+	alertOrm := NewAlert(db)
+
+	fingerprint := "withdraw_root:2:100"
+	newAlert := Alert{
+		Fingerprint: fingerprint,
+		Category:    "withdraw_root",
+		Severity:    "critical",
+		Title:       "L2 withdraw root check failed",
+		Layer:       int(types.Layer2),
+		BlockNumber: 100,
+	}
+	tests := []struct {
+		name string
+		test func(t *testing.T)
+	}{
+		{
+			"getNotExistedAlert", func(t *testing.T) {
+				alert, err := alertOrm.GetAlertByFingerprint(ctx, fingerprint)
+				assert.NoError(t, err)
+				assert.Nil(t, alert)
+			},
+		},
+		{
+			"insertOrUpdateOccurrence", func(t *testing.T) {
+				alert, notify, err := alertOrm.InsertOrUpdateOccurrence(ctx, newAlert, time.Hour)
+				assert.NoError(t, err)
+				assert.True(t, notify)
+				assert.Equal(t, alert.OccurrenceCount, uint64(1))
+
+				// the identical alert is suppressed within the throttle window.
+				alert, notify, err = alertOrm.InsertOrUpdateOccurrence(ctx, newAlert, time.Hour)
+				assert.NoError(t, err)
+				assert.False(t, notify)
+				assert.Equal(t, alert.OccurrenceCount, uint64(2))
+
+				// and notified again after it.
+				alert, notify, err = alertOrm.InsertOrUpdateOccurrence(ctx, newAlert, 0)
+				assert.NoError(t, err)
+				assert.True(t, notify)
+				assert.Equal(t, alert.OccurrenceCount, uint64(3))
+			},
+		},
+		{
+			"concurrentOccurrences", func(t *testing.T) {
+				concurrentAlert := newAlert
+				concurrentAlert.Fingerprint = "withdraw_root:2:300"
+
+				var wg sync.WaitGroup
+				var mu sync.Mutex
+				var notified int
+				for i := 0; i < 10; i++ {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						_, notify, err := alertOrm.InsertOrUpdateOccurrence(ctx, concurrentAlert, time.Hour)
+						assert.NoError(t, err)
+						if notify {
+							mu.Lock()
+							notified++
+							mu.Unlock()
+						}
+					}()
+				}
+				wg.Wait()
+
+				assert.Equal(t, notified, 1)
+				alert, err := alertOrm.GetAlertByFingerprint(ctx, concurrentAlert.Fingerprint)
+				assert.NoError(t, err)
+				assert.Equal(t, alert.OccurrenceCount, uint64(10))
+			},
+		},
+		{
+			"claimReminderAlerts", func(t *testing.T) {
+				alerts, err := alertOrm.ClaimReminderAlerts(ctx, time.Hour, 10)
+				assert.NoError(t, err)
+				assert.Len(t, alerts, 0)
+
+				alerts, err = alertOrm.ClaimReminderAlerts(ctx, 0, 10)
+				assert.NoError(t, err)
+				assert.Len(t, alerts, 2)
+
+				// the claimed alerts are not reminded again within the reminder interval.
+				alerts, err = alertOrm.ClaimReminderAlerts(ctx, time.Hour, 10)
+				assert.NoError(t, err)
+				assert.Len(t, alerts, 0)
+			},
+		},
+		{
+			"resolveAlerts", func(t *testing.T) {
+				alerts, err := alertOrm.ResolveAlerts(ctx, []string{fingerprint, "withdraw_root:2:200"})
+				assert.NoError(t, err)
+				assert.Len(t, alerts, 1)
+				assert.Equal(t, alerts[0].Fingerprint, fingerprint)
+				assert.True(t, alerts[0].Resolved)
+
+				// the resolved alerts are not resolved again, nor reminded.
+				alerts, err = alertOrm.ResolveAlerts(ctx, []string{fingerprint})
+				assert.NoError(t, err)
+				assert.Len(t, alerts, 0)
+
+				alerts, err = alertOrm.ClaimReminderAlerts(ctx, 0, 10)
+				assert.NoError(t, err)
+				assert.Len(t, alerts, 1)
+				assert.Equal(t, alerts[0].Fingerprint, "withdraw_root:2:300")
+			},
+		},
+		{
+			"refireAlert", func(t *testing.T) {
+				alert, notify, err := alertOrm.InsertOrUpdateOccurrence(ctx, newAlert, time.Hour)
+				assert.NoError(t, err)
+				assert.True(t, notify)
+				assert.False(t, alert.Resolved)
+				assert.Nil(t, alert.ResolvedAt)
+				assert.Equal(t, alert.OccurrenceCount, uint64(1))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, test.test)
+	}
+}
@@ -0,0 +1,270 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/scroll-tech/chain-monitor/internal/types"
+	"github.com/scroll-tech/chain-monitor/internal/utils"
+)
+
+// Batch contains the batches committed and finalized on the l1 scroll chain.
+type Batch struct {
+	db *gorm.DB `gorm:"column:-"`
+
+	ID         int64  `json:"id" gorm:"column:id"`
+	BatchIndex uint64 `json:"batch_index" gorm:"batch_index"`
+	BatchHash  string `json:"batch_hash" gorm:"batch_hash"`
+
+	// l2 block range, both are zero if the commit calldata can't be decoded.
+	StartBlockNumber uint64 `json:"start_block_number" gorm:"start_block_number"`
+	EndBlockNumber   uint64 `json:"end_block_number" gorm:"end_block_number"`
+
+	// l1 commit info
+	CommitBlockNumber uint64 `json:"commit_block_number" gorm:"commit_block_number"`
+	CommitTxHash      string `json:"commit_tx_hash" gorm:"commit_tx_hash"`
+
+	// l1 finalize info
+	FinalizeBlockNumber uint64 `json:"finalize_block_number" gorm:"finalize_block_number"`
+	FinalizeTxHash      string `json:"finalize_tx_hash" gorm:"finalize_tx_hash"`
+	// the l2 sent messages of the batch are relayable on l1 since the batch is finalized.
+	FinalizeBlockTime *time.Time `json:"finalize_block_time" gorm:"finalize_block_time"`
+	StateRoot         string     `json:"state_root" gorm:"state_root"`
+	WithdrawRoot      string     `json:"withdraw_root" gorm:"withdraw_root"`
+
+	// l1 revert info
+	RevertBlockNumber uint64 `json:"revert_block_number" gorm:"revert_block_number"`
+	RevertTxHash      string `json:"revert_tx_hash" gorm:"revert_tx_hash"`
+
+	// status
+	LocalWithdrawRoot  string `json:"local_withdraw_root" gorm:"local_withdraw_root"`
+	WithdrawRootStatus int    `json:"withdraw_root_status" gorm:"withdraw_root_status"`
+	Status             int    `json:"status" gorm:"status"`
+	// whether the status of the finalized batch is checked, the batch is alerted if it's finalized before it's valid.
+	FinalizeChecked bool `json:"finalize_checked" gorm:"finalize_checked"`
+
+	WithdrawRootStatusUpdatedAt *time.Time     `json:"withdraw_root_status_updated_at" gorm:"withdraw_root_status_updated_at"`
+	StatusUpdatedAt             *time.Time     `json:"status_updated_at" gorm:"status_updated_at"`
+	CreatedAt                   time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt                   time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt                   gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at"`
+}
+
+// NewBatch creates a new Batch database instance.
+func NewBatch(db *gorm.DB) *Batch {
+	return &Batch{db: db}
+}
+
+// TableName returns the table name for the Batch model.
+func (*Batch) TableName() string {
+	return "batch"
+}
+
+// GetBatchByIndex get the batch of the batch index, returns nil if the batch doesn't exist.
+func (b *Batch) GetBatchByIndex(ctx context.Context, batchIndex uint64) (*Batch, error) {
+	var batch Batch
+	db := b.db.WithContext(ctx)
+	db = db.Where("batch_index = ?", batchIndex)
+	err := db.First(&batch).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		log.Warn("Batch.GetBatchByIndex failed", "error", err)
+		return nil, fmt.Errorf("Batch.GetBatchByIndex failed err:%w", err)
+	}
+	return &batch, nil
+}
+
+// GetUnsettledBatches get the latest batches which are not reverted and whose status is neither valid nor unknown range, and
+// whose l2 block range is unknown or ends at or after minEndBlockNumber, ordered by batch index desc.
+func (b *Batch) GetUnsettledBatches(ctx context.Context, minEndBlockNumber uint64, limit int) ([]Batch, error) {
+	var batches []Batch
+	db := b.db.WithContext(ctx)
+	db = db.Where("status NOT IN ?", []types.BatchStatus{types.BatchStatusTypeValid, types.BatchStatusTypeUnknownRange})
+	db = db.Where("revert_block_number = 0")
+	db = db.Where("end_block_number = 0 OR end_block_number >= ?", minEndBlockNumber)
+	db = db.Order("batch_index desc")
+	db = db.Limit(limit)
+	if err := db.Find(&batches).Error; err != nil {
+		log.Warn("Batch.GetUnsettledBatches failed", "error", err)
+		return nil, fmt.Errorf("Batch.GetUnsettledBatches failed err:%w", err)
+	}
+	return batches, nil
+}
+
+// GetUncheckedFinalizedBatches get the finalized batches whose withdraw root is not checked yet, and whose l2 block range is known
+// and ends at or after minEndBlockNumber, ordered by batch index.
+func (b *Batch) GetUncheckedFinalizedBatches(ctx context.Context, minEndBlockNumber uint64, limit int) ([]Batch, error) {
+	var batches []Batch
+	db := b.db.WithContext(ctx)
+	db = db.Where("withdraw_root_status = ?", types.WithdrawRootStatusTypeUnchecked)
+	db = db.Where("finalize_block_number > 0")
+	db = db.Where("end_block_number > 0")
+	db = db.Where("end_block_number >= ?", minEndBlockNumber)
+	db = db.Order("batch_index asc")
+	db = db.Limit(limit)
+	if err := db.Find(&batches).Error; err != nil {
+		log.Warn("Batch.GetUncheckedFinalizedBatches failed", "error", err)
+		return nil, fmt.Errorf("Batch.GetUncheckedFinalizedBatches failed err:%w", err)
+	}
+	return batches, nil
+}
+
+// InsertOrUpdateCommittedBatch insert or update the commit info and the l2 block range of the batch,
+// the revert info and the status are reset if the batch index is committed again after a revert.
+func (b *Batch) InsertOrUpdateCommittedBatch(ctx context.Context, batch Batch, dbTX ...*gorm.DB) error {
+	db := b.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+
+	db = db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "batch_index"}},
+		DoUpdates: clause.AssignmentColumns([]string{"batch_hash", "start_block_number", "end_block_number", "commit_block_number",
+			"commit_tx_hash", "revert_block_number", "revert_tx_hash", "status", "finalize_checked"}),
+	})
+
+	if err := db.Create(&batch).Error; err != nil {
+		return fmt.Errorf("Batch.InsertOrUpdateCommittedBatch error: %w, batch: %v", err, batch)
+	}
+	return nil
+}
+
+// InsertOrUpdateFinalizedBatch insert or update the finalize info of the batch, the batch is inserted
+// if its commit event is not ingested, e.g. it's committed before the start block.
+func (b *Batch) InsertOrUpdateFinalizedBatch(ctx context.Context, batch Batch, dbTX ...*gorm.DB) error {
+	db := b.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+
+	db = db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "batch_index"}},
+		DoUpdates: clause.AssignmentColumns([]string{"finalize_block_number", "finalize_tx_hash", "finalize_block_time", "state_root", "withdraw_root"}),
+	})
+
+	if err := db.Create(&batch).Error; err != nil {
+		return fmt.Errorf("Batch.InsertOrUpdateFinalizedBatch error: %w, batch: %v", err, batch)
+	}
+	return nil
+}
+
+// UpdateRevertedBatch updates the revert info of the batch.
+func (b *Batch) UpdateRevertedBatch(ctx context.Context, batchIndex, revertBlockNumber uint64, revertTxHash string, dbTX ...*gorm.DB) error {
+	db := b.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+
+	db = db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("batch_index = ?", batchIndex)
+
+	updateFields := map[string]interface{}{
+		"revert_block_number": revertBlockNumber,
+		"revert_tx_hash":      revertTxHash,
+	}
+	if err := db.Updates(updateFields).Error; err != nil {
+		log.Warn("Batch.UpdateRevertedBatch failed", "error", err)
+		return fmt.Errorf("Batch.UpdateRevertedBatch failed err:%w", err)
+	}
+	return nil
+}
+
+// UpdateStatus updates the status of the batch, and whether the status of the finalized batch is checked.
+func (b *Batch) UpdateStatus(ctx context.Context, batchIndex uint64, status types.BatchStatus, finalizeChecked bool, dbTX ...*gorm.DB) error {
+	db := b.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+
+	db = db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("batch_index = ?", batchIndex)
+
+	updateFields := map[string]interface{}{
+		"status":            status,
+		"finalize_checked":  finalizeChecked,
+		"status_updated_at": utils.NowUTC(),
+	}
+	if err := db.Updates(updateFields).Error; err != nil {
+		log.Warn("Batch.UpdateStatus failed", "error", err)
+		return fmt.Errorf("Batch.UpdateStatus failed err:%w", err)
+	}
+	return nil
+}
+
+// UpdateWithdrawRootStatus updates the locally computed withdraw root and the withdraw root check status of the batch.
+func (b *Batch) UpdateWithdrawRootStatus(ctx context.Context, batchIndex uint64, localWithdrawRoot string, status types.WithdrawRootStatus, dbTX ...*gorm.DB) error {
+	db := b.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+
+	db = db.WithContext(ctx)
+	db = db.Model(&Batch{})
+	db = db.Where("batch_index = ?", batchIndex)
+
+	updateFields := map[string]interface{}{
+		"local_withdraw_root":             localWithdrawRoot,
+		"withdraw_root_status":            status,
+		"withdraw_root_status_updated_at": utils.NowUTC(),
+	}
+	if err := db.Updates(updateFields).Error; err != nil {
+		log.Warn("Batch.UpdateWithdrawRootStatus failed", "error", err)
+		return fmt.Errorf("Batch.UpdateWithdrawRootStatus failed err:%w", err)
+	}
+	return nil
+}
+
+// RollbackBatches rollbacks the batch events whose l1 block number >= startBlockNumber after a l1 chain reorganization.
+// The batches committed after the fork point are deleted, and the batches finalized or reverted after the fork point reset the finalize or revert info.
+func (b *Batch) RollbackBatches(ctx context.Context, startBlockNumber uint64, dbTX ...*gorm.DB) error {
+	db := b.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+
+	deleteDB := db.Where("commit_block_number >= ?", startBlockNumber)
+	if err := deleteDB.Unscoped().Delete(&Batch{}).Error; err != nil {
+		return fmt.Errorf("Batch.RollbackBatches delete failed, start block number: %v, err: %w", startBlockNumber, err)
+	}
+
+	updateDB := db.Model(&Batch{}).Where("finalize_block_number >= ?", startBlockNumber)
+	updateFields := map[string]interface{}{
+		"finalize_block_number":           0,
+		"finalize_tx_hash":                "",
+		"finalize_block_time":             nil,
+		"state_root":                      "",
+		"withdraw_root":                   "",
+		"local_withdraw_root":             "",
+		"withdraw_root_status":            types.WithdrawRootStatusTypeUnchecked,
+		"finalize_checked":                false,
+		"withdraw_root_status_updated_at": utils.NowUTC(),
+	}
+	if err := updateDB.Updates(updateFields).Error; err != nil {
+		return fmt.Errorf("Batch.RollbackBatches update failed, start block number: %v, err: %w", startBlockNumber, err)
+	}
+
+	revertDB := db.Model(&Batch{}).Where("revert_block_number >= ?", startBlockNumber)
+	revertFields := map[string]interface{}{
+		"revert_block_number": 0,
+		"revert_tx_hash":      "",
+	}
+	if err := revertDB.Updates(revertFields).Error; err != nil {
+		return fmt.Errorf("Batch.RollbackBatches revert failed, start block number: %v, err: %w", startBlockNumber, err)
+	}
+	return nil
+}
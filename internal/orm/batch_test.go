@@ -0,0 +1,170 @@
+package orm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scroll-tech/chain-monitor/internal/types"
+	"github.com/scroll-tech/chain-monitor/internal/utils/testcontainer"
+)
+
+func TestBatch(t *testing.T) {
+	ctx := context.Background()
+	db := testcontainer.SetupDB(ctx, t)
+	batchOrm := NewBatch(db)
+
+	tests := []struct {
+		name string
+		test func(t *testing.T)
+	}{
+		{
+			"insertOrUpdateBatches", func(t *testing.T) {
+				for i, batchIndex := range []uint64{1, 2, 3} {
+					batch := Batch{
+						BatchIndex:        batchIndex,
+						BatchHash:         "0xfc7d3ea5ec8dc9b664a5a886c3b33d21e665355057601033481a439498efb79a",
+						StartBlockNumber:  uint64(i*10 + 1),
+						EndBlockNumber:    uint64(i*10 + 10),
+						CommitBlockNumber: uint64(100 + i),
+						CommitTxHash:      "0x1c7d3ea5ec8dc9b664a5a886c3b33d21e665355057601033481a439498efb79a",
+					}
+					assert.NoError(t, batchOrm.InsertOrUpdateCommittedBatch(ctx, batch))
+				}
+
+				// batch 0 is committed before the start block, only the finalize info is known.
+				for i, batchIndex := range []uint64{0, 1, 2} {
+					batch := Batch{
+						BatchIndex:          batchIndex,
+						FinalizeBlockNumber: uint64(200 + i),
+						FinalizeTxHash:      "0x2c7d3ea5ec8dc9b664a5a886c3b33d21e665355057601033481a439498efb79a",
+						StateRoot:           "0x3c7d3ea5ec8dc9b664a5a886c3b33d21e665355057601033481a439498efb79a",
+						WithdrawRoot:        "0x4c7d3ea5ec8dc9b664a5a886c3b33d21e665355057601033481a439498efb79a",
+					}
+					assert.NoError(t, batchOrm.InsertOrUpdateFinalizedBatch(ctx, batch))
+				}
+			},
+		},
+		{
+			"getUncheckedFinalizedBatches", func(t *testing.T) {
+				batches, err := batchOrm.GetUncheckedFinalizedBatches(ctx, 0, 10)
+				assert.NoError(t, err)
+				assert.Len(t, batches, 2)
+				assert.Equal(t, uint64(1), batches[0].BatchIndex)
+				assert.Equal(t, uint64(10), batches[0].EndBlockNumber)
+				assert.Equal(t, uint64(101), batches[0].CommitBlockNumber)
+				assert.Equal(t, uint64(201), batches[0].FinalizeBlockNumber)
+				assert.Equal(t, "0x4c7d3ea5ec8dc9b664a5a886c3b33d21e665355057601033481a439498efb79a", batches[0].WithdrawRoot)
+				assert.Equal(t, uint64(2), batches[1].BatchIndex)
+
+				batches, err = batchOrm.GetUncheckedFinalizedBatches(ctx, 11, 10)
+				assert.NoError(t, err)
+				assert.Len(t, batches, 1)
+				assert.Equal(t, uint64(2), batches[0].BatchIndex)
+			},
+		},
+		{
+			"updateWithdrawRootStatus", func(t *testing.T) {
+				localWithdrawRoot := "0x4c7d3ea5ec8dc9b664a5a886c3b33d21e665355057601033481a439498efb79a"
+				assert.NoError(t, batchOrm.UpdateWithdrawRootStatus(ctx, 1, localWithdrawRoot, types.WithdrawRootStatusTypeValid))
+
+				batches, err := batchOrm.GetUncheckedFinalizedBatches(ctx, 0, 10)
+				assert.NoError(t, err)
+				assert.Len(t, batches, 1)
+				assert.Equal(t, uint64(2), batches[0].BatchIndex)
+			},
+		},
+		{
+			"getBatchByIndex", func(t *testing.T) {
+				batch, err := batchOrm.GetBatchByIndex(ctx, 1)
+				assert.NoError(t, err)
+				assert.NotNil(t, batch)
+				assert.Equal(t, uint64(101), batch.CommitBlockNumber)
+				assert.Equal(t, uint64(201), batch.FinalizeBlockNumber)
+
+				batch, err = batchOrm.GetBatchByIndex(ctx, 100)
+				assert.NoError(t, err)
+				assert.Nil(t, batch)
+			},
+		},
+		{
+			"updateStatus", func(t *testing.T) {
+				batches, err := batchOrm.GetUnsettledBatches(ctx, 0, 10)
+				assert.NoError(t, err)
+				assert.Len(t, batches, 4)
+				assert.Equal(t, uint64(3), batches[0].BatchIndex)
+
+				// batch 0 has no l2 block range, it's returned whatever the min end block number is.
+				batches, err = batchOrm.GetUnsettledBatches(ctx, 11, 10)
+				assert.NoError(t, err)
+				assert.Len(t, batches, 3)
+				assert.Equal(t, uint64(0), batches[2].BatchIndex)
+
+				assert.NoError(t, batchOrm.UpdateStatus(ctx, 3, types.BatchStatusTypeValid, false))
+				assert.NoError(t, batchOrm.UpdateStatus(ctx, 2, types.BatchStatusTypeInvalid, true))
+				batches, err = batchOrm.GetUnsettledBatches(ctx, 0, 10)
+				assert.NoError(t, err)
+				assert.Len(t, batches, 3)
+				assert.Equal(t, uint64(2), batches[0].BatchIndex)
+				assert.Equal(t, int(types.BatchStatusTypeInvalid), batches[0].Status)
+				assert.True(t, batches[0].FinalizeChecked)
+			},
+		},
+		{
+			"updateRevertedBatch", func(t *testing.T) {
+				assert.NoError(t, batchOrm.InsertOrUpdateCommittedBatch(ctx, Batch{BatchIndex: 4, CommitBlockNumber: 103, StartBlockNumber: 31, EndBlockNumber: 40}))
+				assert.NoError(t, batchOrm.UpdateRevertedBatch(ctx, 4, 104, "0x5c7d3ea5ec8dc9b664a5a886c3b33d21e665355057601033481a439498efb79a"))
+
+				batch, err := batchOrm.GetBatchByIndex(ctx, 4)
+				assert.NoError(t, err)
+				assert.Equal(t, uint64(104), batch.RevertBlockNumber)
+
+				batches, err := batchOrm.GetUnsettledBatches(ctx, 0, 10)
+				assert.NoError(t, err)
+				assert.Len(t, batches, 3)
+				assert.Equal(t, uint64(2), batches[0].BatchIndex)
+
+				// the batch index is committed again after the revert, the revert info is reset.
+				assert.NoError(t, batchOrm.InsertOrUpdateCommittedBatch(ctx, Batch{BatchIndex: 4, CommitBlockNumber: 105, StartBlockNumber: 31, EndBlockNumber: 41}))
+				batch, err = batchOrm.GetBatchByIndex(ctx, 4)
+				assert.NoError(t, err)
+				assert.Equal(t, uint64(0), batch.RevertBlockNumber)
+				assert.Equal(t, uint64(41), batch.EndBlockNumber)
+				assert.NoError(t, batchOrm.UpdateRevertedBatch(ctx, 4, 106, "0x5c7d3ea5ec8dc9b664a5a886c3b33d21e665355057601033481a439498efb79a"))
+			},
+		},
+		{
+			"rollbackBatches", func(t *testing.T) {
+				// batch 2 is finalized at 202, its finalize info is reset.
+				assert.NoError(t, batchOrm.RollbackBatches(ctx, 202))
+				batches, err := batchOrm.GetUncheckedFinalizedBatches(ctx, 0, 10)
+				assert.NoError(t, err)
+				assert.Len(t, batches, 0)
+
+				var batch Batch
+				assert.NoError(t, db.Where("batch_index = ?", 2).First(&batch).Error)
+				assert.Equal(t, uint64(0), batch.FinalizeBlockNumber)
+				assert.Equal(t, "", batch.WithdrawRoot)
+				assert.Equal(t, uint64(20), batch.EndBlockNumber)
+				assert.False(t, batch.FinalizeChecked)
+
+				// batch 3 and 4 are committed after 102 and deleted, the checked batch 1 is finalized at 201 and reset.
+				assert.NoError(t, batchOrm.RollbackBatches(ctx, 102))
+				var count int64
+				assert.NoError(t, db.Model(&Batch{}).Count(&count).Error)
+				assert.Equal(t, int64(3), count)
+
+				batch = Batch{}
+				assert.NoError(t, db.Where("batch_index = ?", 1).First(&batch).Error)
+				assert.Equal(t, uint64(0), batch.FinalizeBlockNumber)
+				assert.Equal(t, "", batch.LocalWithdrawRoot)
+				assert.Equal(t, int(types.WithdrawRootStatusTypeUnchecked), batch.WithdrawRootStatus)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, test.test)
+	}
+}
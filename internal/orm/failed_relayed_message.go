@@ -0,0 +1,231 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/scroll-tech/chain-monitor/internal/types"
+	"github.com/scroll-tech/chain-monitor/internal/utils"
+)
+
+// FailedRelayedMessage contains the relay failures of a cross chain message on the relayed layer.
+type FailedRelayedMessage struct {
+	db *gorm.DB `gorm:"column:-"`
+
+	ID          int64  `json:"id" gorm:"column:id"`
+	MessageHash string `json:"message_hash" gorm:"message_hash"`
+	Layer       int    `json:"layer" gorm:"layer"`
+
+	// failure info
+	FailedCount            uint64 `json:"failed_count" gorm:"failed_count"`
+	FirstFailedBlockNumber uint64 `json:"first_failed_block_number" gorm:"first_failed_block_number"`
+	LastFailedBlockNumber  uint64 `json:"last_failed_block_number" gorm:"last_failed_block_number"`
+	LastFailedTxHash       string `json:"last_failed_tx_hash" gorm:"last_failed_tx_hash"`
+
+	// status
+	Status int `json:"status" gorm:"status"`
+
+	// the block times of the first and the last failures.
+	FirstFailedAt   time.Time      `json:"first_failed_at" gorm:"first_failed_at"`
+	LastFailedAt    time.Time      `json:"last_failed_at" gorm:"last_failed_at"`
+	StatusUpdatedAt time.Time      `json:"status_updated_at" gorm:"status_updated_at"`
+	CreatedAt       time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt       time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt       gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at"`
+}
+
+// FailedRelayedMessageEvent is a FailedRelayedMessage event, the events are accumulated into the failed relayed messages.
+type FailedRelayedMessageEvent struct {
+	ID          int64     `json:"id" gorm:"column:id"`
+	MessageHash string    `json:"message_hash" gorm:"message_hash"`
+	Layer       int       `json:"layer" gorm:"layer"`
+	BlockNumber uint64    `json:"block_number" gorm:"block_number"`
+	TxHash      string    `json:"tx_hash" gorm:"tx_hash"`
+	LogIndex    uint      `json:"log_index" gorm:"log_index"`
+	BlockTime   time.Time `json:"block_time" gorm:"block_time"`
+
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at"`
+}
+
+// TableName returns the table name for the FailedRelayedMessageEvent model.
+func (*FailedRelayedMessageEvent) TableName() string {
+	return "failed_relayed_message_event"
+}
+
+// NewFailedRelayedMessage creates a new FailedRelayedMessage database instance.
+func NewFailedRelayedMessage(db *gorm.DB) *FailedRelayedMessage {
+	return &FailedRelayedMessage{db: db}
+}
+
+// TableName returns the table name for the FailedRelayedMessage model.
+func (*FailedRelayedMessage) TableName() string {
+	return "failed_relayed_message"
+}
+
+// GetUnalertedFailedRelayedMessages retrieves the earliest failed relayed messages which are neither alerted nor relayed.
+func (m *FailedRelayedMessage) GetUnalertedFailedRelayedMessages(ctx context.Context, layer types.LayerType, limit int) ([]FailedRelayedMessage, error) {
+	var messages []FailedRelayedMessage
+	db := m.db.WithContext(ctx)
+	db = db.Where("layer = ?", layer)
+	db = db.Where("status = ?", types.FailedRelayedMessageStatusTypeUnknown)
+	db = db.Order("id asc")
+	db = db.Limit(limit)
+	if err := db.Find(&messages).Error; err != nil {
+		log.Warn("FailedRelayedMessage.GetUnalertedFailedRelayedMessages failed", "error", err)
+		return nil, fmt.Errorf("FailedRelayedMessage.GetUnalertedFailedRelayedMessages failed err:%w", err)
+	}
+	return messages, nil
+}
+
+// GetAlertedFailedRelayedMessages retrieves the earliest failed relayed messages which are alerted but not relayed yet.
+func (m *FailedRelayedMessage) GetAlertedFailedRelayedMessages(ctx context.Context, layer types.LayerType, limit int) ([]FailedRelayedMessage, error) {
+	var messages []FailedRelayedMessage
+	db := m.db.WithContext(ctx)
+	db = db.Where("layer = ?", layer)
+	db = db.Where("status = ?", types.FailedRelayedMessageStatusTypeAlerted)
+	db = db.Order("id asc")
+	db = db.Limit(limit)
+	if err := db.Find(&messages).Error; err != nil {
+		log.Warn("FailedRelayedMessage.GetAlertedFailedRelayedMessages failed", "error", err)
+		return nil, fmt.Errorf("FailedRelayedMessage.GetAlertedFailedRelayedMessages failed err:%w", err)
+	}
+	return messages, nil
+}
+
+// InsertOrUpdateFailedRelayedMessage records the FailedRelayedMessage event, and accumulates it into the failed relayed
+// message. The events are keyed by the tx hash and the log index, so rescanning blocks won't count them twice, and the
+// failure range is aggregated with LEAST/GREATEST, so the events of the block ranges can be inserted in any order.
+// It returns 0 if the event is recorded already.
+func (m *FailedRelayedMessage) InsertOrUpdateFailedRelayedMessage(ctx context.Context, event FailedRelayedMessageEvent, dbTX ...*gorm.DB) (int64, error) {
+	db := m.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+
+	var rowsAffected int64
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "layer"}, {Name: "tx_hash"}, {Name: "log_index"}},
+			DoNothing: true,
+		}).Create(&event)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		message := FailedRelayedMessage{
+			MessageHash:            event.MessageHash,
+			Layer:                  event.Layer,
+			FailedCount:            1,
+			FirstFailedBlockNumber: event.BlockNumber,
+			LastFailedBlockNumber:  event.BlockNumber,
+			LastFailedTxHash:       event.TxHash,
+			Status:                 int(types.FailedRelayedMessageStatusTypeUnknown),
+			FirstFailedAt:          event.BlockTime,
+			LastFailedAt:           event.BlockTime,
+		}
+		result = tx.Model(&FailedRelayedMessage{}).Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "message_hash"}, {Name: "layer"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"failed_count":              gorm.Expr("failed_relayed_message.failed_count + excluded.failed_count"),
+				"first_failed_block_number": gorm.Expr("LEAST(failed_relayed_message.first_failed_block_number, excluded.first_failed_block_number)"),
+				"last_failed_block_number":  gorm.Expr("GREATEST(failed_relayed_message.last_failed_block_number, excluded.last_failed_block_number)"),
+				"last_failed_tx_hash":       gorm.Expr("CASE WHEN excluded.last_failed_block_number >= failed_relayed_message.last_failed_block_number THEN excluded.last_failed_tx_hash ELSE failed_relayed_message.last_failed_tx_hash END"),
+				"first_failed_at":           gorm.Expr("LEAST(failed_relayed_message.first_failed_at, excluded.first_failed_at)"),
+				"last_failed_at":            gorm.Expr("GREATEST(failed_relayed_message.last_failed_at, excluded.last_failed_at)"),
+			}),
+		}).Create(&message)
+		if result.Error != nil {
+			return result.Error
+		}
+		rowsAffected = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("FailedRelayedMessage.InsertOrUpdateFailedRelayedMessage error: %w, event: %v", err, event)
+	}
+	return rowsAffected, nil
+}
+
+// RollbackFailedRelayedMessages deletes the FailedRelayedMessage events of the layer whose block number >= startBlockNumber,
+// and takes them off the failed relayed messages. The failed relayed messages without any failure left are deleted.
+func (m *FailedRelayedMessage) RollbackFailedRelayedMessages(ctx context.Context, layer types.LayerType, startBlockNumber uint64, dbTX ...*gorm.DB) error {
+	db := m.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+
+	var rolledBackEvents []FailedRelayedMessageEvent
+	deleteDB := db.Unscoped().Clauses(clause.Returning{Columns: []clause.Column{{Name: "message_hash"}}})
+	deleteDB = deleteDB.Where("layer = ? AND block_number >= ?", layer, startBlockNumber)
+	if err := deleteDB.Delete(&rolledBackEvents).Error; err != nil {
+		return fmt.Errorf("FailedRelayedMessage.RollbackFailedRelayedMessages delete events failed, layer: %v, start block number: %v, err: %w", layer, startBlockNumber, err)
+	}
+
+	rolledBackCounts := make(map[string]uint64)
+	for _, event := range rolledBackEvents {
+		rolledBackCounts[event.MessageHash]++
+	}
+
+	for messageHash, rolledBackCount := range rolledBackCounts {
+		var lastEvent FailedRelayedMessageEvent
+		lastDB := db.Where("message_hash = ? AND layer = ?", messageHash, layer)
+		lastDB = lastDB.Order("block_number desc, log_index desc")
+		if err := lastDB.Limit(1).Find(&lastEvent).Error; err != nil {
+			return fmt.Errorf("FailedRelayedMessage.RollbackFailedRelayedMessages get last event failed, message hash: %v, err: %w", messageHash, err)
+		}
+
+		updateFields := map[string]interface{}{
+			"failed_count": gorm.Expr("failed_count - ?", rolledBackCount),
+		}
+		// the failures recorded before the events aren't rolled back, keep their last failure as it is.
+		if lastEvent.ID != 0 {
+			updateFields["last_failed_block_number"] = lastEvent.BlockNumber
+			updateFields["last_failed_tx_hash"] = lastEvent.TxHash
+			updateFields["last_failed_at"] = lastEvent.BlockTime
+		}
+
+		updateDB := db.Model(&FailedRelayedMessage{}).Where("message_hash = ? AND layer = ?", messageHash, layer)
+		if err := updateDB.Updates(updateFields).Error; err != nil {
+			return fmt.Errorf("FailedRelayedMessage.RollbackFailedRelayedMessages update failed, message hash: %v, err: %w", messageHash, err)
+		}
+	}
+
+	deleteDB = db.Unscoped().Where("layer = ? AND failed_count = 0", layer)
+	if err := deleteDB.Delete(&FailedRelayedMessage{}).Error; err != nil {
+		return fmt.Errorf("FailedRelayedMessage.RollbackFailedRelayedMessages delete failed, layer: %v, err: %w", layer, err)
+	}
+	return nil
+}
+
+// UpdateStatus updates the status for the failed relayed messages with the provided ids.
+func (m *FailedRelayedMessage) UpdateStatus(ctx context.Context, ids []int64, status types.FailedRelayedMessageStatus) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	db := m.db.WithContext(ctx)
+	db = db.Model(&FailedRelayedMessage{})
+	db = db.Where("id in (?)", ids)
+
+	updateFields := map[string]interface{}{
+		"status":            status,
+		"status_updated_at": utils.NowUTC(),
+	}
+
+	if err := db.Updates(updateFields).Error; err != nil {
+		log.Warn("FailedRelayedMessage.UpdateStatus failed", "error", err)
+		return fmt.Errorf("FailedRelayedMessage.UpdateStatus failed err:%w", err)
+	}
+	return nil
+}
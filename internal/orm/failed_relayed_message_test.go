@@ -0,0 +1,134 @@
+package orm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scroll-tech/chain-monitor/internal/types"
+	"github.com/scroll-tech/chain-monitor/internal/utils/testcontainer"
+)
+
+func TestFailedRelayedMessage_InsertOrUpdateFailedRelayedMessage(t *testing.T) {
+	ctx := context.Background()
+	db := testcontainer.SetupDB(ctx, t)
+	failedRelayedMessageOrm := NewFailedRelayedMessage(db)
+
+	blockTime := time.Unix(1700000000, 0).UTC()
+	tests := []struct {
+		name string
+		test func(t *testing.T)
+	}{
+		{
+			"insertFailedRelayedMessage", func(t *testing.T) {
+				failedEvent := FailedRelayedMessageEvent{
+					MessageHash: "0x1",
+					Layer:       int(types.Layer2),
+					BlockNumber: 200,
+					TxHash:      "0x1c7d3ea5ec8dc9b664a5a886c3b33d21e665355057601033481a439498efb79a",
+					LogIndex:    1,
+					BlockTime:   blockTime.Add(time.Hour),
+				}
+				affectRows, err := failedRelayedMessageOrm.InsertOrUpdateFailedRelayedMessage(ctx, failedEvent)
+				assert.NoError(t, err)
+				assert.Equal(t, affectRows, int64(1))
+			},
+		},
+		{
+			"accumulateEarlierFailedRelayedMessage", func(t *testing.T) {
+				for _, logIndex := range []uint{1, 2} {
+					failedEvent := FailedRelayedMessageEvent{
+						MessageHash: "0x1",
+						Layer:       int(types.Layer2),
+						BlockNumber: 100,
+						TxHash:      "0xfc7d3ea5ec8dc9b664a5a886c3b33d21e665355057601033481a439498efb79a",
+						LogIndex:    logIndex,
+						BlockTime:   blockTime,
+					}
+					affectRows, err := failedRelayedMessageOrm.InsertOrUpdateFailedRelayedMessage(ctx, failedEvent)
+					assert.NoError(t, err)
+					assert.Equal(t, affectRows, int64(1))
+				}
+			},
+		},
+		{
+			"rescanFailedRelayedMessage", func(t *testing.T) {
+				failedEvent := FailedRelayedMessageEvent{
+					MessageHash: "0x1",
+					Layer:       int(types.Layer2),
+					BlockNumber: 200,
+					TxHash:      "0x1c7d3ea5ec8dc9b664a5a886c3b33d21e665355057601033481a439498efb79a",
+					LogIndex:    1,
+					BlockTime:   blockTime.Add(time.Hour),
+				}
+				affectRows, err := failedRelayedMessageOrm.InsertOrUpdateFailedRelayedMessage(ctx, failedEvent)
+				assert.NoError(t, err)
+				assert.Equal(t, affectRows, int64(0))
+			},
+		},
+		{
+			"getFailedRelayedMessage", func(t *testing.T) {
+				failedMsgs, err := failedRelayedMessageOrm.GetUnalertedFailedRelayedMessages(ctx, types.Layer2, 10)
+				assert.NoError(t, err)
+				assert.Len(t, failedMsgs, 1)
+				assert.Equal(t, failedMsgs[0].FailedCount, uint64(3))
+				assert.Equal(t, failedMsgs[0].FirstFailedBlockNumber, uint64(100))
+				assert.Equal(t, failedMsgs[0].LastFailedBlockNumber, uint64(200))
+				assert.Equal(t, failedMsgs[0].LastFailedTxHash, "0x1c7d3ea5ec8dc9b664a5a886c3b33d21e665355057601033481a439498efb79a")
+				assert.True(t, failedMsgs[0].FirstFailedAt.Equal(blockTime))
+				assert.True(t, failedMsgs[0].LastFailedAt.Equal(blockTime.Add(time.Hour)))
+			},
+		},
+		{
+			"rollbackFailedRelayedMessage", func(t *testing.T) {
+				err := failedRelayedMessageOrm.RollbackFailedRelayedMessages(ctx, types.Layer2, 150)
+				assert.NoError(t, err)
+
+				failedMsgs, err := failedRelayedMessageOrm.GetUnalertedFailedRelayedMessages(ctx, types.Layer2, 10)
+				assert.NoError(t, err)
+				assert.Len(t, failedMsgs, 1)
+				assert.Equal(t, failedMsgs[0].FailedCount, uint64(2))
+				assert.Equal(t, failedMsgs[0].LastFailedBlockNumber, uint64(100))
+				assert.Equal(t, failedMsgs[0].LastFailedTxHash, "0xfc7d3ea5ec8dc9b664a5a886c3b33d21e665355057601033481a439498efb79a")
+				assert.True(t, failedMsgs[0].LastFailedAt.Equal(blockTime))
+
+				err = failedRelayedMessageOrm.RollbackFailedRelayedMessages(ctx, types.Layer2, 100)
+				assert.NoError(t, err)
+
+				failedMsgs, err = failedRelayedMessageOrm.GetUnalertedFailedRelayedMessages(ctx, types.Layer2, 10)
+				assert.NoError(t, err)
+				assert.Len(t, failedMsgs, 0)
+			},
+		},
+		{
+			"updateFailedRelayedMessageStatus", func(t *testing.T) {
+				failedEvent := FailedRelayedMessageEvent{
+					MessageHash: "0x2",
+					Layer:       int(types.Layer2),
+					BlockNumber: 300,
+					TxHash:      "0x2c7d3ea5ec8dc9b664a5a886c3b33d21e665355057601033481a439498efb79a",
+					BlockTime:   blockTime,
+				}
+				_, err := failedRelayedMessageOrm.InsertOrUpdateFailedRelayedMessage(ctx, failedEvent)
+				assert.NoError(t, err)
+
+				failedMsgs, err := failedRelayedMessageOrm.GetUnalertedFailedRelayedMessages(ctx, types.Layer2, 10)
+				assert.NoError(t, err)
+				assert.Len(t, failedMsgs, 1)
+
+				err = failedRelayedMessageOrm.UpdateStatus(ctx, []int64{failedMsgs[0].ID}, types.FailedRelayedMessageStatusTypeAlerted)
+				assert.NoError(t, err)
+
+				failedMsgs, err = failedRelayedMessageOrm.GetUnalertedFailedRelayedMessages(ctx, types.Layer2, 10)
+				assert.NoError(t, err)
+				assert.Len(t, failedMsgs, 0)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, test.test)
+	}
+}
@@ -3,9 +3,11 @@ package orm
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/scroll-tech/go-ethereum/log"
+	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 
@@ -22,24 +24,28 @@ type GatewayMessageMatch struct {
 	TokenType   int    `json:"token_type" gorm:"token_type"`
 
 	// l1 event info
-	L1EventType   int    `json:"l1_event_type" gorm:"l1_event_type"`
-	L1BlockNumber uint64 `json:"l1_block_number" gorm:"l1_block_number"`
-	L1TxHash      string `json:"l1_tx_hash" gorm:"l1_tx_hash"`
-	L1TokenIds    string `json:"l1_token_ids" gorm:"l1_token_ids"`
-	L1Amounts     string `json:"l1_amounts" gorm:"l1_amounts"`
+	L1EventType    int    `json:"l1_event_type" gorm:"l1_event_type"`
+	L1BlockNumber  uint64 `json:"l1_block_number" gorm:"l1_block_number"`
+	L1TxHash       string `json:"l1_tx_hash" gorm:"l1_tx_hash"`
+	L1TokenAddress string `json:"l1_token_address" gorm:"l1_token_address"`
+	L1TokenIds     string `json:"l1_token_ids" gorm:"l1_token_ids"`
+	L1Amounts      string `json:"l1_amounts" gorm:"l1_amounts"`
 
 	// l2 event info
-	L2EventType   int    `json:"l2_event_type" gorm:"l2_event_type"`
-	L2BlockNumber uint64 `json:"l2_block_number" gorm:"l2_block_number"`
-	L2TxHash      string `json:"l2_tx_hash" gorm:"l2_tx_hash"`
-	L2TokenIds    string `json:"l2_token_ids" gorm:"l2_token_ids"`
-	L2Amounts     string `json:"l2_amounts" gorm:"l2_amounts"`
+	L2EventType    int    `json:"l2_event_type" gorm:"l2_event_type"`
+	L2BlockNumber  uint64 `json:"l2_block_number" gorm:"l2_block_number"`
+	L2TxHash       string `json:"l2_tx_hash" gorm:"l2_tx_hash"`
+	L2TokenAddress string `json:"l2_token_address" gorm:"l2_token_address"`
+	L2TokenIds     string `json:"l2_token_ids" gorm:"l2_token_ids"`
+	L2Amounts      string `json:"l2_amounts" gorm:"l2_amounts"`
 
 	// status
 	L1BlockStatus      int `json:"l1_block_status" gorm:"l1_block_status"`
 	L2BlockStatus      int `json:"l2_block_status" gorm:"l2_block_status"`
 	L1CrossChainStatus int `json:"l1_cross_chain_status" gorm:"l1_cross_chain_status"`
 	L2CrossChainStatus int `json:"l2_cross_chain_status" gorm:"l2_cross_chain_status"`
+	// the reason of the failed block status checks, empty if the checks are passed.
+	FailureReason string `json:"failure_reason" gorm:"failure_reason"`
 
 	L1BlockStatusUpdatedAt      time.Time      `json:"l1_block_status_updated_at" gorm:"l1_block_status_updated_at"`
 	L2BlockStatusUpdatedAt      time.Time      `json:"l2_block_status_updated_at" gorm:"l2_block_status_updated_at"`
@@ -81,9 +87,9 @@ func (m *GatewayMessageMatch) GetUncheckedAndDoubleLayerValidGatewayMessageMatch
 	db = db.Where("l2_block_status = ?", types.BlockStatusTypeValid)
 	switch layer {
 	case types.Layer1:
-		db = db.Where("l1_cross_chain_status = ?", types.CrossChainStatusTypeInvalid)
+		db = db.Where("l1_cross_chain_status = ?", types.CrossChainStatusTypeUnchecked)
 	case types.Layer2:
-		db = db.Where("l2_cross_chain_status = ?", types.CrossChainStatusTypeInvalid)
+		db = db.Where("l2_cross_chain_status = ?", types.CrossChainStatusTypeUnchecked)
 	}
 	db = db.Limit(limit)
 	if err := db.Find(&messages).Error; err != nil {
@@ -93,8 +99,106 @@ func (m *GatewayMessageMatch) GetUncheckedAndDoubleLayerValidGatewayMessageMatch
 	return messages, nil
 }
 
+// GetGatewayMessageMatchesByMessageHashes get GatewayMessageMatches by message_hash list
+func (m *GatewayMessageMatch) GetGatewayMessageMatchesByMessageHashes(ctx context.Context, msgHashes []string) ([]GatewayMessageMatch, error) {
+	var messages []GatewayMessageMatch
+	db := m.db.WithContext(ctx)
+	db = db.Where("message_hash in (?)", msgHashes)
+	if err := db.Find(&messages).Error; err != nil {
+		log.Warn("GatewayMessageMatch.GetGatewayMessageMatchesByMessageHashes failed", "error", err)
+		return nil, fmt.Errorf("GatewayMessageMatch.GetGatewayMessageMatchesByMessageHashes failed err:%w", err)
+	}
+	return messages, nil
+}
+
+// GetGatewayMessageMatchesByTxHash get the GatewayMessageMatches whose l1 or l2 tx hash is txHash
+func (m *GatewayMessageMatch) GetGatewayMessageMatchesByTxHash(ctx context.Context, txHash string) ([]GatewayMessageMatch, error) {
+	var messages []GatewayMessageMatch
+	db := m.db.WithContext(ctx)
+	db = db.Where("(l1_tx_hash = ? OR l2_tx_hash = ?)", txHash, txHash)
+	db = db.Order("id asc")
+	if err := db.Find(&messages).Error; err != nil {
+		log.Warn("GatewayMessageMatch.GetGatewayMessageMatchesByTxHash failed", "error", err)
+		return nil, fmt.Errorf("GatewayMessageMatch.GetGatewayMessageMatchesByTxHash failed err:%w", err)
+	}
+	return messages, nil
+}
+
+// ListGatewayMessageMatches lists the GatewayMessageMatches matching the filter from the latest one, and returns the total count of them.
+func (m *GatewayMessageMatch) ListGatewayMessageMatches(ctx context.Context, filter MessageMatchFilter, offset, limit int) ([]GatewayMessageMatch, int64, error) {
+	db := m.db.WithContext(ctx)
+	db = db.Model(&GatewayMessageMatch{})
+	db = filter.apply(db)
+	if filter.TokenType != nil {
+		db = db.Where("token_type = ?", *filter.TokenType)
+	}
+	if filter.TokenAddress != "" {
+		db = db.Where("(l1_token_address = ? OR l2_token_address = ?)", filter.TokenAddress, filter.TokenAddress)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		log.Warn("GatewayMessageMatch.ListGatewayMessageMatches count failed", "error", err)
+		return nil, 0, fmt.Errorf("GatewayMessageMatch.ListGatewayMessageMatches count failed err:%w", err)
+	}
+
+	var messages []GatewayMessageMatch
+	db = db.Order("id desc")
+	db = db.Offset(offset)
+	db = db.Limit(limit)
+	if err := db.Find(&messages).Error; err != nil {
+		log.Warn("GatewayMessageMatch.ListGatewayMessageMatches failed", "error", err)
+		return nil, 0, fmt.Errorf("GatewayMessageMatch.ListGatewayMessageMatches failed err:%w", err)
+	}
+	return messages, total, nil
+}
+
+// GetFinalizedDepositL2TokenAddresses get the distinct l2 token addresses of the finalized erc20 deposits, which are the l2 bridged tokens.
+func (m *GatewayMessageMatch) GetFinalizedDepositL2TokenAddresses(ctx context.Context) ([]string, error) {
+	var tokenAddresses []string
+	db := m.db.WithContext(ctx)
+	db = db.Model(&GatewayMessageMatch{})
+	db = db.Where("l2_event_type = ? AND l2_token_address <> ''", int(types.L2FinalizeDepositERC20))
+	db = db.Distinct("l2_token_address")
+	if err := db.Pluck("l2_token_address", &tokenAddresses).Error; err != nil {
+		log.Warn("GatewayMessageMatch.GetFinalizedDepositL2TokenAddresses failed", "error", err)
+		return nil, fmt.Errorf("GatewayMessageMatch.GetFinalizedDepositL2TokenAddresses failed err:%w", err)
+	}
+	return tokenAddresses, nil
+}
+
+// GetERC20InFlightAmounts get the amounts of the erc20 token pair which are bridged on one layer but not the other yet,
+// as of the l1 block number and the l2 block number. The pending deposits are locked on l1 but not minted on l2 yet,
+// and the pending withdrawals are burned on l2 but not released on l1 yet.
+func (m *GatewayMessageMatch) GetERC20InFlightAmounts(ctx context.Context, l1TokenAddress, l2TokenAddress string, l1BlockNumber, l2BlockNumber uint64) (decimal.Decimal, decimal.Decimal, error) {
+	db := m.db.WithContext(ctx)
+	db = db.Model(&GatewayMessageMatch{})
+	db = db.Select("coalesce(sum(nullif(l1_amounts, '')::numeric) filter (where l1_event_type = ? AND l1_token_address = ? AND l1_block_number > 0 AND l1_block_number <= ? AND (l2_block_number = 0 OR l2_block_number > ?)), 0), "+
+		"coalesce(sum(nullif(l2_amounts, '')::numeric) filter (where l2_event_type = ? AND l2_token_address = ? AND l2_block_number > 0 AND l2_block_number <= ? AND (l1_block_number = 0 OR l1_block_number > ?)), 0)",
+		int(types.L1DepositERC20), l1TokenAddress, l1BlockNumber, l2BlockNumber,
+		int(types.L2WithdrawERC20), l2TokenAddress, l2BlockNumber, l1BlockNumber)
+	db = db.Where("token_type = ?", int(types.TokenTypeERC20))
+	db = db.Where("(l1_token_address = ? OR l2_token_address = ?)", l1TokenAddress, l2TokenAddress)
+
+	var pendingDeposits, pendingWithdrawals decimal.Decimal
+	if err := db.Row().Scan(&pendingDeposits, &pendingWithdrawals); err != nil {
+		log.Warn("GatewayMessageMatch.GetERC20InFlightAmounts failed", "error", err)
+		return decimal.Zero, decimal.Zero, fmt.Errorf("GatewayMessageMatch.GetERC20InFlightAmounts failed err:%w", err)
+	}
+	return pendingDeposits, pendingWithdrawals, nil
+}
+
 // InsertOrUpdateEventInfo insert or update event info
 func (m *GatewayMessageMatch) InsertOrUpdateEventInfo(ctx context.Context, layer types.LayerType, message GatewayMessageMatch, dbTX ...*gorm.DB) (int64, error) {
+	return m.insertOrUpdateEventInfo(ctx, layer, message, false, dbTX...)
+}
+
+// UpsertEventInfo insert or overwrite the event info of the layer, even if the event of the layer has been recorded.
+func (m *GatewayMessageMatch) UpsertEventInfo(ctx context.Context, layer types.LayerType, message GatewayMessageMatch, dbTX ...*gorm.DB) (int64, error) {
+	return m.insertOrUpdateEventInfo(ctx, layer, message, true, dbTX...)
+}
+
+func (m *GatewayMessageMatch) insertOrUpdateEventInfo(ctx context.Context, layer types.LayerType, message GatewayMessageMatch, overwrite bool, dbTX ...*gorm.DB) (int64, error) {
 	db := m.db
 	if len(dbTX) > 0 && dbTX[0] != nil {
 		db = dbTX[0]
@@ -102,15 +206,20 @@ func (m *GatewayMessageMatch) InsertOrUpdateEventInfo(ctx context.Context, layer
 
 	db = db.WithContext(ctx)
 	db = db.Model(&GatewayMessageMatch{})
-	var assignmentColumn clause.Set
+	var columns []string
 	var where clause.Where
 	if layer == types.Layer1 {
-		assignmentColumn = clause.AssignmentColumns([]string{"token_type", "l1_block_number", "l1_tx_hash", "l1_event_type", "l1_token_ids", "l1_amounts", "l1_block_status", "l1_block_status_updated_at"})
+		columns = []string{"token_type", "l1_block_number", "l1_tx_hash", "l1_event_type", "l1_token_address", "l1_token_ids", "l1_amounts", "l1_block_status", "l1_block_status_updated_at"}
 		where = clause.Where{Exprs: []clause.Expression{clause.Eq{Column: "gateway_message_match.l1_block_number", Value: 0}}}
 	} else {
-		assignmentColumn = clause.AssignmentColumns([]string{"token_type", "l2_block_number", "l2_tx_hash", "l2_event_type", "l2_token_ids", "l2_amounts", "l2_block_status", "l2_block_status_updated_at"})
+		columns = []string{"token_type", "l2_block_number", "l2_tx_hash", "l2_event_type", "l2_token_address", "l2_token_ids", "l2_amounts", "l2_block_status", "l2_block_status_updated_at"}
 		where = clause.Where{Exprs: []clause.Expression{clause.Eq{Column: "gateway_message_match.l2_block_number", Value: 0}}}
 	}
+	assignmentColumn := append(clause.AssignmentColumns(columns), failureReasonAssignment(m.TableName(), layer, overwrite))
+
+	if overwrite {
+		where = eventInfoChangedWhere(m.TableName(), columns)
+	}
 
 	db = db.Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "message_hash"}},
@@ -152,6 +261,36 @@ func (m *GatewayMessageMatch) UpdateCrossChainStatus(ctx context.Context, id []i
 	return nil
 }
 
+// UpdateCrossChainStatusFailed marks the cross chain status of the message match as failed with the failure reason. The
+// failed message match is only checked again once its events are rolled back, see RollbackEventInfo.
+func (m *GatewayMessageMatch) UpdateCrossChainStatusFailed(ctx context.Context, id int64, layer types.LayerType, failureReason string) error {
+	db := m.db.WithContext(ctx)
+	db = db.Model(&GatewayMessageMatch{})
+	db = db.Where("id = ?", id)
+
+	var updateFields map[string]interface{}
+	switch layer {
+	case types.Layer1:
+		updateFields = map[string]interface{}{
+			"l1_cross_chain_status":            types.CrossChainStatusTypeFailed,
+			"l1_cross_chain_status_updated_at": utils.NowUTC(),
+			"failure_reason":                   setFailureReason(failureReason),
+		}
+	case types.Layer2:
+		updateFields = map[string]interface{}{
+			"l2_cross_chain_status":            types.CrossChainStatusTypeFailed,
+			"l2_cross_chain_status_updated_at": utils.NowUTC(),
+			"failure_reason":                   setFailureReason(failureReason),
+		}
+	}
+
+	if err := db.Updates(updateFields).Error; err != nil {
+		log.Warn("GatewayMessageMatch.UpdateCrossChainStatusFailed failed", "error", err)
+		return fmt.Errorf("GatewayMessageMatch.UpdateCrossChainStatusFailed failed err:%w", err)
+	}
+	return nil
+}
+
 // UpdateBlockStatus updates the block status for the given layer and block number range.
 func (m *GatewayMessageMatch) UpdateBlockStatus(ctx context.Context, layer types.LayerType, startBlockNumber, endBlockNumber uint64, dbTX ...*gorm.DB) error {
 	db := m.db
@@ -183,3 +322,136 @@ func (m *GatewayMessageMatch) UpdateBlockStatus(ctx context.Context, layer types
 	}
 	return nil
 }
+
+// RollbackEventInfo rollbacks the event info of the layer whose block number >= startBlockNumber after a chain reorganization.
+// The message matches which only contain the rolled back layer event are deleted, the others reset the event info and statuses of the layer.
+func (m *GatewayMessageMatch) RollbackEventInfo(ctx context.Context, layer types.LayerType, startBlockNumber uint64, dbTX ...*gorm.DB) error {
+	db := m.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+
+	var deleteDB, updateDB *gorm.DB
+	var updateFields map[string]interface{}
+	switch layer {
+	case types.Layer1:
+		deleteDB = db.Where("l1_block_number >= ? AND l2_block_number = 0", startBlockNumber)
+		updateDB = db.Model(&GatewayMessageMatch{}).Where("l1_block_number >= ?", startBlockNumber)
+		updateFields = map[string]interface{}{
+			"l1_event_type":                    types.EventTypeUnknown,
+			"l1_block_number":                  0,
+			"l1_tx_hash":                       "",
+			"l1_token_address":                 "",
+			"l1_token_ids":                     "",
+			"l1_amounts":                       "",
+			"l1_block_status":                  types.BlockStatusTypeUnchecked,
+			"failure_reason":                   rollbackFailureReasons(types.Layer1),
+			"l1_cross_chain_status":            types.CrossChainStatusTypeUnchecked,
+			"l2_cross_chain_status":            types.CrossChainStatusTypeUnchecked,
+			"l1_block_status_updated_at":       utils.NowUTC(),
+			"l1_cross_chain_status_updated_at": utils.NowUTC(),
+			"l2_cross_chain_status_updated_at": utils.NowUTC(),
+		}
+	case types.Layer2:
+		deleteDB = db.Where("l2_block_number >= ? AND l1_block_number = 0", startBlockNumber)
+		updateDB = db.Model(&GatewayMessageMatch{}).Where("l2_block_number >= ?", startBlockNumber)
+		updateFields = map[string]interface{}{
+			"l2_event_type":                    types.EventTypeUnknown,
+			"l2_block_number":                  0,
+			"l2_tx_hash":                       "",
+			"l2_token_address":                 "",
+			"l2_token_ids":                     "",
+			"l2_amounts":                       "",
+			"l2_block_status":                  types.BlockStatusTypeUnchecked,
+			"failure_reason":                   rollbackFailureReasons(types.Layer2),
+			"l1_cross_chain_status":            types.CrossChainStatusTypeUnchecked,
+			"l2_cross_chain_status":            types.CrossChainStatusTypeUnchecked,
+			"l2_block_status_updated_at":       utils.NowUTC(),
+			"l1_cross_chain_status_updated_at": utils.NowUTC(),
+			"l2_cross_chain_status_updated_at": utils.NowUTC(),
+		}
+	default:
+		return fmt.Errorf("GatewayMessageMatch.RollbackEventInfo invalid layer: %v", layer)
+	}
+
+	if err := deleteDB.Unscoped().Delete(&GatewayMessageMatch{}).Error; err != nil {
+		return fmt.Errorf("GatewayMessageMatch.RollbackEventInfo delete failed, layer: %v, start block number: %v, err: %w", layer, startBlockNumber, err)
+	}
+
+	if err := updateDB.Updates(updateFields).Error; err != nil {
+		return fmt.Errorf("GatewayMessageMatch.RollbackEventInfo update failed, layer: %v, start block number: %v, err: %w", layer, startBlockNumber, err)
+	}
+	return nil
+}
+
+// eventInfoChangedWhere only lets the overwrite on conflict through when the stored event info differs from the
+// inserted one or the inserted failure reason isn't recorded yet, so the rows which are unchanged by a rescan keep
+// their statuses and update times.
+func eventInfoChangedWhere(tableName string, columns []string) clause.Where {
+	var stored, excluded []string
+	for _, column := range columns {
+		// the update times and the statuses of the later checks are not part of the event info.
+		if strings.HasSuffix(column, "_updated_at") || column == "eth_amount_status" {
+			continue
+		}
+		stored = append(stored, tableName+"."+column)
+		excluded = append(excluded, "excluded."+column)
+	}
+	return clause.Where{Exprs: []clause.Expression{
+		clause.Expr{SQL: fmt.Sprintf("((%s) IS DISTINCT FROM (%s) OR (excluded.failure_reason <> '' AND position(excluded.failure_reason IN %s.failure_reason) = 0))",
+			strings.Join(stored, ", "), strings.Join(excluded, ", "), tableName)},
+	}}
+}
+
+// failureReasonSeparator separates the failure reasons of the checks recorded in a message match. Each failure reason is
+// keyed by its check, the text before the first ": ", such as "Layer1" for the event checks of layer 1 and "Layer2 cross
+// chain check" for the cross chain check of layer 2. The later failure of a check replaces the recorded one of the check,
+// and the failure reasons of the other checks are kept.
+const failureReasonSeparator = "; "
+
+// setFailureReasonSQL returns the SQL which sets the failure reason into the ones recorded in the stored column, the
+// empty failure reason keeps the recorded ones.
+func setFailureReasonSQL(stored, failureReason string) string {
+	return fmt.Sprintf("CASE WHEN %[2]s = '' THEN %[1]s ELSE array_to_string(ARRAY(SELECT r FROM unnest(string_to_array(%[1]s, '%[3]s')) AS r "+
+		"WHERE split_part(r, ': ', 1) <> split_part(%[2]s, ': ', 1)) || ARRAY[%[2]s], '%[3]s') END", stored, failureReason, failureReasonSeparator)
+}
+
+// removeFailureReasonsSQL returns the SQL which removes the failure reasons of the checks from the ones recorded in the
+// stored column.
+func removeFailureReasonsSQL(stored string, checks ...string) string {
+	quoted := make([]string, 0, len(checks))
+	for _, check := range checks {
+		quoted = append(quoted, "'"+check+"'")
+	}
+	return fmt.Sprintf("array_to_string(ARRAY(SELECT r FROM unnest(string_to_array(%[1]s, '%[2]s')) AS r WHERE split_part(r, ': ', 1) NOT IN (%[3]s)), '%[2]s')",
+		stored, failureReasonSeparator, strings.Join(quoted, ", "))
+}
+
+// setFailureReason sets the failure reason of a check into the recorded ones.
+func setFailureReason(failureReason string) clause.Expr {
+	return gorm.Expr(setFailureReasonSQL("failure_reason", "CAST(? AS TEXT)"), failureReason, failureReason, failureReason)
+}
+
+// rollbackFailureReasons removes the failure reasons of the checks reset by the rollback of the layer, which are the
+// checks of the layer and the cross chain checks of both layers.
+func rollbackFailureReasons(layer types.LayerType) clause.Expr {
+	checks := []string{layer.String(), layer.String() + " eth balance check", types.Layer1.String() + " cross chain check", types.Layer2.String() + " cross chain check"}
+	if layer == types.Layer2 {
+		checks = append(checks, types.Layer2.String()+" withdraw root check")
+	}
+	return gorm.Expr(removeFailureReasonsSQL("failure_reason", checks...))
+}
+
+// failureReasonAssignment sets the failure reason of the event checks of the layer on conflict. The overwritten event
+// info drops the failure reason of the event checks recorded before, the failure reasons of the other checks are kept.
+func failureReasonAssignment(tableName string, layer types.LayerType, overwrite bool) clause.Assignment {
+	stored := tableName + ".failure_reason"
+	if overwrite {
+		stored = removeFailureReasonsSQL(stored, layer.String())
+	}
+	return clause.Assignment{
+		Column: clause.Column{Name: "failure_reason"},
+		Value:  gorm.Expr(setFailureReasonSQL(stored, "excluded.failure_reason")),
+	}
+}
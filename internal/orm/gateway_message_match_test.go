@@ -79,9 +79,243 @@ func TestGatewayMessageMatch_InsertOrUpdateEventInfo(t *testing.T) {
 				assert.Equal(t, affectRows, int64(0))
 			},
 		},
+		{
+			name: "failureReasonKeptAndRolledBack",
+			test: func(t *testing.T) {
+				l1EventMsg := GatewayMessageMatch{
+					MessageHash:   "0x3",
+					TokenType:     int(types.TokenTypeERC20),
+					L1EventType:   int(types.L1DepositERC20),
+					L1BlockNumber: 130,
+					L1TxHash:      "0x1c7d3ea5ec8dc9b664a5a886c3b33d21e665355057601033481a439498efb79a",
+					L1Amounts:     "200000000",
+					L1BlockStatus: int(types.BlockStatusTypeFailed),
+					FailureReason: "Layer1: the transfer event's balance don't match the balance of gateway event",
+				}
+				affectRows, err := gatewayMessageMatchOrm.InsertOrUpdateEventInfo(ctx, types.Layer1, l1EventMsg)
+				assert.NoError(t, err)
+				assert.Equal(t, affectRows, int64(1))
+
+				// the l2 event passing the checks keeps the failure reason of l1.
+				l2EventMsg := GatewayMessageMatch{
+					MessageHash:   "0x3",
+					TokenType:     int(types.TokenTypeERC20),
+					L2EventType:   int(types.L2FinalizeDepositERC20),
+					L2BlockNumber: 1300,
+					L2TxHash:      "0x2c7d3ea5ec8dc9b664a5a886c3b33d21e665355057601033481a439498efb79a",
+					L2Amounts:     "200000000",
+					L2BlockStatus: int(types.BlockStatusTypeValid),
+				}
+				affectRows, err = gatewayMessageMatchOrm.InsertOrUpdateEventInfo(ctx, types.Layer2, l2EventMsg)
+				assert.NoError(t, err)
+				assert.Equal(t, affectRows, int64(1))
+
+				messages, err := gatewayMessageMatchOrm.GetGatewayMessageMatchesByMessageHashes(ctx, []string{"0x3"})
+				assert.NoError(t, err)
+				assert.Len(t, messages, 1)
+				assert.Equal(t, int(types.BlockStatusTypeFailed), messages[0].L1BlockStatus)
+				assert.Equal(t, int(types.BlockStatusTypeValid), messages[0].L2BlockStatus)
+				assert.Equal(t, l1EventMsg.FailureReason, messages[0].FailureReason)
+
+				// rolling back l2 keeps the failure reason of l1, rolling back l1 clears it.
+				assert.NoError(t, gatewayMessageMatchOrm.RollbackEventInfo(ctx, types.Layer2, 1300))
+				messages, err = gatewayMessageMatchOrm.GetGatewayMessageMatchesByMessageHashes(ctx, []string{"0x3"})
+				assert.NoError(t, err)
+				assert.Len(t, messages, 1)
+				assert.Equal(t, l1EventMsg.FailureReason, messages[0].FailureReason)
+
+				affectRows, err = gatewayMessageMatchOrm.InsertOrUpdateEventInfo(ctx, types.Layer2, l2EventMsg)
+				assert.NoError(t, err)
+				assert.Equal(t, affectRows, int64(1))
+				assert.NoError(t, gatewayMessageMatchOrm.RollbackEventInfo(ctx, types.Layer1, 130))
+				messages, err = gatewayMessageMatchOrm.GetGatewayMessageMatchesByMessageHashes(ctx, []string{"0x3"})
+				assert.NoError(t, err)
+				assert.Len(t, messages, 1)
+				assert.Equal(t, int(types.BlockStatusTypeUnchecked), messages[0].L1BlockStatus)
+				assert.Equal(t, "", messages[0].FailureReason)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, test.test)
+	}
+}
+
+func TestGatewayMessageMatch_UpsertEventInfo(t *testing.T) {
+	ctx := context.Background()
+	db := testcontainer.SetupDB(ctx, t)
+	gatewayMessageMatchOrm := NewGatewayMessageMatch(db)
+
+	tests := []struct {
+		name string
+		test func(t *testing.T)
+	}{
+		{
+			name: "l1ERC20DepositOverwritten",
+			test: func(t *testing.T) {
+				l1EventMsg := GatewayMessageMatch{
+					MessageHash:   "0x2",
+					TokenType:     int(types.TokenTypeERC20),
+					L1EventType:   int(types.L1DepositERC20),
+					L1BlockNumber: 120,
+					L1TxHash:      "0xfc7d3ea5ec8dc9b664a5a886c3b33d21e665355057601033481a439498efb79a",
+					L1Amounts:     "200000000",
+				}
+				affectRows, err := gatewayMessageMatchOrm.InsertOrUpdateEventInfo(ctx, types.Layer1, l1EventMsg)
+				assert.NoError(t, err)
+				assert.Equal(t, affectRows, int64(1))
+
+				l1EventMsg.L1Amounts = "100000000"
+				affectRows, err = gatewayMessageMatchOrm.UpsertEventInfo(ctx, types.Layer1, l1EventMsg)
+				assert.NoError(t, err)
+				assert.Equal(t, affectRows, int64(1))
+
+				messages, err := gatewayMessageMatchOrm.GetGatewayMessageMatchesByMessageHashes(ctx, []string{"0x2"})
+				assert.NoError(t, err)
+				assert.Len(t, messages, 1)
+				assert.Equal(t, "100000000", messages[0].L1Amounts)
+			},
+		},
+		{
+			name: "l1ERC20DepositUnchanged",
+			test: func(t *testing.T) {
+				messages, err := gatewayMessageMatchOrm.GetGatewayMessageMatchesByMessageHashes(ctx, []string{"0x2"})
+				assert.NoError(t, err)
+				assert.Len(t, messages, 1)
+				err = gatewayMessageMatchOrm.UpdateCrossChainStatus(ctx, []int64{messages[0].ID}, types.Layer1, types.CrossChainStatusTypeValid)
+				assert.NoError(t, err)
+
+				l1EventMsg := GatewayMessageMatch{
+					MessageHash:   "0x2",
+					TokenType:     int(types.TokenTypeERC20),
+					L1EventType:   int(types.L1DepositERC20),
+					L1BlockNumber: 120,
+					L1TxHash:      "0xfc7d3ea5ec8dc9b664a5a886c3b33d21e665355057601033481a439498efb79a",
+					L1Amounts:     "100000000",
+				}
+				affectRows, err := gatewayMessageMatchOrm.UpsertEventInfo(ctx, types.Layer1, l1EventMsg)
+				assert.NoError(t, err)
+				assert.Equal(t, affectRows, int64(0))
+
+				messages, err = gatewayMessageMatchOrm.GetGatewayMessageMatchesByMessageHashes(ctx, []string{"0x2"})
+				assert.NoError(t, err)
+				assert.Len(t, messages, 1)
+				assert.Equal(t, int(types.CrossChainStatusTypeValid), messages[0].L1CrossChainStatus)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, test.test)
+	}
+}
+
+func TestGatewayMessageMatch_Query(t *testing.T) {
+	ctx := context.Background()
+	db := testcontainer.SetupDB(ctx, t)
+	gatewayMessageMatchOrm := NewGatewayMessageMatch(db)
+
+	messages := []GatewayMessageMatch{
+		{MessageHash: "0x1", TokenType: int(types.TokenTypeETH), L1EventType: int(types.L1DepositETH), L1BlockNumber: 100, L1TxHash: "0xa1", L1Amounts: "1"},
+		{MessageHash: "0x2", TokenType: int(types.TokenTypeERC20), L1EventType: int(types.L1DepositERC20), L1BlockNumber: 101, L1TxHash: "0xa1", L1TokenAddress: "0xt1", L1Amounts: "2"},
+		{MessageHash: "0x3", TokenType: int(types.TokenTypeERC20), L1EventType: int(types.L1DepositERC20), L1BlockNumber: 200, L1TxHash: "0xa2", L1TokenAddress: "0xt2", L1Amounts: "3"},
+	}
+	for _, message := range messages {
+		_, err := gatewayMessageMatchOrm.InsertOrUpdateEventInfo(ctx, types.Layer1, message)
+		assert.NoError(t, err)
+	}
+
+	tests := []struct {
+		name string
+		test func(t *testing.T)
+	}{
+		{
+			name: "getByTxHash",
+			test: func(t *testing.T) {
+				matches, err := gatewayMessageMatchOrm.GetGatewayMessageMatchesByTxHash(ctx, "0xa1")
+				assert.NoError(t, err)
+				assert.Len(t, matches, 2)
+				assert.Equal(t, "0x1", matches[0].MessageHash)
+				assert.Equal(t, "0x2", matches[1].MessageHash)
+			},
+		},
+		{
+			name: "listByTokenTypeAndBlockRange",
+			test: func(t *testing.T) {
+				tokenType := int(types.TokenTypeERC20)
+				filter := MessageMatchFilter{TokenType: &tokenType}
+				matches, total, err := gatewayMessageMatchOrm.ListGatewayMessageMatches(ctx, filter, 0, 1)
+				assert.NoError(t, err)
+				assert.Equal(t, int64(2), total)
+				assert.Len(t, matches, 1)
+				assert.Equal(t, "0x3", matches[0].MessageHash)
+
+				filter.Layer = types.Layer1
+				filter.StartBlockNumber = 100
+				filter.EndBlockNumber = 150
+				matches, total, err = gatewayMessageMatchOrm.ListGatewayMessageMatches(ctx, filter, 0, 10)
+				assert.NoError(t, err)
+				assert.Equal(t, int64(1), total)
+				assert.Equal(t, "0x2", matches[0].MessageHash)
+
+				filter = MessageMatchFilter{TokenAddress: "0xt2"}
+				matches, total, err = gatewayMessageMatchOrm.ListGatewayMessageMatches(ctx, filter, 0, 10)
+				assert.NoError(t, err)
+				assert.Equal(t, int64(1), total)
+				assert.Equal(t, "0x3", matches[0].MessageHash)
+			},
+		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, test.test)
 	}
 }
+
+func TestGatewayMessageMatch_GetERC20InFlightAmounts(t *testing.T) {
+	ctx := context.Background()
+	db := testcontainer.SetupDB(ctx, t)
+	gatewayMessageMatchOrm := NewGatewayMessageMatch(db)
+
+	l1Messages := []GatewayMessageMatch{
+		{MessageHash: "0x1", TokenType: int(types.TokenTypeERC20), L1EventType: int(types.L1DepositERC20), L1BlockNumber: 100, L1TokenAddress: "0xl1", L1Amounts: "10"},
+		{MessageHash: "0x2", TokenType: int(types.TokenTypeERC20), L1EventType: int(types.L1DepositERC20), L1BlockNumber: 150, L1TokenAddress: "0xl1", L1Amounts: "20"},
+		{MessageHash: "0x3", TokenType: int(types.TokenTypeERC20), L1EventType: int(types.L1DepositERC20), L1BlockNumber: 250, L1TokenAddress: "0xl1", L1Amounts: "40"},
+		{MessageHash: "0x5", TokenType: int(types.TokenTypeERC20), L1EventType: int(types.L1FinalizeWithdrawERC20), L1BlockNumber: 180, L1TokenAddress: "0xl1", L1Amounts: "7"},
+		{MessageHash: "0x6", TokenType: int(types.TokenTypeERC20), L1EventType: int(types.L1DepositERC20), L1BlockNumber: 100, L1TokenAddress: "0xother", L1Amounts: "100"},
+	}
+	for _, message := range l1Messages {
+		_, err := gatewayMessageMatchOrm.InsertOrUpdateEventInfo(ctx, types.Layer1, message)
+		assert.NoError(t, err)
+	}
+
+	l2Messages := []GatewayMessageMatch{
+		{MessageHash: "0x1", TokenType: int(types.TokenTypeERC20), L2EventType: int(types.L2FinalizeDepositERC20), L2BlockNumber: 50, L2TokenAddress: "0xl2", L2Amounts: "10"},
+		{MessageHash: "0x4", TokenType: int(types.TokenTypeERC20), L2EventType: int(types.L2WithdrawERC20), L2BlockNumber: 55, L2TokenAddress: "0xl2", L2Amounts: "5"},
+		{MessageHash: "0x5", TokenType: int(types.TokenTypeERC20), L2EventType: int(types.L2WithdrawERC20), L2BlockNumber: 30, L2TokenAddress: "0xl2", L2Amounts: "7"},
+	}
+	for _, message := range l2Messages {
+		_, err := gatewayMessageMatchOrm.InsertOrUpdateEventInfo(ctx, types.Layer2, message)
+		assert.NoError(t, err)
+	}
+
+	pendingDeposits, pendingWithdrawals, err := gatewayMessageMatchOrm.GetERC20InFlightAmounts(ctx, "0xl1", "0xl2", 200, 60)
+	assert.NoError(t, err)
+	assert.Equal(t, "20", pendingDeposits.String())
+	assert.Equal(t, "5", pendingWithdrawals.String())
+
+	pendingDeposits, pendingWithdrawals, err = gatewayMessageMatchOrm.GetERC20InFlightAmounts(ctx, "0xl1", "0xl2", 200, 40)
+	assert.NoError(t, err)
+	assert.Equal(t, "30", pendingDeposits.String())
+	assert.Equal(t, "0", pendingWithdrawals.String())
+
+	pendingDeposits, pendingWithdrawals, err = gatewayMessageMatchOrm.GetERC20InFlightAmounts(ctx, "0xnone", "0xnone", 200, 60)
+	assert.NoError(t, err)
+	assert.True(t, pendingDeposits.IsZero())
+	assert.True(t, pendingWithdrawals.IsZero())
+
+	tokenAddresses, err := gatewayMessageMatchOrm.GetFinalizedDepositL2TokenAddresses(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0xl2"}, tokenAddresses)
+}
@@ -0,0 +1,100 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/scroll-tech/chain-monitor/internal/types"
+)
+
+// L1ETHRefund contains the eth refunded by the l1 messenger for a dropped message. The messenger sends the value back
+// through the gateway which sent the message without any messenger event, so the refunds are kept by the refund events.
+type L1ETHRefund struct {
+	db *gorm.DB `gorm:"column:-"`
+
+	ID             int64  `json:"id" gorm:"column:id"`
+	EventType      int    `json:"event_type" gorm:"event_type"`
+	BlockNumber    uint64 `json:"block_number" gorm:"block_number"`
+	TxHash         string `json:"tx_hash" gorm:"tx_hash"`
+	LogIndex       uint   `json:"log_index" gorm:"log_index"`
+	GatewayAddress string `json:"gateway_address" gorm:"gateway_address"`
+	Amount         string `json:"amount" gorm:"amount"`
+
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at"`
+}
+
+// NewL1ETHRefund creates a new L1ETHRefund database instance.
+func NewL1ETHRefund(db *gorm.DB) *L1ETHRefund {
+	return &L1ETHRefund{db: db}
+}
+
+// TableName returns the table name for the L1ETHRefund model.
+func (*L1ETHRefund) TableName() string {
+	return "l1_eth_refund"
+}
+
+// GetRefundsByBlockRange get the refunds whose block number is in [startBlockNumber, endBlockNumber].
+func (m *L1ETHRefund) GetRefundsByBlockRange(ctx context.Context, startBlockNumber, endBlockNumber uint64) ([]L1ETHRefund, error) {
+	var refunds []L1ETHRefund
+	db := m.db.WithContext(ctx)
+	db = db.Where("block_number >= ?", startBlockNumber)
+	db = db.Where("block_number <= ?", endBlockNumber)
+	db = db.Order("block_number asc, log_index asc")
+	if err := db.Find(&refunds).Error; err != nil {
+		log.Warn("L1ETHRefund.GetRefundsByBlockRange failed", "error", err)
+		return nil, fmt.Errorf("L1ETHRefund.GetRefundsByBlockRange failed err:%w", err)
+	}
+	return refunds, nil
+}
+
+// InsertRefunds inserts the refunds, the refunds are keyed by the tx hash and the log index, so rescanning blocks won't
+// insert them twice.
+func (m *L1ETHRefund) InsertRefunds(ctx context.Context, refunds []L1ETHRefund, dbTX ...*gorm.DB) error {
+	if len(refunds) == 0 {
+		return nil
+	}
+
+	db := m.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+
+	db = db.WithContext(ctx)
+	db = db.Model(&L1ETHRefund{})
+	db = db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tx_hash"}, {Name: "log_index"}},
+		DoNothing: true,
+	})
+
+	if err := db.Create(&refunds).Error; err != nil {
+		return fmt.Errorf("L1ETHRefund.InsertRefunds error: %w", err)
+	}
+	return nil
+}
+
+// Rollback deletes the refunds whose block number >= startBlockNumber after a chain reorganization. The refunds have no
+// l2 info, it's a no-op for layer2.
+func (m *L1ETHRefund) Rollback(ctx context.Context, layer types.LayerType, startBlockNumber uint64, dbTX ...*gorm.DB) error {
+	if layer != types.Layer1 {
+		return nil
+	}
+
+	db := m.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+
+	db = db.WithContext(ctx)
+	db = db.Where("block_number >= ?", startBlockNumber)
+	if err := db.Unscoped().Delete(&L1ETHRefund{}).Error; err != nil {
+		return fmt.Errorf("L1ETHRefund.Rollback failed, start block number: %v, err: %w", startBlockNumber, err)
+	}
+	return nil
+}
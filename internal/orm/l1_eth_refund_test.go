@@ -0,0 +1,88 @@
+package orm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scroll-tech/chain-monitor/internal/types"
+	"github.com/scroll-tech/chain-monitor/internal/utils/testcontainer"
+)
+
+func TestL1ETHRefund(t *testing.T) {
+	ctx := context.Background()
+	db := testcontainer.SetupDB(ctx, t)
+	refundOrm := NewL1ETHRefund(db)
+
+	refund := func(blockNumber uint64, txHash string, logIndex uint, amount string) L1ETHRefund {
+		return L1ETHRefund{
+			EventType:      int(types.L1RefundETH),
+			BlockNumber:    blockNumber,
+			TxHash:         txHash,
+			LogIndex:       logIndex,
+			GatewayAddress: "0x7F2b8C31F88B6006c382775eea88297Ec1e3E905",
+			Amount:         amount,
+		}
+	}
+
+	tests := []struct {
+		name string
+		test func(t *testing.T)
+	}{
+		{
+			"insertRefunds", func(t *testing.T) {
+				assert.NoError(t, refundOrm.InsertRefunds(ctx, nil))
+				assert.NoError(t, refundOrm.InsertRefunds(ctx, []L1ETHRefund{
+					refund(100, "0x1c7d3ea5ec8dc9b664a5a886c3b33d21e665355057601033481a439498efb79a", 1, "10"),
+					refund(100, "0x1c7d3ea5ec8dc9b664a5a886c3b33d21e665355057601033481a439498efb79a", 3, "20"),
+					refund(110, "0x2c7d3ea5ec8dc9b664a5a886c3b33d21e665355057601033481a439498efb79a", 0, "30"),
+				}))
+			},
+		},
+		{
+			"insertRescannedRefunds", func(t *testing.T) {
+				// the rescanned refund is kept once.
+				assert.NoError(t, refundOrm.InsertRefunds(ctx, []L1ETHRefund{
+					refund(110, "0x2c7d3ea5ec8dc9b664a5a886c3b33d21e665355057601033481a439498efb79a", 0, "30"),
+				}))
+
+				refunds, err := refundOrm.GetRefundsByBlockRange(ctx, 0, 200)
+				assert.NoError(t, err)
+				assert.Len(t, refunds, 3)
+			},
+		},
+		{
+			"getRefundsByBlockRange", func(t *testing.T) {
+				refunds, err := refundOrm.GetRefundsByBlockRange(ctx, 100, 109)
+				assert.NoError(t, err)
+				assert.Len(t, refunds, 2)
+				assert.Equal(t, uint(1), refunds[0].LogIndex)
+				assert.Equal(t, "20", refunds[1].Amount)
+
+				refunds, err = refundOrm.GetRefundsByBlockRange(ctx, 101, 200)
+				assert.NoError(t, err)
+				assert.Len(t, refunds, 1)
+				assert.Equal(t, uint64(110), refunds[0].BlockNumber)
+			},
+		},
+		{
+			"rollback", func(t *testing.T) {
+				assert.NoError(t, refundOrm.Rollback(ctx, types.Layer2, 0))
+				refunds, err := refundOrm.GetRefundsByBlockRange(ctx, 0, 200)
+				assert.NoError(t, err)
+				assert.Len(t, refunds, 3)
+
+				assert.NoError(t, refundOrm.Rollback(ctx, types.Layer1, 101))
+				refunds, err = refundOrm.GetRefundsByBlockRange(ctx, 0, 200)
+				assert.NoError(t, err)
+				assert.Len(t, refunds, 2)
+				assert.Equal(t, uint64(100), refunds[1].BlockNumber)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, test.test)
+	}
+}
@@ -0,0 +1,181 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/scroll-tech/chain-monitor/internal/types"
+)
+
+// L1MessageQueue contains the messages enqueued in the l1 message queue and popped by the committed batches.
+type L1MessageQueue struct {
+	db *gorm.DB `gorm:"column:-"`
+
+	ID         int64  `json:"id" gorm:"column:id"`
+	QueueIndex uint64 `json:"queue_index" gorm:"queue_index"`
+
+	// l1 enqueue info
+	Sender             string `json:"sender" gorm:"sender"`
+	Target             string `json:"target" gorm:"target"`
+	Value              string `json:"value" gorm:"value"`
+	GasLimit           string `json:"gas_limit" gorm:"gas_limit"`
+	EnqueueBlockNumber uint64 `json:"enqueue_block_number" gorm:"enqueue_block_number"`
+	EnqueueTxHash      string `json:"enqueue_tx_hash" gorm:"enqueue_tx_hash"`
+
+	// l1 dequeue info
+	DequeueBlockNumber uint64 `json:"dequeue_block_number" gorm:"dequeue_block_number"`
+	DequeueTxHash      string `json:"dequeue_tx_hash" gorm:"dequeue_tx_hash"`
+	Skipped            bool   `json:"skipped" gorm:"skipped"`
+	DropBlockNumber    uint64 `json:"drop_block_number" gorm:"drop_block_number"`
+
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at"`
+}
+
+// NewL1MessageQueue creates a new L1MessageQueue database instance.
+func NewL1MessageQueue(db *gorm.DB) *L1MessageQueue {
+	return &L1MessageQueue{db: db}
+}
+
+// TableName returns the table name for the L1MessageQueue model.
+func (*L1MessageQueue) TableName() string {
+	return "l1_message_queue"
+}
+
+// GetLatestDequeuedMessage get the dequeued message of the largest queue index, returns nil if no message is dequeued.
+func (m *L1MessageQueue) GetLatestDequeuedMessage(ctx context.Context) (*L1MessageQueue, error) {
+	var message L1MessageQueue
+	db := m.db.WithContext(ctx)
+	db = db.Where("dequeue_block_number > 0")
+	db = db.Order("queue_index desc")
+	err := db.First(&message).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		log.Warn("L1MessageQueue.GetLatestDequeuedMessage failed", "error", err)
+		return nil, fmt.Errorf("L1MessageQueue.GetLatestDequeuedMessage failed err:%w", err)
+	}
+	return &message, nil
+}
+
+// GetMessagesByQueueIndexes get the messages of the queue indexes.
+func (m *L1MessageQueue) GetMessagesByQueueIndexes(ctx context.Context, queueIndexes []uint64) ([]L1MessageQueue, error) {
+	var messages []L1MessageQueue
+	db := m.db.WithContext(ctx)
+	db = db.Where("queue_index IN ?", queueIndexes)
+	db = db.Order("queue_index asc")
+	if err := db.Find(&messages).Error; err != nil {
+		log.Warn("L1MessageQueue.GetMessagesByQueueIndexes failed", "error", err)
+		return nil, fmt.Errorf("L1MessageQueue.GetMessagesByQueueIndexes failed err:%w", err)
+	}
+	return messages, nil
+}
+
+// InsertOrUpdateEnqueued insert or update the enqueue info of the message.
+func (m *L1MessageQueue) InsertOrUpdateEnqueued(ctx context.Context, message L1MessageQueue, dbTX ...*gorm.DB) error {
+	db := m.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+
+	db = db.WithContext(ctx)
+	db = db.Model(&L1MessageQueue{})
+	db = db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "queue_index"}},
+		DoUpdates: clause.AssignmentColumns([]string{"sender", "target", "value", "gas_limit", "enqueue_block_number", "enqueue_tx_hash"}),
+	})
+
+	if err := db.Create(&message).Error; err != nil {
+		return fmt.Errorf("L1MessageQueue.InsertOrUpdateEnqueued error: %w, message: %v", err, message)
+	}
+	return nil
+}
+
+// InsertOrUpdateDequeued insert or update the dequeue info of the popped messages, the messages may be enqueued before the start block.
+func (m *L1MessageQueue) InsertOrUpdateDequeued(ctx context.Context, messages []L1MessageQueue, dbTX ...*gorm.DB) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	db := m.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+
+	db = db.WithContext(ctx)
+	db = db.Model(&L1MessageQueue{})
+	db = db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "queue_index"}},
+		DoUpdates: clause.AssignmentColumns([]string{"dequeue_block_number", "dequeue_tx_hash", "skipped"}),
+	})
+
+	if err := db.Create(&messages).Error; err != nil {
+		return fmt.Errorf("L1MessageQueue.InsertOrUpdateDequeued error: %w", err)
+	}
+	return nil
+}
+
+// UpdateDropped updates the drop info of the skipped message.
+func (m *L1MessageQueue) UpdateDropped(ctx context.Context, queueIndex, dropBlockNumber uint64, dbTX ...*gorm.DB) error {
+	db := m.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+
+	db = db.WithContext(ctx)
+	db = db.Model(&L1MessageQueue{})
+	db = db.Where("queue_index = ?", queueIndex)
+	if err := db.Update("drop_block_number", dropBlockNumber).Error; err != nil {
+		log.Warn("L1MessageQueue.UpdateDropped failed", "error", err)
+		return fmt.Errorf("L1MessageQueue.UpdateDropped failed err:%w", err)
+	}
+	return nil
+}
+
+// Rollback rollbacks the message queue info whose l1 block number >= startBlockNumber after a chain reorganization, and
+// deletes the messages which have no info left. The message queue has no l2 info, it's a no-op for layer2.
+func (m *L1MessageQueue) Rollback(ctx context.Context, layer types.LayerType, startBlockNumber uint64, dbTX ...*gorm.DB) error {
+	if layer != types.Layer1 {
+		return nil
+	}
+
+	db := m.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+
+	enqueueFields := map[string]interface{}{"sender": "", "target": "", "value": "", "gas_limit": "", "enqueue_block_number": 0, "enqueue_tx_hash": ""}
+	if err := rollbackL1MessageQueueColumns(db, "enqueue_block_number", startBlockNumber, enqueueFields); err != nil {
+		return err
+	}
+	dequeueFields := map[string]interface{}{"dequeue_block_number": 0, "dequeue_tx_hash": "", "skipped": false}
+	if err := rollbackL1MessageQueueColumns(db, "dequeue_block_number", startBlockNumber, dequeueFields); err != nil {
+		return err
+	}
+	if err := rollbackL1MessageQueueColumns(db, "drop_block_number", startBlockNumber, map[string]interface{}{"drop_block_number": 0}); err != nil {
+		return err
+	}
+
+	deleteDB := db.Where("enqueue_block_number = 0 AND dequeue_block_number = 0")
+	if err := deleteDB.Unscoped().Delete(&L1MessageQueue{}).Error; err != nil {
+		return fmt.Errorf("L1MessageQueue.Rollback delete failed, start block number: %v, err: %w", startBlockNumber, err)
+	}
+	return nil
+}
+
+func rollbackL1MessageQueueColumns(db *gorm.DB, blockNumberColumn string, startBlockNumber uint64, fields map[string]interface{}) error {
+	db = db.Model(&L1MessageQueue{}).Where(blockNumberColumn+" >= ?", startBlockNumber)
+	if err := db.Updates(fields).Error; err != nil {
+		return fmt.Errorf("L1MessageQueue.Rollback %v failed, start block number: %v, err: %w", blockNumberColumn, startBlockNumber, err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package orm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scroll-tech/chain-monitor/internal/types"
+	"github.com/scroll-tech/chain-monitor/internal/utils/testcontainer"
+)
+
+func TestL1MessageQueue(t *testing.T) {
+	ctx := context.Background()
+	db := testcontainer.SetupDB(ctx, t)
+	messageQueueOrm := NewL1MessageQueue(db)
+
+	tests := []struct {
+		name string
+		test func(t *testing.T)
+	}{
+		{
+			"getLatestDequeuedMessageEmpty", func(t *testing.T) {
+				message, err := messageQueueOrm.GetLatestDequeuedMessage(ctx)
+				assert.NoError(t, err)
+				assert.Nil(t, message)
+			},
+		},
+		{
+			"insertOrUpdateEnqueued", func(t *testing.T) {
+				for _, queueIndex := range []uint64{0, 1, 2} {
+					message := L1MessageQueue{
+						QueueIndex:         queueIndex,
+						Sender:             "0x6774Bcbd5ceCeF1336b5300fb5186a12DDD8b367",
+						Target:             "0x781e90f1c8Fc4611c9b7497C3B47F99Ef6969CbC",
+						Value:              "0",
+						GasLimit:           "1000000",
+						EnqueueBlockNumber: 100 + queueIndex,
+						EnqueueTxHash:      "0x1c7d3ea5ec8dc9b664a5a886c3b33d21e665355057601033481a439498efb79a",
+					}
+					assert.NoError(t, messageQueueOrm.InsertOrUpdateEnqueued(ctx, message))
+				}
+			},
+		},
+		{
+			"insertOrUpdateDequeued", func(t *testing.T) {
+				// queue index 3 is dequeued before its enqueue event is ingested.
+				messages := []L1MessageQueue{
+					{QueueIndex: 0, DequeueBlockNumber: 200, DequeueTxHash: "0x2c7d3ea5ec8dc9b664a5a886c3b33d21e665355057601033481a439498efb79a"},
+					{QueueIndex: 1, DequeueBlockNumber: 200, DequeueTxHash: "0x2c7d3ea5ec8dc9b664a5a886c3b33d21e665355057601033481a439498efb79a", Skipped: true},
+					{QueueIndex: 3, DequeueBlockNumber: 210, DequeueTxHash: "0x3c7d3ea5ec8dc9b664a5a886c3b33d21e665355057601033481a439498efb79a"},
+				}
+				assert.NoError(t, messageQueueOrm.InsertOrUpdateDequeued(ctx, messages))
+
+				messages, err := messageQueueOrm.GetMessagesByQueueIndexes(ctx, []uint64{0, 1})
+				assert.NoError(t, err)
+				assert.Len(t, messages, 2)
+				assert.Equal(t, uint64(100), messages[0].EnqueueBlockNumber)
+				assert.Equal(t, uint64(200), messages[0].DequeueBlockNumber)
+				assert.False(t, messages[0].Skipped)
+				assert.Equal(t, "0x6774Bcbd5ceCeF1336b5300fb5186a12DDD8b367", messages[1].Sender)
+				assert.True(t, messages[1].Skipped)
+
+				message, err := messageQueueOrm.GetLatestDequeuedMessage(ctx)
+				assert.NoError(t, err)
+				assert.NotNil(t, message)
+				assert.Equal(t, uint64(3), message.QueueIndex)
+				assert.Equal(t, uint64(0), message.EnqueueBlockNumber)
+			},
+		},
+		{
+			"updateDropped", func(t *testing.T) {
+				assert.NoError(t, messageQueueOrm.UpdateDropped(ctx, 1, 300))
+
+				messages, err := messageQueueOrm.GetMessagesByQueueIndexes(ctx, []uint64{1})
+				assert.NoError(t, err)
+				assert.Len(t, messages, 1)
+				assert.Equal(t, uint64(300), messages[0].DropBlockNumber)
+			},
+		},
+		{
+			"rollbackLayer2", func(t *testing.T) {
+				assert.NoError(t, messageQueueOrm.Rollback(ctx, types.Layer2, 0))
+
+				messages, err := messageQueueOrm.GetMessagesByQueueIndexes(ctx, []uint64{0, 1, 2, 3})
+				assert.NoError(t, err)
+				assert.Len(t, messages, 4)
+			},
+		},
+		{
+			"rollbackLayer1", func(t *testing.T) {
+				assert.NoError(t, messageQueueOrm.Rollback(ctx, types.Layer1, 201))
+
+				// queue index 3 has no info left and is deleted, the dequeue info of queue index 0 and 1 is kept.
+				messages, err := messageQueueOrm.GetMessagesByQueueIndexes(ctx, []uint64{0, 1, 2, 3})
+				assert.NoError(t, err)
+				assert.Len(t, messages, 3)
+
+				message, err := messageQueueOrm.GetLatestDequeuedMessage(ctx)
+				assert.NoError(t, err)
+				assert.NotNil(t, message)
+				assert.Equal(t, uint64(1), message.QueueIndex)
+
+				assert.NoError(t, messageQueueOrm.Rollback(ctx, types.Layer1, 101))
+
+				// queue index 1 and 2 have no info left and are deleted.
+				messages, err = messageQueueOrm.GetMessagesByQueueIndexes(ctx, []uint64{0, 1, 2})
+				assert.NoError(t, err)
+				assert.Len(t, messages, 1)
+				assert.Equal(t, uint64(100), messages[0].EnqueueBlockNumber)
+				assert.Equal(t, "0x6774Bcbd5ceCeF1336b5300fb5186a12DDD8b367", messages[0].Sender)
+				assert.Equal(t, uint64(0), messages[0].DequeueBlockNumber)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, test.test)
+	}
+}
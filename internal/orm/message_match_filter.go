@@ -0,0 +1,57 @@
+package orm
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/scroll-tech/chain-monitor/internal/types"
+)
+
+// MessageMatchFilter filters the message matches by the status columns and the block range, the nil fields match all.
+type MessageMatchFilter struct {
+	L1BlockStatus      *int
+	L2BlockStatus      *int
+	L1CrossChainStatus *int
+	L2CrossChainStatus *int
+
+	// only for messenger message matches.
+	WithdrawRootStatus *int
+
+	// only for gateway message matches.
+	TokenType    *int
+	TokenAddress string
+
+	// the block range of the layer, it's ignored if the layer is unknown.
+	Layer            types.LayerType
+	StartBlockNumber uint64
+	EndBlockNumber   uint64
+}
+
+// apply adds the status and block range conditions of the filter to db.
+func (f *MessageMatchFilter) apply(db *gorm.DB) *gorm.DB {
+	if f.L1BlockStatus != nil {
+		db = db.Where("l1_block_status = ?", *f.L1BlockStatus)
+	}
+	if f.L2BlockStatus != nil {
+		db = db.Where("l2_block_status = ?", *f.L2BlockStatus)
+	}
+	if f.L1CrossChainStatus != nil {
+		db = db.Where("l1_cross_chain_status = ?", *f.L1CrossChainStatus)
+	}
+	if f.L2CrossChainStatus != nil {
+		db = db.Where("l2_cross_chain_status = ?", *f.L2CrossChainStatus)
+	}
+
+	switch f.Layer {
+	case types.Layer1:
+		db = db.Where("l1_block_number >= ?", f.StartBlockNumber)
+		if f.EndBlockNumber != 0 {
+			db = db.Where("l1_block_number <= ?", f.EndBlockNumber)
+		}
+	case types.Layer2:
+		db = db.Where("l2_block_number >= ?", f.StartBlockNumber)
+		if f.EndBlockNumber != 0 {
+			db = db.Where("l2_block_number <= ?", f.EndBlockNumber)
+		}
+	}
+	return db
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"strings"
 	"time"
 
 	"github.com/scroll-tech/go-ethereum/log"
@@ -38,6 +39,17 @@ type MessengerMessageMatch struct {
 	ETHAmount       string `json:"eth_amount" gorm:"eth_amount"`
 	ETHAmountStatus int    `json:"eth_amount_status" gorm:"eth_amount_status"`
 
+	// the fields of the sent message, the eth amount is the value of the message. They're only set once the sent
+	// message event is ingested, and hash to the message hash.
+	Sender       string `json:"sender" gorm:"sender"`
+	Target       string `json:"target" gorm:"target"`
+	MessageNonce string `json:"message_nonce" gorm:"message_nonce"`
+	Calldata     []byte `json:"calldata" gorm:"calldata"`
+	// the block time of the sent message event.
+	SentBlockTime *time.Time `json:"sent_block_time" gorm:"sent_block_time"`
+	// the time the message is alerted as stuck, the stuck messages are only alerted once.
+	StuckAlertedAt *time.Time `json:"stuck_alerted_at" gorm:"stuck_alerted_at"`
+
 	// status
 	L1ETHBalanceStatus int `json:"l1_eth_balance_status" gorm:"l1_eth_balance_status"`
 	L2ETHBalanceStatus int `json:"l2_eth_balance_status" gorm:"l2_eth_balance_status"`
@@ -46,6 +58,8 @@ type MessengerMessageMatch struct {
 	L1CrossChainStatus int `json:"l1_cross_chain_status" gorm:"l1_cross_chain_status"`
 	L2CrossChainStatus int `json:"l2_cross_chain_status" gorm:"l2_cross_chain_status"`
 	WithdrawRootStatus int `json:"withdraw_root_status" gorm:"withdraw_root_status"`
+	// the reason of the failed block status checks, empty if the checks are passed.
+	FailureReason string `json:"failure_reason" gorm:"failure_reason"`
 
 	// only not null in the last message of each block.
 	MessageProof []byte `json:"message_proof" gorm:"message_proof"`
@@ -92,11 +106,11 @@ func (m *MessengerMessageMatch) GetUncheckedLatestETHMessageMatch(ctx context.Co
 	db := m.db.WithContext(ctx)
 	switch layer {
 	case types.Layer1:
-		db = db.Where("l1_eth_balance_status = ?", types.ETHBalanceStatusTypeInvalid)
+		db = db.Where("l1_eth_balance_status = ?", types.ETHBalanceStatusTypeUnchecked)
 		db = db.Where("l1_block_status = ?", types.BlockStatusTypeValid)
 		db = db.Order("l1_block_number asc")
 	case types.Layer2:
-		db = db.Where("l2_eth_balance_status = ?", types.ETHBalanceStatusTypeInvalid)
+		db = db.Where("l2_eth_balance_status = ?", types.ETHBalanceStatusTypeUnchecked)
 		db = db.Where("l2_block_status = ?", types.BlockStatusTypeValid)
 		db = db.Order("l2_block_number asc")
 	}
@@ -108,19 +122,40 @@ func (m *MessengerMessageMatch) GetUncheckedLatestETHMessageMatch(ctx context.Co
 	return messages, nil
 }
 
+// GetUncheckedAndDoubleLayerValidMessengerMessageMatches retrieves the earliest relayed messenger message match records
+// whose cross chain status of the layer is unchecked and which are valid in both Layer1 and Layer2.
+func (m *MessengerMessageMatch) GetUncheckedAndDoubleLayerValidMessengerMessageMatches(ctx context.Context, layer types.LayerType, limit int) ([]MessengerMessageMatch, error) {
+	var messages []MessengerMessageMatch
+	db := m.db.WithContext(ctx)
+	db = db.Where("l1_block_status = ?", types.BlockStatusTypeValid)
+	db = db.Where("l2_block_status = ?", types.BlockStatusTypeValid)
+	switch layer {
+	case types.Layer1:
+		db = db.Where("l1_cross_chain_status = ?", types.CrossChainStatusTypeUnchecked)
+	case types.Layer2:
+		db = db.Where("l2_cross_chain_status = ?", types.CrossChainStatusTypeUnchecked)
+	}
+	db = db.Limit(limit)
+	if err := db.Find(&messages).Error; err != nil {
+		log.Warn("MessengerMessageMatch.GetUncheckedAndDoubleLayerValidMessengerMessageMatches failed", "error", err)
+		return nil, fmt.Errorf("MessengerMessageMatch.GetUncheckedAndDoubleLayerValidMessengerMessageMatches failed err:%w", err)
+	}
+	return messages, nil
+}
+
 // GetETHMessageMatchByBlockRange get the unchecked eth message match records by block range
 func (m *MessengerMessageMatch) GetETHMessageMatchByBlockRange(ctx context.Context, layer types.LayerType, startBlockNumber, endBlockNumber uint64) ([]*MessengerMessageMatch, error) {
 	var messages []*MessengerMessageMatch
 	db := m.db.WithContext(ctx)
 	switch layer {
 	case types.Layer1:
-		db = db.Where("l1_eth_balance_status = ?", types.ETHBalanceStatusTypeInvalid)
+		db = db.Where("l1_eth_balance_status = ?", types.ETHBalanceStatusTypeUnchecked)
 		db = db.Where("l1_block_status = ?", types.BlockStatusTypeValid)
 		db = db.Where("l1_block_number >= ?", startBlockNumber)
 		db = db.Where("l1_block_number <= ?", endBlockNumber)
 		db = db.Order("l1_block_number asc")
 	case types.Layer2:
-		db = db.Where("l2_eth_balance_status = ?", types.ETHBalanceStatusTypeInvalid)
+		db = db.Where("l2_eth_balance_status = ?", types.ETHBalanceStatusTypeUnchecked)
 		db = db.Where("l2_block_status = ?", types.BlockStatusTypeValid)
 		db = db.Where("l2_block_number >= ?", startBlockNumber)
 		db = db.Where("l2_block_number <= ?", endBlockNumber)
@@ -157,39 +192,61 @@ func (m *MessengerMessageMatch) GetLatestBlockValidMessageMatch(ctx context.Cont
 	return &message, nil
 }
 
-// GetETHCheckStartBlockNumberAndBalance fetches the latest valid Ethereum balance match record for the specified layer
-// and returns the block number and messenger balance for the specified layer.
-func (m *MessengerMessageMatch) GetETHCheckStartBlockNumberAndBalance(ctx context.Context, layer types.LayerType) (*big.Int, error) {
+// GetETHCheckStartBlockNumberAndBalance fetches the latest checked Ethereum balance match record for the specified layer
+// and returns the block number and messenger balance for the specified layer. The failed records keep the actual
+// messenger balance, so the checks go on from them.
+func (m *MessengerMessageMatch) GetETHCheckStartBlockNumberAndBalance(ctx context.Context, layer types.LayerType) (uint64, *big.Int, error) {
 	var message MessengerMessageMatch
 	db := m.db.WithContext(ctx)
 	switch layer {
 	case types.Layer1:
-		db = db.Where("l1_eth_balance_status = ?", types.ETHBalanceStatusTypeValid)
+		db = db.Where("l1_eth_balance_status IN (?)", []types.ETHBalanceStatus{types.ETHBalanceStatusTypeValid, types.ETHBalanceStatusTypeFailed})
 		db = db.Order("l1_block_number desc")
 	case types.Layer2:
-		db = db.Where("l2_eth_balance_status = ?", types.ETHBalanceStatusTypeValid)
+		db = db.Where("l2_eth_balance_status IN (?)", []types.ETHBalanceStatus{types.ETHBalanceStatusTypeValid, types.ETHBalanceStatusTypeFailed})
 		db = db.Order("l2_block_number desc")
 	}
 	err := db.First(&message).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, nil
+			return 0, nil, nil
 		}
 		log.Warn("MessengerMessageMatch.GetETHCheckStartBlockNumberAndBalance failed", "error", err)
-		return nil, fmt.Errorf("MessengerMessageMatch.GetETHCheckStartBlockNumberAndBalance failed err:%w", err)
+		return 0, nil, fmt.Errorf("MessengerMessageMatch.GetETHCheckStartBlockNumberAndBalance failed err:%w", err)
 	}
 
 	// Return the block number and messenger balance for the specified layer
 	switch layer {
 	case types.Layer1:
-		return message.L1MessengerETHBalance.BigInt(), nil
+		return message.L1BlockNumber, message.L1MessengerETHBalance.BigInt(), nil
 	case types.Layer2:
-		return message.L2MessengerETHBalance.BigInt(), nil
+		return message.L2BlockNumber, message.L2MessengerETHBalance.BigInt(), nil
 	default:
-		return nil, fmt.Errorf("invalid layer: %v", layer)
+		return 0, nil, fmt.Errorf("invalid layer: %v", layer)
 	}
 }
 
+// GetLatestL1SentMessageMatch fetches the l1 sent message with the largest message nonce, nil if there is none. The
+// messages ingested before the message nonces are stored are ignored.
+func (m *MessengerMessageMatch) GetLatestL1SentMessageMatch(ctx context.Context) (*MessengerMessageMatch, error) {
+	var message MessengerMessageMatch
+	db := m.db.WithContext(ctx)
+	db = db.Where("l1_event_type = ?", types.L1SentMessage)
+	db = db.Where("message_nonce <> ''")
+	db = db.Order("CAST(message_nonce AS NUMERIC) desc")
+	db = db.Order("l1_block_number desc")
+	err := db.First(&message).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Warn("MessengerMessageMatch.GetLatestL1SentMessageMatch failed", "error", err)
+		return nil, fmt.Errorf("MessengerMessageMatch.GetLatestL1SentMessageMatch failed err:%w", err)
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &message, nil
+}
+
 // GetLatestValidL2SentMessageMatch fetches the valid l2 sent message with the largest message nonce.
 func (m *MessengerMessageMatch) GetLatestValidL2SentMessageMatch(ctx context.Context) (*MessengerMessageMatch, error) {
 	var message MessengerMessageMatch
@@ -208,11 +265,82 @@ func (m *MessengerMessageMatch) GetLatestValidL2SentMessageMatch(ctx context.Con
 	return &message, nil
 }
 
+// GetLatestCheckedL2SentMessageMatch fetches the l2 sent message with the largest message nonce whose withdraw root is checked.
+func (m *MessengerMessageMatch) GetLatestCheckedL2SentMessageMatch(ctx context.Context) (*MessengerMessageMatch, error) {
+	var message MessengerMessageMatch
+	db := m.db.WithContext(ctx)
+	db = db.Where("withdraw_root_status <> ?", types.WithdrawRootStatusTypeUnchecked)
+	db = db.Where("next_message_nonce > 0")
+	db = db.Order("next_message_nonce DESC")
+	err := db.First(&message).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		log.Warn("MessengerMessageMatch.GetLatestCheckedL2SentMessageMatch failed", "error", err)
+		return nil, fmt.Errorf("MessengerMessageMatch.GetLatestCheckedL2SentMessageMatch failed, err:%w", err)
+	}
+	return &message, nil
+}
+
+// GetLatestValidL2SentMessageMatchBefore fetches the valid l2 sent message with the largest message nonce less than nextMessageNonce-1.
+func (m *MessengerMessageMatch) GetLatestValidL2SentMessageMatchBefore(ctx context.Context, nextMessageNonce uint64) (*MessengerMessageMatch, error) {
+	var message MessengerMessageMatch
+	db := m.db.WithContext(ctx)
+	db = db.Where("withdraw_root_status = ?", types.WithdrawRootStatusTypeValid)
+	db = db.Where("next_message_nonce > 0")
+	db = db.Where("next_message_nonce < ?", nextMessageNonce)
+	db = db.Order("next_message_nonce DESC")
+	err := db.First(&message).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		log.Warn("MessengerMessageMatch.GetLatestValidL2SentMessageMatchBefore failed", "error", err)
+		return nil, fmt.Errorf("MessengerMessageMatch.GetLatestValidL2SentMessageMatchBefore failed, err:%w", err)
+	}
+	return &message, nil
+}
+
+// GetLatestValidL2SentMessageMatchAtBlock fetches the valid l2 sent message with the largest message nonce whose l2 block number <= blockNumber.
+func (m *MessengerMessageMatch) GetLatestValidL2SentMessageMatchAtBlock(ctx context.Context, blockNumber uint64) (*MessengerMessageMatch, error) {
+	var message MessengerMessageMatch
+	db := m.db.WithContext(ctx)
+	db = db.Where("withdraw_root_status = ?", types.WithdrawRootStatusTypeValid)
+	db = db.Where("next_message_nonce > 0")
+	db = db.Where("l2_block_number <= ?", blockNumber)
+	db = db.Order("next_message_nonce DESC")
+	err := db.First(&message).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		log.Warn("MessengerMessageMatch.GetLatestValidL2SentMessageMatchAtBlock failed", "error", err)
+		return nil, fmt.Errorf("MessengerMessageMatch.GetLatestValidL2SentMessageMatchAtBlock failed, err:%w", err)
+	}
+	return &message, nil
+}
+
+// GetL2SentMessagesAfterNonce fetches the l2 sent messages whose next message nonce is greater than nextMessageNonce
+// up to the end block number, in the message nonce order.
+func (m *MessengerMessageMatch) GetL2SentMessagesAfterNonce(ctx context.Context, nextMessageNonce, endBlockNumber uint64) ([]*MessengerMessageMatch, error) {
+	var messages []*MessengerMessageMatch
+	db := m.db.WithContext(ctx)
+	db = db.Where("next_message_nonce > ?", nextMessageNonce)
+	db = db.Where("l2_block_number <= ?", endBlockNumber)
+	db = db.Order("next_message_nonce ASC")
+	if err := db.Find(&messages).Error; err != nil {
+		log.Warn("MessengerMessageMatch.GetL2SentMessagesAfterNonce failed", "error", err)
+		return nil, fmt.Errorf("MessengerMessageMatch.GetL2SentMessagesAfterNonce failed, err:%w", err)
+	}
+	return messages, nil
+}
+
 // GetL2SentMessagesInBlockRange fetches the message match records of l2 sent message within the block range.
 func (m *MessengerMessageMatch) GetL2SentMessagesInBlockRange(ctx context.Context, startBlockNumber, endBlockNumber uint64) ([]*MessengerMessageMatch, error) {
 	var messages []*MessengerMessageMatch
 	db := m.db.WithContext(ctx)
-	db = db.Where("withdraw_root_status = ?", types.WithdrawRootStatusTypeUnknown)
+	db = db.Where("withdraw_root_status = ?", types.WithdrawRootStatusTypeUnchecked)
 	db = db.Where("l2_block_number >= ?", startBlockNumber)
 	db = db.Where("l2_block_number <= ?", endBlockNumber)
 	db = db.Where("next_message_nonce > 0")
@@ -240,8 +368,169 @@ func (m *MessengerMessageMatch) GetMessageMatchByMessageHash(ctx context.Context
 	return &message, nil
 }
 
+// GetMessageMatchesByMessageHashes get MessageMatches by message_hash list
+func (m *MessengerMessageMatch) GetMessageMatchesByMessageHashes(ctx context.Context, msgHashes []string) ([]MessengerMessageMatch, error) {
+	var messages []MessengerMessageMatch
+	db := m.db.WithContext(ctx)
+	db = db.Where("message_hash in (?)", msgHashes)
+	if err := db.Find(&messages).Error; err != nil {
+		log.Warn("MessengerMessageMatch.GetMessageMatchesByMessageHashes failed", "error", err)
+		return nil, fmt.Errorf("MessengerMessageMatch.GetMessageMatchesByMessageHashes failed, err:%w", err)
+	}
+	return messages, nil
+}
+
+// GetMessageMatchesByTxHash get the MessageMatches whose l1 or l2 tx hash is txHash
+func (m *MessengerMessageMatch) GetMessageMatchesByTxHash(ctx context.Context, txHash string) ([]MessengerMessageMatch, error) {
+	var messages []MessengerMessageMatch
+	db := m.db.WithContext(ctx)
+	db = db.Where("(l1_tx_hash = ? OR l2_tx_hash = ?)", txHash, txHash)
+	db = db.Order("id asc")
+	if err := db.Find(&messages).Error; err != nil {
+		log.Warn("MessengerMessageMatch.GetMessageMatchesByTxHash failed", "error", err)
+		return nil, fmt.Errorf("MessengerMessageMatch.GetMessageMatchesByTxHash failed, err:%w", err)
+	}
+	return messages, nil
+}
+
+// ListMessageMatches lists the MessageMatches matching the filter from the latest one, and returns the total count of them.
+func (m *MessengerMessageMatch) ListMessageMatches(ctx context.Context, filter MessageMatchFilter, offset, limit int) ([]MessengerMessageMatch, int64, error) {
+	db := m.db.WithContext(ctx)
+	db = db.Model(&MessengerMessageMatch{})
+	db = filter.apply(db)
+	if filter.WithdrawRootStatus != nil {
+		db = db.Where("withdraw_root_status = ?", *filter.WithdrawRootStatus)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		log.Warn("MessengerMessageMatch.ListMessageMatches count failed", "error", err)
+		return nil, 0, fmt.Errorf("MessengerMessageMatch.ListMessageMatches count failed, err:%w", err)
+	}
+
+	var messages []MessengerMessageMatch
+	db = db.Order("id desc")
+	db = db.Offset(offset)
+	db = db.Limit(limit)
+	if err := db.Find(&messages).Error; err != nil {
+		log.Warn("MessengerMessageMatch.ListMessageMatches failed", "error", err)
+		return nil, 0, fmt.Errorf("MessengerMessageMatch.ListMessageMatches failed, err:%w", err)
+	}
+	return messages, total, nil
+}
+
+// PendingMessageAges the age distribution of the messages which are sent but not relayed yet.
+type PendingMessageAges struct {
+	Count uint64
+	// the sum of the ages in seconds.
+	AgeSum float64
+	// the cumulative count of the messages whose age is less than or equal to each bound.
+	BucketCounts []uint64
+}
+
+// PendingMessage is a message which is sent but not relayed yet, and the time since which it's pending.
+type PendingMessage struct {
+	MessengerMessageMatch `gorm:"embedded"`
+	PendingSince          time.Time `json:"pending_since" gorm:"column:pending_since"`
+}
+
+// pendingMessageScope filters the messages which are sent on the layer but not relayed on the other layer yet, and returns
+// the column of the time since which they're relayable. A l1 sent message is relayable since it's sent, while a l2 sent
+// message is only relayable since the batch including it is finalized on l1, the ones in the unfinalized batches are excluded.
+func pendingMessageScope(db *gorm.DB, layer types.LayerType) (*gorm.DB, string, error) {
+	var pendingSinceColumn string
+	switch layer {
+	case types.Layer1:
+		db = db.Where("messenger_message_match.l1_event_type = ?", types.L1SentMessage)
+		db = db.Where("messenger_message_match.l2_event_type = ?", types.EventTypeUnknown)
+		pendingSinceColumn = "messenger_message_match.sent_block_time"
+	case types.Layer2:
+		db = db.Joins("JOIN batch ON messenger_message_match.l2_block_number BETWEEN batch.start_block_number AND batch.end_block_number" +
+			" AND batch.end_block_number > 0 AND batch.revert_block_number = 0 AND batch.deleted_at IS NULL")
+		db = db.Where("messenger_message_match.l2_event_type = ?", types.L2SentMessage)
+		db = db.Where("messenger_message_match.l1_event_type = ?", types.EventTypeUnknown)
+		pendingSinceColumn = "batch.finalize_block_time"
+	default:
+		return nil, "", fmt.Errorf("invalid layer: %v", layer)
+	}
+	db = db.Where(pendingSinceColumn + " IS NOT NULL")
+	return db, pendingSinceColumn, nil
+}
+
+// GetPendingMessages fetches the earliest messages sent on the layer which are pending since sentBefore or earlier,
+// and are neither relayed on the other layer nor alerted as stuck yet.
+func (m *MessengerMessageMatch) GetPendingMessages(ctx context.Context, layer types.LayerType, sentBefore time.Time, limit int) ([]PendingMessage, error) {
+	var messages []PendingMessage
+	db, pendingSinceColumn, err := pendingMessageScope(m.db.WithContext(ctx).Model(&MessengerMessageMatch{}), layer)
+	if err != nil {
+		return nil, err
+	}
+	db = db.Select("messenger_message_match.*, " + pendingSinceColumn + " AS pending_since")
+	db = db.Where("messenger_message_match.stuck_alerted_at IS NULL")
+	db = db.Where(pendingSinceColumn+" <= ?", sentBefore)
+	db = db.Order(pendingSinceColumn + " asc")
+	db = db.Limit(limit)
+	if err := db.Scan(&messages).Error; err != nil {
+		log.Warn("MessengerMessageMatch.GetPendingMessages failed", "error", err)
+		return nil, fmt.Errorf("MessengerMessageMatch.GetPendingMessages failed, err:%w", err)
+	}
+	return messages, nil
+}
+
+// UpdateStuckAlertedAt marks the messages as alerted as stuck.
+func (m *MessengerMessageMatch) UpdateStuckAlertedAt(ctx context.Context, messageHashes []string) error {
+	if len(messageHashes) == 0 {
+		return nil
+	}
+	db := m.db.WithContext(ctx)
+	db = db.Model(&MessengerMessageMatch{})
+	db = db.Where("message_hash in (?)", messageHashes)
+	if err := db.Update("stuck_alerted_at", utils.NowUTC()).Error; err != nil {
+		log.Warn("MessengerMessageMatch.UpdateStuckAlertedAt failed", "error", err)
+		return fmt.Errorf("MessengerMessageMatch.UpdateStuckAlertedAt failed, err:%w", err)
+	}
+	return nil
+}
+
+// GetPendingMessageAges aggregates the ages of the messages sent on the layer which are not relayed on the other layer yet.
+func (m *MessengerMessageMatch) GetPendingMessageAges(ctx context.Context, layer types.LayerType, bounds []time.Duration) (*PendingMessageAges, error) {
+	db, pendingSinceColumn, err := pendingMessageScope(m.db.WithContext(ctx).Model(&MessengerMessageMatch{}), layer)
+	if err != nil {
+		return nil, err
+	}
+
+	now := utils.NowUTC()
+	selects := []string{"count(*)", "coalesce(sum(extract(epoch from (? - " + pendingSinceColumn + "))), 0)"}
+	args := []interface{}{now}
+	for _, bound := range bounds {
+		selects = append(selects, "count(*) filter (where "+pendingSinceColumn+" >= ?)")
+		args = append(args, now.Add(-bound))
+	}
+	db = db.Select(strings.Join(selects, ", "), args...)
+
+	ages := PendingMessageAges{BucketCounts: make([]uint64, len(bounds))}
+	dest := []interface{}{&ages.Count, &ages.AgeSum}
+	for i := range ages.BucketCounts {
+		dest = append(dest, &ages.BucketCounts[i])
+	}
+	if err := db.Row().Scan(dest...); err != nil {
+		log.Warn("MessengerMessageMatch.GetPendingMessageAges failed", "error", err)
+		return nil, fmt.Errorf("MessengerMessageMatch.GetPendingMessageAges failed, err:%w", err)
+	}
+	return &ages, nil
+}
+
 // InsertOrUpdateEventInfo insert or update event info
 func (m *MessengerMessageMatch) InsertOrUpdateEventInfo(ctx context.Context, layer types.LayerType, message MessengerMessageMatch, dbTX ...*gorm.DB) (int64, error) {
+	return m.insertOrUpdateEventInfo(ctx, layer, message, false, dbTX...)
+}
+
+// UpsertEventInfo insert or overwrite the event info of the layer, even if the event of the layer has been recorded.
+func (m *MessengerMessageMatch) UpsertEventInfo(ctx context.Context, layer types.LayerType, message MessengerMessageMatch, dbTX ...*gorm.DB) (int64, error) {
+	return m.insertOrUpdateEventInfo(ctx, layer, message, true, dbTX...)
+}
+
+func (m *MessengerMessageMatch) insertOrUpdateEventInfo(ctx context.Context, layer types.LayerType, message MessengerMessageMatch, overwrite bool, dbTX ...*gorm.DB) (int64, error) {
 	db := m.db
 	if len(dbTX) > 0 && dbTX[0] != nil {
 		db = dbTX[0]
@@ -249,28 +538,37 @@ func (m *MessengerMessageMatch) InsertOrUpdateEventInfo(ctx context.Context, lay
 
 	db = db.WithContext(ctx)
 	db = db.Model(&MessengerMessageMatch{})
-	var assignmentColumn clause.Set
+	var columns []string
 	var where clause.Where
 	if layer == types.Layer1 {
 		if message.L1EventType == int(types.L1SentMessage) { // sent
-			assignmentColumn = clause.AssignmentColumns([]string{"l1_block_number", "l1_event_type", "l1_tx_hash", "eth_amount", "eth_amount_status", "l1_block_status", "l1_block_status_updated_at"})
+			columns = []string{"l1_block_number", "l1_event_type", "l1_tx_hash", "eth_amount", "eth_amount_status", "sender", "target", "message_nonce", "calldata", "sent_block_time", "l1_block_status", "l1_block_status_updated_at"}
 			where = clause.Where{Exprs: []clause.Expression{clause.Eq{Column: "messenger_message_match.l1_block_number", Value: 0}}}
 		} else if message.L1EventType == int(types.L1RelayedMessage) { // relayed
-			assignmentColumn = clause.AssignmentColumns([]string{"l1_block_number", "l1_event_type", "l1_tx_hash", "l1_block_status", "l1_block_status_updated_at"})
+			columns = []string{"l1_block_number", "l1_event_type", "l1_tx_hash", "l1_block_status", "l1_block_status_updated_at"}
 			where = clause.Where{Exprs: []clause.Expression{clause.Eq{Column: "messenger_message_match.l1_block_number", Value: 0}}}
 		}
 	}
 
 	if layer == types.Layer2 {
 		if message.L2EventType == int(types.L2SentMessage) { // sent
-			assignmentColumn = clause.AssignmentColumns([]string{"l2_block_number", "l2_event_type", "l2_tx_hash", "eth_amount", "eth_amount_status", "next_message_nonce", "l2_block_status", "l2_block_status_updated_at"})
+			columns = []string{"l2_block_number", "l2_event_type", "l2_tx_hash", "eth_amount", "eth_amount_status", "next_message_nonce", "sender", "target", "message_nonce", "calldata", "sent_block_time", "l2_block_status", "l2_block_status_updated_at"}
 			where = clause.Where{Exprs: []clause.Expression{clause.Eq{Column: "messenger_message_match.l2_block_number", Value: 0}}}
 		} else if message.L2EventType == int(types.L2RelayedMessage) { // relayed
-			assignmentColumn = clause.AssignmentColumns([]string{"l2_block_number", "l2_event_type", "l2_tx_hash", "l2_block_status", "l2_block_status_updated_at"})
+			columns = []string{"l2_block_number", "l2_event_type", "l2_tx_hash", "l2_block_status", "l2_block_status_updated_at"}
 			where = clause.Where{Exprs: []clause.Expression{clause.Eq{Column: "messenger_message_match.l2_block_number", Value: 0}}}
 		}
 	}
 
+	var assignmentColumn clause.Set
+	if columns != nil {
+		assignmentColumn = append(clause.AssignmentColumns(columns), failureReasonAssignment(m.TableName(), layer, overwrite))
+	}
+
+	if overwrite && columns != nil {
+		where = eventInfoChangedWhere(m.TableName(), columns)
+	}
+
 	db = db.Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "message_hash"}},
 		Where:     where,
@@ -284,7 +582,8 @@ func (m *MessengerMessageMatch) InsertOrUpdateEventInfo(ctx context.Context, lay
 	return result.RowsAffected, nil
 }
 
-// UpdateMsgProofAndStatus insert or update the withdrawal tree root's message proof and withdraw root status
+// UpdateMsgProofAndStatus insert or update the withdrawal tree root's message proof and withdraw root status, the failure
+// reason is updated if the withdraw root check is failed.
 func (m *MessengerMessageMatch) UpdateMsgProofAndStatus(ctx context.Context, message *MessengerMessageMatch, dbTX ...*gorm.DB) error {
 	if message == nil {
 		return nil
@@ -301,6 +600,9 @@ func (m *MessengerMessageMatch) UpdateMsgProofAndStatus(ctx context.Context, mes
 		"message_proof":        message.MessageProof,
 		"withdraw_root_status": message.WithdrawRootStatus,
 	}
+	if message.FailureReason != "" {
+		updateFields["failure_reason"] = setFailureReason(message.FailureReason)
+	}
 
 	if err := db.Updates(updateFields).Error; err != nil {
 		return fmt.Errorf("MessengerMessageMatch.UpdateMsgProofAndStatus failed err:%w", err)
@@ -308,6 +610,63 @@ func (m *MessengerMessageMatch) UpdateMsgProofAndStatus(ctx context.Context, mes
 	return nil
 }
 
+// UpdateCrossChainStatus updates the cross chain status for the message matches with the provided ids.
+func (m *MessengerMessageMatch) UpdateCrossChainStatus(ctx context.Context, id []int64, layer types.LayerType, status types.CrossChainStatusType) error {
+	db := m.db.WithContext(ctx)
+	db = db.Model(&MessengerMessageMatch{})
+	db = db.Where("id in (?)", id)
+
+	var updateFields map[string]interface{}
+	switch layer {
+	case types.Layer1:
+		updateFields = map[string]interface{}{
+			"l1_cross_chain_status":            status,
+			"l1_cross_chain_status_updated_at": utils.NowUTC(),
+		}
+	case types.Layer2:
+		updateFields = map[string]interface{}{
+			"l2_cross_chain_status":            status,
+			"l2_cross_chain_status_updated_at": utils.NowUTC(),
+		}
+	}
+
+	if err := db.Updates(updateFields).Error; err != nil {
+		log.Warn("MessengerMessageMatch.UpdateCrossChainStatus failed", "error", err)
+		return fmt.Errorf("MessengerMessageMatch.UpdateCrossChainStatus failed err:%w", err)
+	}
+	return nil
+}
+
+// UpdateCrossChainStatusFailed marks the cross chain status of the message match as failed with the failure reason. The
+// failed message match is only checked again once its events are rolled back, see RollbackEventInfo.
+func (m *MessengerMessageMatch) UpdateCrossChainStatusFailed(ctx context.Context, id int64, layer types.LayerType, failureReason string) error {
+	db := m.db.WithContext(ctx)
+	db = db.Model(&MessengerMessageMatch{})
+	db = db.Where("id = ?", id)
+
+	var updateFields map[string]interface{}
+	switch layer {
+	case types.Layer1:
+		updateFields = map[string]interface{}{
+			"l1_cross_chain_status":            types.CrossChainStatusTypeFailed,
+			"l1_cross_chain_status_updated_at": utils.NowUTC(),
+			"failure_reason":                   setFailureReason(failureReason),
+		}
+	case types.Layer2:
+		updateFields = map[string]interface{}{
+			"l2_cross_chain_status":            types.CrossChainStatusTypeFailed,
+			"l2_cross_chain_status_updated_at": utils.NowUTC(),
+			"failure_reason":                   setFailureReason(failureReason),
+		}
+	}
+
+	if err := db.Updates(updateFields).Error; err != nil {
+		log.Warn("MessengerMessageMatch.UpdateCrossChainStatusFailed failed", "error", err)
+		return fmt.Errorf("MessengerMessageMatch.UpdateCrossChainStatusFailed failed err:%w", err)
+	}
+	return nil
+}
+
 // UpdateBlockStatus updates the block status for the given layer and block number range.
 func (m *MessengerMessageMatch) UpdateBlockStatus(ctx context.Context, layer types.LayerType, startBlockNumber, endBlockNumber uint64, dbTX ...*gorm.DB) error {
 	db := m.db
@@ -340,7 +699,7 @@ func (m *MessengerMessageMatch) UpdateBlockStatus(ctx context.Context, layer typ
 	return nil
 }
 
-// UpdateETHBalance update the eth balance and eth status
+// UpdateETHBalance update the eth balance and eth status, the failure reason is updated if the eth balance check is failed.
 func (m *MessengerMessageMatch) UpdateETHBalance(ctx context.Context, layer types.LayerType, messageMatch MessengerMessageMatch, dbTX ...*gorm.DB) error {
 	db := m.db
 	if len(dbTX) > 0 && dbTX[0] != nil {
@@ -356,19 +715,104 @@ func (m *MessengerMessageMatch) UpdateETHBalance(ctx context.Context, layer type
 	case types.Layer1:
 		updateFields = map[string]interface{}{
 			"l1_messenger_eth_balance":         messageMatch.L1MessengerETHBalance,
-			"l1_eth_balance_status":            types.ETHBalanceStatusTypeValid,
+			"l1_eth_balance_status":            messageMatch.L1ETHBalanceStatus,
 			"l1_eth_balance_status_updated_at": utils.NowUTC(),
 		}
 	case types.Layer2:
 		updateFields = map[string]interface{}{
 			"l2_messenger_eth_balance":         messageMatch.L2MessengerETHBalance,
-			"l2_eth_balance_status":            types.ETHBalanceStatusTypeValid,
+			"l2_eth_balance_status":            messageMatch.L2ETHBalanceStatus,
 			"l2_eth_balance_status_updated_at": utils.NowUTC(),
 		}
 	}
+	if messageMatch.FailureReason != "" {
+		updateFields["failure_reason"] = setFailureReason(messageMatch.FailureReason)
+	}
 	if err := db.Updates(updateFields).Error; err != nil {
 		log.Warn("MessengerMessageMatch.UpdateETHBalance failed", "error", err)
 		return fmt.Errorf("MessengerMessageMatch.UpdateETHBalance failed err:%w", err)
 	}
 	return nil
 }
+
+// RollbackEventInfo rollbacks the event info of the layer whose block number >= startBlockNumber after a chain reorganization.
+// The message matches which only contain the rolled back layer event are deleted, the others reset the event info and statuses of the layer.
+func (m *MessengerMessageMatch) RollbackEventInfo(ctx context.Context, layer types.LayerType, startBlockNumber uint64, dbTX ...*gorm.DB) error {
+	db := m.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+	db = db.WithContext(ctx)
+
+	var deleteDB, updateDB *gorm.DB
+	var updateFields map[string]interface{}
+	switch layer {
+	case types.Layer1:
+		deleteDB = db.Where("l1_block_number >= ? AND l2_block_number = 0", startBlockNumber)
+		updateDB = db.Model(&MessengerMessageMatch{}).Where("l1_block_number >= ?", startBlockNumber)
+		updateFields = map[string]interface{}{
+			"l1_event_type":                    types.EventTypeUnknown,
+			"l1_block_number":                  0,
+			"l1_tx_hash":                       "",
+			"l1_messenger_eth_balance":         decimal.Zero,
+			"l1_block_status":                  types.BlockStatusTypeUnchecked,
+			"failure_reason":                   rollbackFailureReasons(types.Layer1),
+			"eth_amount":                       gorm.Expr("CASE WHEN l1_event_type = ? THEN '' ELSE eth_amount END", types.L1SentMessage),
+			"eth_amount_status":                gorm.Expr("CASE WHEN l1_event_type = ? THEN ? ELSE eth_amount_status END", types.L1SentMessage, types.ETHAmountStatusTypeUnset),
+			"sent_block_time":                  gorm.Expr("CASE WHEN l1_event_type = ? THEN NULL ELSE sent_block_time END", types.L1SentMessage),
+			"sender":                           gorm.Expr("CASE WHEN l1_event_type = ? THEN '' ELSE sender END", types.L1SentMessage),
+			"target":                           gorm.Expr("CASE WHEN l1_event_type = ? THEN '' ELSE target END", types.L1SentMessage),
+			"message_nonce":                    gorm.Expr("CASE WHEN l1_event_type = ? THEN '' ELSE message_nonce END", types.L1SentMessage),
+			"calldata":                         gorm.Expr("CASE WHEN l1_event_type = ? THEN NULL ELSE calldata END", types.L1SentMessage),
+			"stuck_alerted_at":                 nil,
+			"l1_eth_balance_status":            types.ETHBalanceStatusTypeUnchecked,
+			"l1_cross_chain_status":            types.CrossChainStatusTypeUnchecked,
+			"l2_cross_chain_status":            types.CrossChainStatusTypeUnchecked,
+			"l1_block_status_updated_at":       utils.NowUTC(),
+			"l1_eth_balance_status_updated_at": utils.NowUTC(),
+			"l1_cross_chain_status_updated_at": utils.NowUTC(),
+			"l2_cross_chain_status_updated_at": utils.NowUTC(),
+		}
+	case types.Layer2:
+		deleteDB = db.Where("l2_block_number >= ? AND l1_block_number = 0", startBlockNumber)
+		updateDB = db.Model(&MessengerMessageMatch{}).Where("l2_block_number >= ?", startBlockNumber)
+		updateFields = map[string]interface{}{
+			"l2_event_type":                    types.EventTypeUnknown,
+			"l2_block_number":                  0,
+			"l2_tx_hash":                       "",
+			"l2_messenger_eth_balance":         decimal.Zero,
+			"l2_block_status":                  types.BlockStatusTypeUnchecked,
+			"failure_reason":                   rollbackFailureReasons(types.Layer2),
+			"eth_amount":                       gorm.Expr("CASE WHEN l2_event_type = ? THEN '' ELSE eth_amount END", types.L2SentMessage),
+			"eth_amount_status":                gorm.Expr("CASE WHEN l2_event_type = ? THEN ? ELSE eth_amount_status END", types.L2SentMessage, types.ETHAmountStatusTypeUnset),
+			"sent_block_time":                  gorm.Expr("CASE WHEN l2_event_type = ? THEN NULL ELSE sent_block_time END", types.L2SentMessage),
+			"sender":                           gorm.Expr("CASE WHEN l2_event_type = ? THEN '' ELSE sender END", types.L2SentMessage),
+			"target":                           gorm.Expr("CASE WHEN l2_event_type = ? THEN '' ELSE target END", types.L2SentMessage),
+			"message_nonce":                    gorm.Expr("CASE WHEN l2_event_type = ? THEN '' ELSE message_nonce END", types.L2SentMessage),
+			"calldata":                         gorm.Expr("CASE WHEN l2_event_type = ? THEN NULL ELSE calldata END", types.L2SentMessage),
+			"stuck_alerted_at":                 nil,
+			"l2_eth_balance_status":            types.ETHBalanceStatusTypeUnchecked,
+			"l1_cross_chain_status":            types.CrossChainStatusTypeUnchecked,
+			"l2_cross_chain_status":            types.CrossChainStatusTypeUnchecked,
+			"withdraw_root_status":             types.WithdrawRootStatusTypeUnchecked,
+			"message_proof":                    nil,
+			"next_message_nonce":               0,
+			"l2_block_status_updated_at":       utils.NowUTC(),
+			"l2_eth_balance_status_updated_at": utils.NowUTC(),
+			"l1_cross_chain_status_updated_at": utils.NowUTC(),
+			"l2_cross_chain_status_updated_at": utils.NowUTC(),
+			"message_proof_updated_at":         utils.NowUTC(),
+		}
+	default:
+		return fmt.Errorf("MessengerMessageMatch.RollbackEventInfo invalid layer: %v", layer)
+	}
+
+	if err := deleteDB.Unscoped().Delete(&MessengerMessageMatch{}).Error; err != nil {
+		return fmt.Errorf("MessengerMessageMatch.RollbackEventInfo delete failed, layer: %v, start block number: %v, err: %w", layer, startBlockNumber, err)
+	}
+
+	if err := updateDB.Updates(updateFields).Error; err != nil {
+		return fmt.Errorf("MessengerMessageMatch.RollbackEventInfo update failed, layer: %v, start block number: %v, err: %w", layer, startBlockNumber, err)
+	}
+	return nil
+}
@@ -3,10 +3,13 @@ package orm
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/scroll-tech/chain-monitor/internal/types"
+	"github.com/scroll-tech/chain-monitor/internal/utils"
 	"github.com/scroll-tech/chain-monitor/internal/utils/testcontainer"
 )
 
@@ -27,6 +30,10 @@ func TestMessengerMessageMatch_InsertOrUpdateEventInfo(t *testing.T) {
 					L1BlockNumber: 120,
 					L1TxHash:      "0xfc7d3ea5ec8dc9b664a5a886c3b33d21e665355057601033481a439498efb79a",
 					ETHAmount:     "1000",
+					Sender:        "0x6774Bcbd5ceCeF1336b5300fb5186a12DDD8b367",
+					Target:        "0x781e90f1c8Fc4611c9b7497C3B47F99Ef6969CbC",
+					MessageNonce:  "7",
+					Calldata:      []byte{0x8e, 0xaa, 0xc8, 0xa3},
 				}
 				affectRows, err := messengerOrm.InsertOrUpdateEventInfo(ctx, types.Layer1, l1SentEventMsg1)
 				assert.NoError(t, err)
@@ -125,6 +132,10 @@ func TestMessengerMessageMatch_InsertOrUpdateEventInfo(t *testing.T) {
 				assert.Equal(t, msgMatch.L1BlockNumber, uint64(120))
 				assert.Equal(t, msgMatch.L1TxHash, "0xfc7d3ea5ec8dc9b664a5a886c3b33d21e665355057601033481a439498efb79a")
 				assert.Equal(t, msgMatch.ETHAmount, "1000")
+				assert.Equal(t, msgMatch.Sender, "0x6774Bcbd5ceCeF1336b5300fb5186a12DDD8b367")
+				assert.Equal(t, msgMatch.Target, "0x781e90f1c8Fc4611c9b7497C3B47F99Ef6969CbC")
+				assert.Equal(t, msgMatch.MessageNonce, "7")
+				assert.Equal(t, msgMatch.Calldata, []byte{0x8e, 0xaa, 0xc8, 0xa3})
 
 				assert.Equal(t, msgMatch.L2EventType, int(types.L2RelayedMessage))
 				assert.Equal(t, msgMatch.L2BlockNumber, uint64(1200))
@@ -153,3 +164,325 @@ func TestMessengerMessageMatch_InsertOrUpdateEventInfo(t *testing.T) {
 		t.Run(test.name, test.test)
 	}
 }
+
+func TestMessengerMessageMatch_RollbackEventInfo(t *testing.T) {
+	ctx := context.Background()
+	db := testcontainer.SetupDB(ctx, t)
+	messengerOrm := NewMessengerMessageMatch(db)
+
+	events := []struct {
+		layer   types.LayerType
+		message MessengerMessageMatch
+	}{
+		{types.Layer1, MessengerMessageMatch{MessageHash: "0x1", L1EventType: int(types.L1SentMessage), L1BlockNumber: 100, ETHAmount: "100", ETHAmountStatus: int(types.ETHAmountStatusTypeSet),
+			Sender: "0x6774Bcbd5ceCeF1336b5300fb5186a12DDD8b367", Target: "0x781e90f1c8Fc4611c9b7497C3B47F99Ef6969CbC", MessageNonce: "1", Calldata: []byte{0x1}}},
+		{types.Layer2, MessengerMessageMatch{MessageHash: "0x1", L2EventType: int(types.L2RelayedMessage), L2BlockNumber: 200}},
+		{types.Layer2, MessengerMessageMatch{MessageHash: "0x2", L2EventType: int(types.L2SentMessage), L2BlockNumber: 201, ETHAmount: "200", ETHAmountStatus: int(types.ETHAmountStatusTypeSet),
+			Sender: "0x781e90f1c8Fc4611c9b7497C3B47F99Ef6969CbC", Target: "0x6774Bcbd5ceCeF1336b5300fb5186a12DDD8b367", MessageNonce: "2", Calldata: []byte{0x2}}},
+		{types.Layer1, MessengerMessageMatch{MessageHash: "0x2", L1EventType: int(types.L1RelayedMessage), L1BlockNumber: 101}},
+	}
+	for _, event := range events {
+		_, err := messengerOrm.InsertOrUpdateEventInfo(ctx, event.layer, event.message)
+		assert.NoError(t, err)
+	}
+
+	assert.NoError(t, messengerOrm.RollbackEventInfo(ctx, types.Layer1, 100))
+
+	// the message sent on l1 is kept by the l2 relay, the fields of the sent message are cleared with the l1 event.
+	message, err := messengerOrm.GetMessageMatchByMessageHash(ctx, "0x1")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), message.L1BlockNumber)
+	assert.Equal(t, "", message.ETHAmount)
+	assert.Equal(t, int(types.ETHAmountStatusTypeUnset), message.ETHAmountStatus)
+	assert.Equal(t, "", message.Sender)
+	assert.Equal(t, "", message.Target)
+	assert.Equal(t, "", message.MessageNonce)
+	assert.Nil(t, message.Calldata)
+
+	// the message sent on l2 keeps the fields of the sent message after the l1 relay is rolled back.
+	message, err = messengerOrm.GetMessageMatchByMessageHash(ctx, "0x2")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), message.L1BlockNumber)
+	assert.Equal(t, "200", message.ETHAmount)
+	assert.Equal(t, int(types.ETHAmountStatusTypeSet), message.ETHAmountStatus)
+	assert.Equal(t, "2", message.MessageNonce)
+	assert.Equal(t, []byte{0x2}, message.Calldata)
+}
+
+func TestMessengerMessageMatch_GetPendingMessages(t *testing.T) {
+	ctx := context.Background()
+	db := testcontainer.SetupDB(ctx, t)
+	messengerOrm := NewMessengerMessageMatch(db)
+	batchOrm := NewBatch(db)
+
+	sentAt := utils.NowUTC().Add(-2 * time.Hour).Truncate(time.Second)
+	finalizedAt := utils.NowUTC().Add(-30 * time.Minute).Truncate(time.Second)
+	messages := []struct {
+		layer   types.LayerType
+		message MessengerMessageMatch
+	}{
+		{types.Layer1, MessengerMessageMatch{MessageHash: "0x1", L1EventType: int(types.L1SentMessage), L1BlockNumber: 100, SentBlockTime: &sentAt}},
+		{types.Layer1, MessengerMessageMatch{MessageHash: "0x2", L1EventType: int(types.L1SentMessage), L1BlockNumber: 101, SentBlockTime: &sentAt}},
+		{types.Layer2, MessengerMessageMatch{MessageHash: "0x2", L2EventType: int(types.L2RelayedMessage), L2BlockNumber: 200}},
+		{types.Layer2, MessengerMessageMatch{MessageHash: "0x3", L2EventType: int(types.L2SentMessage), L2BlockNumber: 201, SentBlockTime: &sentAt}},
+		// the batch including it isn't finalized.
+		{types.Layer2, MessengerMessageMatch{MessageHash: "0x4", L2EventType: int(types.L2SentMessage), L2BlockNumber: 301, SentBlockTime: &sentAt}},
+	}
+	for _, m := range messages {
+		_, err := messengerOrm.InsertOrUpdateEventInfo(ctx, m.layer, m.message)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, batchOrm.InsertOrUpdateCommittedBatch(ctx, Batch{BatchIndex: 1, StartBlockNumber: 200, EndBlockNumber: 300, CommitBlockNumber: 150}))
+	assert.NoError(t, batchOrm.InsertOrUpdateFinalizedBatch(ctx, Batch{BatchIndex: 1, FinalizeBlockNumber: 160, FinalizeBlockTime: &finalizedAt}))
+	assert.NoError(t, batchOrm.InsertOrUpdateCommittedBatch(ctx, Batch{BatchIndex: 2, StartBlockNumber: 301, EndBlockNumber: 400, CommitBlockNumber: 170}))
+
+	tests := []struct {
+		name string
+		test func(t *testing.T)
+	}{
+		{
+			"getPendingMessages", func(t *testing.T) {
+				pending, err := messengerOrm.GetPendingMessages(ctx, types.Layer1, utils.NowUTC().Add(-time.Hour), 10)
+				assert.NoError(t, err)
+				assert.Len(t, pending, 1)
+				assert.Equal(t, pending[0].MessageHash, "0x1")
+				assert.True(t, pending[0].PendingSince.Equal(sentAt))
+
+				// the l2 sent message is pending since the batch is finalized.
+				pending, err = messengerOrm.GetPendingMessages(ctx, types.Layer2, utils.NowUTC().Add(-time.Hour), 10)
+				assert.NoError(t, err)
+				assert.Len(t, pending, 0)
+
+				pending, err = messengerOrm.GetPendingMessages(ctx, types.Layer2, utils.NowUTC(), 10)
+				assert.NoError(t, err)
+				assert.Len(t, pending, 1)
+				assert.Equal(t, pending[0].MessageHash, "0x3")
+				assert.True(t, pending[0].PendingSince.Equal(finalizedAt))
+			},
+		},
+		{
+			"getPendingMessageAges", func(t *testing.T) {
+				ages, err := messengerOrm.GetPendingMessageAges(ctx, types.Layer1, []time.Duration{time.Hour, 3 * time.Hour})
+				assert.NoError(t, err)
+				assert.Equal(t, ages.Count, uint64(1))
+				assert.Equal(t, ages.BucketCounts, []uint64{0, 1})
+
+				ages, err = messengerOrm.GetPendingMessageAges(ctx, types.Layer2, []time.Duration{time.Hour, 3 * time.Hour})
+				assert.NoError(t, err)
+				assert.Equal(t, ages.Count, uint64(1))
+				assert.Equal(t, ages.BucketCounts, []uint64{1, 1})
+			},
+		},
+		{
+			"updateStuckAlertedAt", func(t *testing.T) {
+				assert.NoError(t, messengerOrm.UpdateStuckAlertedAt(ctx, []string{"0x1"}))
+
+				// the alerted messages are not alerted again.
+				pending, err := messengerOrm.GetPendingMessages(ctx, types.Layer1, utils.NowUTC(), 10)
+				assert.NoError(t, err)
+				assert.Len(t, pending, 0)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, test.test)
+	}
+}
+
+func TestMessengerMessageMatch_GetL2SentMessagesAfterNonce(t *testing.T) {
+	ctx := context.Background()
+	db := testcontainer.SetupDB(ctx, t)
+	messengerOrm := NewMessengerMessageMatch(db)
+
+	for i, hash := range []string{"0x1", "0x2", "0x3", "0x4"} {
+		message := MessengerMessageMatch{
+			MessageHash:      hash,
+			L2EventType:      int(types.L2SentMessage),
+			L2BlockNumber:    uint64(100 + i/2),
+			NextMessageNonce: uint64(i + 1),
+		}
+		_, err := messengerOrm.InsertOrUpdateEventInfo(ctx, types.Layer2, message)
+		assert.NoError(t, err)
+	}
+	err := messengerOrm.UpdateMsgProofAndStatus(ctx, &MessengerMessageMatch{
+		MessageHash:        "0x2",
+		MessageProof:       []byte{},
+		WithdrawRootStatus: int(types.WithdrawRootStatusTypeValid),
+	})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name string
+		test func(t *testing.T)
+	}{
+		{
+			"getLatestValidL2SentMessageMatchBefore", func(t *testing.T) {
+				message, err := messengerOrm.GetLatestValidL2SentMessageMatchBefore(ctx, 4)
+				assert.NoError(t, err)
+				assert.NotNil(t, message)
+				assert.Equal(t, "0x2", message.MessageHash)
+
+				message, err = messengerOrm.GetLatestValidL2SentMessageMatchBefore(ctx, 2)
+				assert.NoError(t, err)
+				assert.Nil(t, message)
+			},
+		},
+		{
+			"getLatestValidL2SentMessageMatchAtBlock", func(t *testing.T) {
+				message, err := messengerOrm.GetLatestValidL2SentMessageMatchAtBlock(ctx, 101)
+				assert.NoError(t, err)
+				assert.NotNil(t, message)
+				assert.Equal(t, "0x2", message.MessageHash)
+
+				message, err = messengerOrm.GetLatestValidL2SentMessageMatchAtBlock(ctx, 99)
+				assert.NoError(t, err)
+				assert.Nil(t, message)
+			},
+		},
+		{
+			"getL2SentMessagesAfterNonce", func(t *testing.T) {
+				messages, err := messengerOrm.GetL2SentMessagesAfterNonce(ctx, 1, 101)
+				assert.NoError(t, err)
+				assert.Len(t, messages, 3)
+				assert.Equal(t, "0x2", messages[0].MessageHash)
+				assert.Equal(t, "0x4", messages[2].MessageHash)
+
+				messages, err = messengerOrm.GetL2SentMessagesAfterNonce(ctx, 0, 100)
+				assert.NoError(t, err)
+				assert.Len(t, messages, 2)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, test.test)
+	}
+}
+
+func TestMessengerMessageMatch_UpdateFailedStatuses(t *testing.T) {
+	ctx := context.Background()
+	db := testcontainer.SetupDB(ctx, t)
+	messengerOrm := NewMessengerMessageMatch(db)
+
+	for i, hash := range []string{"0x1", "0x2"} {
+		_, err := messengerOrm.InsertOrUpdateEventInfo(ctx, types.Layer1, MessengerMessageMatch{MessageHash: hash, L1EventType: int(types.L1SentMessage), L1BlockNumber: uint64(100 + i), ETHAmount: "100"})
+		assert.NoError(t, err)
+		_, err = messengerOrm.InsertOrUpdateEventInfo(ctx, types.Layer2, MessengerMessageMatch{MessageHash: hash, L2EventType: int(types.L2RelayedMessage), L2BlockNumber: uint64(200 + i)})
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, messengerOrm.UpdateBlockStatus(ctx, types.Layer1, 100, 101))
+	assert.NoError(t, messengerOrm.UpdateBlockStatus(ctx, types.Layer2, 200, 201))
+
+	tests := []struct {
+		name string
+		test func(t *testing.T)
+	}{
+		{
+			"updateCrossChainStatus", func(t *testing.T) {
+				messages, err := messengerOrm.GetUncheckedAndDoubleLayerValidMessengerMessageMatches(ctx, types.Layer2, 10)
+				assert.NoError(t, err)
+				assert.Len(t, messages, 2)
+
+				message, err := messengerOrm.GetMessageMatchByMessageHash(ctx, "0x1")
+				assert.NoError(t, err)
+				assert.NoError(t, messengerOrm.UpdateCrossChainStatus(ctx, []int64{message.ID}, types.Layer2, types.CrossChainStatusTypeValid))
+
+				message, err = messengerOrm.GetMessageMatchByMessageHash(ctx, "0x2")
+				assert.NoError(t, err)
+				assert.NoError(t, messengerOrm.UpdateCrossChainStatusFailed(ctx, message.ID, types.Layer2, "Layer2 cross chain check: MismatchTypeL2EventNotMatch"))
+
+				messages, err = messengerOrm.GetUncheckedAndDoubleLayerValidMessengerMessageMatches(ctx, types.Layer2, 10)
+				assert.NoError(t, err)
+				assert.Len(t, messages, 0)
+
+				message, err = messengerOrm.GetMessageMatchByMessageHash(ctx, "0x2")
+				assert.NoError(t, err)
+				assert.Equal(t, int(types.CrossChainStatusTypeFailed), message.L2CrossChainStatus)
+				assert.Equal(t, "Layer2 cross chain check: MismatchTypeL2EventNotMatch", message.FailureReason)
+			},
+		},
+		{
+			"updateETHBalanceFailed", func(t *testing.T) {
+				message, err := messengerOrm.GetMessageMatchByMessageHash(ctx, "0x1")
+				assert.NoError(t, err)
+				err = messengerOrm.UpdateETHBalance(ctx, types.Layer1, MessengerMessageMatch{
+					ID:                    message.ID,
+					L1MessengerETHBalance: decimal.NewFromInt(90),
+					L1ETHBalanceStatus:    int(types.ETHBalanceStatusTypeFailed),
+					FailureReason:         "Layer1 eth balance check: expected messenger balance 100, actual balance 90 at block 100",
+				})
+				assert.NoError(t, err)
+
+				// the failed records keep the actual balance, the next checks start from it.
+				blockNumber, balance, err := messengerOrm.GetETHCheckStartBlockNumberAndBalance(ctx, types.Layer1)
+				assert.NoError(t, err)
+				assert.Equal(t, int64(90), balance.Int64())
+				assert.Equal(t, message.L1BlockNumber, blockNumber)
+
+				message, err = messengerOrm.GetMessageMatchByMessageHash(ctx, "0x1")
+				assert.NoError(t, err)
+				assert.Equal(t, int(types.ETHBalanceStatusTypeFailed), message.L1ETHBalanceStatus)
+				assert.Equal(t, "Layer1 eth balance check: expected messenger balance 100, actual balance 90 at block 100", message.FailureReason)
+			},
+		},
+		{
+			"failureReasonsKeyedByCheck", func(t *testing.T) {
+				message, err := messengerOrm.GetMessageMatchByMessageHash(ctx, "0x1")
+				assert.NoError(t, err)
+
+				// the failure reasons of the checks are kept together, the later failure of a check replaces its reason.
+				assert.NoError(t, messengerOrm.UpdateCrossChainStatusFailed(ctx, message.ID, types.Layer2, "Layer2 cross chain check: MismatchTypeL2EventNotMatch"))
+				err = messengerOrm.UpdateETHBalance(ctx, types.Layer1, MessengerMessageMatch{
+					ID:                    message.ID,
+					L1MessengerETHBalance: decimal.NewFromInt(80),
+					L1ETHBalanceStatus:    int(types.ETHBalanceStatusTypeFailed),
+					FailureReason:         "Layer1 eth balance check: expected messenger balance 100, actual balance 80 at block 100",
+				})
+				assert.NoError(t, err)
+				message, err = messengerOrm.GetMessageMatchByMessageHash(ctx, "0x1")
+				assert.NoError(t, err)
+				assert.Equal(t, "Layer2 cross chain check: MismatchTypeL2EventNotMatch; Layer1 eth balance check: expected messenger balance 100, actual balance 80 at block 100", message.FailureReason)
+
+				// rolling back l2 resets the cross chain checks, the eth balance check of l1 is kept.
+				assert.NoError(t, messengerOrm.RollbackEventInfo(ctx, types.Layer2, 200))
+				message, err = messengerOrm.GetMessageMatchByMessageHash(ctx, "0x1")
+				assert.NoError(t, err)
+				assert.Equal(t, "Layer1 eth balance check: expected messenger balance 100, actual balance 80 at block 100", message.FailureReason)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, test.test)
+	}
+}
+
+func TestMessengerMessageMatch_GetLatestL1SentMessageMatch(t *testing.T) {
+	ctx := context.Background()
+	db := testcontainer.SetupDB(ctx, t)
+	messengerOrm := NewMessengerMessageMatch(db)
+
+	message, err := messengerOrm.GetLatestL1SentMessageMatch(ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, message)
+
+	messages := []MessengerMessageMatch{
+		{MessageHash: "0x1", L1EventType: int(types.L1SentMessage), L1BlockNumber: 100, MessageNonce: "9"},
+		{MessageHash: "0x2", L1EventType: int(types.L1SentMessage), L1BlockNumber: 101, MessageNonce: "11"},
+		{MessageHash: "0x3", L1EventType: int(types.L1SentMessage), L1BlockNumber: 101, MessageNonce: "10"},
+		// the messages ingested before the message nonces are stored.
+		{MessageHash: "0x4", L1EventType: int(types.L1SentMessage), L1BlockNumber: 102},
+		{MessageHash: "0x5", L1EventType: int(types.L1RelayedMessage), L1BlockNumber: 103},
+	}
+	for _, m := range messages {
+		_, err = messengerOrm.InsertOrUpdateEventInfo(ctx, types.Layer1, m)
+		assert.NoError(t, err)
+	}
+
+	message, err = messengerOrm.GetLatestL1SentMessageMatch(ctx)
+	assert.NoError(t, err)
+	assert.NotNil(t, message)
+	assert.Equal(t, "0x2", message.MessageHash)
+	assert.Equal(t, "11", message.MessageNonce)
+}
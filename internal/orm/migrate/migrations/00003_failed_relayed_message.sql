@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose FailedRelayedMessageBegin
+CREATE TABLE failed_relayed_message
+(
+    id                               BIGSERIAL       PRIMARY KEY,
+    message_hash                     VARCHAR         NOT NULL,
+    layer                            INTEGER         NOT NULL,
+
+    -- failure info
+    failed_count                     BIGINT          NOT NULL,
+    last_failed_block_number         BIGINT          NOT NULL,
+    last_failed_tx_hash              VARCHAR         NOT NULL,
+
+    -- status
+    status                           INTEGER         NOT NULL,
+
+    first_failed_at                  TIMESTAMP(0)    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_failed_at                   TIMESTAMP(0)    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    status_updated_at                TIMESTAMP(0)    DEFAULT NULL,
+    created_at                       TIMESTAMP(0)    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at                       TIMESTAMP(0)    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at                       TIMESTAMP(0)    DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX if not exists idx_frm_message_hash_layer ON failed_relayed_message (message_hash, layer);
+CREATE INDEX if not exists idx_frm_layer_status_id ON failed_relayed_message (layer, status, id);
+-- +goose FailedRelayedMessageEnd
+
+-- +goose Down
+-- +goose FailedRelayedMessageBegin
+drop table if exists failed_relayed_message;
+-- +goose FailedRelayedMessageEnd
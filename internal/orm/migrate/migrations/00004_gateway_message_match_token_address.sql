@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose GatewayMessageMatchTokenAddressBegin
+ALTER TABLE gateway_message_match
+    ADD COLUMN l1_token_address VARCHAR NOT NULL DEFAULT '',
+    ADD COLUMN l2_token_address VARCHAR NOT NULL DEFAULT '';
+-- +goose GatewayMessageMatchTokenAddressEnd
+
+-- +goose Down
+-- +goose GatewayMessageMatchTokenAddressBegin
+ALTER TABLE gateway_message_match
+    DROP COLUMN IF EXISTS l1_token_address,
+    DROP COLUMN IF EXISTS l2_token_address;
+-- +goose GatewayMessageMatchTokenAddressEnd
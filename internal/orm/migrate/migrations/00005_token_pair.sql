@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose TokenPairBegin
+CREATE TABLE token_pair
+(
+    id                               BIGSERIAL       PRIMARY KEY,
+    token_type                       INTEGER         NOT NULL,
+    l1_token_address                 VARCHAR         NOT NULL,
+    l2_token_address                 VARCHAR         NOT NULL,
+    source                           INTEGER         NOT NULL,
+
+    created_at                       TIMESTAMP(0)    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at                       TIMESTAMP(0)    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at                       TIMESTAMP(0)    DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX if not exists idx_tp_l1_token_address ON token_pair (l1_token_address);
+CREATE UNIQUE INDEX if not exists idx_tp_l2_token_address ON token_pair (l2_token_address);
+-- +goose TokenPairEnd
+
+-- +goose Down
+-- +goose TokenPairBegin
+drop table if exists token_pair;
+-- +goose TokenPairEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose ProcessedBlockHashBegin
+CREATE TABLE processed_block_hash
+(
+    id                               BIGSERIAL       PRIMARY KEY,
+    layer                            INTEGER         NOT NULL,
+    block_number                     BIGINT          NOT NULL,
+    block_hash                       VARCHAR         NOT NULL,
+
+    created_at                       TIMESTAMP(0)    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at                       TIMESTAMP(0)    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at                       TIMESTAMP(0)    DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX if not exists idx_pbh_layer_block_number ON processed_block_hash (layer, block_number);
+-- +goose ProcessedBlockHashEnd
+
+-- +goose Down
+-- +goose ProcessedBlockHashBegin
+drop table if exists processed_block_hash;
+-- +goose ProcessedBlockHashEnd
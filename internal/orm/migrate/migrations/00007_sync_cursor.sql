@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose SyncCursorBegin
+CREATE TABLE sync_cursor
+(
+    id                               BIGSERIAL       PRIMARY KEY,
+    layer                            INTEGER         NOT NULL,
+    checker                          VARCHAR         NOT NULL,
+    block_number                     BIGINT          NOT NULL,
+    block_hash                       VARCHAR         NOT NULL,
+
+    created_at                       TIMESTAMP(0)    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at                       TIMESTAMP(0)    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at                       TIMESTAMP(0)    DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX if not exists idx_sc_layer_checker ON sync_cursor (layer, checker);
+-- +goose SyncCursorEnd
+
+-- +goose Down
+-- +goose SyncCursorBegin
+drop table if exists sync_cursor;
+-- +goose SyncCursorEnd
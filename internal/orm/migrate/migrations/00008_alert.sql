@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose AlertBegin
+CREATE TABLE alert
+(
+    id                               BIGSERIAL       PRIMARY KEY,
+    fingerprint                      VARCHAR         NOT NULL,
+    category                         VARCHAR         NOT NULL,
+    severity                         VARCHAR         NOT NULL,
+    title                            VARCHAR         NOT NULL,
+    layer                            INTEGER         NOT NULL DEFAULT 0,
+    message_hash                     VARCHAR         NOT NULL DEFAULT '',
+    block_number                     BIGINT          NOT NULL DEFAULT 0,
+
+    occurrence_count                 BIGINT          NOT NULL,
+    resolved                         BOOLEAN         NOT NULL DEFAULT false,
+
+    first_seen_at                    TIMESTAMP(0)    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at                     TIMESTAMP(0)    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_notified_at                 TIMESTAMP(0)    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    resolved_at                      TIMESTAMP(0)    DEFAULT NULL,
+    created_at                       TIMESTAMP(0)    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at                       TIMESTAMP(0)    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at                       TIMESTAMP(0)    DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX if not exists idx_alert_fingerprint ON alert (fingerprint);
+CREATE INDEX if not exists idx_alert_resolved_category ON alert (resolved, category);
+-- +goose AlertEnd
+
+-- +goose Down
+-- +goose AlertBegin
+drop table if exists alert;
+-- +goose AlertEnd
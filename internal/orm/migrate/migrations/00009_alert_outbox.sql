@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose AlertOutboxBegin
+CREATE TABLE alert_outbox
+(
+    id                               BIGSERIAL       PRIMARY KEY,
+    sink                             VARCHAR         NOT NULL,
+    category                         VARCHAR         NOT NULL,
+    payload                          TEXT            NOT NULL,
+
+    status                           INTEGER         NOT NULL,
+    attempts                         INTEGER         NOT NULL DEFAULT 0,
+    last_error                       TEXT            NOT NULL DEFAULT '',
+    next_attempt_at                  TIMESTAMP(0)    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    sent_at                          TIMESTAMP(0)    DEFAULT NULL,
+
+    created_at                       TIMESTAMP(0)    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at                       TIMESTAMP(0)    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at                       TIMESTAMP(0)    DEFAULT NULL
+);
+
+CREATE INDEX if not exists idx_ao_sink_status_next_attempt_at ON alert_outbox (sink, status, next_attempt_at);
+-- +goose AlertOutboxEnd
+
+-- +goose Down
+-- +goose AlertOutboxBegin
+drop table if exists alert_outbox;
+-- +goose AlertOutboxEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose PendingIndexBegin
+CREATE INDEX if not exists idx_mmm_l1event_l2event_created_at ON messenger_message_match (l1_event_type, l2_event_type, created_at);
+-- +goose PendingIndexEnd
+
+-- +goose Down
+-- +goose PendingIndexBegin
+drop index if exists idx_mmm_l1event_l2event_created_at;
+-- +goose PendingIndexEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose MessageMatchTxHashIndexBegin
+CREATE INDEX if not exists idx_gmm_l1_tx_hash ON gateway_message_match (l1_tx_hash);
+CREATE INDEX if not exists idx_gmm_l2_tx_hash ON gateway_message_match (l2_tx_hash);
+CREATE INDEX if not exists idx_mmm_l1_tx_hash ON messenger_message_match (l1_tx_hash);
+CREATE INDEX if not exists idx_mmm_l2_tx_hash ON messenger_message_match (l2_tx_hash);
+-- +goose MessageMatchTxHashIndexEnd
+
+-- +goose Down
+-- +goose MessageMatchTxHashIndexBegin
+drop index if exists idx_gmm_l1_tx_hash;
+drop index if exists idx_gmm_l2_tx_hash;
+drop index if exists idx_mmm_l1_tx_hash;
+drop index if exists idx_mmm_l2_tx_hash;
+-- +goose MessageMatchTxHashIndexEnd
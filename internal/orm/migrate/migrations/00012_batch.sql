@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose BatchBegin
+CREATE TABLE batch
+(
+    id                               BIGSERIAL       PRIMARY KEY,
+    batch_index                      BIGINT          NOT NULL,
+    batch_hash                       VARCHAR         NOT NULL DEFAULT '',
+
+    -- l2 block range, which is decoded from the commit tx calldata
+    start_block_number               BIGINT          NOT NULL DEFAULT 0,
+    end_block_number                 BIGINT          NOT NULL DEFAULT 0,
+
+    -- l1 commit info
+    commit_block_number              BIGINT          NOT NULL DEFAULT 0,
+    commit_tx_hash                   VARCHAR         NOT NULL DEFAULT '',
+
+    -- l1 finalize info
+    finalize_block_number            BIGINT          NOT NULL DEFAULT 0,
+    finalize_tx_hash                 VARCHAR         NOT NULL DEFAULT '',
+    state_root                       VARCHAR         NOT NULL DEFAULT '',
+    withdraw_root                    VARCHAR         NOT NULL DEFAULT '',
+
+    -- status
+    local_withdraw_root              VARCHAR         NOT NULL DEFAULT '',
+    withdraw_root_status             INTEGER         NOT NULL DEFAULT 0,
+
+    withdraw_root_status_updated_at  TIMESTAMP(0)    DEFAULT NULL,
+    created_at                       TIMESTAMP(0)    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at                       TIMESTAMP(0)    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at                       TIMESTAMP(0)    DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX if not exists idx_batch_batch_index ON batch (batch_index);
+CREATE INDEX if not exists idx_batch_withdraw_root_status ON batch (withdraw_root_status, batch_index);
+CREATE INDEX if not exists idx_batch_commit_block_number ON batch (commit_block_number);
+CREATE INDEX if not exists idx_batch_finalize_block_number ON batch (finalize_block_number);
+-- +goose BatchEnd
+
+-- +goose Down
+-- +goose BatchBegin
+drop table if exists batch;
+-- +goose BatchEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose BatchStatusBegin
+ALTER TABLE batch
+    ADD COLUMN status INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN finalize_checked BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN revert_block_number BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN revert_tx_hash VARCHAR NOT NULL DEFAULT '',
+    ADD COLUMN status_updated_at TIMESTAMP(0) DEFAULT NULL,
+    ADD COLUMN finalize_block_time TIMESTAMP(0) DEFAULT NULL;
+
+-- the block times of the finalized batches are unknown, the update times are the closest approximation.
+UPDATE batch
+SET finalize_block_time = updated_at
+WHERE finalize_block_number > 0;
+
+CREATE INDEX if not exists idx_batch_status ON batch (status, revert_block_number, batch_index DESC);
+CREATE INDEX if not exists idx_batch_revert_block_number ON batch (revert_block_number);
+CREATE INDEX if not exists idx_batch_end_block_number ON batch (end_block_number);
+-- +goose BatchStatusEnd
+
+-- +goose Down
+-- +goose BatchStatusBegin
+drop index if exists idx_batch_status;
+drop index if exists idx_batch_revert_block_number;
+drop index if exists idx_batch_end_block_number;
+ALTER TABLE batch
+    DROP COLUMN IF EXISTS status,
+    DROP COLUMN IF EXISTS finalize_checked,
+    DROP COLUMN IF EXISTS revert_block_number,
+    DROP COLUMN IF EXISTS revert_tx_hash,
+    DROP COLUMN IF EXISTS status_updated_at,
+    DROP COLUMN IF EXISTS finalize_block_time;
+-- +goose BatchStatusEnd
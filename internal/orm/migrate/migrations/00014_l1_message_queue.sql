@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose L1MessageQueueBegin
+CREATE TABLE l1_message_queue
+(
+    id                      BIGSERIAL       PRIMARY KEY,
+    queue_index             BIGINT          NOT NULL,
+
+    -- l1 enqueue info
+    sender                  VARCHAR         NOT NULL DEFAULT '',
+    target                  VARCHAR         NOT NULL DEFAULT '',
+    value                   VARCHAR         NOT NULL DEFAULT '',
+    gas_limit               VARCHAR         NOT NULL DEFAULT '',
+    enqueue_block_number    BIGINT          NOT NULL DEFAULT 0,
+    enqueue_tx_hash         VARCHAR         NOT NULL DEFAULT '',
+
+    -- l1 dequeue info, the messages are popped by the committed batches which include them on l2,
+    -- and the skipped messages are popped without being included
+    dequeue_block_number    BIGINT          NOT NULL DEFAULT 0,
+    dequeue_tx_hash         VARCHAR         NOT NULL DEFAULT '',
+    skipped                 BOOLEAN         NOT NULL DEFAULT false,
+    drop_block_number       BIGINT          NOT NULL DEFAULT 0,
+
+    created_at              TIMESTAMP(0)    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at              TIMESTAMP(0)    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at              TIMESTAMP(0)    DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX if not exists idx_l1_message_queue_queue_index ON l1_message_queue (queue_index);
+CREATE INDEX if not exists idx_l1_message_queue_enqueue_block_number ON l1_message_queue (enqueue_block_number);
+CREATE INDEX if not exists idx_l1_message_queue_dequeue_block_number ON l1_message_queue (dequeue_block_number);
+-- +goose L1MessageQueueEnd
+
+-- +goose Down
+-- +goose L1MessageQueueBegin
+drop table if exists l1_message_queue;
+-- +goose L1MessageQueueEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose MessageMatchFailureReasonBegin
+ALTER TABLE gateway_message_match
+    ADD COLUMN failure_reason VARCHAR NOT NULL DEFAULT '';
+
+ALTER TABLE messenger_message_match
+    ADD COLUMN failure_reason VARCHAR NOT NULL DEFAULT '';
+-- +goose MessageMatchFailureReasonEnd
+
+-- +goose Down
+-- +goose MessageMatchFailureReasonBegin
+ALTER TABLE gateway_message_match
+    DROP COLUMN IF EXISTS failure_reason;
+
+ALTER TABLE messenger_message_match
+    DROP COLUMN IF EXISTS failure_reason;
+-- +goose MessageMatchFailureReasonEnd
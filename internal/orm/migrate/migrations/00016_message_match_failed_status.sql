@@ -0,0 +1,95 @@
+-- +goose Up
+-- +goose MessageMatchFailedStatusBegin
+-- The checks which were alerted before left their statuses unchecked, mark them failed with the alert titles. Each failure
+-- reason is keyed by its check and appended to the ones recorded by the other checks.
+UPDATE gateway_message_match AS m
+SET l1_cross_chain_status = 2,
+    failure_reason        = CASE WHEN m.failure_reason = '' THEN '' ELSE m.failure_reason || '; ' END || 'Layer1 cross chain check: ' || a.title
+FROM alert AS a
+WHERE a.category = 'cross_chain_gateway'
+  AND a.layer = 1
+  AND a.resolved = false
+  AND a.message_hash = m.message_hash
+  AND m.l1_cross_chain_status = 0;
+
+UPDATE gateway_message_match AS m
+SET l2_cross_chain_status = 2,
+    failure_reason        = CASE WHEN m.failure_reason = '' THEN '' ELSE m.failure_reason || '; ' END || 'Layer2 cross chain check: ' || a.title
+FROM alert AS a
+WHERE a.category = 'cross_chain_gateway'
+  AND a.layer = 2
+  AND a.resolved = false
+  AND a.message_hash = m.message_hash
+  AND m.l2_cross_chain_status = 0;
+
+UPDATE messenger_message_match AS m
+SET l1_cross_chain_status = 2,
+    failure_reason        = CASE WHEN m.failure_reason = '' THEN '' ELSE m.failure_reason || '; ' END || 'Layer1 cross chain check: ' || a.title
+FROM alert AS a
+WHERE a.category = 'cross_chain_messenger'
+  AND a.layer = 1
+  AND a.resolved = false
+  AND a.message_hash = m.message_hash
+  AND m.l1_cross_chain_status = 0;
+
+UPDATE messenger_message_match AS m
+SET l2_cross_chain_status = 2,
+    failure_reason        = CASE WHEN m.failure_reason = '' THEN '' ELSE m.failure_reason || '; ' END || 'Layer2 cross chain check: ' || a.title
+FROM alert AS a
+WHERE a.category = 'cross_chain_messenger'
+  AND a.layer = 2
+  AND a.resolved = false
+  AND a.message_hash = m.message_hash
+  AND m.l2_cross_chain_status = 0;
+
+-- The eth balance check is alerted at the last message of the block, all the messages of the block failed the check.
+UPDATE messenger_message_match AS m
+SET l1_eth_balance_status = 2,
+    failure_reason        = CASE WHEN m.failure_reason = '' THEN '' ELSE m.failure_reason || '; ' END || 'Layer1 eth balance check: ' || a.title
+FROM alert AS a
+WHERE a.category = 'cross_chain_eth'
+  AND a.layer = 1
+  AND a.resolved = false
+  AND a.block_number = m.l1_block_number
+  AND m.l1_eth_balance_status = 0;
+
+UPDATE messenger_message_match AS m
+SET l2_eth_balance_status = 2,
+    failure_reason        = CASE WHEN m.failure_reason = '' THEN '' ELSE m.failure_reason || '; ' END || 'Layer2 eth balance check: ' || a.title
+FROM alert AS a
+WHERE a.category = 'cross_chain_eth'
+  AND a.layer = 2
+  AND a.resolved = false
+  AND a.block_number = m.l2_block_number
+  AND m.l2_eth_balance_status = 0;
+
+-- The withdraw root check is alerted at the block, the l2 sent messages of the block failed the check. The messages after
+-- them are failed by the next withdraw root check, since the withdraw trie diverged.
+UPDATE messenger_message_match AS m
+SET withdraw_root_status = 2,
+    failure_reason       = CASE WHEN m.failure_reason = '' THEN '' ELSE m.failure_reason || '; ' END || 'Layer2 withdraw root check: ' || a.title
+FROM alert AS a
+WHERE a.category = 'withdraw_root'
+  AND a.layer = 2
+  AND a.resolved = false
+  AND a.block_number = m.l2_block_number
+  AND m.l2_event_type = 3
+  AND m.withdraw_root_status = 0;
+-- +goose MessageMatchFailedStatusEnd
+
+-- +goose Down
+-- +goose MessageMatchFailedStatusBegin
+UPDATE gateway_message_match
+SET l1_cross_chain_status = CASE WHEN l1_cross_chain_status = 2 THEN 0 ELSE l1_cross_chain_status END,
+    l2_cross_chain_status = CASE WHEN l2_cross_chain_status = 2 THEN 0 ELSE l2_cross_chain_status END,
+    failure_reason        = CASE WHEN l1_block_status = 2 OR l2_block_status = 2 THEN failure_reason ELSE '' END
+WHERE l1_cross_chain_status = 2 OR l2_cross_chain_status = 2;
+
+UPDATE messenger_message_match
+SET l1_cross_chain_status = CASE WHEN l1_cross_chain_status = 2 THEN 0 ELSE l1_cross_chain_status END,
+    l2_cross_chain_status = CASE WHEN l2_cross_chain_status = 2 THEN 0 ELSE l2_cross_chain_status END,
+    l1_eth_balance_status = CASE WHEN l1_eth_balance_status = 2 THEN 0 ELSE l1_eth_balance_status END,
+    l2_eth_balance_status = CASE WHEN l2_eth_balance_status = 2 THEN 0 ELSE l2_eth_balance_status END,
+    failure_reason        = CASE WHEN l1_block_status = 2 OR l2_block_status = 2 THEN failure_reason ELSE '' END
+WHERE l1_cross_chain_status = 2 OR l2_cross_chain_status = 2 OR l1_eth_balance_status = 2 OR l2_eth_balance_status = 2;
+-- +goose MessageMatchFailedStatusEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose MessengerMessageMatchMessageFieldsBegin
+ALTER TABLE messenger_message_match
+    ADD COLUMN sender        VARCHAR NOT NULL DEFAULT '',
+    ADD COLUMN target        VARCHAR NOT NULL DEFAULT '',
+    ADD COLUMN message_nonce VARCHAR NOT NULL DEFAULT '',
+    ADD COLUMN calldata      BYTEA   DEFAULT NULL;
+-- +goose MessengerMessageMatchMessageFieldsEnd
+
+-- +goose Down
+-- +goose MessengerMessageMatchMessageFieldsBegin
+ALTER TABLE messenger_message_match
+    DROP COLUMN IF EXISTS sender,
+    DROP COLUMN IF EXISTS target,
+    DROP COLUMN IF EXISTS message_nonce,
+    DROP COLUMN IF EXISTS calldata;
+-- +goose MessengerMessageMatchMessageFieldsEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose FailedRelayedMessageEventBegin
+CREATE TABLE failed_relayed_message_event
+(
+    id                               BIGSERIAL       PRIMARY KEY,
+    message_hash                     VARCHAR         NOT NULL,
+    layer                            INTEGER         NOT NULL,
+    block_number                     BIGINT          NOT NULL,
+    tx_hash                          VARCHAR         NOT NULL,
+    log_index                        INTEGER         NOT NULL,
+    block_time                       TIMESTAMP(0)    NOT NULL,
+
+    created_at                       TIMESTAMP(0)    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at                       TIMESTAMP(0)    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at                       TIMESTAMP(0)    DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX if not exists idx_frme_layer_tx_hash_log_index ON failed_relayed_message_event (layer, tx_hash, log_index);
+CREATE INDEX if not exists idx_frme_layer_block_number ON failed_relayed_message_event (layer, block_number);
+CREATE INDEX if not exists idx_frme_message_hash_layer ON failed_relayed_message_event (message_hash, layer);
+
+-- the failures before this migration are unknown, the first failed block numbers of them are 0.
+ALTER TABLE failed_relayed_message
+    ADD COLUMN first_failed_block_number BIGINT NOT NULL DEFAULT 0;
+-- +goose FailedRelayedMessageEventEnd
+
+-- +goose Down
+-- +goose FailedRelayedMessageEventBegin
+ALTER TABLE failed_relayed_message
+    DROP COLUMN IF EXISTS first_failed_block_number;
+drop table if exists failed_relayed_message_event;
+-- +goose FailedRelayedMessageEventEnd
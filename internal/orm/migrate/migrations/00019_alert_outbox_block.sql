@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose AlertOutboxBlockBegin
+ALTER TABLE alert_outbox
+    ADD COLUMN layer        INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN block_number BIGINT  NOT NULL DEFAULT 0;
+
+CREATE INDEX if not exists idx_ao_layer_block_number ON alert_outbox (layer, block_number);
+CREATE INDEX if not exists idx_alert_layer_block_number ON alert (layer, block_number);
+-- +goose AlertOutboxBlockEnd
+
+-- +goose Down
+-- +goose AlertOutboxBlockBegin
+drop index if exists idx_alert_layer_block_number;
+ALTER TABLE alert_outbox
+    DROP COLUMN IF EXISTS layer,
+    DROP COLUMN IF EXISTS block_number;
+-- +goose AlertOutboxBlockEnd
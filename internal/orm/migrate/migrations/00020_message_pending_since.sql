@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose MessagePendingSinceBegin
+ALTER TABLE messenger_message_match
+    ADD COLUMN sent_block_time  TIMESTAMP(0) DEFAULT NULL,
+    ADD COLUMN stuck_alerted_at TIMESTAMP(0) DEFAULT NULL;
+
+-- the block times of the ingested messages are unknown, the ingestion times are the closest approximation.
+UPDATE messenger_message_match
+SET sent_block_time = created_at
+WHERE l1_event_type = 1 OR l2_event_type = 3;
+
+CREATE INDEX if not exists idx_mmm_sent_block_time ON messenger_message_match (sent_block_time);
+-- +goose MessagePendingSinceEnd
+
+-- +goose Down
+-- +goose MessagePendingSinceBegin
+drop index if exists idx_mmm_sent_block_time;
+ALTER TABLE messenger_message_match
+    DROP COLUMN IF EXISTS sent_block_time,
+    DROP COLUMN IF EXISTS stuck_alerted_at;
+-- +goose MessagePendingSinceEnd
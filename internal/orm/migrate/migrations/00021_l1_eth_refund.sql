@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose L1ETHRefundBegin
+CREATE TABLE l1_eth_refund
+(
+    id                  BIGSERIAL       PRIMARY KEY,
+    event_type          INTEGER         NOT NULL,
+    block_number        BIGINT          NOT NULL,
+    tx_hash             VARCHAR         NOT NULL,
+    log_index           INTEGER         NOT NULL,
+    gateway_address     VARCHAR         NOT NULL,
+    amount              VARCHAR         NOT NULL,
+
+    created_at          TIMESTAMP(0)    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at          TIMESTAMP(0)    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at          TIMESTAMP(0)    DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX if not exists idx_ler_tx_hash_log_index ON l1_eth_refund (tx_hash, log_index);
+CREATE INDEX if not exists idx_ler_block_number ON l1_eth_refund (block_number);
+-- +goose L1ETHRefundEnd
+
+-- +goose Down
+-- +goose L1ETHRefundBegin
+drop table if exists l1_eth_refund;
+-- +goose L1ETHRefundEnd
@@ -0,0 +1,103 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/scroll-tech/chain-monitor/internal/types"
+)
+
+// ProcessedBlockHash contains the hash of the last block of each processed block range.
+type ProcessedBlockHash struct {
+	db *gorm.DB `gorm:"column:-"`
+
+	ID          int64  `json:"id" gorm:"column:id"`
+	Layer       int    `json:"layer" gorm:"layer"`
+	BlockNumber uint64 `json:"block_number" gorm:"block_number"`
+	BlockHash   string `json:"block_hash" gorm:"block_hash"`
+
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at"`
+}
+
+// NewProcessedBlockHash creates a new ProcessedBlockHash database instance.
+func NewProcessedBlockHash(db *gorm.DB) *ProcessedBlockHash {
+	return &ProcessedBlockHash{db: db}
+}
+
+// TableName returns the table name for the ProcessedBlockHash model.
+func (*ProcessedBlockHash) TableName() string {
+	return "processed_block_hash"
+}
+
+// GetLatestProcessedBlockHashes get the latest processed block hashes of the layer, ordered by block number desc.
+func (p *ProcessedBlockHash) GetLatestProcessedBlockHashes(ctx context.Context, layer types.LayerType, offset, limit int) ([]ProcessedBlockHash, error) {
+	var blockHashes []ProcessedBlockHash
+	db := p.db.WithContext(ctx)
+	db = db.Where("layer = ?", layer)
+	db = db.Order("block_number desc")
+	db = db.Offset(offset)
+	db = db.Limit(limit)
+	if err := db.Find(&blockHashes).Error; err != nil {
+		log.Warn("ProcessedBlockHash.GetLatestProcessedBlockHashes failed", "error", err)
+		return nil, fmt.Errorf("ProcessedBlockHash.GetLatestProcessedBlockHashes failed err:%w", err)
+	}
+	return blockHashes, nil
+}
+
+// InsertOrUpdateProcessedBlockHash insert or update the processed block hash.
+func (p *ProcessedBlockHash) InsertOrUpdateProcessedBlockHash(ctx context.Context, blockHash ProcessedBlockHash, dbTX ...*gorm.DB) error {
+	db := p.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+
+	db = db.WithContext(ctx)
+	db = db.Model(&ProcessedBlockHash{})
+	db = db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "layer"}, {Name: "block_number"}},
+		DoUpdates: clause.AssignmentColumns([]string{"block_hash"}),
+	})
+
+	if err := db.Create(&blockHash).Error; err != nil {
+		return fmt.Errorf("ProcessedBlockHash.InsertOrUpdateProcessedBlockHash error: %w, block hash: %v", err, blockHash)
+	}
+	return nil
+}
+
+// DeleteProcessedBlockHashes deletes the processed block hashes of the layer whose block number >= startBlockNumber.
+func (p *ProcessedBlockHash) DeleteProcessedBlockHashes(ctx context.Context, layer types.LayerType, startBlockNumber uint64, dbTX ...*gorm.DB) error {
+	db := p.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+
+	db = db.WithContext(ctx)
+	db = db.Where("layer = ? AND block_number >= ?", layer, startBlockNumber)
+	if err := db.Unscoped().Delete(&ProcessedBlockHash{}).Error; err != nil {
+		return fmt.Errorf("ProcessedBlockHash.DeleteProcessedBlockHashes failed, layer: %v, start block number: %v, err: %w", layer, startBlockNumber, err)
+	}
+	return nil
+}
+
+// PruneProcessedBlockHashes deletes the processed block hashes of the layer whose block number < endBlockNumber,
+// the blocks are final so they're never reorged.
+func (p *ProcessedBlockHash) PruneProcessedBlockHashes(ctx context.Context, layer types.LayerType, endBlockNumber uint64, dbTX ...*gorm.DB) error {
+	db := p.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+
+	db = db.WithContext(ctx)
+	db = db.Where("layer = ? AND block_number < ?", layer, endBlockNumber)
+	if err := db.Unscoped().Delete(&ProcessedBlockHash{}).Error; err != nil {
+		return fmt.Errorf("ProcessedBlockHash.PruneProcessedBlockHashes failed, layer: %v, end block number: %v, err: %w", layer, endBlockNumber, err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package orm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scroll-tech/chain-monitor/internal/types"
+	"github.com/scroll-tech/chain-monitor/internal/utils/testcontainer"
+)
+
+func TestProcessedBlockHash(t *testing.T) {
+	ctx := context.Background()
+	db := testcontainer.SetupDB(ctx, t)
+	processedBlockHashOrm := NewProcessedBlockHash(db)
+
+	tests := []struct {
+		name string
+		test func(t *testing.T)
+	}{
+		{
+			"insertProcessedBlockHash", func(t *testing.T) {
+				for _, blockNumber := range []uint64{50, 100, 150} {
+					blockHash := ProcessedBlockHash{
+						Layer:       int(types.Layer1),
+						BlockNumber: blockNumber,
+						BlockHash:   "0xfc7d3ea5ec8dc9b664a5a886c3b33d21e665355057601033481a439498efb79a",
+					}
+					assert.NoError(t, processedBlockHashOrm.InsertOrUpdateProcessedBlockHash(ctx, blockHash))
+				}
+
+				blockHash := ProcessedBlockHash{
+					Layer:       int(types.Layer1),
+					BlockNumber: 150,
+					BlockHash:   "0x1c7d3ea5ec8dc9b664a5a886c3b33d21e665355057601033481a439498efb79a",
+				}
+				assert.NoError(t, processedBlockHashOrm.InsertOrUpdateProcessedBlockHash(ctx, blockHash))
+			},
+		},
+		{
+			"getLatestProcessedBlockHashes", func(t *testing.T) {
+				blockHashes, err := processedBlockHashOrm.GetLatestProcessedBlockHashes(ctx, types.Layer1, 0, 2)
+				assert.NoError(t, err)
+				assert.Len(t, blockHashes, 2)
+				assert.Equal(t, blockHashes[0].BlockNumber, uint64(150))
+				assert.Equal(t, blockHashes[0].BlockHash, "0x1c7d3ea5ec8dc9b664a5a886c3b33d21e665355057601033481a439498efb79a")
+				assert.Equal(t, blockHashes[1].BlockNumber, uint64(100))
+
+				blockHashes, err = processedBlockHashOrm.GetLatestProcessedBlockHashes(ctx, types.Layer2, 0, 2)
+				assert.NoError(t, err)
+				assert.Len(t, blockHashes, 0)
+			},
+		},
+		{
+			"deleteProcessedBlockHashes", func(t *testing.T) {
+				assert.NoError(t, processedBlockHashOrm.DeleteProcessedBlockHashes(ctx, types.Layer1, 100))
+
+				blockHashes, err := processedBlockHashOrm.GetLatestProcessedBlockHashes(ctx, types.Layer1, 0, 10)
+				assert.NoError(t, err)
+				assert.Len(t, blockHashes, 1)
+				assert.Equal(t, blockHashes[0].BlockNumber, uint64(50))
+			},
+		},
+		{
+			"pruneProcessedBlockHashes", func(t *testing.T) {
+				assert.NoError(t, processedBlockHashOrm.PruneProcessedBlockHashes(ctx, types.Layer1, 50))
+				blockHashes, err := processedBlockHashOrm.GetLatestProcessedBlockHashes(ctx, types.Layer1, 0, 10)
+				assert.NoError(t, err)
+				assert.Len(t, blockHashes, 1)
+
+				assert.NoError(t, processedBlockHashOrm.PruneProcessedBlockHashes(ctx, types.Layer1, 51))
+				blockHashes, err = processedBlockHashOrm.GetLatestProcessedBlockHashes(ctx, types.Layer1, 0, 10)
+				assert.NoError(t, err)
+				assert.Len(t, blockHashes, 0)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, test.test)
+	}
+}
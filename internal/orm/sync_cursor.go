@@ -0,0 +1,86 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/scroll-tech/chain-monitor/internal/types"
+	"github.com/scroll-tech/chain-monitor/internal/utils"
+)
+
+// SyncCursor contains the last fully processed block of each layer and checker.
+type SyncCursor struct {
+	db *gorm.DB `gorm:"column:-"`
+
+	ID          int64  `json:"id" gorm:"column:id"`
+	Layer       int    `json:"layer" gorm:"layer"`
+	Checker     string `json:"checker" gorm:"checker"`
+	BlockNumber uint64 `json:"block_number" gorm:"block_number"`
+	BlockHash   string `json:"block_hash" gorm:"block_hash"`
+
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at"`
+}
+
+// NewSyncCursor creates a new SyncCursor database instance.
+func NewSyncCursor(db *gorm.DB) *SyncCursor {
+	return &SyncCursor{db: db}
+}
+
+// TableName returns the table name for the SyncCursor model.
+func (*SyncCursor) TableName() string {
+	return "sync_cursor"
+}
+
+// GetSyncCursor get the sync cursor of the layer and checker, returns nil if the cursor doesn't exist.
+func (s *SyncCursor) GetSyncCursor(ctx context.Context, layer types.LayerType, checker types.CheckerType) (*SyncCursor, error) {
+	var cursor SyncCursor
+	db := s.db.WithContext(ctx)
+	db = db.Where("layer = ? AND checker = ?", layer, checker)
+	err := db.First(&cursor).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		log.Warn("SyncCursor.GetSyncCursor failed", "error", err)
+		return nil, fmt.Errorf("SyncCursor.GetSyncCursor failed err:%w", err)
+	}
+	return &cursor, nil
+}
+
+// InsertOrUpdateSyncCursor insert or update the sync cursor of the layer and checker.
+func (s *SyncCursor) InsertOrUpdateSyncCursor(ctx context.Context, layer types.LayerType, checker types.CheckerType, blockNumber uint64, blockHash string, dbTX ...*gorm.DB) error {
+	db := s.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+
+	db = db.WithContext(ctx)
+	db = db.Model(&SyncCursor{})
+	db = db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "layer"}, {Name: "checker"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"block_number": blockNumber,
+			"block_hash":   blockHash,
+			"updated_at":   utils.NowUTC(),
+		}),
+	})
+
+	cursor := SyncCursor{
+		Layer:       int(layer),
+		Checker:     string(checker),
+		BlockNumber: blockNumber,
+		BlockHash:   blockHash,
+	}
+	if err := db.Create(&cursor).Error; err != nil {
+		return fmt.Errorf("SyncCursor.InsertOrUpdateSyncCursor error: %w, cursor: %v", err, cursor)
+	}
+	return nil
+}
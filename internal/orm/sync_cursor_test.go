@@ -0,0 +1,53 @@
+package orm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scroll-tech/chain-monitor/internal/types"
+	"github.com/scroll-tech/chain-monitor/internal/utils/testcontainer"
+)
+
+func TestSyncCursor(t *testing.T) {
+	ctx := context.Background()
+	db := testcontainer.SetupDB(ctx, t)
+	syncCursorOrm := NewSyncCursor(db)
+
+	tests := []struct {
+		name string
+		test func(t *testing.T)
+	}{
+		{
+			"getNotExistedSyncCursor", func(t *testing.T) {
+				cursor, err := syncCursorOrm.GetSyncCursor(ctx, types.Layer1, types.CheckerTypeContract)
+				assert.NoError(t, err)
+				assert.Nil(t, cursor)
+			},
+		},
+		{
+			"insertOrUpdateSyncCursor", func(t *testing.T) {
+				err := syncCursorOrm.InsertOrUpdateSyncCursor(ctx, types.Layer1, types.CheckerTypeContract, 100, "0xfc7d3ea5ec8dc9b664a5a886c3b33d21e665355057601033481a439498efb79a")
+				assert.NoError(t, err)
+
+				err = syncCursorOrm.InsertOrUpdateSyncCursor(ctx, types.Layer1, types.CheckerTypeContract, 150, "0x1c7d3ea5ec8dc9b664a5a886c3b33d21e665355057601033481a439498efb79a")
+				assert.NoError(t, err)
+
+				cursor, err := syncCursorOrm.GetSyncCursor(ctx, types.Layer1, types.CheckerTypeContract)
+				assert.NoError(t, err)
+				assert.NotNil(t, cursor)
+				assert.Equal(t, cursor.BlockNumber, uint64(150))
+				assert.Equal(t, cursor.BlockHash, "0x1c7d3ea5ec8dc9b664a5a886c3b33d21e665355057601033481a439498efb79a")
+
+				cursor, err = syncCursorOrm.GetSyncCursor(ctx, types.Layer2, types.CheckerTypeContract)
+				assert.NoError(t, err)
+				assert.Nil(t, cursor)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, test.test)
+	}
+}
@@ -0,0 +1,118 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/scroll-tech/go-ethereum/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/scroll-tech/chain-monitor/internal/types"
+)
+
+// TokenPair contains the l1 token address and its counterpart l2 token address.
+type TokenPair struct {
+	db *gorm.DB `gorm:"column:-"`
+
+	ID             int64  `json:"id" gorm:"column:id"`
+	TokenType      int    `json:"token_type" gorm:"token_type"`
+	L1TokenAddress string `json:"l1_token_address" gorm:"l1_token_address"`
+	L2TokenAddress string `json:"l2_token_address" gorm:"l2_token_address"`
+	Source         int    `json:"source" gorm:"source"`
+
+	CreatedAt time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at"`
+}
+
+// NewTokenPair creates a new TokenPair database instance.
+func NewTokenPair(db *gorm.DB) *TokenPair {
+	return &TokenPair{db: db}
+}
+
+// TableName returns the table name for the TokenPair model.
+func (*TokenPair) TableName() string {
+	return "token_pair"
+}
+
+// GetTokenPairsByTokenAddress get the token pairs which contain either the l1 token address or the l2 token address.
+func (t *TokenPair) GetTokenPairsByTokenAddress(ctx context.Context, l1TokenAddress, l2TokenAddress string) ([]TokenPair, error) {
+	var tokenPairs []TokenPair
+	db := t.db.WithContext(ctx)
+	db = db.Where("l1_token_address = ? OR l2_token_address = ?", l1TokenAddress, l2TokenAddress)
+	if err := db.Find(&tokenPairs).Error; err != nil {
+		log.Warn("TokenPair.GetTokenPairsByTokenAddress failed", "error", err)
+		return nil, fmt.Errorf("TokenPair.GetTokenPairsByTokenAddress failed err:%w", err)
+	}
+	return tokenPairs, nil
+}
+
+// GetTokenPairs get all the token pairs of the token type.
+func (t *TokenPair) GetTokenPairs(ctx context.Context, tokenType types.TokenType) ([]TokenPair, error) {
+	var tokenPairs []TokenPair
+	db := t.db.WithContext(ctx)
+	db = db.Where("token_type = ?", int(tokenType))
+	db = db.Order("id asc")
+	if err := db.Find(&tokenPairs).Error; err != nil {
+		log.Warn("TokenPair.GetTokenPairs failed", "error", err)
+		return nil, fmt.Errorf("TokenPair.GetTokenPairs failed err:%w", err)
+	}
+	return tokenPairs, nil
+}
+
+// InsertTokenPair insert the token pair, the token pair is ignored if either token address already exists.
+func (t *TokenPair) InsertTokenPair(ctx context.Context, tokenPair TokenPair, dbTX ...*gorm.DB) (int64, error) {
+	db := t.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+
+	db = db.WithContext(ctx)
+	db = db.Model(&TokenPair{})
+	db = db.Clauses(clause.OnConflict{DoNothing: true})
+
+	result := db.Create(&tokenPair)
+	if result.Error != nil {
+		return 0, fmt.Errorf("TokenPair.InsertTokenPair error: %w, token pair: %v", result.Error, tokenPair)
+	}
+	return result.RowsAffected, nil
+}
+
+// InsertOrUpdateConfiguredTokenPairs persists the configured token pairs. The learned token pairs conflicting with the
+// configured ones are replaced, since the configured token pairs take precedence.
+func (t *TokenPair) InsertOrUpdateConfiguredTokenPairs(ctx context.Context, tokenPairs []TokenPair, dbTX ...*gorm.DB) error {
+	db := t.db
+	if len(dbTX) > 0 && dbTX[0] != nil {
+		db = dbTX[0]
+	}
+
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, tokenPair := range tokenPairs {
+			deleteDB := tx.Unscoped().Where("(l1_token_address = ? OR l2_token_address = ?) AND NOT (l1_token_address = ? AND l2_token_address = ?)",
+				tokenPair.L1TokenAddress, tokenPair.L2TokenAddress, tokenPair.L1TokenAddress, tokenPair.L2TokenAddress)
+			result := deleteDB.Delete(&TokenPair{})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected > 0 {
+				log.Warn("the learned token pairs conflicting with the configured token pair are replaced", "l1 token", tokenPair.L1TokenAddress, "l2 token", tokenPair.L2TokenAddress)
+			}
+
+			tokenPair.Source = int(types.TokenPairSourceTypeConfigured)
+			insertDB := tx.Model(&TokenPair{}).Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "l1_token_address"}},
+				DoUpdates: clause.AssignmentColumns([]string{"token_type", "source", "deleted_at"}),
+			})
+			if err := insertDB.Create(&tokenPair).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("TokenPair.InsertOrUpdateConfiguredTokenPairs error: %w", err)
+	}
+	return nil
+}
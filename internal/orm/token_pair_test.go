@@ -0,0 +1,103 @@
+package orm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/scroll-tech/chain-monitor/internal/types"
+	"github.com/scroll-tech/chain-monitor/internal/utils/testcontainer"
+)
+
+func TestTokenPair_InsertTokenPair(t *testing.T) {
+	ctx := context.Background()
+	db := testcontainer.SetupDB(ctx, t)
+	tokenPairOrm := NewTokenPair(db)
+
+	tests := []struct {
+		name string
+		test func(t *testing.T)
+	}{
+		{
+			"insertTokenPair", func(t *testing.T) {
+				tokenPair := TokenPair{
+					TokenType:      int(types.TokenTypeERC20),
+					L1TokenAddress: "0x1",
+					L2TokenAddress: "0x2",
+					Source:         int(types.TokenPairSourceTypeLearned),
+				}
+				affectRows, err := tokenPairOrm.InsertTokenPair(ctx, tokenPair)
+				assert.NoError(t, err)
+				assert.Equal(t, affectRows, int64(1))
+			},
+		},
+		{
+			"insertConflictedTokenPair", func(t *testing.T) {
+				tokenPair := TokenPair{
+					TokenType:      int(types.TokenTypeERC20),
+					L1TokenAddress: "0x1",
+					L2TokenAddress: "0x3",
+					Source:         int(types.TokenPairSourceTypeLearned),
+				}
+				affectRows, err := tokenPairOrm.InsertTokenPair(ctx, tokenPair)
+				assert.NoError(t, err)
+				assert.Equal(t, affectRows, int64(0))
+			},
+		},
+		{
+			"getTokenPairs", func(t *testing.T) {
+				tokenPairs, err := tokenPairOrm.GetTokenPairsByTokenAddress(ctx, "0x4", "0x2")
+				assert.NoError(t, err)
+				assert.Len(t, tokenPairs, 1)
+				assert.Equal(t, tokenPairs[0].L1TokenAddress, "0x1")
+				assert.Equal(t, tokenPairs[0].L2TokenAddress, "0x2")
+			},
+		},
+		{
+			"getTokenPairsByTokenType", func(t *testing.T) {
+				tokenPairs, err := tokenPairOrm.GetTokenPairs(ctx, types.TokenTypeERC20)
+				assert.NoError(t, err)
+				assert.Len(t, tokenPairs, 1)
+				assert.Equal(t, tokenPairs[0].L1TokenAddress, "0x1")
+
+				tokenPairs, err = tokenPairOrm.GetTokenPairs(ctx, types.TokenTypeERC721)
+				assert.NoError(t, err)
+				assert.Len(t, tokenPairs, 0)
+			},
+		},
+		{
+			"insertOrUpdateConfiguredTokenPairs", func(t *testing.T) {
+				configuredTokenPairs := []TokenPair{
+					{TokenType: int(types.TokenTypeERC20), L1TokenAddress: "0x1", L2TokenAddress: "0x2"},
+					{TokenType: int(types.TokenTypeERC20), L1TokenAddress: "0x5", L2TokenAddress: "0x6"},
+				}
+				err := tokenPairOrm.InsertOrUpdateConfiguredTokenPairs(ctx, configuredTokenPairs)
+				assert.NoError(t, err)
+
+				tokenPairs, err := tokenPairOrm.GetTokenPairs(ctx, types.TokenTypeERC20)
+				assert.NoError(t, err)
+				assert.Len(t, tokenPairs, 2)
+				for _, tokenPair := range tokenPairs {
+					assert.Equal(t, tokenPair.Source, int(types.TokenPairSourceTypeConfigured))
+				}
+			},
+		},
+		{
+			"replaceConflictedLearnedTokenPair", func(t *testing.T) {
+				err := tokenPairOrm.InsertOrUpdateConfiguredTokenPairs(ctx, []TokenPair{{TokenType: int(types.TokenTypeERC20), L1TokenAddress: "0x1", L2TokenAddress: "0x7"}})
+				assert.NoError(t, err)
+
+				tokenPairs, err := tokenPairOrm.GetTokenPairsByTokenAddress(ctx, "0x1", "0x2")
+				assert.NoError(t, err)
+				assert.Len(t, tokenPairs, 1)
+				assert.Equal(t, tokenPairs[0].L2TokenAddress, "0x7")
+				assert.Equal(t, tokenPairs[0].Source, int(types.TokenPairSourceTypeConfigured))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, test.test)
+	}
+}
@@ -20,4 +20,9 @@ func Route(router *gin.Engine) {
 
 func v1(router *gin.RouterGroup) {
 	router.GET("/batch_status", controller.FinalizeBatchCtl.BatchStatus)
+	router.GET("/batch/:index", controller.FinalizeBatchCtl.Batch)
+	router.GET("/message_match", controller.MessageMatchCtl.MessageMatch)
+	router.GET("/message_matches", controller.MessageMatchCtl.MessageMatches)
+	router.GET("/message_matches/tx", controller.MessageMatchCtl.MessageMatchesByTxHash)
+	router.GET("/withdraw_proof", controller.WithdrawProofCtl.WithdrawProof)
 }
@@ -0,0 +1,17 @@
+package types
+
+//go:generate stringer -type AlertOutboxStatus
+
+// AlertOutboxStatus represents the delivery status of an alert in the outbox.
+type AlertOutboxStatus int
+
+const (
+	// AlertOutboxStatusTypeUnknown represents the alert outbox status is unknown.
+	AlertOutboxStatusTypeUnknown AlertOutboxStatus = iota
+	// AlertOutboxStatusTypePending represents the alert is waiting to be delivered or retried.
+	AlertOutboxStatusTypePending
+	// AlertOutboxStatusTypeSent represents the alert has been delivered to the sink.
+	AlertOutboxStatusTypeSent
+	// AlertOutboxStatusTypeDeadLetter represents the alert failed to be delivered after the max attempts.
+	AlertOutboxStatusTypeDeadLetter
+)
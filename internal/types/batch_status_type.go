@@ -0,0 +1,17 @@
+package types
+
+//go:generate stringer -type BatchStatus
+
+// BatchStatus represents the verdict of a batch committed on layer 1, which is computed from the message matches of its l2 blocks.
+type BatchStatus int
+
+const (
+	// BatchStatusTypePending represents a batch whose l2 blocks are not ingested or checked yet.
+	BatchStatusTypePending BatchStatus = iota
+	// BatchStatusTypeValid represents a batch whose l2 blocks pass all the checks.
+	BatchStatusTypeValid
+	// BatchStatusTypeInvalid represents a batch whose l2 blocks fail the checks.
+	BatchStatusTypeInvalid
+	// BatchStatusTypeUnknownRange represents a batch whose l2 block range can't be decoded from its commit tx, so its l2 blocks can't be checked.
+	BatchStatusTypeUnknownRange
+)
@@ -6,8 +6,10 @@ package types
 type BlockStatus int
 
 const (
-	// BlockStatusTypeInvalid represents an invalid block.
-	BlockStatusTypeInvalid BlockStatus = iota
+	// BlockStatusTypeUnchecked represents a block whose events are not checked yet.
+	BlockStatusTypeUnchecked BlockStatus = iota
 	// BlockStatusTypeValid represents a valid block.
 	BlockStatusTypeValid
+	// BlockStatusTypeFailed represents a block whose events failed the checks, the failure reason is recorded.
+	BlockStatusTypeFailed
 )
@@ -8,13 +8,14 @@ func _() {
 	// An "invalid array index" compiler error signifies that the constant values have changed.
 	// Re-run the stringer command to generate them again.
 	var x [1]struct{}
-	_ = x[BlockStatusTypeInvalid-0]
+	_ = x[BlockStatusTypeUnchecked-0]
 	_ = x[BlockStatusTypeValid-1]
+	_ = x[BlockStatusTypeFailed-2]
 }
 
-const _BlockStatus_name = "BlockStatusTypeInvalidBlockStatusTypeValid"
+const _BlockStatus_name = "BlockStatusTypeUncheckedBlockStatusTypeValidBlockStatusTypeFailed"
 
-var _BlockStatus_index = [...]uint8{0, 22, 42}
+var _BlockStatus_index = [...]uint8{0, 24, 44, 65}
 
 func (i BlockStatus) String() string {
 	if i < 0 || i >= BlockStatus(len(_BlockStatus_index)-1) {
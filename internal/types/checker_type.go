@@ -0,0 +1,9 @@
+package types
+
+// CheckerType represents the checker which processes the blocks, each checker has its own sync cursor of every layer.
+type CheckerType string
+
+const (
+	// CheckerTypeContract represents the contract controller which ingests the gateway and messenger events.
+	CheckerTypeContract CheckerType = "contract"
+)
@@ -6,8 +6,12 @@ package types
 type CrossChainStatusType int
 
 const (
-	// CrossChainStatusTypeInvalid represents an invalid cross-chain transaction.
-	CrossChainStatusTypeInvalid CrossChainStatusType = iota
+	// CrossChainStatusTypeUnchecked represents a cross-chain transaction which is not checked yet.
+	CrossChainStatusTypeUnchecked CrossChainStatusType = iota
 	// CrossChainStatusTypeValid represents a valid cross-chain transaction.
 	CrossChainStatusTypeValid
+	// CrossChainStatusTypeFailed represents a cross-chain transaction which failed the check, the failure reason is recorded.
+	// The failed one isn't checked again since its events don't change, until the events are rolled back by a reorg, which
+	// resets it to unchecked.
+	CrossChainStatusTypeFailed
 )
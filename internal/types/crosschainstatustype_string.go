@@ -8,13 +8,14 @@ func _() {
 	// An "invalid array index" compiler error signifies that the constant values have changed.
 	// Re-run the stringer command to generate them again.
 	var x [1]struct{}
-	_ = x[CrossChainStatusTypeInvalid-0]
+	_ = x[CrossChainStatusTypeUnchecked-0]
 	_ = x[CrossChainStatusTypeValid-1]
+	_ = x[CrossChainStatusTypeFailed-2]
 }
 
-const _CrossChainStatusType_name = "CrossChainStatusTypeInvalidCrossChainStatusTypeValid"
+const _CrossChainStatusType_name = "CrossChainStatusTypeUncheckedCrossChainStatusTypeValidCrossChainStatusTypeFailed"
 
-var _CrossChainStatusType_index = [...]uint8{0, 27, 52}
+var _CrossChainStatusType_index = [...]uint8{0, 29, 54, 80}
 
 func (i CrossChainStatusType) String() string {
 	if i < 0 || i >= CrossChainStatusType(len(_CrossChainStatusType_index)-1) {
@@ -7,4 +7,8 @@ const (
 	InternalServerError = 500
 	// ErrParameterInvalidNo is invalid params
 	ErrParameterInvalidNo = 40001
+	// ErrWithdrawProofNotFoundNo is the message is not a l2 sent message
+	ErrWithdrawProofNotFoundNo = 40004
+	// ErrBatchNotFoundNo is the batch is not committed or not ingested
+	ErrBatchNotFoundNo = 40005
 )
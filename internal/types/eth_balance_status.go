@@ -6,8 +6,10 @@ package types
 type ETHBalanceStatus int
 
 const (
-	// ETHBalanceStatusTypeInvalid represents an invalid balance.
-	ETHBalanceStatusTypeInvalid ETHBalanceStatus = iota
+	// ETHBalanceStatusTypeUnchecked represents a balance which is not checked yet.
+	ETHBalanceStatusTypeUnchecked ETHBalanceStatus = iota
 	// ETHBalanceStatusTypeValid represents a valid balance.
 	ETHBalanceStatusTypeValid
+	// ETHBalanceStatusTypeFailed represents a balance which failed the check, the failure reason is recorded.
+	ETHBalanceStatusTypeFailed
 )
@@ -8,13 +8,14 @@ func _() {
 	// An "invalid array index" compiler error signifies that the constant values have changed.
 	// Re-run the stringer command to generate them again.
 	var x [1]struct{}
-	_ = x[ETHBalanceStatusTypeInvalid-0]
+	_ = x[ETHBalanceStatusTypeUnchecked-0]
 	_ = x[ETHBalanceStatusTypeValid-1]
+	_ = x[ETHBalanceStatusTypeFailed-2]
 }
 
-const _ETHBalanceStatus_name = "ETHBalanceStatusTypeInvalidETHBalanceStatusTypeValid"
+const _ETHBalanceStatus_name = "ETHBalanceStatusTypeUncheckedETHBalanceStatusTypeValidETHBalanceStatusTypeFailed"
 
-var _ETHBalanceStatus_index = [...]uint8{0, 27, 52}
+var _ETHBalanceStatus_index = [...]uint8{0, 29, 54, 80}
 
 func (i ETHBalanceStatus) String() string {
 	if i < 0 || i >= ETHBalanceStatus(len(_ETHBalanceStatus_index)-1) {
@@ -16,4 +16,10 @@ const (
 	ERC1155EventCategory
 	// MessengerEventCategory represents the messenger events.
 	MessengerEventCategory
+	// ETHEventCategory represents the ETH gateway events.
+	ETHEventCategory
+	// BatchEventCategory represents the l1 scroll chain batch events.
+	BatchEventCategory
+	// MessageQueueEventCategory represents the l1 message queue events.
+	MessageQueueEventCategory
 )
@@ -90,4 +90,23 @@ const (
 	L2FinalizeBatchDepositERC1155
 	// L2BatchWithdrawERC1155 represents the event for batch withdrawing ERC1155 tokens on Layer 2.
 	L2BatchWithdrawERC1155
+
+	// L1FailedRelayedMessage represents a message failed to be relayed on Layer 1.
+	L1FailedRelayedMessage
+	// L2FailedRelayedMessage represents a message failed to be relayed on Layer 2.
+	L2FailedRelayedMessage
+
+	// L1CommitBatch represents a batch committed on Layer 1.
+	L1CommitBatch
+	// L1FinalizeBatch represents a batch finalized on Layer 1.
+	L1FinalizeBatch
+	// L1RevertBatch represents a committed batch reverted on Layer 1.
+	L1RevertBatch
+
+	// L1QueueTransaction represents a message appended to the message queue on Layer 1.
+	L1QueueTransaction
+	// L1DequeueTransaction represents the messages popped from the message queue on Layer 1, some of them may be skipped.
+	L1DequeueTransaction
+	// L1DropTransaction represents a skipped message dropped from the message queue on Layer 1.
+	L1DropTransaction
 )
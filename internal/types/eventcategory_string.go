@@ -13,11 +13,14 @@ func _() {
 	_ = x[ERC721EventCategory-2]
 	_ = x[ERC1155EventCategory-3]
 	_ = x[MessengerEventCategory-4]
+	_ = x[ETHEventCategory-5]
+	_ = x[BatchEventCategory-6]
+	_ = x[MessageQueueEventCategory-7]
 }
 
-const _EventCategory_name = "EventCategoryUnknownERC20EventCategoryERC721EventCategoryERC1155EventCategoryMessengerEventCategory"
+const _EventCategory_name = "EventCategoryUnknownERC20EventCategoryERC721EventCategoryERC1155EventCategoryMessengerEventCategoryETHEventCategoryBatchEventCategoryMessageQueueEventCategory"
 
-var _EventCategory_index = [...]uint8{0, 20, 38, 57, 77, 99}
+var _EventCategory_index = [...]uint8{0, 20, 38, 57, 77, 99, 115, 133, 158}
 
 func (i EventCategory) String() string {
 	if i < 0 || i >= EventCategory(len(_EventCategory_index)-1) {
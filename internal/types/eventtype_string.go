@@ -43,11 +43,19 @@ func _() {
 	_ = x[L1BatchRefundERC1155-32]
 	_ = x[L2FinalizeBatchDepositERC1155-33]
 	_ = x[L2BatchWithdrawERC1155-34]
+	_ = x[L1FailedRelayedMessage-35]
+	_ = x[L2FailedRelayedMessage-36]
+	_ = x[L1CommitBatch-37]
+	_ = x[L1FinalizeBatch-38]
+	_ = x[L1RevertBatch-39]
+	_ = x[L1QueueTransaction-40]
+	_ = x[L1DequeueTransaction-41]
+	_ = x[L1DropTransaction-42]
 }
 
-const _EventType_name = "EventTypeUnknownL1SentMessageL1RelayedMessageL2SentMessageL2RelayedMessageL1DepositETHL1FinalizeWithdrawETHL1RefundETHL2FinalizeDepositETHL2WithdrawETHL1DepositERC20L1FinalizeWithdrawERC20L1RefundERC20L2FinalizeDepositERC20L2WithdrawERC20L1DepositERC721L1FinalizeWithdrawERC721L1RefundERC721L2FinalizeDepositERC721L2WithdrawERC721L1DepositERC1155L1FinalizeWithdrawERC1155L1RefundERC1155L2FinalizeDepositERC1155L2WithdrawERC1155L1BatchDepositERC721L1FinalizeBatchWithdrawERC721L1BatchRefundERC721L2FinalizeBatchDepositERC721L2BatchWithdrawERC721L1BatchDepositERC1155L1FinalizeBatchWithdrawERC1155L1BatchRefundERC1155L2FinalizeBatchDepositERC1155L2BatchWithdrawERC1155"
+const _EventType_name = "EventTypeUnknownL1SentMessageL1RelayedMessageL2SentMessageL2RelayedMessageL1DepositETHL1FinalizeWithdrawETHL1RefundETHL2FinalizeDepositETHL2WithdrawETHL1DepositERC20L1FinalizeWithdrawERC20L1RefundERC20L2FinalizeDepositERC20L2WithdrawERC20L1DepositERC721L1FinalizeWithdrawERC721L1RefundERC721L2FinalizeDepositERC721L2WithdrawERC721L1DepositERC1155L1FinalizeWithdrawERC1155L1RefundERC1155L2FinalizeDepositERC1155L2WithdrawERC1155L1BatchDepositERC721L1FinalizeBatchWithdrawERC721L1BatchRefundERC721L2FinalizeBatchDepositERC721L2BatchWithdrawERC721L1BatchDepositERC1155L1FinalizeBatchWithdrawERC1155L1BatchRefundERC1155L2FinalizeBatchDepositERC1155L2BatchWithdrawERC1155L1FailedRelayedMessageL2FailedRelayedMessageL1CommitBatchL1FinalizeBatchL1RevertBatchL1QueueTransactionL1DequeueTransactionL1DropTransaction"
 
-var _EventType_index = [...]uint16{0, 16, 29, 45, 58, 74, 86, 107, 118, 138, 151, 165, 188, 201, 223, 238, 253, 277, 291, 314, 330, 346, 371, 386, 410, 427, 447, 476, 495, 523, 544, 565, 595, 615, 644, 666}
+var _EventType_index = [...]uint16{0, 16, 29, 45, 58, 74, 86, 107, 118, 138, 151, 165, 188, 201, 223, 238, 253, 277, 291, 314, 330, 346, 371, 386, 410, 427, 447, 476, 495, 523, 544, 565, 595, 615, 644, 666, 688, 710, 723, 738, 751, 769, 789, 806}
 
 func (i EventType) String() string {
 	if i >= EventType(len(_EventType_index)-1) {
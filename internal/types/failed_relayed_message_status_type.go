@@ -0,0 +1,15 @@
+package types
+
+//go:generate stringer -type FailedRelayedMessageStatus
+
+// FailedRelayedMessageStatus represents the alert status of a message which failed to be relayed.
+type FailedRelayedMessageStatus int
+
+const (
+	// FailedRelayedMessageStatusTypeUnknown represents the failed relayed message hasn't been alerted.
+	FailedRelayedMessageStatusTypeUnknown FailedRelayedMessageStatus = iota
+	// FailedRelayedMessageStatusTypeAlerted represents the failed relayed message has been alerted.
+	FailedRelayedMessageStatusTypeAlerted
+	// FailedRelayedMessageStatusTypeRelayed represents the message has been relayed successfully after failures.
+	FailedRelayedMessageStatusTypeRelayed
+)
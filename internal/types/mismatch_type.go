@@ -18,4 +18,8 @@ const (
 	MismatchTypeL1AmountNotMatch
 	// MismatchTypeL2AmountNotMatch represents a mismatch where the layer2 amount does not match the Layer 1
 	MismatchTypeL2AmountNotMatch
+	// MismatchTypeTokenPairNotMatch represents a mismatch where the layer1 token is not paired with the layer2 token.
+	MismatchTypeTokenPairNotMatch
+	// MismatchTypeMessageHashNotMatch represents a mismatch where the hash of the relay tx input does not match the sent message hash.
+	MismatchTypeMessageHashNotMatch
 )
@@ -14,11 +14,13 @@ func _() {
 	_ = x[MismatchTypeL2EventNotMatch-3]
 	_ = x[MismatchTypeL1AmountNotMatch-4]
 	_ = x[MismatchTypeL2AmountNotMatch-5]
+	_ = x[MismatchTypeTokenPairNotMatch-6]
+	_ = x[MismatchTypeMessageHashNotMatch-7]
 }
 
-const _MismatchType_name = "MismatchTypeUnknownMismatchTypeValidMismatchTypeL1EventNotMatchMismatchTypeL2EventNotMatchMismatchTypeL1AmountNotMatchMismatchTypeL2AmountNotMatch"
+const _MismatchType_name = "MismatchTypeUnknownMismatchTypeValidMismatchTypeL1EventNotMatchMismatchTypeL2EventNotMatchMismatchTypeL1AmountNotMatchMismatchTypeL2AmountNotMatchMismatchTypeTokenPairNotMatchMismatchTypeMessageHashNotMatch"
 
-var _MismatchType_index = [...]uint8{0, 19, 36, 63, 90, 118, 146}
+var _MismatchType_index = [...]uint8{0, 19, 36, 63, 90, 118, 146, 175, 206}
 
 func (i MismatchType) String() string {
 	if i < 0 || i >= MismatchType(len(_MismatchType_index)-1) {
@@ -6,3 +6,46 @@ type FinalizeBatchCheckParam struct {
 	StartBlockNumber uint64 `form:"start_block_number" json:"start_block_number" binding:"required"`
 	EndBlockNumber   uint64 `form:"end_block_number" json:"end_block_number" binding:"required"`
 }
+
+// BatchParam the param of the batch lookup by batch index
+type BatchParam struct {
+	BatchIndex uint64 `uri:"index" json:"batch_index"`
+}
+
+// MessageMatchParam the param of the message match lookup by message hash
+type MessageMatchParam struct {
+	MessageHash string `form:"message_hash" json:"message_hash" binding:"required"`
+}
+
+// MessageMatchTxHashParam the param of the message match lookup by l1 or l2 tx hash
+type MessageMatchTxHashParam struct {
+	TxHash string `form:"tx_hash" json:"tx_hash" binding:"required"`
+}
+
+// MessageMatchListParam the param of the message match listing, the unset filters match all
+type MessageMatchListParam struct {
+	// gateway or messenger
+	Kind string `form:"kind" json:"kind" binding:"required,oneof=gateway messenger"`
+
+	L1BlockStatus      *int `form:"l1_block_status" json:"l1_block_status"`
+	L2BlockStatus      *int `form:"l2_block_status" json:"l2_block_status"`
+	L1CrossChainStatus *int `form:"l1_cross_chain_status" json:"l1_cross_chain_status"`
+	L2CrossChainStatus *int `form:"l2_cross_chain_status" json:"l2_cross_chain_status"`
+	WithdrawRootStatus *int `form:"withdraw_root_status" json:"withdraw_root_status"`
+
+	TokenType    *int   `form:"token_type" json:"token_type"`
+	TokenAddress string `form:"token_address" json:"token_address"`
+
+	// the block range of the layer, an end block number of 0 means no upper bound
+	Layer            int    `form:"layer" json:"layer" binding:"omitempty,oneof=1 2"`
+	StartBlockNumber uint64 `form:"start_block_number" json:"start_block_number"`
+	EndBlockNumber   uint64 `form:"end_block_number" json:"end_block_number"`
+
+	Page     int `form:"page" json:"page" binding:"omitempty,min=1"`
+	PageSize int `form:"page_size" json:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// WithdrawProofParam the param of the withdraw proof of a l2 sent message
+type WithdrawProofParam struct {
+	MessageHash string `form:"message_hash" json:"message_hash" binding:"required"`
+}
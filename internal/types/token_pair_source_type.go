@@ -0,0 +1,15 @@
+package types
+
+//go:generate stringer -type TokenPairSource
+
+// TokenPairSource represents where the l1/l2 token pair comes from.
+type TokenPairSource int
+
+const (
+	// TokenPairSourceTypeUnknown represents an unknown token pair source.
+	TokenPairSourceTypeUnknown TokenPairSource = iota
+	// TokenPairSourceTypeConfigured represents the token pair is configured.
+	TokenPairSourceTypeConfigured
+	// TokenPairSourceTypeLearned represents the token pair is learned from the first matched gateway message.
+	TokenPairSourceTypeLearned
+)
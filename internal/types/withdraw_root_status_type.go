@@ -6,8 +6,10 @@ package types
 type WithdrawRootStatus int
 
 const (
-	// WithdrawRootStatusTypeUnknown represents an unknown l2 withdraw root status.
-	WithdrawRootStatusTypeUnknown WithdrawRootStatus = iota
+	// WithdrawRootStatusTypeUnchecked represents a l2 withdraw root which is not checked yet.
+	WithdrawRootStatusTypeUnchecked WithdrawRootStatus = iota
 	// WithdrawRootStatusTypeValid represents a valid l2 withdraw root status.
 	WithdrawRootStatusTypeValid
+	// WithdrawRootStatusTypeFailed represents a withdraw root which mismatches the one computed locally.
+	WithdrawRootStatusTypeFailed
 )
@@ -8,13 +8,14 @@ func _() {
 	// An "invalid array index" compiler error signifies that the constant values have changed.
 	// Re-run the stringer command to generate them again.
 	var x [1]struct{}
-	_ = x[WithdrawRootStatusTypeUnknown-0]
+	_ = x[WithdrawRootStatusTypeUnchecked-0]
 	_ = x[WithdrawRootStatusTypeValid-1]
+	_ = x[WithdrawRootStatusTypeFailed-2]
 }
 
-const _WithdrawRootStatus_name = "WithdrawRootStatusTypeUnknownWithdrawRootStatusTypeValid"
+const _WithdrawRootStatus_name = "WithdrawRootStatusTypeUncheckedWithdrawRootStatusTypeValidWithdrawRootStatusTypeFailed"
 
-var _WithdrawRootStatus_index = [...]uint8{0, 29, 56}
+var _WithdrawRootStatus_index = [...]uint8{0, 31, 58, 86}
 
 func (i WithdrawRootStatus) String() string {
 	if i < 0 || i >= WithdrawRootStatus(len(_WithdrawRootStatus_index)-1) {
@@ -26,6 +26,10 @@ var (
 		&DBMigrateFlag,
 		&DBRollBackFlag,
 		&DBResetFlag,
+
+		&SyncCursorLayerFlag,
+		&SyncCursorBlockFlag,
+		&SyncCursorRollbackFlag,
 	}
 	// ConfigFileFlag load json type config file.
 	ConfigFileFlag = cli.StringFlag{
@@ -123,4 +127,56 @@ var (
 		Usage: "Clean and reset database.",
 		Value: false,
 	}
+
+	// SyncCursorLayerFlag the layer of the sync cursor to set.
+	SyncCursorLayerFlag = cli.IntFlag{
+		Name:  "cursor.layer",
+		Usage: "The layer of the sync cursor to set: 1=layer1, 2=layer2.",
+		Value: 1,
+	}
+	// SyncCursorBlockFlag set the sync cursor.
+	SyncCursorBlockFlag = cli.Uint64Flag{
+		Name:  "cursor.block",
+		Usage: "Set the sync cursor to the <block number>, the watcher resumes from the block after it.",
+	}
+	// SyncCursorRollbackFlag rollback the ingested events after the sync cursor.
+	SyncCursorRollbackFlag = cli.BoolFlag{
+		Name:  "cursor.rollback",
+		Usage: "Roll back the ingested events after the <block number> of cursor.block, so they are re-ingested.",
+		Value: false,
+	}
+)
+
+var (
+	// RescanFlags is used for the rescan command flags.
+	RescanFlags = []cli.Flag{
+		&RescanLayerFlag,
+		&RescanFromFlag,
+		&RescanToFlag,
+		&RescanDryRunFlag,
+	}
+	// RescanLayerFlag the layer to rescan.
+	RescanLayerFlag = cli.IntFlag{
+		Name:     "layer",
+		Usage:    "The layer to rescan: 1=layer1, 2=layer2.",
+		Required: true,
+	}
+	// RescanFromFlag the first block of the rescan range.
+	RescanFromFlag = cli.Uint64Flag{
+		Name:     "from",
+		Usage:    "The first block number of the rescan range.",
+		Required: true,
+	}
+	// RescanToFlag the last block of the rescan range.
+	RescanToFlag = cli.Uint64Flag{
+		Name:     "to",
+		Usage:    "The last block number of the rescan range.",
+		Required: true,
+	}
+	// RescanDryRunFlag only report the mismatches without updating the database.
+	RescanDryRunFlag = cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "Only report the mismatches, the rescanned results are not written into the database.",
+		Value: false,
+	}
 )
@@ -187,6 +187,21 @@ func RecoverBranchFromProof(proof []common.Hash, index uint64, msgHash common.Ha
 	return branches
 }
 
+// ComputeRootFromProof computes the root hash of withdraw trie from the merkle proof of the message at index,
+// it's the same as the verification of the proof on l1.
+func ComputeRootFromProof(proof []common.Hash, index uint64, msgHash common.Hash) common.Hash {
+	root := msgHash
+	for height := 0; height < len(proof); height++ {
+		if index%2 == 0 {
+			root = keccak2(root, proof[height])
+		} else {
+			root = keccak2(proof[height], root)
+		}
+		index >>= 1
+	}
+	return root
+}
+
 // Keccak2 compute the keccack256 of two concatenations of bytes32
 func keccak2(a common.Hash, b common.Hash) common.Hash {
 	return common.BytesToHash(crypto.Keccak256(append(a.Bytes()[:], b.Bytes()[:]...)))
@@ -125,7 +125,7 @@ func TestWithdrawTrieOneByOne(t *testing.T) {
 			assert.Equal(t, withdrawTrie.NextMessageNonce, uint64(i+1))
 			assert.Equal(t, expectedRoot.String(), withdrawTrie.MessageRoot().String())
 			proof := DecodeBytesToMerkleProof(proofBytes[0])
-			verifiedRoot := verifyMerkleProof(uint64(i), hash, proof)
+			verifiedRoot := ComputeRootFromProof(proof, uint64(i), hash)
 			assert.Equal(t, expectedRoot.String(), verifiedRoot.String())
 		}
 	}
@@ -166,26 +166,13 @@ func TestWithdrawTrieMultiple(t *testing.T) {
 			for i := initial; i <= finish; i++ {
 				hash := common.BigToHash(big.NewInt(int64(i + 1)))
 				proof := DecodeBytesToMerkleProof(proofBytes[i-initial])
-				verifiedRoot := verifyMerkleProof(uint64(i), hash, proof)
+				verifiedRoot := ComputeRootFromProof(proof, uint64(i), hash)
 				assert.Equal(t, expectedRoots[finish].String(), verifiedRoot.String())
 			}
 		}
 	}
 }
 
-func verifyMerkleProof(index uint64, leaf common.Hash, proof []common.Hash) common.Hash {
-	root := leaf
-	for _, h := range proof {
-		if index%2 == 0 {
-			root = keccak2(root, h)
-		} else {
-			root = keccak2(h, root)
-		}
-		index >>= 1
-	}
-	return root
-}
-
 func computeMerkleRoot(hashes []common.Hash) common.Hash {
 	if len(hashes) == 0 {
 		return common.Hash{}